@@ -0,0 +1,161 @@
+/*
+Copyright 2021, 2022
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	// MaxSyncWindows is the maximum number of sync windows that may be specified for a single GitOpsDeployment
+	MaxSyncWindows = 10
+
+	// maxSyncWindowTransitions is the maximum number of times that a window opens or closes, that we will examine
+	// when looking for the next time at which a sync is allowed.
+	maxSyncWindowTransitions = 10000
+)
+
+// parsedSyncWindow is a SyncWindow that has been parsed into its schedule, duration, and location
+type parsedSyncWindow struct {
+	kind     SyncWindowKind
+	schedule cron.Schedule
+	duration time.Duration
+	location *time.Location
+}
+
+// Validate returns an error if any of the fields of the sync window are invalid.
+func (w SyncWindow) Validate() error {
+	_, err := w.parse()
+	return err
+}
+
+func (w SyncWindow) parse() (parsedSyncWindow, error) {
+
+	if w.Kind != SyncWindowKind_Allow && w.Kind != SyncWindowKind_Deny {
+		return parsedSyncWindow{}, fmt.Errorf("sync window kind must be '%s' or '%s'", SyncWindowKind_Allow, SyncWindowKind_Deny)
+	}
+
+	schedule, err := cron.ParseStandard(w.Schedule)
+	if err != nil {
+		return parsedSyncWindow{}, fmt.Errorf("invalid sync window schedule '%s': %v", w.Schedule, err)
+	}
+
+	duration, err := time.ParseDuration(w.Duration)
+	if err != nil || duration <= 0 {
+		return parsedSyncWindow{}, fmt.Errorf("invalid sync window duration '%s': must be a positive duration, such as '1h' or '30m'", w.Duration)
+	}
+
+	location := time.UTC
+	if w.TimeZone != "" {
+		if location, err = time.LoadLocation(w.TimeZone); err != nil {
+			return parsedSyncWindow{}, fmt.Errorf("invalid sync window time zone '%s': %v", w.TimeZone, err)
+		}
+	}
+
+	return parsedSyncWindow{kind: w.Kind, schedule: schedule, duration: duration, location: location}, nil
+}
+
+// activeAt returns true if the window is open at the given time, along with the time at which it closes.
+func (p parsedSyncWindow) activeAt(t time.Time) (bool, time.Time) {
+
+	// The most recent opening of the window that could still be active is the first one after (t - duration)
+	start := p.schedule.Next(t.In(p.location).Add(-p.duration))
+	if start.IsZero() || start.After(t) {
+		return false, time.Time{}
+	}
+
+	return true, start.Add(p.duration)
+}
+
+// SyncAllowed returns true if a sync may occur at the given time: that is, if none of the deny windows are open,
+// and either none of the windows are allow windows, or at least one allow window is open.
+//
+// If a sync is not allowed, the time at which a sync will next be allowed is also returned. This will be the zero
+// time if it could not be determined (for example, because a deny window never closes).
+func (s SyncWindows) SyncAllowed(now time.Time) (bool, time.Time, error) {
+
+	if len(s) == 0 {
+		return true, time.Time{}, nil
+	}
+
+	var windows []parsedSyncWindow
+	for _, window := range s {
+		parsed, err := window.parse()
+		if err != nil {
+			return false, time.Time{}, err
+		}
+		windows = append(windows, parsed)
+	}
+
+	if syncAllowedAt(windows, now) {
+		return true, time.Time{}, nil
+	}
+
+	// A sync is only allowed or denied anew when a window opens or closes, so step through those points in time
+	t := now
+	for i := 0; i < maxSyncWindowTransitions; i++ {
+
+		var next time.Time
+		for _, window := range windows {
+
+			transition := window.schedule.Next(t.In(window.location))
+			if active, end := window.activeAt(t); active {
+				transition = end
+			}
+
+			if !transition.IsZero() && (next.IsZero() || transition.Before(next)) {
+				next = transition
+			}
+		}
+
+		if next.IsZero() {
+			break
+		}
+
+		t = next
+		if syncAllowedAt(windows, t) {
+			return false, t, nil
+		}
+	}
+
+	return false, time.Time{}, nil
+}
+
+func syncAllowedAt(windows []parsedSyncWindow, t time.Time) bool {
+
+	allowWindowDefined := false
+	allowWindowActive := false
+
+	for _, window := range windows {
+
+		active, _ := window.activeAt(t)
+
+		if window.kind == SyncWindowKind_Deny && active {
+			return false
+		}
+
+		if window.kind == SyncWindowKind_Allow {
+			allowWindowDefined = true
+			allowWindowActive = allowWindowActive || active
+		}
+	}
+
+	return !allowWindowDefined || allowWindowActive
+}
@@ -0,0 +1,99 @@
+package v1alpha1
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("GitOpsDeployment sync window tests.", func() {
+
+	// Monday, 9:30 AM UTC
+	monday0930 := time.Date(2024, time.January, 1, 9, 30, 0, 0, time.UTC)
+
+	businessHours := SyncWindow{
+		Kind:     SyncWindowKind_Allow,
+		Schedule: "0 9 * * 1-5",
+		Duration: "8h",
+	}
+
+	Context("Testing Validate function for SyncWindow type.", func() {
+
+		It("should accept a valid sync window", func() {
+			Expect(businessHours.Validate()).To(Succeed())
+
+			window := businessHours
+			window.TimeZone = "America/New_York"
+			Expect(window.Validate()).To(Succeed())
+		})
+
+		DescribeTable("should reject an invalid sync window",
+			func(window SyncWindow) {
+				Expect(window.Validate()).ToNot(Succeed())
+			},
+			Entry("invalid kind", SyncWindow{Kind: "sometimes", Schedule: "0 9 * * *", Duration: "1h"}),
+			Entry("invalid schedule", SyncWindow{Kind: SyncWindowKind_Deny, Schedule: "0 25 * * *", Duration: "1h"}),
+			Entry("invalid duration", SyncWindow{Kind: SyncWindowKind_Deny, Schedule: "0 9 * * *", Duration: "an hour"}),
+			Entry("zero duration", SyncWindow{Kind: SyncWindowKind_Deny, Schedule: "0 9 * * *", Duration: "0s"}),
+			Entry("invalid time zone", SyncWindow{Kind: SyncWindowKind_Deny, Schedule: "0 9 * * *", Duration: "1h", TimeZone: "Mars/Olympus_Mons"}),
+		)
+	})
+
+	Context("Testing SyncAllowed function for SyncWindows type.", func() {
+
+		It("should allow sync when there are no sync windows", func() {
+			allowed, _, err := SyncWindows{}.SyncAllowed(monday0930)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(allowed).To(BeTrue())
+		})
+
+		It("should only allow sync while an allow window is open", func() {
+			windows := SyncWindows{businessHours}
+
+			allowed, _, err := windows.SyncAllowed(monday0930)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(allowed).To(BeTrue())
+
+			By("returning the start of the next allow window, once the current window has closed")
+			allowed, nextOpen, err := windows.SyncAllowed(monday0930.Add(8 * time.Hour))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(allowed).To(BeFalse())
+			Expect(nextOpen).To(BeTemporally("==", time.Date(2024, time.January, 2, 9, 0, 0, 0, time.UTC)))
+
+			By("skipping over the weekend")
+			allowed, nextOpen, err = windows.SyncAllowed(time.Date(2024, time.January, 6, 12, 0, 0, 0, time.UTC))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(allowed).To(BeFalse())
+			Expect(nextOpen).To(BeTemporally("==", time.Date(2024, time.January, 8, 9, 0, 0, 0, time.UTC)))
+		})
+
+		It("should deny sync while a deny window is open, even if an allow window is also open", func() {
+			windows := SyncWindows{
+				businessHours,
+				{Kind: SyncWindowKind_Deny, Schedule: "0 9 1 1 *", Duration: "24h"},
+			}
+
+			allowed, nextOpen, err := windows.SyncAllowed(monday0930)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(allowed).To(BeFalse())
+			Expect(nextOpen).To(BeTemporally("==", time.Date(2024, time.January, 2, 9, 0, 0, 0, time.UTC)))
+		})
+
+		It("should evaluate the schedule in the time zone of the sync window", func() {
+			window := businessHours
+			window.TimeZone = "America/New_York"
+
+			// 9:30 AM UTC is 4:30 AM in New York, so the window has not yet opened
+			allowed, nextOpen, err := SyncWindows{window}.SyncAllowed(monday0930)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(allowed).To(BeFalse())
+			Expect(nextOpen).To(BeTemporally("==", time.Date(2024, time.January, 1, 14, 0, 0, 0, time.UTC)))
+		})
+
+		It("should return an error if a sync window is invalid", func() {
+			_, _, err := SyncWindows{{Kind: SyncWindowKind_Allow, Schedule: "not a schedule", Duration: "1h"}}.SyncAllowed(monday0930)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
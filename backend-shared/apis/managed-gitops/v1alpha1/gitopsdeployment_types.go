@@ -22,8 +22,16 @@ import (
 
 // GitOpsDeploymentSpec defines the desired state of GitOpsDeployment
 type GitOpsDeploymentSpec struct {
+	// Source is a reference to the location of the application's manifests.
+	// Exactly one of Source or Sources must be specified.
+	// +optional
 	Source ApplicationSource `json:"source"`
 
+	// Sources is a list of references to the locations of the application's manifests, for applications that are
+	// composed from more than one repository (for example, manifests in one repository and Helm values in another).
+	// Exactly one of Source or Sources must be specified.
+	Sources []ApplicationSource `json:"sources,omitempty"`
+
 	// Destination is a reference to a target namespace/cluster to deploy to.
 	// This field may be empty: if it is empty, it is assumed that the destination
 	// is the same namespace as the GitOpsDeployment CR.
@@ -32,6 +40,10 @@ type GitOpsDeploymentSpec struct {
 	// SyncPolicy controls when and how a sync will be performed.
 	SyncPolicy *SyncPolicy `json:"syncPolicy,omitempty"`
 
+	// IgnoreDifferences is a list of resources and fields which should be ignored when comparing the live state of the
+	// deployment with the desired state, for example fields that are mutated by a HorizontalPodAutoscaler, webhook, or operator.
+	IgnoreDifferences []ResourceIgnoreDifferences `json:"ignoreDifferences,omitempty"`
+
 	// Two possible values:
 	// - Automated: whenever a new commit occurs in the GitOps repository, or the Argo CD Application is out of sync, Argo CD should be told to (re)synchronize.
 	// - Manual: Argo CD should never be told to resynchronize. Instead, synchronize operations will be triggered via GitOpsDeploymentSyncRun operations only.
@@ -40,18 +52,133 @@ type GitOpsDeploymentSpec struct {
 	// Note: This is somewhat of a placeholder for more advanced logic that can be implemented in the future.
 	// For an example of this type of logic, see the 'syncPolicy' field of Argo CD Application.
 	Type string `json:"type"`
+
+	// Suspend pauses reconciliation of the GitOpsDeployment, without deleting any of its deployed resources.
+	// While suspended:
+	// - automated sync is disabled in Argo CD,
+	// - changes that are made to the Argo CD Application are not reverted by the GitOps Service, and
+	// - the deployed resources are never deleted by the orphaned resource clean-up of the GitOps Service.
+	// The health and sync status of the GitOpsDeployment continue to be reported.
+	Suspend bool `json:"suspend,omitempty"`
+
+	// DependsOn is a list of the names of other GitOpsDeployments, in the same namespace, that must be both Synced and
+	// Healthy before this GitOpsDeployment is deployed:
+	// - For Automated GitOpsDeployments, the Argo CD Application is not created (or updated) until all of its
+	//   dependencies are ready.
+	// - For Manual GitOpsDeployments, GitOpsDeploymentSyncRuns are held until all of its dependencies are ready.
+	// While waiting, the 'WaitingOnDependencies' condition of the GitOpsDeployment lists the dependencies that are not yet ready.
+	// +kubebuilder:validation:MaxItems=32
+	DependsOn []string `json:"dependsOn,omitempty"`
+
+	// DeletionPolicy controls what happens to the deployed resources when the GitOpsDeployment is deleted:
+	// - Cascade (the default): the deployed resources are deleted in the background, after the GitOpsDeployment is deleted.
+	// - Foreground: the GitOpsDeployment is not deleted until all of the deployed resources have been deleted.
+	// - Orphan: the deployed resources are left running, and are never deleted by the orphaned resource clean-up of the
+	//   GitOps Service. This may be used to hand the resources over to another tool.
+	// See `DeletionPolicy_*`
+	DeletionPolicy DeletionPolicy `json:"deletionPolicy,omitempty"`
 }
 
+// DeletionPolicy defines what happens to the deployed resources of a GitOpsDeployment when it is deleted.
+// +kubebuilder:validation:Enum=Cascade;Orphan;Foreground
+type DeletionPolicy string
+
+const (
+	DeletionPolicy_Cascade    DeletionPolicy = "Cascade"
+	DeletionPolicy_Orphan     DeletionPolicy = "Orphan"
+	DeletionPolicy_Foreground DeletionPolicy = "Foreground"
+)
+
 // ApplicationSource contains all required information about the source of an application
 type ApplicationSource struct {
 	// RepoURL is the URL to the repository (Git or Helm) that contains the application manifests
 	RepoURL string `json:"repoURL"`
 	// Path is a directory path within the Git repository, and is only valid for applications sourced from Git.
-	Path string `json:"path"`
+	Path string `json:"path,omitempty"`
 	// TargetRevision defines the revision of the source to sync the application to.
 	// In case of Git, this can be commit, tag, or branch. If omitted, will equal to HEAD.
 	// In case of Helm, this is a semver tag for the Chart's version.
 	TargetRevision string `json:"targetRevision,omitempty"`
+
+	// Chart is a Helm chart name, and must be specified for applications sourced from a Helm repo.
+	// When Chart is specified, Path should be empty.
+	Chart string `json:"chart,omitempty"`
+
+	// Helm holds Helm-specific options, for applications sourced from a Helm chart.
+	Helm *ApplicationSourceHelm `json:"helm,omitempty"`
+	// Kustomize holds Kustomize-specific overrides, for applications sourced from a Kustomize directory.
+	Kustomize *ApplicationSourceKustomize `json:"kustomize,omitempty"`
+
+	// Ref is a reference name for this source, and is only valid within .spec.sources. Other sources in the list may
+	// then refer to files in this source: for example, a Helm value file of '$<ref>/path/to/values.yaml'.
+	Ref string `json:"ref,omitempty"`
+}
+
+// ApplicationSourceKustomize holds Kustomize-specific overrides
+type ApplicationSourceKustomize struct {
+	// NamePrefix is a prefix appended to resources for Kustomize apps.
+	// It may only contain lowercase alphanumeric characters, '-' and '.'.
+	NamePrefix string `json:"namePrefix,omitempty"`
+
+	// NameSuffix is a suffix appended to resources for Kustomize apps.
+	// It may only contain lowercase alphanumeric characters, '-' and '.'.
+	NameSuffix string `json:"nameSuffix,omitempty"`
+
+	// Images is a list of Kustomize image override specifications, for example: 'quay.io/org/image:v2' or 'image=quay.io/org/image:v2'
+	Images []string `json:"images,omitempty"`
+
+	// CommonLabels is a list of additional labels to add to rendered manifests
+	CommonLabels map[string]string `json:"commonLabels,omitempty"`
+
+	// CommonAnnotations is a list of additional annotations to add to rendered manifests
+	CommonAnnotations map[string]string `json:"commonAnnotations,omitempty"`
+}
+
+// ApplicationSourceHelm holds Helm-specific options
+type ApplicationSourceHelm struct {
+	// ReleaseName is the Helm release name to use. If omitted it will use the application name
+	ReleaseName string `json:"releaseName,omitempty"`
+
+	// Values specifies Helm values to be passed to 'helm template', defined as an inline YAML block.
+	// The block must be a YAML object, of at most 32768 bytes.
+	Values string `json:"values,omitempty"`
+
+	// ValueFiles is a list of Helm value files (relative to the chart) to use when generating a template
+	ValueFiles []string `json:"valueFiles,omitempty"`
+
+	// Parameters is a list of Helm parameters which are passed to the 'helm template' command upon manifest generation
+	Parameters []HelmParameter `json:"parameters,omitempty"`
+}
+
+// MaxHelmValuesLength is the maximum length, in bytes, of the inline Helm values of a source: the values are stored
+// as part of the Argo CD Application spec in the database, which is itself limited in size.
+const MaxHelmValuesLength = 32768
+
+// HelmParameter is a parameter that's passed to 'helm template' during manifest generation
+type HelmParameter struct {
+	// Name is the name of the Helm parameter
+	Name string `json:"name"`
+	// Value is the value for the Helm parameter
+	Value string `json:"value,omitempty"`
+	// ForceString determines whether to tell Helm to interpret booleans and numbers as strings
+	ForceString bool `json:"forceString,omitempty"`
+}
+
+// ResourceIgnoreDifferences contains the resource fields which should be ignored during comparison
+type ResourceIgnoreDifferences struct {
+	// Group is the API group of the resource. The empty string refers to the core API group.
+	Group string `json:"group,omitempty"`
+	// Kind is the kind of the resource
+	Kind string `json:"kind"`
+	// Name is the name of the resource. If empty, all resources of this group/kind are matched.
+	Name string `json:"name,omitempty"`
+	// Namespace is the namespace of the resource. If empty, resources in all namespaces are matched.
+	Namespace string `json:"namespace,omitempty"`
+	// JSONPointers is a list of JSON pointers (RFC 6901) to the fields to ignore, for example '/spec/replicas'
+	JSONPointers []string `json:"jsonPointers,omitempty"`
+	// JQPathExpressions is a list of JQ path expressions to the fields to ignore, for example
+	// '.spec.template.spec.containers[] | select(.name == "istio-proxy")'
+	JQPathExpressions []string `json:"jqPathExpressions,omitempty"`
 }
 
 // ApplicationDestination holds information about the application's destination
@@ -66,6 +193,17 @@ const (
 	// DeletionFinalizer will indicate the GitOpsDeployment to wait until all its dependencies are removed.
 	// In the absence of this finalizer, GitOpsDeployment will be deleted first and its dependencies will be removed in the background.
 	DeletionFinalizer string = "resources-finalizer.managed-gitops.redhat.com"
+
+	// DeletionFinalizerAddedAnnotation is set to 'true' on a GitOpsDeployment when the DeletionFinalizer was added to it
+	// automatically, for its 'Foreground' or 'Orphan' deletion policy: the finalizer is removed again if the deletion policy
+	// is changed back to 'Cascade'. A DeletionFinalizer that was added by the user is left as is.
+	DeletionFinalizerAddedAnnotation string = "managed-gitops.redhat.com/deletion-finalizer-added"
+
+	// OrphanedResourceAnnotation is set to 'true' on the deployed resources of a GitOpsDeployment that was deleted with
+	// the 'Orphan' deletion policy: these resources are never deleted by the orphaned resource clean-up.
+	// Only resources deployed to the namespace of the GitOpsDeployment are annotated, as the clean-up does not scan
+	// remote environments.
+	OrphanedResourceAnnotation string = "managed-gitops.redhat.com/orphaned"
 )
 
 type SyncOption string
@@ -74,13 +212,118 @@ type SyncOption string
 const (
 	SyncOptions_CreateNamespace_true  SyncOption = "CreateNamespace=true"
 	SyncOptions_CreateNamespace_false SyncOption = "CreateNamespace=false"
+
+	SyncOptions_ServerSideApply_true  SyncOption = "ServerSideApply=true"
+	SyncOptions_ServerSideApply_false SyncOption = "ServerSideApply=false"
+
+	SyncOptions_PruneLast_true SyncOption = "PruneLast=true"
+
+	SyncOptions_ApplyOutOfSyncOnly_true SyncOption = "ApplyOutOfSyncOnly=true"
+
+	SyncOptions_Replace_true SyncOption = "Replace=true"
+
+	SyncOptions_Validate_false SyncOption = "Validate=false"
+
+	SyncOptions_RespectIgnoreDifferences_true SyncOption = "RespectIgnoreDifferences=true"
+
+	SyncOptions_PrunePropagationPolicy_foreground SyncOption = "PrunePropagationPolicy=foreground"
+	SyncOptions_PrunePropagationPolicy_background SyncOption = "PrunePropagationPolicy=background"
+	SyncOptions_PrunePropagationPolicy_orphan     SyncOption = "PrunePropagationPolicy=orphan"
 )
 
+// SyncOptions_PrunePropagationPolicy_prefix is the prefix shared by all of the PrunePropagationPolicy sync options
+const SyncOptions_PrunePropagationPolicy_prefix = "PrunePropagationPolicy="
+
+// IsValidSyncOption returns true if the sync option is one of the supported values above, false otherwise.
+func IsValidSyncOption(syncOption SyncOption) bool {
+	switch syncOption {
+	case SyncOptions_CreateNamespace_true, SyncOptions_CreateNamespace_false,
+		SyncOptions_ServerSideApply_true, SyncOptions_ServerSideApply_false,
+		SyncOptions_PruneLast_true,
+		SyncOptions_ApplyOutOfSyncOnly_true,
+		SyncOptions_Replace_true,
+		SyncOptions_Validate_false,
+		SyncOptions_RespectIgnoreDifferences_true,
+		SyncOptions_PrunePropagationPolicy_foreground, SyncOptions_PrunePropagationPolicy_background, SyncOptions_PrunePropagationPolicy_orphan:
+		return true
+	}
+	return false
+}
+
 type SyncPolicy struct {
+	// Automated controls the behaviour of automated sync, and may only be specified when .spec.type is 'automated'.
+	// If it is not specified, automated sync will prune, self-heal, and allow empty, as before.
+	Automated *SyncPolicyAutomated `json:"automated,omitempty"`
+
 	// Options allow you to specify whole app sync-options.
 	// This option may be empty, if and when it is empty it is considered that there are no SyncOptions present.
 	SyncOptions SyncOptions `json:"syncOptions,omitempty"`
+
+	// Retry controls how failed syncs are retried. If it is not specified, automated syncs are retried
+	// indefinitely (with a backoff of 5s, factor 2, max 3m), and manual syncs are not retried.
+	Retry *RetryStrategy `json:"retry,omitempty"`
+
+	// SyncWindows restrict the times at which the GitOpsDeployment may be synchronized, whether by automated sync, or by
+	// a GitOpsDeploymentSyncRun. If no sync windows are specified, a sync may occur at any time.
+	SyncWindows SyncWindows `json:"syncWindows,omitempty"`
+}
+
+// SyncWindowKind is the kind of a sync window: either 'allow' or 'deny'
+type SyncWindowKind string
+
+const (
+	// SyncWindowKind_Allow windows are the only times at which a sync may occur (if at least one is specified)
+	SyncWindowKind_Allow SyncWindowKind = "allow"
+	// SyncWindowKind_Deny windows are times at which a sync may not occur; they take precedence over allow windows.
+	SyncWindowKind_Deny SyncWindowKind = "deny"
+)
+
+// SyncWindows is a list of sync windows
+type SyncWindows []SyncWindow
+
+// SyncWindow is a recurring period of time, during which sync is either allowed or denied.
+type SyncWindow struct {
+	// Kind is either 'allow' or 'deny'
+	Kind SyncWindowKind `json:"kind"`
+
+	// Schedule is a cron expression, in standard 5-field format (e.g. '0 9 * * 1-5'), for when the window opens
+	Schedule string `json:"schedule"`
+
+	// Duration is how long the window is open for, after it opens, for example '8h' or '30m'
+	Duration string `json:"duration"`
+
+	// TimeZone is the IANA time zone of the schedule, for example 'Europe/Berlin'. Defaults to UTC.
+	TimeZone string `json:"timeZone,omitempty"`
+}
+
+// SyncPolicyAutomated controls the behaviour of an automated sync.
+// Each field defaults to true, if not specified.
+type SyncPolicyAutomated struct {
+	// Prune specifies whether to delete resources from the cluster that are no longer found in the sources, as part of automated sync (default: true)
+	Prune *bool `json:"prune,omitempty"`
+
+	// SelfHeal specifies whether to revert resources back to their desired state upon modification in the cluster (default: true)
+	SelfHeal *bool `json:"selfHeal,omitempty"`
+
+	// AllowEmpty allows the deployment to have zero live resources (default: true)
+	AllowEmpty *bool `json:"allowEmpty,omitempty"`
+}
+
+// IsPrune returns the value of Prune, or its default if it is not specified.
+func (a *SyncPolicyAutomated) IsPrune() bool {
+	return a == nil || a.Prune == nil || *a.Prune
+}
+
+// IsSelfHeal returns the value of SelfHeal, or its default if it is not specified.
+func (a *SyncPolicyAutomated) IsSelfHeal() bool {
+	return a == nil || a.SelfHeal == nil || *a.SelfHeal
+}
+
+// IsAllowEmpty returns the value of AllowEmpty, or its default if it is not specified.
+func (a *SyncPolicyAutomated) IsAllowEmpty() bool {
+	return a == nil || a.AllowEmpty == nil || *a.AllowEmpty
 }
+
 type SyncOptions []SyncOption
 
 const (
@@ -116,7 +359,9 @@ type ResourceStatus struct {
 
 // ReconciledState contains the last version of the GitOpsDeployment resource that the ArgoCD Controller reconciled
 type ReconciledState struct {
-	Source      GitOpsDeploymentSource      `json:"source"`
+	Source GitOpsDeploymentSource `json:"source"`
+	// Sources contains the state of each source, for GitOpsDeployments that use .spec.sources
+	Sources     []GitOpsDeploymentSource    `json:"sources,omitempty"`
 	Destination GitOpsDeploymentDestination `json:"destination"`
 }
 
@@ -126,6 +371,12 @@ type GitOpsDeploymentSource struct {
 	Path    string `json:"path"`
 	RepoURL string `json:"repoURL"`
 	Branch  string `json:"branch"`
+	// Chart contains the Helm chart name, for sources that are Helm charts
+	Chart string `json:"chart,omitempty"`
+	// Ref contains the reference name of the source, for sources within .spec.sources
+	Ref string `json:"ref,omitempty"`
+	// Revision contains the revision that the source was last compared against, for sources within .spec.sources
+	Revision string `json:"revision,omitempty"`
 }
 
 // GitOpsDeploymentDestination contains the information of .status.Sync.CompareTo.Destination field of ArgoCD Application
@@ -149,6 +400,103 @@ type GitOpsDeploymentStatus struct {
 
 	// OperationState contains information about any ongoing operations, such as a sync
 	OperationState *OperationState `json:"operationState,omitempty"`
+
+	// History contains information about the most recent deployments of the GitOpsDeployment, from oldest to newest.
+	// At most MaxDeploymentHistory entries are kept.
+	History []DeploymentHistory `json:"history,omitempty"`
+
+	// ResourceTree contains the resources that are owned by the resources of the GitOpsDeployment (for example, the
+	// ReplicaSets and Pods of a Deployment) with their health, and the most recent warning events of unhealthy resources.
+	// It is only collected if resource tree collection is enabled on the cluster-agent (via ENABLE_RESOURCE_TREE_COLLECTION).
+	// +optional
+	ResourceTree *ResourceTree `json:"resourceTree,omitempty"`
+}
+
+const (
+	// MaxResourceTreeNodes is the maximum number of nodes that are kept in the resource tree of a GitOpsDeployment
+	MaxResourceTreeNodes = 100
+
+	// MaxResourceTreeEvents is the maximum number of events that are kept in the resource tree of a GitOpsDeployment
+	MaxResourceTreeEvents = 20
+
+	// MaxResourceTreeMessageLength is the maximum length of the health message of a node, and of the message of an event
+	MaxResourceTreeMessageLength = 512
+)
+
+// ResourceTree contains the live resources of a GitOpsDeployment, and the recent warning events of unhealthy resources,
+// as reported by Argo CD.
+type ResourceTree struct {
+	// Nodes contains the resources of the GitOpsDeployment, and the resources that are owned by them.
+	// At most MaxResourceTreeNodes nodes are kept.
+	Nodes []ResourceNode `json:"nodes,omitempty"`
+
+	// Events contains the most recent warning events of resources that are not healthy, from newest to oldest.
+	// At most MaxResourceTreeEvents events are kept.
+	Events []ResourceEvent `json:"events,omitempty"`
+
+	// Truncated is true if nodes or events were omitted, to bound the size of the resource tree
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// ResourceRef identifies a resource of a GitOpsDeployment
+type ResourceRef struct {
+	Group     string `json:"group,omitempty"`
+	Version   string `json:"version,omitempty"`
+	Kind      string `json:"kind,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+}
+
+// ResourceNode is a live resource in the resource tree of a GitOpsDeployment
+type ResourceNode struct {
+	ResourceRef `json:",inline"`
+
+	// ParentRefs are the resources that own this resource, if any
+	ParentRefs []ResourceRef `json:"parentRefs,omitempty"`
+
+	// Health is the health of the resource, if Argo CD is able to assess it
+	Health *HealthStatus `json:"health,omitempty"`
+}
+
+// ResourceEvent is a warning event of a resource in the resource tree of a GitOpsDeployment
+type ResourceEvent struct {
+	// InvolvedObject is the resource that the event is about
+	InvolvedObject ResourceRef `json:"involvedObject"`
+
+	// Reason is a short, machine understandable, description of the event (for example, 'BackOff')
+	Reason string `json:"reason,omitempty"`
+
+	// Message is a human-readable description of the event
+	Message string `json:"message,omitempty"`
+
+	// Count is the number of times the event has occurred
+	Count int32 `json:"count,omitempty"`
+
+	// LastTimestamp is the time at which the event most recently occurred
+	LastTimestamp metav1.Time `json:"lastTimestamp,omitempty"`
+}
+
+// MaxDeploymentHistory is the maximum number of entries that are kept in the deployment history of a GitOpsDeployment
+const MaxDeploymentHistory = 10
+
+// DeploymentHistory contains information about a previous deployment of the GitOpsDeployment
+type DeploymentHistory struct {
+	// ID identifies the deployment history entry: it may be used as the .spec.rollbackToHistoryID of a GitOpsDeploymentSyncRun
+	ID string `json:"id,omitempty"`
+	// Revision is the revision (for example, the Git commit SHA) that was deployed
+	Revision string `json:"revision"`
+	// Source is the source that was deployed
+	Source ApplicationSource `json:"source,omitempty"`
+	// Sources are the sources that were deployed, for GitOpsDeployments that use .spec.sources
+	Sources []ApplicationSource `json:"sources,omitempty"`
+	// Revisions are the revisions that were deployed, one for each of the sources, for GitOpsDeployments that use .spec.sources
+	Revisions []string `json:"revisions,omitempty"`
+	// DeployedAt is the time at which the deployment completed
+	DeployedAt metav1.Time `json:"deployedAt"`
+	// InitiatedBy is the name of the user that initiated the deployment, or 'automated' for an automated sync
+	InitiatedBy string `json:"initiatedBy,omitempty"`
+	// SyncRun is the name of the GitOpsDeploymentSyncRun that triggered the deployment, if any
+	SyncRun string `json:"syncRun,omitempty"`
 }
 
 // OperationState contains information about state of a running operation
@@ -435,6 +783,17 @@ type GitOpsDeploymentConditionType string
 const (
 	GitOpsDeploymentConditionSyncError     GitOpsDeploymentConditionType = "SyncError"
 	GitOpsDeploymentConditionErrorOccurred GitOpsDeploymentConditionType = "ErrorOccurred"
+
+	// GitOpsDeploymentConditionSyncWindowClosed is true while the sync windows of the GitOpsDeployment prevent it from being synced.
+	// The message of the condition contains the time at which the next sync window opens.
+	GitOpsDeploymentConditionSyncWindowClosed GitOpsDeploymentConditionType = "SyncWindowClosed"
+
+	// GitOpsDeploymentConditionSuspended is true while reconciliation of the GitOpsDeployment is suspended, via .spec.suspend.
+	GitOpsDeploymentConditionSuspended GitOpsDeploymentConditionType = "Suspended"
+
+	// GitOpsDeploymentConditionWaitingOnDependencies is true while one or more of the GitOpsDeployments in .spec.dependsOn
+	// are not yet Synced and Healthy. The message of the condition lists the dependencies that are not yet ready.
+	GitOpsDeploymentConditionWaitingOnDependencies GitOpsDeploymentConditionType = "WaitingOnDependencies"
 )
 
 // GitOpsConditionStatus is a type which represents possible comparison results
@@ -491,11 +850,20 @@ type GitOpsDeploymentReasonType string
 const (
 	GitopsDeploymentReasonSyncError     GitOpsDeploymentReasonType = "SyncError"
 	GitopsDeploymentReasonErrorOccurred GitOpsDeploymentReasonType = "ErrorOccurred"
+
+	GitopsDeploymentReasonSyncWindowClosed GitOpsDeploymentReasonType = "SyncWindowClosed"
+
+	GitopsDeploymentReasonSuspended GitOpsDeploymentReasonType = "Suspended"
+
+	GitopsDeploymentReasonWaitingOnDependencies GitOpsDeploymentReasonType = "WaitingOnDependencies"
 )
 
 const (
-	GitOpsDeploymentUserError_InvalidPathSlash = "spec.source.path cannot be '/'"
-	GitOpsDeploymentUserError_PathIsRequired   = "spec.source.path is a required field and it cannot be empty"
+	GitOpsDeploymentUserError_InvalidPathSlash             = "spec.source.path cannot be '/'"
+	GitOpsDeploymentUserError_PathIsRequired               = "spec.source.path is a required field and it cannot be empty"
+	GitOpsDeploymentUserError_PathAndChartAreExclusive     = "spec.source.path and spec.source.chart cannot both be specified"
+	GitOpsDeploymentUserError_SourceAndSourcesAreExclusive = "spec.source and spec.sources cannot both be specified"
+	GitOpsDeploymentUserError_SourcesPathIsRequired        = "each entry in spec.sources requires a path, a chart, or a ref"
 )
 
 // +kubebuilder:object:root=true
@@ -17,12 +17,21 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
 
 	logutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/log"
+	"gopkg.in/yaml.v2"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -32,14 +41,55 @@ const (
 	error_nonempty_namespace_empty_environment = "the environment field should not be empty when the namespace is non-empty"
 	error_invalid_sync_option                  = "the specified sync option in .spec.syncPolicy.syncOptions is either mispelled or is not supported by GitOpsDeployment"
 	error_invalid_spec_type                    = "spec type must be manual or automated"
+	error_path_and_chart_exclusive             = GitOpsDeploymentUserError_PathAndChartAreExclusive
+	error_invalid_helm_parameter               = "the name of a Helm parameter in .spec.source.helm.parameters cannot be empty"
+	error_invalid_helm_value_file              = "a Helm value file in .spec.source.helm.valueFiles cannot be empty"
+	error_invalid_helm_values                  = ".spec.source.helm.values must be a valid YAML object"
+	error_helm_values_too_long                 = ".spec.source.helm.values is too long"
+	error_invalid_kustomize_image              = "an image in .spec.source.kustomize.images cannot be empty"
+	error_invalid_kustomize_name_affix         = "the namePrefix and nameSuffix of .spec.source.kustomize may only contain lowercase alphanumeric characters, '-' and '.'"
+	error_invalid_kustomize_common_label       = "invalid label in .spec.source.kustomize.commonLabels"
+	error_invalid_kustomize_common_annotation  = "invalid annotation key in .spec.source.kustomize.commonAnnotations"
+	error_source_and_sources_exclusive         = GitOpsDeploymentUserError_SourceAndSourcesAreExclusive
+	error_sources_missing_repo_url             = "each entry in .spec.sources requires a repoURL"
+	error_sources_missing_path                 = GitOpsDeploymentUserError_SourcesPathIsRequired
+	error_ref_only_valid_in_sources            = ".spec.source.ref is only valid within .spec.sources"
+	error_invalid_source_ref                   = "invalid ref in .spec.sources"
+	error_duplicate_source_ref                 = "duplicate ref in .spec.sources"
+	error_unknown_source_ref                   = "a Helm value file in .spec.sources refers to a ref that does not exist"
+	error_automated_requires_automated_type    = ".spec.syncPolicy.automated may only be specified when spec type is automated"
+	error_multiple_prune_propagation_policies  = "only one PrunePropagationPolicy may be specified in .spec.syncPolicy.syncOptions"
+	error_ignore_differences_missing_kind      = "each entry in .spec.ignoreDifferences requires a kind"
+	error_ignore_differences_missing_fields    = "each entry in .spec.ignoreDifferences requires at least one of jsonPointers or jqPathExpressions"
+	error_invalid_json_pointer                 = "invalid JSON pointer in .spec.ignoreDifferences"
+	error_invalid_jq_path_expression           = "invalid JQ path expression in .spec.ignoreDifferences"
+	error_too_many_sync_windows                = "too many sync windows in .spec.syncPolicy.syncWindows"
+	error_invalid_sync_window                  = "invalid sync window in .spec.syncPolicy.syncWindows"
+	error_invalid_retry_limit                  = ".spec.syncPolicy.retry.limit must be -1 (unlimited) or greater"
+	error_invalid_retry_backoff_duration       = "invalid duration in .spec.syncPolicy.retry.backoff"
+	error_invalid_retry_backoff_factor         = ".spec.syncPolicy.retry.backoff.factor must be 1 or greater"
+	error_invalid_depends_on                   = "invalid GitOpsDeployment name in .spec.dependsOn"
+	error_duplicate_depends_on                 = "duplicate GitOpsDeployment name in .spec.dependsOn"
+	error_depends_on_self                      = "a GitOpsDeployment cannot depend on itself in .spec.dependsOn"
+	error_dependency_cycle                     = "the dependencies in .spec.dependsOn would form a cycle"
 )
 
 // log is for logging in this package.
 var gitopsdeploymentlog = logf.Log.WithName(logutil.LogLogger_managed_gitops)
 
+// gitOpsDeploymentValidator implements the validating webhook of GitOpsDeployment.
+type gitOpsDeploymentValidator struct {
+	// reader is used to read the other GitOpsDeployments of a namespace, in order to detect cycles in .spec.dependsOn.
+	// It reads directly from the API server, rather than from the cache of the manager.
+	reader client.Reader
+}
+
+var _ admission.CustomValidator = &gitOpsDeploymentValidator{}
+
 func (r *GitOpsDeployment) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
+		WithValidator(&gitOpsDeploymentValidator{reader: mgr.GetAPIReader()}).
 		Complete()
 }
 
@@ -56,10 +106,13 @@ func (r *GitOpsDeployment) Default() {
 
 //+kubebuilder:webhook:path=/validate-managed-gitops-redhat-com-v1alpha1-gitopsdeployment,mutating=false,failurePolicy=fail,sideEffects=None,groups=managed-gitops.redhat.com,resources=gitopsdeployments,verbs=create;update,versions=v1alpha1,name=vgitopsdeployment.kb.io,admissionReviewVersions=v1
 
-var _ webhook.Validator = &GitOpsDeployment{}
+// ValidateCreate implements admission.CustomValidator so a webhook will be registered for the type
+func (v *gitOpsDeploymentValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
 
-// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
-func (r *GitOpsDeployment) ValidateCreate() (admission.Warnings, error) {
+	r, ok := obj.(*GitOpsDeployment)
+	if !ok {
+		return nil, fmt.Errorf("expected a GitOpsDeployment, but received %T", obj)
+	}
 
 	log := gitopsdeploymentlog.WithValues(logutil.Log_K8s_Request_Name, r.Name, logutil.Log_K8s_Request_Namespace, r.Namespace, "kind", "GitOpsDeployment")
 
@@ -70,11 +123,21 @@ func (r *GitOpsDeployment) ValidateCreate() (admission.Warnings, error) {
 		return nil, err
 	}
 
+	if err := r.validateDependencyCycles(ctx, v.reader); err != nil {
+		log.Info("webhook rejected invalid create", "error", fmt.Sprintf("%v", err))
+		return nil, err
+	}
+
 	return nil, nil
 }
 
-// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
-func (r *GitOpsDeployment) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+// ValidateUpdate implements admission.CustomValidator so a webhook will be registered for the type
+func (v *gitOpsDeploymentValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+
+	r, ok := newObj.(*GitOpsDeployment)
+	if !ok {
+		return nil, fmt.Errorf("expected a GitOpsDeployment, but received %T", newObj)
+	}
 
 	log := gitopsdeploymentlog.WithValues(logutil.Log_K8s_Request_Name, r.Name, logutil.Log_K8s_Request_Namespace, r.Namespace, "kind", "GitOpsDeployment")
 
@@ -85,35 +148,67 @@ func (r *GitOpsDeployment) ValidateUpdate(old runtime.Object) (admission.Warning
 		return nil, err
 	}
 
+	if err := r.validateDependencyCycles(ctx, v.reader); err != nil {
+		log.Info("webhook rejected invalid update", "error", fmt.Sprintf("%v", err))
+		return nil, err
+	}
+
 	return nil, nil
 }
 
-// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
-func (r *GitOpsDeployment) ValidateDelete() (admission.Warnings, error) {
+// ValidateDelete implements admission.CustomValidator so a webhook will be registered for the type
+func (v *gitOpsDeploymentValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
 
-	log := gitopsdeploymentlog.WithValues(logutil.Log_K8s_Request_Name, r.Name, logutil.Log_K8s_Request_Namespace, r.Namespace, "kind", "GitOpsDeployment")
+	if r, ok := obj.(*GitOpsDeployment); ok {
+		log := gitopsdeploymentlog.WithValues(logutil.Log_K8s_Request_Name, r.Name, logutil.Log_K8s_Request_Namespace, r.Namespace, "kind", "GitOpsDeployment")
 
-	log.V(logutil.LogLevel_Debug).Info("validate delete")
+		log.V(logutil.LogLevel_Debug).Info("validate delete")
+	}
 
 	return nil, nil
 }
 
 func (r *GitOpsDeployment) validateGitOpsDeployment() error {
 
-	// Check whether Type is manual or automated
-	if !(r.Spec.Type == GitOpsDeploymentSpecType_Automated || r.Spec.Type == GitOpsDeploymentSpecType_Manual) {
+	// Check whether Type is manual or automated (case-insensitive, consistent with the backend)
+	if !(strings.EqualFold(r.Spec.Type, GitOpsDeploymentSpecType_Automated) || strings.EqualFold(r.Spec.Type, GitOpsDeploymentSpecType_Manual)) {
 		return errors.New(error_invalid_spec_type)
 	}
 
 	// Check whether sync options are valid
 	if r.Spec.SyncPolicy != nil {
+		prunePropagationPolicies := 0
 		for _, syncOptionString := range r.Spec.SyncPolicy.SyncOptions {
 
-			if !(syncOptionString == SyncOptions_CreateNamespace_true ||
-				syncOptionString == SyncOptions_CreateNamespace_false) {
+			if !IsValidSyncOption(syncOptionString) {
 				return errors.New(error_invalid_sync_option)
 			}
 
+			if strings.HasPrefix(string(syncOptionString), SyncOptions_PrunePropagationPolicy_prefix) {
+				prunePropagationPolicies++
+			}
+		}
+
+		if prunePropagationPolicies > 1 {
+			return errors.New(error_multiple_prune_propagation_policies)
+		}
+
+		if r.Spec.SyncPolicy.Automated != nil && !strings.EqualFold(r.Spec.Type, GitOpsDeploymentSpecType_Automated) {
+			return errors.New(error_automated_requires_automated_type)
+		}
+
+		if err := validateRetryStrategy(r.Spec.SyncPolicy.Retry); err != nil {
+			return err
+		}
+
+		if len(r.Spec.SyncPolicy.SyncWindows) > MaxSyncWindows {
+			return fmt.Errorf("%s: at most %d may be specified", error_too_many_sync_windows, MaxSyncWindows)
+		}
+
+		for _, syncWindow := range r.Spec.SyncPolicy.SyncWindows {
+			if err := syncWindow.Validate(); err != nil {
+				return fmt.Errorf("%s: %v", error_invalid_sync_window, err)
+			}
 		}
 	}
 
@@ -121,5 +216,392 @@ func (r *GitOpsDeployment) validateGitOpsDeployment() error {
 		return errors.New(error_nonempty_namespace_empty_environment)
 	}
 
+	if err := validateIgnoreDifferences(r.Spec.IgnoreDifferences); err != nil {
+		return err
+	}
+
+	if err := validateDependsOn(r.Name, r.Spec.DependsOn); err != nil {
+		return err
+	}
+
+	if len(r.Spec.Sources) > 0 {
+
+		if !reflect.DeepEqual(r.Spec.Source, ApplicationSource{}) {
+			return errors.New(error_source_and_sources_exclusive)
+		}
+
+		if err := validateApplicationSources(r.Spec.Sources); err != nil {
+			return err
+		}
+
+	} else {
+
+		if r.Spec.Source.Ref != "" {
+			return errors.New(error_ref_only_valid_in_sources)
+		}
+
+		if err := validateApplicationSourceHelm(r.Spec.Source); err != nil {
+			return err
+		}
+
+		if err := validateApplicationSourceKustomize(r.Spec.Source); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateRetryStrategy checks the .spec.syncPolicy.retry field: durations are parsed the same way as Argo CD
+// parses them, that is, either as a number of seconds, or as a Go duration (e.g. "2m", "1h")
+func validateRetryStrategy(retry *RetryStrategy) error {
+
+	if retry == nil {
+		return nil
+	}
+
+	if retry.Limit < -1 {
+		return errors.New(error_invalid_retry_limit)
+	}
+
+	if retry.Backoff == nil {
+		return nil
+	}
+
+	for _, duration := range []string{retry.Backoff.Duration, retry.Backoff.MaxDuration} {
+		if duration == "" {
+			continue
+		}
+		if _, err := strconv.Atoi(duration); err == nil {
+			continue
+		}
+		if _, err := time.ParseDuration(duration); err != nil {
+			return fmt.Errorf("%s: '%s'", error_invalid_retry_backoff_duration, duration)
+		}
+	}
+
+	if retry.Backoff.Factor != nil && *retry.Backoff.Factor < 1 {
+		return errors.New(error_invalid_retry_backoff_factor)
+	}
+
 	return nil
 }
+
+// validateIgnoreDifferences checks that each entry of .spec.ignoreDifferences identifies a kind of resource, and
+// that the fields to ignore are specified as valid JSON pointers and/or JQ path expressions
+func validateIgnoreDifferences(ignoreDifferences []ResourceIgnoreDifferences) error {
+
+	for _, ignoreDifference := range ignoreDifferences {
+
+		if strings.TrimSpace(ignoreDifference.Kind) == "" {
+			return errors.New(error_ignore_differences_missing_kind)
+		}
+
+		if len(ignoreDifference.JSONPointers) == 0 && len(ignoreDifference.JQPathExpressions) == 0 {
+			return errors.New(error_ignore_differences_missing_fields)
+		}
+
+		for _, jsonPointer := range ignoreDifference.JSONPointers {
+			if !isValidJSONPointer(jsonPointer) {
+				return fmt.Errorf("%s: '%s'", error_invalid_json_pointer, jsonPointer)
+			}
+		}
+
+		for _, jqPathExpression := range ignoreDifference.JQPathExpressions {
+			if !isValidJQPathExpression(jqPathExpression) {
+				return fmt.Errorf("%s: '%s'", error_invalid_jq_path_expression, jqPathExpression)
+			}
+		}
+	}
+
+	return nil
+}
+
+// isValidJSONPointer returns true if the string is a non-empty JSON pointer, as defined by RFC 6901: each reference
+// token is prefixed by '/', and '~' may only appear as part of the '~0' and '~1' escape sequences.
+func isValidJSONPointer(jsonPointer string) bool {
+
+	if !strings.HasPrefix(jsonPointer, "/") {
+		return false
+	}
+
+	for i := 0; i < len(jsonPointer); i++ {
+		if jsonPointer[i] != '~' {
+			continue
+		}
+		if i+1 >= len(jsonPointer) || (jsonPointer[i+1] != '0' && jsonPointer[i+1] != '1') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isValidJQPathExpression performs a basic syntax check of a JQ path expression: the expression must begin with
+// a path ('.'), and its quotes, brackets and parentheses must be balanced.
+func isValidJQPathExpression(expression string) bool {
+
+	expression = strings.TrimSpace(expression)
+	if !strings.HasPrefix(expression, ".") || strings.ContainsAny(expression, "\r\n") {
+		return false
+	}
+
+	closingToOpening := map[rune]rune{')': '(', ']': '[', '}': '{'}
+
+	var stack []rune
+	inString := false
+	escaped := false
+
+	for _, c := range expression {
+
+		if inString {
+			if escaped {
+				escaped = false
+			} else if c == '\\' {
+				escaped = true
+			} else if c == '"' {
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '(', '[', '{':
+			stack = append(stack, c)
+		case ')', ']', '}':
+			if len(stack) == 0 || stack[len(stack)-1] != closingToOpening[c] {
+				return false
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+
+	return !inString && len(stack) == 0
+}
+
+// validateApplicationSourceHelm checks the Helm-specific fields of an ApplicationSource
+func validateApplicationSourceHelm(source ApplicationSource) error {
+
+	if source.Chart != "" && source.Path != "" {
+		return errors.New(error_path_and_chart_exclusive)
+	}
+
+	if source.Helm == nil {
+		return nil
+	}
+
+	for _, parameter := range source.Helm.Parameters {
+		if strings.TrimSpace(parameter.Name) == "" {
+			return errors.New(error_invalid_helm_parameter)
+		}
+	}
+
+	for _, valueFile := range source.Helm.ValueFiles {
+		if strings.TrimSpace(valueFile) == "" {
+			return errors.New(error_invalid_helm_value_file)
+		}
+	}
+
+	if len(source.Helm.Values) > MaxHelmValuesLength {
+		return fmt.Errorf("%s: at most %d bytes may be specified", error_helm_values_too_long, MaxHelmValuesLength)
+	}
+
+	if source.Helm.Values != "" {
+		values := map[string]any{}
+		if err := yaml.Unmarshal([]byte(source.Helm.Values), &values); err != nil {
+			return errors.New(error_invalid_helm_values)
+		}
+	}
+
+	return nil
+}
+
+// kustomizeNameAffixRegex matches the characters that may be part of a Kubernetes resource name, to which the
+// Kustomize name prefix and suffix are added.
+var kustomizeNameAffixRegex = regexp.MustCompile(`^[a-z0-9.-]*$`)
+
+// validateApplicationSourceKustomize checks the Kustomize-specific fields of an ApplicationSource
+func validateApplicationSourceKustomize(source ApplicationSource) error {
+
+	if source.Kustomize == nil {
+		return nil
+	}
+
+	for _, nameAffix := range []string{source.Kustomize.NamePrefix, source.Kustomize.NameSuffix} {
+		if !kustomizeNameAffixRegex.MatchString(nameAffix) {
+			return fmt.Errorf("%s: '%s'", error_invalid_kustomize_name_affix, nameAffix)
+		}
+	}
+
+	for _, image := range source.Kustomize.Images {
+		if strings.TrimSpace(image) == "" {
+			return errors.New(error_invalid_kustomize_image)
+		}
+	}
+
+	for key, value := range source.Kustomize.CommonLabels {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("%s: key '%s': %s", error_invalid_kustomize_common_label, key, strings.Join(errs, ", "))
+		}
+		if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+			return fmt.Errorf("%s: value of key '%s': %s", error_invalid_kustomize_common_label, key, strings.Join(errs, ", "))
+		}
+	}
+
+	for key := range source.Kustomize.CommonAnnotations {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			return fmt.Errorf("%s: key '%s': %s", error_invalid_kustomize_common_annotation, key, strings.Join(errs, ", "))
+		}
+	}
+
+	return nil
+}
+
+// validateApplicationSources checks each entry of .spec.sources, and that any '$<ref>/' references between the
+// sources point to a source that exists.
+func validateApplicationSources(sources []ApplicationSource) error {
+
+	refs := map[string]bool{}
+
+	for _, source := range sources {
+
+		if strings.TrimSpace(source.RepoURL) == "" {
+			return errors.New(error_sources_missing_repo_url)
+		}
+
+		// A source which only provides files to other sources (via 'ref') does not need a path or a chart
+		if source.Path == "" && source.Chart == "" && source.Ref == "" {
+			return errors.New(error_sources_missing_path)
+		}
+
+		if source.Ref != "" {
+			if errs := validation.IsDNS1123Label(source.Ref); len(errs) > 0 {
+				return fmt.Errorf("%s: '%s': %s", error_invalid_source_ref, source.Ref, strings.Join(errs, ", "))
+			}
+
+			if refs[source.Ref] {
+				return fmt.Errorf("%s: '%s'", error_duplicate_source_ref, source.Ref)
+			}
+			refs[source.Ref] = true
+		}
+
+		if err := validateApplicationSourceHelm(source); err != nil {
+			return err
+		}
+
+		if err := validateApplicationSourceKustomize(source); err != nil {
+			return err
+		}
+	}
+
+	// Helm value files of the form '$<ref>/path/to/values.yaml' must refer to the 'ref' of another source
+	for _, source := range sources {
+		if source.Helm == nil {
+			continue
+		}
+
+		for _, valueFile := range source.Helm.ValueFiles {
+			if !strings.HasPrefix(valueFile, "$") {
+				continue
+			}
+
+			ref := strings.SplitN(strings.TrimPrefix(valueFile, "$"), "/", 2)[0]
+			if !refs[ref] {
+				return fmt.Errorf("%s: '%s'", error_unknown_source_ref, valueFile)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateDependsOn checks that each entry of .spec.dependsOn is a valid GitOpsDeployment name, which is neither
+// repeated nor the name of the GitOpsDeployment itself.
+func validateDependsOn(name string, dependsOn []string) error {
+
+	dependencies := map[string]bool{}
+
+	for _, dependency := range dependsOn {
+
+		if errs := validation.IsDNS1123Subdomain(dependency); len(errs) > 0 {
+			return fmt.Errorf("%s: '%s': %s", error_invalid_depends_on, dependency, strings.Join(errs, ", "))
+		}
+
+		if dependency == name {
+			return errors.New(error_depends_on_self)
+		}
+
+		if dependencies[dependency] {
+			return fmt.Errorf("%s: '%s'", error_duplicate_depends_on, dependency)
+		}
+		dependencies[dependency] = true
+	}
+
+	return nil
+}
+
+// validateDependencyCycles rejects a GitOpsDeployment whose .spec.dependsOn would (directly, or via other
+// GitOpsDeployments in the namespace) depend on the GitOpsDeployment itself.
+func (r *GitOpsDeployment) validateDependencyCycles(ctx context.Context, reader client.Reader) error {
+
+	if len(r.Spec.DependsOn) == 0 {
+		return nil
+	}
+
+	// Fail closed: the dependencies cannot be accepted, if they cannot be verified not to form a cycle
+	if reader == nil {
+		return fmt.Errorf("unable to verify that .spec.dependsOn does not form a cycle: no client is available to the webhook")
+	}
+
+	var gitopsDeplList GitOpsDeploymentList
+	if err := reader.List(ctx, &gitopsDeplList, &client.ListOptions{Namespace: r.Namespace}); err != nil {
+		return fmt.Errorf("unable to list GitOpsDeployments in namespace '%s': %v", r.Namespace, err)
+	}
+
+	dependencyGraph := map[string][]string{}
+	for _, gitopsDepl := range gitopsDeplList.Items {
+		dependencyGraph[gitopsDepl.Name] = gitopsDepl.Spec.DependsOn
+	}
+	// The GitOpsDeployment that is being validated replaces the existing version, if any
+	dependencyGraph[r.Name] = r.Spec.DependsOn
+
+	if cycle := findDependencyCycle(r.Name, dependencyGraph); len(cycle) > 0 {
+		return fmt.Errorf("%s: %s", error_dependency_cycle, strings.Join(cycle, " -> "))
+	}
+
+	return nil
+}
+
+// findDependencyCycle returns the names of the GitOpsDeployments that form a cycle back to 'name' (beginning and
+// ending with 'name'), based on the given map of GitOpsDeployment name to the names of its dependencies. Returns
+// nil if 'name' is not part of a cycle.
+func findDependencyCycle(name string, dependencyGraph map[string][]string) []string {
+
+	visited := map[string]bool{}
+
+	var visit func(current string, path []string) []string
+	visit = func(current string, path []string) []string {
+
+		for _, dependency := range dependencyGraph[current] {
+
+			if dependency == name {
+				return append(path, dependency)
+			}
+
+			if visited[dependency] {
+				continue
+			}
+			visited[dependency] = true
+
+			if cycle := visit(dependency, append(path, dependency)); cycle != nil {
+				return cycle
+			}
+		}
+
+		return nil
+	}
+
+	return visit(name, []string{name})
+}
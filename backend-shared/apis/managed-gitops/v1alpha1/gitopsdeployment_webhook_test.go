@@ -2,13 +2,16 @@ package v1alpha1
 
 import (
 	"context"
+	"strings"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -165,4 +168,594 @@ var _ = Describe("GitOpsDeployment validation webhook", func() {
 			Expect(err).ToNot(HaveOccurred())
 		})
 	})
+
+	Context("Validate GitOpsDeployment CR with a Helm chart source", func() {
+
+		It("Should accept a valid Helm chart source", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.Source.Chart = "my-chart"
+			gitopsDepl.Spec.Source.Helm = &ApplicationSourceHelm{
+				ReleaseName: "my-release",
+				Values:      "replicaCount: 2\n",
+				ValueFiles:  []string{"values-prod.yaml"},
+				Parameters:  []HelmParameter{{Name: "image.tag", Value: "v1"}},
+			}
+
+			Expect(gitopsDepl.validateGitOpsDeployment()).To(Succeed())
+		})
+
+		It("Should fail when both path and chart are specified", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.Source.Path = "environments/prod"
+			gitopsDepl.Spec.Source.Chart = "my-chart"
+
+			err := gitopsDepl.validateGitOpsDeployment()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_path_and_chart_exclusive))
+		})
+
+		It("Should fail when a Helm parameter has an empty name", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.Source.Chart = "my-chart"
+			gitopsDepl.Spec.Source.Helm = &ApplicationSourceHelm{
+				Parameters: []HelmParameter{{Name: " ", Value: "v1"}},
+			}
+
+			err := gitopsDepl.validateGitOpsDeployment()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_invalid_helm_parameter))
+		})
+
+		It("Should fail when a Helm value file is empty", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.Source.Chart = "my-chart"
+			gitopsDepl.Spec.Source.Helm = &ApplicationSourceHelm{
+				ValueFiles: []string{""},
+			}
+
+			err := gitopsDepl.validateGitOpsDeployment()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_invalid_helm_value_file))
+		})
+
+		It("Should fail when the inline Helm values are not a YAML object", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.Source.Chart = "my-chart"
+			gitopsDepl.Spec.Source.Helm = &ApplicationSourceHelm{
+				Values: "- not\n- an\n- object\n",
+			}
+
+			err := gitopsDepl.validateGitOpsDeployment()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_invalid_helm_values))
+		})
+
+		It("Should fail when the inline Helm values are too long", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.Source.Chart = "my-chart"
+			gitopsDepl.Spec.Source.Helm = &ApplicationSourceHelm{
+				Values: "key: " + strings.Repeat("a", MaxHelmValuesLength),
+			}
+
+			err := gitopsDepl.validateGitOpsDeployment()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_helm_values_too_long))
+		})
+
+		It("Should fail when the inline Helm values of a source in .spec.sources are not valid YAML", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.Sources = []ApplicationSource{{
+				RepoURL: "https://charts.example.com",
+				Chart:   "my-chart",
+				Helm: &ApplicationSourceHelm{
+					Values: "key: [unterminated",
+				},
+			}}
+
+			err := gitopsDepl.validateGitOpsDeployment()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_invalid_helm_values))
+		})
+	})
+
+	Context("Validate GitOpsDeployment CR with Kustomize overrides", func() {
+
+		It("Should accept valid Kustomize overrides", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.Source.Kustomize = &ApplicationSourceKustomize{
+				NamePrefix:        "prod-",
+				Images:            []string{"quay.io/org/image:v2"},
+				CommonLabels:      map[string]string{"app.kubernetes.io/part-of": "my-app"},
+				CommonAnnotations: map[string]string{"owner": "team a"},
+			}
+
+			Expect(gitopsDepl.validateGitOpsDeployment()).To(Succeed())
+		})
+
+		It("Should fail when the name suffix contains characters that are not valid in a resource name", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.Source.Kustomize = &ApplicationSourceKustomize{
+				NameSuffix: "-v2;",
+			}
+
+			err := gitopsDepl.validateGitOpsDeployment()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_invalid_kustomize_name_affix))
+		})
+
+		It("Should accept common annotation values containing quotes and special characters", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.Source.Kustomize = &ApplicationSourceKustomize{
+				CommonAnnotations: map[string]string{"description": "Team A's \"prod\" app; 100% `managed` & monitored\n"},
+			}
+
+			Expect(gitopsDepl.validateGitOpsDeployment()).To(Succeed())
+		})
+
+		It("Should fail when an image override is empty", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.Source.Kustomize = &ApplicationSourceKustomize{
+				Images: []string{" "},
+			}
+
+			err := gitopsDepl.validateGitOpsDeployment()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_invalid_kustomize_image))
+		})
+
+		It("Should fail when a common label value is not a valid label value", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.Source.Kustomize = &ApplicationSourceKustomize{
+				CommonLabels: map[string]string{"env": "not a valid value"},
+			}
+
+			err := gitopsDepl.validateGitOpsDeployment()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_invalid_kustomize_common_label))
+		})
+
+		It("Should fail when a common annotation key is invalid", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.Source.Kustomize = &ApplicationSourceKustomize{
+				CommonAnnotations: map[string]string{"not a valid key": "value"},
+			}
+
+			err := gitopsDepl.validateGitOpsDeployment()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_invalid_kustomize_common_annotation))
+		})
+	})
+
+	Context("Validate GitOpsDeployment CR with multiple sources", func() {
+
+		It("Should accept multiple sources, where one source refers to the value files of another", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.Sources = []ApplicationSource{
+				{
+					RepoURL: "https://charts.example.com",
+					Chart:   "my-chart",
+					Helm: &ApplicationSourceHelm{
+						ValueFiles: []string{"$values/environments/prod/values.yaml"},
+					},
+				},
+				{RepoURL: "https://github.com/test/values", Ref: "values"},
+			}
+
+			Expect(gitopsDepl.validateGitOpsDeployment()).To(Succeed())
+		})
+
+		It("Should fail when both source and sources are specified", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.Source = ApplicationSource{RepoURL: "https://github.com/test/test", Path: "environments/prod"}
+			gitopsDepl.Spec.Sources = []ApplicationSource{
+				{RepoURL: "https://github.com/test/test", Path: "environments/prod"},
+			}
+
+			err := gitopsDepl.validateGitOpsDeployment()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_source_and_sources_exclusive))
+		})
+
+		It("Should fail when ref is specified on a single source", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.Source = ApplicationSource{RepoURL: "https://github.com/test/test", Path: "environments/prod", Ref: "values"}
+
+			err := gitopsDepl.validateGitOpsDeployment()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_ref_only_valid_in_sources))
+		})
+
+		It("Should fail when two sources have the same ref", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.Sources = []ApplicationSource{
+				{RepoURL: "https://github.com/test/a", Ref: "values"},
+				{RepoURL: "https://github.com/test/b", Ref: "values"},
+			}
+
+			err := gitopsDepl.validateGitOpsDeployment()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_duplicate_source_ref))
+		})
+
+		It("Should fail when a Helm value file refers to a ref that does not exist", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.Sources = []ApplicationSource{
+				{
+					RepoURL: "https://charts.example.com",
+					Chart:   "my-chart",
+					Helm: &ApplicationSourceHelm{
+						ValueFiles: []string{"$other/values.yaml"},
+					},
+				},
+				{RepoURL: "https://github.com/test/values", Ref: "values"},
+			}
+
+			err := gitopsDepl.validateGitOpsDeployment()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_unknown_source_ref))
+		})
+
+		It("Should fail when a source has no repoURL", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.Sources = []ApplicationSource{
+				{Path: "environments/prod"},
+			}
+
+			err := gitopsDepl.validateGitOpsDeployment()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_sources_missing_repo_url))
+		})
+	})
+
+	Context("Validate GitOpsDeployment CR with an automated sync policy", func() {
+
+		It("Should accept an automated sync policy when spec type is automated", func() {
+			prune := false
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.SyncPolicy = &SyncPolicy{
+				Automated: &SyncPolicyAutomated{Prune: &prune},
+			}
+
+			Expect(gitopsDepl.validateGitOpsDeployment()).To(Succeed())
+		})
+
+		It("Should compare the spec type case-insensitively, consistent with the backend", func() {
+			gitopsDepl.Spec.Type = "Automated"
+			gitopsDepl.Spec.SyncPolicy = &SyncPolicy{
+				Automated: &SyncPolicyAutomated{},
+			}
+
+			Expect(gitopsDepl.validateGitOpsDeployment()).To(Succeed())
+		})
+
+		It("Should fail when an automated sync policy is specified and spec type is manual", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Manual
+			gitopsDepl.Spec.SyncPolicy = &SyncPolicy{
+				Automated: &SyncPolicyAutomated{},
+			}
+
+			err := gitopsDepl.validateGitOpsDeployment()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_automated_requires_automated_type))
+		})
+	})
+
+	Context("Validate GitOpsDeployment CR with a sync retry strategy", func() {
+
+		It("Should accept a valid retry strategy", func() {
+			factor := int64(3)
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Manual
+			gitopsDepl.Spec.SyncPolicy = &SyncPolicy{
+				Retry: &RetryStrategy{
+					Limit: 5,
+					Backoff: &Backoff{
+						Duration:    "10",
+						Factor:      &factor,
+						MaxDuration: "5m",
+					},
+				},
+			}
+
+			Expect(gitopsDepl.validateGitOpsDeployment()).To(Succeed())
+		})
+
+		It("Should fail when the retry limit is less than -1", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.SyncPolicy = &SyncPolicy{
+				Retry: &RetryStrategy{Limit: -2},
+			}
+
+			err := gitopsDepl.validateGitOpsDeployment()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_invalid_retry_limit))
+		})
+
+		It("Should fail when a backoff duration is invalid", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.SyncPolicy = &SyncPolicy{
+				Retry: &RetryStrategy{
+					Limit:   3,
+					Backoff: &Backoff{MaxDuration: "three minutes"},
+				},
+			}
+
+			err := gitopsDepl.validateGitOpsDeployment()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_invalid_retry_backoff_duration))
+		})
+
+		It("Should fail when the backoff factor is less than 1", func() {
+			factor := int64(0)
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.SyncPolicy = &SyncPolicy{
+				Retry: &RetryStrategy{
+					Limit:   3,
+					Backoff: &Backoff{Factor: &factor},
+				},
+			}
+
+			err := gitopsDepl.validateGitOpsDeployment()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_invalid_retry_backoff_factor))
+		})
+	})
+
+	Context("Validate GitOpsDeployment CR with Argo CD sync options", func() {
+
+		It("Should accept each of the supported sync options", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.SyncPolicy = &SyncPolicy{
+				SyncOptions: SyncOptions{
+					SyncOptions_CreateNamespace_true,
+					SyncOptions_ServerSideApply_true,
+					SyncOptions_PruneLast_true,
+					SyncOptions_ApplyOutOfSyncOnly_true,
+					SyncOptions_Replace_true,
+					SyncOptions_Validate_false,
+					SyncOptions_RespectIgnoreDifferences_true,
+					SyncOptions_PrunePropagationPolicy_foreground,
+				},
+			}
+
+			Expect(gitopsDepl.validateGitOpsDeployment()).To(Succeed())
+		})
+
+		It("Should fail when a sync option has an unsupported value", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.SyncPolicy = &SyncPolicy{
+				SyncOptions: SyncOptions{"Validate=true"},
+			}
+
+			err := gitopsDepl.validateGitOpsDeployment()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_invalid_sync_option))
+		})
+
+		It("Should fail when more than one PrunePropagationPolicy is specified", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.SyncPolicy = &SyncPolicy{
+				SyncOptions: SyncOptions{
+					SyncOptions_PrunePropagationPolicy_foreground,
+					SyncOptions_PrunePropagationPolicy_orphan,
+				},
+			}
+
+			err := gitopsDepl.validateGitOpsDeployment()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_multiple_prune_propagation_policies))
+		})
+	})
+
+	Context("Validate GitOpsDeployment CR with ignoreDifferences", func() {
+
+		It("Should accept valid JSON pointers and JQ path expressions", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.IgnoreDifferences = []ResourceIgnoreDifferences{
+				{
+					Group:        "apps",
+					Kind:         "Deployment",
+					JSONPointers: []string{"/spec/replicas", "/metadata/annotations/example.com~1owner"},
+				},
+				{
+					Group:             "admissionregistration.k8s.io",
+					Kind:              "MutatingWebhookConfiguration",
+					Name:              "my-webhook",
+					JQPathExpressions: []string{`.webhooks[]?.clientConfig.caBundle`, `.spec.template.spec.containers[] | select(.name == "istio-proxy")`},
+				},
+			}
+
+			Expect(gitopsDepl.validateGitOpsDeployment()).To(Succeed())
+		})
+
+		It("Should fail when an entry has no kind", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.IgnoreDifferences = []ResourceIgnoreDifferences{
+				{Group: "apps", JSONPointers: []string{"/spec/replicas"}},
+			}
+
+			err := gitopsDepl.validateGitOpsDeployment()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_ignore_differences_missing_kind))
+		})
+
+		It("Should fail when an entry has no fields to ignore", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.IgnoreDifferences = []ResourceIgnoreDifferences{
+				{Group: "apps", Kind: "Deployment"},
+			}
+
+			err := gitopsDepl.validateGitOpsDeployment()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_ignore_differences_missing_fields))
+		})
+
+		It("Should fail when a JSON pointer is invalid", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+
+			for _, jsonPointer := range []string{"spec/replicas", "/metadata/annotations/a~2b", "/spec~"} {
+				gitopsDepl.Spec.IgnoreDifferences = []ResourceIgnoreDifferences{
+					{Kind: "Deployment", JSONPointers: []string{jsonPointer}},
+				}
+
+				err := gitopsDepl.validateGitOpsDeployment()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(error_invalid_json_pointer))
+			}
+		})
+
+		It("Should fail when a JQ path expression is invalid", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+
+			for _, expression := range []string{"spec.replicas", ".spec.containers[", `.spec.containers[] | select(.name == "istio)`} {
+				gitopsDepl.Spec.IgnoreDifferences = []ResourceIgnoreDifferences{
+					{Kind: "Deployment", JQPathExpressions: []string{expression}},
+				}
+
+				err := gitopsDepl.validateGitOpsDeployment()
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(error_invalid_jq_path_expression))
+			}
+		})
+	})
+
+	Context("Validate GitOpsDeployment CR with sync windows", func() {
+
+		It("Should accept valid sync windows", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.SyncPolicy = &SyncPolicy{
+				SyncWindows: SyncWindows{
+					{Kind: SyncWindowKind_Allow, Schedule: "0 9 * * 1-5", Duration: "8h", TimeZone: "Europe/Berlin"},
+					{Kind: SyncWindowKind_Deny, Schedule: "0 0 20 12 *", Duration: "336h"},
+				},
+			}
+
+			Expect(gitopsDepl.validateGitOpsDeployment()).To(Succeed())
+		})
+
+		It("Should fail when a sync window is invalid", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Manual
+			gitopsDepl.Spec.SyncPolicy = &SyncPolicy{
+				SyncWindows: SyncWindows{
+					{Kind: SyncWindowKind_Allow, Schedule: "every weekday", Duration: "8h"},
+				},
+			}
+
+			err := gitopsDepl.validateGitOpsDeployment()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_invalid_sync_window))
+		})
+
+		It("Should fail when too many sync windows are specified", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.SyncPolicy = &SyncPolicy{}
+			for i := 0; i <= MaxSyncWindows; i++ {
+				gitopsDepl.Spec.SyncPolicy.SyncWindows = append(gitopsDepl.Spec.SyncPolicy.SyncWindows,
+					SyncWindow{Kind: SyncWindowKind_Deny, Schedule: "0 0 * * *", Duration: "1h"})
+			}
+
+			err := gitopsDepl.validateGitOpsDeployment()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_too_many_sync_windows))
+		})
+	})
+
+	Context("Validate GitOpsDeployment CR with dependsOn", func() {
+
+		It("Should accept valid dependencies", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.DependsOn = []string{"database", "cache"}
+
+			Expect(gitopsDepl.validateGitOpsDeployment()).To(Succeed())
+		})
+
+		It("Should fail when a dependency is not a valid name", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.DependsOn = []string{"Not_A_Valid_Name"}
+
+			err := gitopsDepl.validateGitOpsDeployment()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_invalid_depends_on))
+		})
+
+		It("Should fail when the GitOpsDeployment depends on itself", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.DependsOn = []string{gitopsDepl.Name}
+
+			err := gitopsDepl.validateGitOpsDeployment()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_depends_on_self))
+		})
+
+		It("Should fail when a dependency is repeated", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.DependsOn = []string{"database", "database"}
+
+			err := gitopsDepl.validateGitOpsDeployment()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_duplicate_depends_on))
+		})
+
+		It("Should find a cycle between GitOpsDeployments, and ignore dependencies that are not part of a cycle", func() {
+			dependencyGraph := map[string][]string{
+				"api":      {"database", "cache"},
+				"cache":    {},
+				"database": {"storage"},
+				"storage":  {"api"},
+				"frontend": {"api"},
+			}
+
+			Expect(findDependencyCycle("api", dependencyGraph)).To(Equal([]string{"api", "database", "storage", "api"}))
+			Expect(findDependencyCycle("frontend", dependencyGraph)).To(BeNil())
+
+			dependencyGraph["storage"] = []string{}
+			Expect(findDependencyCycle("api", dependencyGraph)).To(BeNil())
+		})
+
+		It("Should reject a GitOpsDeployment whose dependencies would form a cycle with the existing GitOpsDeployments of the namespace", func() {
+
+			scheme := runtime.NewScheme()
+			Expect(AddToScheme(scheme)).To(Succeed())
+
+			database := &GitOpsDeployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "database", Namespace: namespace.Name},
+				Spec:       GitOpsDeploymentSpec{DependsOn: []string{gitopsDepl.Name}},
+			}
+			cache := &GitOpsDeployment{
+				ObjectMeta: metav1.ObjectMeta{Name: "cache", Namespace: namespace.Name},
+			}
+
+			reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(database, cache).Build()
+
+			By("depending on a GitOpsDeployment that is not part of a cycle")
+			gitopsDepl.Spec.DependsOn = []string{"cache"}
+			Expect(gitopsDepl.validateDependencyCycles(ctx, reader)).To(Succeed())
+
+			By("depending on a GitOpsDeployment that depends on this GitOpsDeployment")
+			gitopsDepl.Spec.DependsOn = []string{"cache", "database"}
+			err := gitopsDepl.validateDependencyCycles(ctx, reader)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_dependency_cycle))
+			Expect(err.Error()).To(ContainSubstring("my-gitops-depl -> database -> my-gitops-depl"))
+
+			By("validating via the webhook validator, which uses its injected reader")
+			validator := &gitOpsDeploymentValidator{reader: reader}
+			_, err = validator.ValidateCreate(ctx, gitopsDepl)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_dependency_cycle))
+		})
+
+		It("Should reject dependencies, if the webhook has no reader to verify they do not form a cycle", func() {
+			gitopsDepl.Spec.Type = GitOpsDeploymentSpecType_Automated
+			gitopsDepl.Spec.DependsOn = []string{"database"}
+
+			validator := &gitOpsDeploymentValidator{}
+			_, err := validator.ValidateCreate(ctx, gitopsDepl)
+			Expect(err).To(HaveOccurred())
+
+			By("accepting a GitOpsDeployment without dependencies")
+			gitopsDepl.Spec.DependsOn = nil
+			_, err = validator.ValidateCreate(ctx, gitopsDepl)
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
 })
@@ -0,0 +1,140 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// MaxDiffResources is the maximum number of resources that are reported in the status of a GitOpsDeploymentDiff:
+	// the remaining resources are omitted, and .status.truncated is set.
+	MaxDiffResources = 50
+
+	// MaxResourceDiffLength is the maximum length of the diff of a single resource, in the status of a
+	// GitOpsDeploymentDiff: longer diffs are cut, and .truncated of the resource is set.
+	MaxResourceDiffLength = 4096
+)
+
+// GitOpsDeploymentDiffSpec defines the desired state of GitOpsDeploymentDiff
+type GitOpsDeploymentDiffSpec struct {
+	// Reference to the target GitOpsDeployment whose manifests should be compared against the live state
+	GitopsDeploymentName string `json:"gitopsDeploymentName"`
+
+	// Revision is the candidate revision (for example, a Git commit SHA, branch or tag) of the GitOps repository
+	// of the GitOpsDeployment, whose manifests are rendered and compared against the live state.
+	Revision string `json:"revision"`
+}
+
+// GitOpsDeploymentDiffStatus defines the observed state of GitOpsDeploymentDiff
+type GitOpsDeploymentDiffStatus struct {
+	// Phase is the current phase of the diff: Pending, Running, Succeeded or Failed
+	Phase DiffPhase `json:"phase,omitempty"`
+
+	// Message contains human-readable details about the phase, for example the reason the diff failed
+	Message string `json:"message,omitempty"`
+
+	// StartedAt is the time the diff was started
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// FinishedAt is the time the diff finished, either successfully or unsuccessfully
+	FinishedAt *metav1.Time `json:"finishedAt,omitempty"`
+
+	// Revision is the revision (for example, the Git commit SHA) that the manifests were rendered from
+	Revision string `json:"revision,omitempty"`
+
+	// Resources contains the diff of each resource that would be changed by deploying the revision: resources
+	// that are unchanged are not listed.
+	Resources []ResourceDiff `json:"resources,omitempty"`
+
+	// Truncated is true if some of the changed resources were omitted from .status.resources, because there were
+	// more than can be reported.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// IsFinished returns true if the diff of the GitOpsDeploymentDiff has finished, either successfully or unsuccessfully.
+func (status GitOpsDeploymentDiffStatus) IsFinished() bool {
+	return status.Phase == DiffPhase_Succeeded || status.Phase == DiffPhase_Failed
+}
+
+// DiffPhase is the phase of a GitOpsDeploymentDiff.
+// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed
+type DiffPhase string
+
+const (
+	DiffPhase_Pending   DiffPhase = "Pending"
+	DiffPhase_Running   DiffPhase = "Running"
+	DiffPhase_Succeeded DiffPhase = "Succeeded"
+	DiffPhase_Failed    DiffPhase = "Failed"
+)
+
+// ResourceDiffStatus describes how a resource would be changed by deploying a revision.
+// +kubebuilder:validation:Enum=Added;Modified;Removed
+type ResourceDiffStatus string
+
+const (
+	// ResourceDiffStatus_Added: the resource is defined at the revision, but does not exist on the cluster
+	ResourceDiffStatus_Added ResourceDiffStatus = "Added"
+
+	// ResourceDiffStatus_Modified: the resource exists on the cluster, but differs from its definition at the revision
+	ResourceDiffStatus_Modified ResourceDiffStatus = "Modified"
+
+	// ResourceDiffStatus_Removed: the resource exists on the cluster, but is no longer defined at the revision
+	ResourceDiffStatus_Removed ResourceDiffStatus = "Removed"
+)
+
+// ResourceDiff is the diff of a single resource, between the live state and its definition at a revision
+type ResourceDiff struct {
+	Group     string `json:"group,omitempty"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+
+	// Status describes how the resource would be changed: Added, Modified or Removed
+	Status ResourceDiffStatus `json:"status"`
+
+	// Diff is a unified diff of the resource, as YAML, from the live state to its definition at the revision
+	Diff string `json:"diff,omitempty"`
+
+	// Truncated is true if the diff was cut, because it was longer than can be reported
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// GitOpsDeploymentDiff is the Schema for the gitopsdeploymentdiffs API
+type GitOpsDeploymentDiff struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GitOpsDeploymentDiffSpec   `json:"spec,omitempty"`
+	Status GitOpsDeploymentDiffStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GitOpsDeploymentDiffList contains a list of GitOpsDeploymentDiff
+type GitOpsDeploymentDiffList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GitOpsDeploymentDiff `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GitOpsDeploymentDiff{}, &GitOpsDeploymentDiffList{})
+}
@@ -22,6 +22,10 @@ import (
 
 const (
 	ManagedEnvironmentStatusConnectionInitializationSucceeded = "ConnectionInitializationSucceeded"
+
+	// ManagedEnvironmentStatusReachable indicates whether the cluster of the managed environment was reachable, using the
+	// credentials of the managed environment, when it was last (periodically) probed by the GitOps Service.
+	ManagedEnvironmentStatusReachable = "Reachable"
 )
 
 // The GitOpsDeploymentManagedEnvironment CR describes a remote cluster which the GitOps Service will deploy to, via Argo CD.
@@ -33,7 +37,9 @@ type GitOpsDeploymentManagedEnvironmentSpec struct {
 	// APIURL is the URL of the cluster to connect to
 	APIURL string `json:"apiURL"`
 
-	// ClusterCredentialsSecret is a reference to a Secret that contains cluster connection details. The cluster details should be in the form of a kubeconfig file.
+	// ClusterCredentialsSecret is a reference to a Secret that contains cluster connection details. The cluster details should be in the form of a kubeconfig file
+	// (Secret type 'managed-gitops.redhat.com/managed-environment'), or of a 'server' URL, service account 'token', and optional 'ca.crt' (Secret type
+	// 'managed-gitops.redhat.com/managed-environment-token'). Kubeconfig users may authenticate with a token, or with client certificate/key data.
 	ClusterCredentialsSecret string `json:"credentialsSecret"`
 
 	// AllowInsecureSkipTLSVerify controls whether Argo CD will accept a Kubernetes API URL with untrusted-TLS certificate.
@@ -41,6 +47,20 @@ type GitOpsDeploymentManagedEnvironmentSpec struct {
 	// Defaults to false.
 	AllowInsecureSkipTLSVerify bool `json:"allowInsecureSkipTLSVerify"`
 
+	// CABundleConfigMap is the name of a ConfigMap, in the same namespace, that contains the PEM-encoded CA bundle that
+	// is used to verify the TLS certificate of the cluster, under the 'ca.crt' key.
+	// Optional: If not specified, the CA bundle may instead be provided under the 'ca.crt' key of the ClusterCredentialsSecret.
+	// This is used for clusters with a TLS certificate that is signed by a private CA. It is ignored if AllowInsecureSkipTLSVerify is true.
+	// Changes to the ConfigMap are only applied automatically if it has the 'managed-gitops.redhat.com/ca-bundle: "true"' label.
+	CABundleConfigMap string `json:"caBundleConfigMap,omitempty"`
+
+	// ProxyURL is the URL of the HTTP(S) proxy that is used to connect to the cluster, for clusters that are only reachable via an
+	// egress proxy (for example, 'http://proxy.example.com:3128').
+	// Optional: If not specified, the cluster is connected to directly.
+	// This is used by both the GitOps Service and Argo CD to connect to the cluster.
+	// +kubebuilder:validation:Pattern=`^(https?|socks5)://.+$`
+	ProxyURL string `json:"proxyURL,omitempty"`
+
 	// CreateNewServiceAccount controls whether Argo CD will use the ServiceAccount provided by the user in the Secret, or if a new ServiceAccount
 	// should be created.
 	//
@@ -74,6 +94,16 @@ type GitOpsDeploymentManagedEnvironmentSpec struct {
 	ClusterResources bool `json:"clusterResources,omitempty"`
 }
 
+const (
+	// ManagedEnvironmentCABundleKey is the key of the CA bundle, within the ConfigMap referenced by .spec.caBundleConfigMap,
+	// or within the ClusterCredentialsSecret.
+	ManagedEnvironmentCABundleKey = "ca.crt"
+
+	// ManagedEnvironmentCABundleLabel should be set to 'true' on the ConfigMaps referenced by .spec.caBundleConfigMap: only
+	// the ConfigMaps with this label are watched, so that changes to the CA bundle are applied to the managed environment.
+	ManagedEnvironmentCABundleLabel = "managed-gitops.redhat.com/ca-bundle"
+)
+
 type AllowInsecureSkipTLSVerify bool
 
 // Insecure TLS Status types
@@ -87,6 +117,11 @@ const (
 // GitOpsDeploymentManagedEnvironmentStatus defines the observed state of GitOpsDeploymentManagedEnvironment
 type GitOpsDeploymentManagedEnvironmentStatus struct {
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// LastProbeTime is the time of the probe of the cluster of the managed environment that last changed the 'Reachable'
+	// condition. The status is only updated when the result of a probe changes: the time of the most recent probe is
+	// exported via the 'managed_environment_last_probe_timestamp_seconds' metric.
+	LastProbeTime *metav1.Time `json:"lastProbeTime,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -118,6 +153,13 @@ const (
 	ConditionReasonUnableToParseKubeconfigData        ManagedEnvironmentConditionReason = "UnableToParseKubeconfigData"
 	ConditionReasonInvalidNamespaceList               ManagedEnvironmentConditionReason = "InvalidNamespaceList"
 	ConditionReasonUnableToRetrieveRestConfig         ManagedEnvironmentConditionReason = "UnableToRetrieveRestConfig"
+	ConditionReasonInvalidCABundle                    ManagedEnvironmentConditionReason = "InvalidCABundle"
+	ConditionReasonInvalidProxyURL                    ManagedEnvironmentConditionReason = "InvalidProxyURL"
+	ConditionReasonUnsupportedAuthType                ManagedEnvironmentConditionReason = "UnsupportedAuthType"
+	ConditionReasonClusterUnreachable                 ManagedEnvironmentConditionReason = "ClusterUnreachable"
+	ConditionReasonInvalidCredentials                 ManagedEnvironmentConditionReason = "InvalidCredentials"
+	ConditionReasonInsufficientPermissions            ManagedEnvironmentConditionReason = "InsufficientPermissions"
+	ConditionReasonNamespaceNotFound                  ManagedEnvironmentConditionReason = "NamespaceNotFound"
 	ConditionReasonUnknownError                       ManagedEnvironmentConditionReason = "UnknownError"
 )
 
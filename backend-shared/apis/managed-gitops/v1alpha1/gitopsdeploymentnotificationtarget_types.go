@@ -0,0 +1,132 @@
+/*
+Copyright 2021.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GitOpsDeploymentNotificationTargetSpec defines the desired state of GitOpsDeploymentNotificationTarget
+type GitOpsDeploymentNotificationTargetSpec struct {
+
+	// URL of the webhook that notifications are POSTed to, as JSON (for example, a Slack incoming webhook)
+	// The webhook must be reachable at a public address: loopback, link-local and private addresses are rejected,
+	// and redirects are not followed.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+
+	// HMACSecretRef is an optional reference to a key of a K8s Secret, in the namespace of the
+	// GitOpsDeploymentNotificationTarget. If set, the value of the key is used to sign the payload of each
+	// notification with HMAC-SHA256: the signature is sent in the 'X-GitOps-Signature' header.
+	HMACSecretRef *SecretKeyReference `json:"hmacSecretRef,omitempty"`
+
+	// Events is the list of events that should be sent to the webhook. If empty, all events are sent.
+	Events []NotificationEventType `json:"events,omitempty"`
+
+	// GitOpsDeploymentNames is the list of names of GitOpsDeployments, in the namespace of the
+	// GitOpsDeploymentNotificationTarget, whose events should be sent to the webhook. If empty, the events of all
+	// GitOpsDeployments in the namespace are sent.
+	GitOpsDeploymentNames []string `json:"gitopsDeploymentNames,omitempty"`
+}
+
+// SecretKeyReference references a key of a K8s Secret in the same namespace
+type SecretKeyReference struct {
+	// Name of the Secret
+	Name string `json:"name"`
+
+	// Key of the Secret whose value should be used
+	Key string `json:"key"`
+}
+
+// NotificationEventType is a change to the state of a GitOpsDeployment that a notification can be sent for.
+// +kubebuilder:validation:Enum=HealthDegraded;HealthRecovered;SyncFailed;SyncSucceeded
+type NotificationEventType string
+
+const (
+	// NotificationEvent_HealthDegraded: the health of the GitOpsDeployment has become Degraded
+	NotificationEvent_HealthDegraded NotificationEventType = "HealthDegraded"
+
+	// NotificationEvent_HealthRecovered: the health of the GitOpsDeployment has become Healthy, after being Degraded
+	NotificationEvent_HealthRecovered NotificationEventType = "HealthRecovered"
+
+	// NotificationEvent_SyncFailed: a sync operation of the GitOpsDeployment has failed
+	NotificationEvent_SyncFailed NotificationEventType = "SyncFailed"
+
+	// NotificationEvent_SyncSucceeded: a sync operation of the GitOpsDeployment has succeeded
+	NotificationEvent_SyncSucceeded NotificationEventType = "SyncSucceeded"
+)
+
+// Matches returns true if notifications for the given event of the given GitOpsDeployment should be sent to the target.
+func (spec GitOpsDeploymentNotificationTargetSpec) Matches(gitopsDeploymentName string, event NotificationEventType) bool {
+
+	if len(spec.Events) > 0 {
+		found := false
+		for _, specEvent := range spec.Events {
+			if specEvent == event {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if len(spec.GitOpsDeploymentNames) > 0 {
+		found := false
+		for _, name := range spec.GitOpsDeploymentNames {
+			if name == gitopsDeploymentName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GitOpsDeploymentNotificationTargetStatus defines the observed state of GitOpsDeploymentNotificationTarget
+type GitOpsDeploymentNotificationTargetStatus struct {
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+
+// GitOpsDeploymentNotificationTarget is the Schema for the gitopsdeploymentnotificationtargets API
+type GitOpsDeploymentNotificationTarget struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GitOpsDeploymentNotificationTargetSpec   `json:"spec,omitempty"`
+	Status GitOpsDeploymentNotificationTargetStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// GitOpsDeploymentNotificationTargetList contains a list of GitOpsDeploymentNotificationTarget
+type GitOpsDeploymentNotificationTargetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GitOpsDeploymentNotificationTarget `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GitOpsDeploymentNotificationTarget{}, &GitOpsDeploymentNotificationTargetList{})
+}
@@ -27,11 +27,171 @@ type GitOpsDeploymentSyncRunSpec struct {
 
 	// Optional: If specified, tells the GitOps Service to deploy a particular git commit SHA
 	RevisionID string `json:"revisionID,omitempty"`
+
+	// Optional: If specified, tells the GitOps Service to roll back the GitOpsDeployment to a revision that was
+	// previously deployed, as listed in .status.history of the GitOpsDeployment. Automated sync of the
+	// GitOpsDeployment is disabled for as long as the GitOpsDeploymentSyncRun exists: delete the
+	// GitOpsDeploymentSyncRun to resume automated sync.
+	//
+	// If the revision was deployed more than once, the GitOpsDeployment is rolled back to the most recent deployment
+	// of it.
+	//
+	// RollbackToRevision, RollbackToHistoryID and RevisionID are mutually exclusive.
+	RollbackToRevision string `json:"rollbackToRevision,omitempty"`
+
+	// Optional: If specified, tells the GitOps Service to roll back the GitOpsDeployment to the deployment history
+	// entry with this ID, as listed in .status.history of the GitOpsDeployment. Automated sync of the GitOpsDeployment
+	// is disabled for as long as the GitOpsDeploymentSyncRun exists, as with RollbackToRevision.
+	//
+	// RollbackToRevision, RollbackToHistoryID and RevisionID are mutually exclusive.
+	RollbackToHistoryID string `json:"rollbackToHistoryID,omitempty"`
+
+	// Optional: If true, resources that are no longer defined in the GitOps repository are deleted by the sync
+	Prune bool `json:"prune,omitempty"`
+
+	// Optional: If true, the sync is only simulated: no resources are modified on the target cluster
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// Optional: If true, resources which cannot be updated are deleted and re-created by the sync
+	Force bool `json:"force,omitempty"`
+
+	// Optional: The strategy used to sync the resources: either 'hook' (the default), which runs any sync hooks that are
+	// defined in the GitOps repository, or 'apply', which applies the resources without running sync hooks.
+	SyncStrategy SyncRunSyncStrategy `json:"syncStrategy,omitempty"`
+
+	// Optional: If specified, only the listed resources are synced, rather than all of the resources of the GitOpsDeployment
+	// +kubebuilder:validation:MaxItems=64
+	Resources []SyncRunResource `json:"resources,omitempty"`
+
+	// Optional: If specified, the GitOpsDeploymentSyncRun is deleted once this number of seconds has elapsed since
+	// the sync finished (as reported by .status.finishedAt).
+	// +kubebuilder:validation:Minimum=0
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+}
+
+const (
+	// SyncRunHistoryLimitAnnotation may be set on a Namespace to limit the number of finished GitOpsDeploymentSyncRuns
+	// that are kept, per GitOpsDeployment, in that Namespace: for example, a value of '5' keeps the 5 most recently
+	// finished GitOpsDeploymentSyncRuns of each GitOpsDeployment, and the older ones are deleted.
+	SyncRunHistoryLimitAnnotation = "managed-gitops.redhat.com/sync-run-history-limit"
+)
+
+// SyncRunSyncStrategy is the strategy used by a GitOpsDeploymentSyncRun to sync resources.
+// +kubebuilder:validation:Enum=apply;hook
+type SyncRunSyncStrategy string
+
+const (
+	SyncRunSyncStrategy_Apply SyncRunSyncStrategy = "apply"
+	SyncRunSyncStrategy_Hook  SyncRunSyncStrategy = "hook"
+)
+
+// SyncRunResource selects a single resource of the GitOpsDeployment to sync.
+type SyncRunResource struct {
+	// Group is the API group of the resource: empty for resources of the core API group (for example, a ConfigMap)
+	Group string `json:"group,omitempty"`
+
+	// Kind is the kind of the resource, for example 'ConfigMap'
+	Kind string `json:"kind"`
+
+	// Namespace is the namespace of the resource: empty for cluster-scoped resources
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name is the name of the resource
+	Name string `json:"name"`
+}
+
+// IsRollback returns true if the GitOpsDeploymentSyncRun rolls back its GitOpsDeployment to a previously deployed revision.
+func (spec GitOpsDeploymentSyncRunSpec) IsRollback() bool {
+	return spec.RollbackToRevision != "" || spec.RollbackToHistoryID != ""
+}
+
+// GetRevision returns the revision that the GitOpsDeploymentSyncRun should deploy. For a rollback to a deployment
+// history ID, the revision is that of the deployment history entry, which is not known from the spec: "" is returned.
+func (spec GitOpsDeploymentSyncRunSpec) GetRevision() string {
+	if spec.IsRollback() {
+		return spec.RollbackToRevision
+	}
+	return spec.RevisionID
+}
+
+// HasSameSyncOptions returns true if the sync options (Prune, DryRun, Force, SyncStrategy and Resources) of both
+// GitOpsDeploymentSyncRun specs are equal.
+func (spec GitOpsDeploymentSyncRunSpec) HasSameSyncOptions(other GitOpsDeploymentSyncRunSpec) bool {
+	if spec.Prune != other.Prune || spec.DryRun != other.DryRun || spec.Force != other.Force || spec.SyncStrategy != other.SyncStrategy {
+		return false
+	}
+	if len(spec.Resources) != len(other.Resources) {
+		return false
+	}
+	for i := range spec.Resources {
+		if spec.Resources[i] != other.Resources[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// IsFinished returns true if the sync of the GitOpsDeploymentSyncRun has finished, either successfully or unsuccessfully.
+// A sync that has Failed is retried until it succeeds, and so is only finished if it could not be retried (for example,
+// because the sync windows of the GitOpsDeployment are invalid), which is when FinishedAt is set.
+func (status GitOpsDeploymentSyncRunStatus) IsFinished() bool {
+	if status.FinishedAt == nil {
+		return false
+	}
+	return status.Phase == SyncRunPhase_Succeeded || status.Phase == SyncRunPhase_Failed || status.Phase == SyncRunPhase_Terminated
 }
 
 // GitOpsDeploymentSyncRunStatus defines the observed state of GitOpsDeploymentSyncRun
 type GitOpsDeploymentSyncRunStatus struct {
 	Conditions []GitOpsDeploymentSyncRunCondition `json:"conditions,omitempty"`
+
+	// Phase is the current phase of the sync: Pending, Running, Succeeded, Failed or Terminated.
+	// A sync is Terminated if it was terminated before it completed, for example by a user of Argo CD.
+	// A sync that has Failed is retried until it succeeds, after which the phase will return to Running.
+	Phase SyncRunPhase `json:"phase,omitempty"`
+
+	// Message contains human-readable details about the phase, for example the reason the sync failed
+	Message string `json:"message,omitempty"`
+
+	// StartedAt is the time the sync was started
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// FinishedAt is the time the sync finished, either successfully or unsuccessfully: it is not set for a failed
+	// sync that will be retried
+	FinishedAt *metav1.Time `json:"finishedAt,omitempty"`
+
+	// Revision is the revision (for example, the Git commit SHA) that was synced
+	Revision string `json:"revision,omitempty"`
+
+	// Resources contains the result of the sync of each resource
+	Resources []SyncRunResourceResult `json:"resources,omitempty"`
+}
+
+// SyncRunPhase is the phase of a GitOpsDeploymentSyncRun.
+// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed;Terminated
+type SyncRunPhase string
+
+const (
+	SyncRunPhase_Pending    SyncRunPhase = "Pending"
+	SyncRunPhase_Running    SyncRunPhase = "Running"
+	SyncRunPhase_Succeeded  SyncRunPhase = "Succeeded"
+	SyncRunPhase_Failed     SyncRunPhase = "Failed"
+	SyncRunPhase_Terminated SyncRunPhase = "Terminated"
+)
+
+// SyncRunResourceResult is the result of the sync of a single resource, as reported by Argo CD
+type SyncRunResourceResult struct {
+	Group     string `json:"group,omitempty"`
+	Version   string `json:"version,omitempty"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+
+	// Status is the result of the sync of the resource: Synced, SyncFailed, Pruned or PruneSkipped
+	Status string `json:"status,omitempty"`
+
+	// Message contains human-readable details about the result, for example the reason the sync of the resource failed
+	Message string `json:"message,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -67,7 +227,14 @@ type GitOpsDeploymentSyncRunCondition struct {
 type SyncRunReasonType string
 
 const (
-	SyncRunReasonErrorOccurred GitOpsDeploymentReasonType = "ErrorOccurred"
+	SyncRunReasonErrorOccurred     GitOpsDeploymentReasonType = "ErrorOccurred"
+	SyncRunReasonSyncWindowClosed  SyncRunReasonType          = "SyncWindowClosed"
+	SyncRunReasonRollbackSucceeded SyncRunReasonType          = "RollbackSucceeded"
+	SyncRunReasonRollbackFailed    SyncRunReasonType          = "RollbackFailed"
+
+	// SyncRunReasonWaitingOnDependencies is set while the sync of a GitOpsDeploymentSyncRun is held, because the
+	// dependencies of its GitOpsDeployment are not yet ready.
+	SyncRunReasonWaitingOnDependencies SyncRunReasonType = "WaitingOnDependencies"
 )
 
 // GitOpsDeploymentConditionType represents type of GitOpsDeployment condition.
@@ -75,6 +242,10 @@ type SyncRunConditionType string
 
 const (
 	GitOpsDeploymentSyncRunConditionErrorOccurred SyncRunConditionType = "ErrorOccurred"
+
+	// GitOpsDeploymentSyncRunConditionRollbackCompleted is set once the rollback of a GitOpsDeploymentSyncRun with
+	// .spec.rollbackToRevision has completed: the reason of the condition indicates whether it succeeded.
+	GitOpsDeploymentSyncRunConditionRollbackCompleted SyncRunConditionType = "RollbackCompleted"
 )
 
 //+kubebuilder:object:root=true
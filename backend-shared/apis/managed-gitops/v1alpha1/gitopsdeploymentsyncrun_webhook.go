@@ -31,6 +31,13 @@ import (
 const (
 	error_invalid_name = "name should not be zyxwvutsrqponmlkjihgfedcba-abcdefghijklmnoqrstuvwxyz"
 	invalid_name       = "zyxwvutsrqponmlkjihgfedcba-abcdefghijklmnoqrstuvwxyz"
+
+	error_revision_and_rollback_exclusive  = "only one of .spec.revisionID, .spec.rollbackToRevision and .spec.rollbackToHistoryID may be specified"
+	error_rollback_revision_is_immutable   = ".spec.rollbackToRevision and .spec.rollbackToHistoryID are immutable: changing them from their initial values is not supported"
+	error_sync_options_are_immutable       = ".spec.prune, .spec.dryRun, .spec.force, .spec.syncStrategy and .spec.resources are immutable: changing them from their initial values is not supported"
+	error_invalid_sync_strategy            = ".spec.syncStrategy must be either apply or hook"
+	error_invalid_sync_run_resource        = "each entry in .spec.resources requires a kind and a name"
+	error_invalid_ttl_seconds_after_finish = ".spec.ttlSecondsAfterFinished must not be negative"
 )
 
 // log is for logging in this package.
@@ -73,6 +80,11 @@ func (r *GitOpsDeploymentSyncRun) ValidateCreate() (admission.Warnings, error) {
 		return nil, err
 	}
 
+	if err := r.validateGitOpsDeploymentSyncRun(); err != nil {
+		log.Info("webhook rejected invalid create", "error", fmt.Sprintf("%v", err))
+		return nil, err
+	}
+
 	return nil, nil
 }
 
@@ -83,6 +95,30 @@ func (r *GitOpsDeploymentSyncRun) ValidateUpdate(old runtime.Object) (admission.
 
 	log.V(logutil.LogLevel_Debug).Info("validate update")
 
+	oldSyncRun, ok := old.(*GitOpsDeploymentSyncRun)
+	if !ok {
+		err := fmt.Errorf("unable to convert object to GitOpsDeploymentSyncRun")
+		log.Info("webhook rejected invalid update", "error", fmt.Sprintf("%v", err))
+		return nil, err
+	}
+
+	if oldSyncRun.Spec.RollbackToRevision != r.Spec.RollbackToRevision || oldSyncRun.Spec.RollbackToHistoryID != r.Spec.RollbackToHistoryID {
+		err := errors.New(error_rollback_revision_is_immutable)
+		log.Info("webhook rejected invalid update", "error", fmt.Sprintf("%v", err))
+		return nil, err
+	}
+
+	if !oldSyncRun.Spec.HasSameSyncOptions(r.Spec) {
+		err := errors.New(error_sync_options_are_immutable)
+		log.Info("webhook rejected invalid update", "error", fmt.Sprintf("%v", err))
+		return nil, err
+	}
+
+	if err := r.validateGitOpsDeploymentSyncRun(); err != nil {
+		log.Info("webhook rejected invalid update", "error", fmt.Sprintf("%v", err))
+		return nil, err
+	}
+
 	return nil, nil
 }
 
@@ -95,3 +131,32 @@ func (r *GitOpsDeploymentSyncRun) ValidateDelete() (admission.Warnings, error) {
 
 	return nil, nil
 }
+
+func (r *GitOpsDeploymentSyncRun) validateGitOpsDeploymentSyncRun() error {
+
+	specified := 0
+	for _, field := range []string{r.Spec.RevisionID, r.Spec.RollbackToRevision, r.Spec.RollbackToHistoryID} {
+		if field != "" {
+			specified++
+		}
+	}
+	if specified > 1 {
+		return errors.New(error_revision_and_rollback_exclusive)
+	}
+
+	if r.Spec.SyncStrategy != "" && r.Spec.SyncStrategy != SyncRunSyncStrategy_Apply && r.Spec.SyncStrategy != SyncRunSyncStrategy_Hook {
+		return errors.New(error_invalid_sync_strategy)
+	}
+
+	for _, resource := range r.Spec.Resources {
+		if resource.Kind == "" || resource.Name == "" {
+			return errors.New(error_invalid_sync_run_resource)
+		}
+	}
+
+	if r.Spec.TTLSecondsAfterFinished != nil && *r.Spec.TTLSecondsAfterFinished < 0 {
+		return errors.New(error_invalid_ttl_seconds_after_finish)
+	}
+
+	return nil
+}
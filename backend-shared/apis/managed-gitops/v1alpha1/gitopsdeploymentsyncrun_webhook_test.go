@@ -59,4 +59,138 @@ var _ = Describe("GitOpsDeploymentSyncRun validation webhook", func() {
 		})
 	})
 
+	Context("Validate GitOpsDeploymentSyncRun CR with rollbackToRevision", func() {
+
+		It("Should accept a rollback to a revision", func() {
+			gitopsDeplSyncRunCr.Spec.RevisionID = ""
+			gitopsDeplSyncRunCr.Spec.RollbackToRevision = "0a1b2c3d"
+
+			Expect(gitopsDeplSyncRunCr.validateGitOpsDeploymentSyncRun()).To(Succeed())
+		})
+
+		It("Should fail when both revisionID and rollbackToRevision are specified", func() {
+			gitopsDeplSyncRunCr.Spec.RollbackToRevision = "0a1b2c3d"
+
+			err := gitopsDeplSyncRunCr.validateGitOpsDeploymentSyncRun()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_revision_and_rollback_exclusive))
+		})
+
+		It("Should fail when rollbackToRevision is updated", func() {
+			gitopsDeplSyncRunCr.Spec.RevisionID = ""
+			gitopsDeplSyncRunCr.Spec.RollbackToRevision = "0a1b2c3d"
+
+			newSyncRunCr := gitopsDeplSyncRunCr.DeepCopy()
+			newSyncRunCr.Spec.RollbackToRevision = "4e5f6a7b"
+
+			_, err := newSyncRunCr.ValidateUpdate(gitopsDeplSyncRunCr)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_rollback_revision_is_immutable))
+		})
+
+		It("Should accept a rollback to a deployment history ID", func() {
+			gitopsDeplSyncRunCr.Spec.RevisionID = ""
+			gitopsDeplSyncRunCr.Spec.RollbackToHistoryID = "a6c9e2b0-1d1e-4c4f-9b7a-0f3c2d1e4b5a"
+
+			Expect(gitopsDeplSyncRunCr.validateGitOpsDeploymentSyncRun()).To(Succeed())
+		})
+
+		It("Should fail when both rollbackToRevision and rollbackToHistoryID are specified", func() {
+			gitopsDeplSyncRunCr.Spec.RevisionID = ""
+			gitopsDeplSyncRunCr.Spec.RollbackToRevision = "0a1b2c3d"
+			gitopsDeplSyncRunCr.Spec.RollbackToHistoryID = "a6c9e2b0-1d1e-4c4f-9b7a-0f3c2d1e4b5a"
+
+			err := gitopsDeplSyncRunCr.validateGitOpsDeploymentSyncRun()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_revision_and_rollback_exclusive))
+		})
+
+		It("Should fail when rollbackToHistoryID is updated", func() {
+			gitopsDeplSyncRunCr.Spec.RevisionID = ""
+			gitopsDeplSyncRunCr.Spec.RollbackToHistoryID = "a6c9e2b0-1d1e-4c4f-9b7a-0f3c2d1e4b5a"
+
+			newSyncRunCr := gitopsDeplSyncRunCr.DeepCopy()
+			newSyncRunCr.Spec.RollbackToHistoryID = "5d0b8f3e-7a2c-4e61-8d9f-2b4a6c8e0f1d"
+
+			_, err := newSyncRunCr.ValidateUpdate(gitopsDeplSyncRunCr)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_rollback_revision_is_immutable))
+		})
+
+		It("Should fail when the sync options are updated", func() {
+			gitopsDeplSyncRunCr.Spec.Prune = true
+			gitopsDeplSyncRunCr.Spec.Resources = []SyncRunResource{{Kind: "ConfigMap", Name: "my-config"}}
+
+			By("accepting an update which doesn't change the sync options")
+			newSyncRunCr := gitopsDeplSyncRunCr.DeepCopy()
+			_, err := newSyncRunCr.ValidateUpdate(gitopsDeplSyncRunCr)
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, update := range []func(spec *GitOpsDeploymentSyncRunSpec){
+				func(spec *GitOpsDeploymentSyncRunSpec) { spec.Prune = false },
+				func(spec *GitOpsDeploymentSyncRunSpec) { spec.DryRun = true },
+				func(spec *GitOpsDeploymentSyncRunSpec) { spec.Force = true },
+				func(spec *GitOpsDeploymentSyncRunSpec) { spec.SyncStrategy = SyncRunSyncStrategy_Apply },
+				func(spec *GitOpsDeploymentSyncRunSpec) { spec.Resources[0].Name = "other-config" },
+				func(spec *GitOpsDeploymentSyncRunSpec) { spec.Resources = nil },
+			} {
+				newSyncRunCr := gitopsDeplSyncRunCr.DeepCopy()
+				update(&newSyncRunCr.Spec)
+
+				_, err := newSyncRunCr.ValidateUpdate(gitopsDeplSyncRunCr)
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(ContainSubstring(error_sync_options_are_immutable))
+			}
+		})
+	})
+
+	Context("Validate GitOpsDeploymentSyncRun CR with sync options", func() {
+
+		It("Should accept valid sync options", func() {
+			gitopsDeplSyncRunCr.Spec.Prune = true
+			gitopsDeplSyncRunCr.Spec.Force = true
+			gitopsDeplSyncRunCr.Spec.SyncStrategy = SyncRunSyncStrategy_Apply
+			gitopsDeplSyncRunCr.Spec.Resources = []SyncRunResource{{Kind: "ConfigMap", Namespace: "my-namespace", Name: "my-config"}}
+
+			Expect(gitopsDeplSyncRunCr.validateGitOpsDeploymentSyncRun()).To(Succeed())
+		})
+
+		It("Should fail when the sync strategy is invalid", func() {
+			gitopsDeplSyncRunCr.Spec.SyncStrategy = "replace"
+
+			err := gitopsDeplSyncRunCr.validateGitOpsDeploymentSyncRun()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_invalid_sync_strategy))
+		})
+
+		It("Should fail when a resource has no name", func() {
+			gitopsDeplSyncRunCr.Spec.Resources = []SyncRunResource{{Group: "apps", Kind: "Deployment"}}
+
+			err := gitopsDeplSyncRunCr.validateGitOpsDeploymentSyncRun()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_invalid_sync_run_resource))
+		})
+	})
+
+	Context("Validate GitOpsDeploymentSyncRun CR with ttlSecondsAfterFinished", func() {
+
+		It("Should accept a TTL of zero or more seconds", func() {
+			ttl := int32(0)
+			gitopsDeplSyncRunCr.Spec.TTLSecondsAfterFinished = &ttl
+			Expect(gitopsDeplSyncRunCr.validateGitOpsDeploymentSyncRun()).To(Succeed())
+
+			ttl = 3600
+			Expect(gitopsDeplSyncRunCr.validateGitOpsDeploymentSyncRun()).To(Succeed())
+		})
+
+		It("Should fail when the TTL is negative", func() {
+			ttl := int32(-1)
+			gitopsDeplSyncRunCr.Spec.TTLSecondsAfterFinished = &ttl
+
+			err := gitopsDeplSyncRunCr.validateGitOpsDeploymentSyncRun()
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring(error_invalid_ttl_seconds_after_finish))
+		})
+	})
+
 })
@@ -67,6 +67,10 @@ func (dbq *PostgreSQLDatabaseQueries) CreateApplicationState(ctx context.Context
 		return fmt.Errorf("resources value exceeds maximum size: max: %d, actual: %d", maxSize, noOfBytesInObj)
 	}
 
+	if noOfBytesInObj := binary.Size(obj.Resource_tree); noOfBytesInObj > DbFieldMap["ApplicationStateResourceTreeLength"] {
+		return fmt.Errorf("resource tree value exceeds maximum size: max: %d, actual: %d", DbFieldMap["ApplicationStateResourceTreeLength"], noOfBytesInObj)
+	}
+
 	// Inserting ApplicationState object
 	result, err := dbq.dbConnection.Model(obj).Context(ctx).Insert()
 	if err != nil {
@@ -105,6 +109,10 @@ func (dbq *PostgreSQLDatabaseQueries) UpdateApplicationState(ctx context.Context
 		return fmt.Errorf("resources value exceeds maximum size: max: %d, actual: %d", maxSize, noOfBytesInObj)
 	}
 
+	if noOfBytesInObj := binary.Size(obj.Resource_tree); noOfBytesInObj > DbFieldMap["ApplicationStateResourceTreeLength"] {
+		return fmt.Errorf("resource tree value exceeds maximum size: max: %d, actual: %d", DbFieldMap["ApplicationStateResourceTreeLength"], noOfBytesInObj)
+	}
+
 	result, err := dbq.dbConnection.Model(obj).Context(ctx).
 		Where("Applicationstate_application_id = ?", obj.Applicationstate_application_id).Update()
 	if err != nil {
@@ -5,6 +5,7 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
 	db "github.com/redhat-appstudio/managed-gitops/backend-shared/db"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/util"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/fauxargocd"
@@ -89,6 +90,10 @@ var _ = Describe("ApplicationStates Tests", func() {
 			Expect(appStatusBytes).ToNot(BeNil())
 			applicationState.ArgoCD_Application_Status = appStatusBytes
 
+			resourceTreeBytes, err := util.CompressObject(managedgitopsv1alpha1.ResourceTree{Truncated: true})
+			Expect(err).ToNot(HaveOccurred())
+			applicationState.Resource_tree = resourceTreeBytes
+
 			err = dbq.UpdateApplicationState(ctx, applicationState)
 			Expect(err).ToNot(HaveOccurred())
 
@@ -107,6 +112,13 @@ var _ = Describe("ApplicationStates Tests", func() {
 
 			err = dbq.CreateApplicationState(ctx, applicationState)
 			Expect(err).To(HaveOccurred())
+
+			applicationState.ArgoCD_Application_Status = appStatusBytes
+			applicationState.Resource_tree = make([]byte, 262145)
+
+			err = dbq.CreateApplicationState(ctx, applicationState)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("resource tree value exceeds maximum size"))
 		})
 	})
 
@@ -14,6 +14,10 @@ const (
 	ClusterCredentialsServiceaccountBearerTokenLength                       = 2048
 	ClusterCredentialsServiceaccountNsLength                                = 128
 	ClusterCredentialsNamespacesLength                                      = 4096
+	ClusterCredentialsCaBundleLength                                        = 65000
+	ClusterCredentialsClientCertificateDataLength                           = 65000
+	ClusterCredentialsClientKeyDataLength                                   = 65000
+	ClusterCredentialsProxyURLLength                                        = 512
 	GitopsEngineClusterGitopsengineclusterIDLength                          = 48
 	GitopsEngineInstanceGitopsengineinstanceIDLength                        = 48
 	GitopsEngineInstanceNamespaceNameLength                                 = 48
@@ -38,7 +42,7 @@ const (
 	OperationHumanReadableStateLength                                       = 1024
 	ApplicationApplicationIDLength                                          = 48
 	ApplicationNameLength                                                   = 256
-	ApplicationSpecFieldLength                                              = 16384
+	ApplicationSpecFieldLength                                              = 65536
 	ApplicationEngineInstanceInstIDLength                                   = 48
 	ApplicationManagedEnvironmentIDLength                                   = 48
 	ApplicationStateApplicationstateApplicationIDLength                     = 48
@@ -63,6 +67,13 @@ const (
 	SyncOperationDeploymentNameLength                                       = 256
 	SyncOperationRevisionLength                                             = 256
 	SyncOperationDesiredStateLength                                         = 16
+	SyncOperationSyncWindowsLength                                          = 4096
+	SyncOperationSyncStrategyLength                                         = 16
+	SyncOperationResourcesLength                                            = 4096
+	SyncOperationPhaseLength                                                = 16
+	SyncOperationPhaseMessageLength                                         = 1024
+	SyncOperationSyncedRevisionLength                                       = 256
+	SyncOperationResourceResultsLength                                      = 16384
 	RepositoryCredentialsRepositorycredentialsIDLength                      = 48
 	RepositoryCredentialsRepoCredUserIDLength                               = 48
 	RepositoryCredentialsRepoCredURLLength                                  = 512
@@ -79,6 +90,22 @@ const (
 	AppProjectManagedEnvironmentClusteruserIDLength                         = 48
 	ApplicationOwnerApplicationOwnerApplicationIDLength                     = 48
 	ApplicationOwnerApplicationOwnerUserIDLength                            = 48
+	DeploymentHistoryDeploymenthistoryIDLength                              = 48
+	DeploymentHistoryApplicationIDLength                                    = 48
+	DeploymentHistoryRevisionLength                                         = 256
+	DeploymentHistorySourceLength                                           = 4096
+	DeploymentHistorySourcesLength                                          = 16384
+	DeploymentHistoryRevisionsLength                                        = 2048
+	DeploymentHistoryInitiatedByLength                                      = 256
+	DeploymentHistorySyncRunNameLength                                      = 256
+	DiffOperationDiffoperationIDLength                                      = 48
+	DiffOperationApplicationIDLength                                        = 48
+	DiffOperationDeploymentNameLength                                       = 256
+	DiffOperationRevisionLength                                             = 256
+	DiffOperationPhaseLength                                                = 16
+	DiffOperationPhaseMessageLength                                         = 1024
+	DiffOperationResolvedRevisionLength                                     = 256
+	DiffOperationResourceDiffsLength                                        = 262144
 )
 
 // TruncateVarchar converts string to "str..." if chars is > maxLength
@@ -118,6 +145,10 @@ var DbFieldMap = map[string]int{
 	"ClusterCredentialsServiceaccountBearerTokenLength":                       ClusterCredentialsServiceaccountBearerTokenLength,
 	"ClusterCredentialsServiceaccountNsLength":                                ClusterCredentialsServiceaccountNsLength,
 	"ClusterCredentialsNamespacesLength":                                      ClusterCredentialsNamespacesLength,
+	"ClusterCredentialsCaBundleLength":                                        ClusterCredentialsCaBundleLength,
+	"ClusterCredentialsClientCertificateDataLength":                           ClusterCredentialsClientCertificateDataLength,
+	"ClusterCredentialsClientKeyDataLength":                                   ClusterCredentialsClientKeyDataLength,
+	"ClusterCredentialsProxyURLLength":                                        ClusterCredentialsProxyURLLength,
 	"GitopsEngineClusterGitopsengineclusterIDLength":                          GitopsEngineClusterGitopsengineclusterIDLength,
 	"GitopsEngineInstanceGitopsengineinstanceIDLength":                        GitopsEngineInstanceGitopsengineinstanceIDLength,
 	"GitopsEngineInstanceNamespaceNameLength":                                 GitopsEngineInstanceNamespaceNameLength,
@@ -148,6 +179,7 @@ var DbFieldMap = map[string]int{
 	"ApplicationManagedEnvironmentIDLength":                                   ApplicationManagedEnvironmentIDLength,
 	"ApplicationStateApplicationstateApplicationIDLength":                     ApplicationStateApplicationstateApplicationIDLength,
 	"ApplicationStateStatusLength":                                            262144,
+	"ApplicationStateResourceTreeLength":                                      262144,
 	"DeploymentToApplicationMappingDeploymenttoapplicationmappingUIDIDLength": DeploymentToApplicationMappingDeploymenttoapplicationmappingUIDIDLength,
 	"DeploymentToApplicationMappingNameLength":                                DeploymentToApplicationMappingNameLength,
 	"DeploymentToApplicationMappingDeploymentNameLength":                      DeploymentToApplicationMappingNameLength,
@@ -182,6 +214,13 @@ var DbFieldMap = map[string]int{
 	"SyncOperationDeploymentNameFieldLength":                                  SyncOperationDeploymentNameLength,
 	"SyncOperationRevisionLength":                                             SyncOperationRevisionLength,
 	"SyncOperationDesiredStateLength":                                         SyncOperationDesiredStateLength,
+	"SyncOperationSyncWindowsLength":                                          SyncOperationSyncWindowsLength,
+	"SyncOperationSyncStrategyLength":                                         SyncOperationSyncStrategyLength,
+	"SyncOperationResourcesLength":                                            SyncOperationResourcesLength,
+	"SyncOperationPhaseLength":                                                SyncOperationPhaseLength,
+	"SyncOperationPhaseMessageLength":                                         SyncOperationPhaseMessageLength,
+	"SyncOperationSyncedRevisionLength":                                       SyncOperationSyncedRevisionLength,
+	"SyncOperationResourceResultsLength":                                      SyncOperationResourceResultsLength,
 	"RepositoryCredentialsRepositorycredentialsIDLength":                      RepositoryCredentialsRepositorycredentialsIDLength,
 	"RepositoryCredentialsRepoCredUserIDLength":                               RepositoryCredentialsRepoCredUserIDLength,
 	"RepositoryCredentialsRepoCredURLLength":                                  RepositoryCredentialsRepoCredURLLength,
@@ -198,6 +237,25 @@ var DbFieldMap = map[string]int{
 	"AppProjectManagedEnvironmentClusteruserIDLength":                         AppProjectManagedEnvironmentClusteruserIDLength,
 	"ApplicationOwnerApplicationOwnerApplicationIDLength":                     ApplicationOwnerApplicationOwnerApplicationIDLength,
 	"ApplicationOwnerApplicationOwnerUserIDLength":                            ApplicationOwnerApplicationOwnerUserIDLength,
+	"DeploymentHistoryDeploymenthistoryIDLength":                              DeploymentHistoryDeploymenthistoryIDLength,
+	"DeploymentHistoryDeploymentHistoryIDLength":                              DeploymentHistoryDeploymenthistoryIDLength,
+	"DeploymentHistoryApplicationIDLength":                                    DeploymentHistoryApplicationIDLength,
+	"DeploymentHistoryRevisionLength":                                         DeploymentHistoryRevisionLength,
+	"DeploymentHistorySourceLength":                                           DeploymentHistorySourceLength,
+	"DeploymentHistorySourcesLength":                                          DeploymentHistorySourcesLength,
+	"DeploymentHistoryRevisionsLength":                                        DeploymentHistoryRevisionsLength,
+	"DeploymentHistoryInitiatedByLength":                                      DeploymentHistoryInitiatedByLength,
+	"DeploymentHistorySyncRunNameLength":                                      DeploymentHistorySyncRunNameLength,
+	"DiffOperationDiffoperationIDLength":                                      DiffOperationDiffoperationIDLength,
+	"DiffOperationDiffOperationIDLength":                                      DiffOperationDiffoperationIDLength,
+	"DiffOperationApplicationIDLength":                                        DiffOperationApplicationIDLength,
+	"DiffOperationDeploymentNameLength":                                       DiffOperationDeploymentNameLength,
+	"DiffOperationDeploymentNameFieldLength":                                  DiffOperationDeploymentNameLength,
+	"DiffOperationRevisionLength":                                             DiffOperationRevisionLength,
+	"DiffOperationPhaseLength":                                                DiffOperationPhaseLength,
+	"DiffOperationPhaseMessageLength":                                         DiffOperationPhaseMessageLength,
+	"DiffOperationResolvedRevisionLength":                                     DiffOperationResolvedRevisionLength,
+	"DiffOperationResourceDiffsLength":                                        DiffOperationResourceDiffsLength,
 }
 
 // Get value of constants based on constant variable name given as String.
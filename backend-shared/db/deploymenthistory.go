@@ -0,0 +1,153 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// DeploymentHistory_InitiatedBy_Automated is the value of the InitiatedBy field, for deployments that were initiated by an automated sync.
+	DeploymentHistory_InitiatedBy_Automated = "automated"
+)
+
+func (dbq *PostgreSQLDatabaseQueries) UnsafeListAllDeploymentHistory(ctx context.Context, deploymentHistory *[]DeploymentHistory) error {
+
+	if err := validateUnsafeQueryParamsNoPK(dbq); err != nil {
+		return err
+	}
+
+	if err := dbq.dbConnection.Model(deploymentHistory).Context(ctx).Select(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (dbq *PostgreSQLDatabaseQueries) CreateDeploymentHistory(ctx context.Context, obj *DeploymentHistory) error {
+
+	if err := validateQueryParamsEntity(obj, dbq); err != nil {
+		return err
+	}
+
+	if dbq.allowTestUuids {
+		if IsEmpty(obj.DeploymentHistory_id) {
+			obj.DeploymentHistory_id = generateUuid()
+		}
+	} else {
+		if !IsEmpty(obj.DeploymentHistory_id) {
+			return fmt.Errorf("primary key should be empty")
+		}
+
+		obj.DeploymentHistory_id = generateUuid()
+	}
+
+	if err := isEmptyValues("CreateDeploymentHistory",
+		"Application_id", obj.Application_id,
+		"Revision", obj.Revision); err != nil {
+		return err
+	}
+
+	if obj.DeployedAt.IsZero() {
+		return fmt.Errorf("deployed at field should not be empty in CreateDeploymentHistory")
+	}
+
+	if err := validateFieldLength(obj); err != nil {
+		return err
+	}
+
+	obj.Created_on = time.Now()
+
+	result, err := dbq.dbConnection.Model(obj).Context(ctx).Insert()
+	if err != nil {
+		return fmt.Errorf("error on inserting deployment history: %v", err)
+	}
+
+	if result.RowsAffected() != 1 {
+		return fmt.Errorf("unexpected number of rows affected: %d", result.RowsAffected())
+	}
+
+	return nil
+}
+
+// ListDeploymentHistoryByApplicationId returns the DeploymentHistory rows of an Application, ordered from the oldest
+// deployment to the most recent.
+func (dbq *PostgreSQLDatabaseQueries) ListDeploymentHistoryByApplicationId(ctx context.Context, applicationId string, deploymentHistory *[]DeploymentHistory) error {
+
+	if err := validateQueryParamsNoPK(dbq); err != nil {
+		return err
+	}
+
+	if err := isEmptyValues("ListDeploymentHistoryByApplicationId",
+		"applicationId", applicationId); err != nil {
+		return err
+	}
+
+	if err := dbq.dbConnection.Model(deploymentHistory).
+		Where("dh.application_id = ?", applicationId).
+		Order("deployed_at ASC", "seq_id ASC").
+		Context(ctx).
+		Select(); err != nil {
+
+		return fmt.Errorf("error on retrieving ListDeploymentHistoryByApplicationId: %v", err)
+	}
+
+	return nil
+}
+
+func (dbq *PostgreSQLDatabaseQueries) DeleteDeploymentHistoryById(ctx context.Context, id string) (int, error) {
+
+	if err := validateQueryParams(id, dbq); err != nil {
+		return 0, err
+	}
+
+	result := &DeploymentHistory{}
+
+	deleteResult, err := dbq.dbConnection.Model(result).
+		Where("dh.deploymenthistory_id = ?", id).
+		Context(ctx).
+		Delete()
+
+	if err != nil {
+		return 0, fmt.Errorf("error on deleting deployment history: %v", err)
+	}
+
+	return deleteResult.RowsAffected(), nil
+}
+
+// DeleteDeploymentHistoryByApplicationId deletes all the DeploymentHistory rows of an Application.
+func (dbq *PostgreSQLDatabaseQueries) DeleteDeploymentHistoryByApplicationId(ctx context.Context, applicationId string) (int, error) {
+
+	if err := validateQueryParamsNoPK(dbq); err != nil {
+		return 0, err
+	}
+
+	if err := isEmptyValues("DeleteDeploymentHistoryByApplicationId",
+		"applicationId", applicationId); err != nil {
+		return 0, err
+	}
+
+	result := &DeploymentHistory{}
+
+	deleteResult, err := dbq.dbConnection.Model(result).
+		Where("dh.application_id = ?", applicationId).
+		Context(ctx).
+		Delete()
+
+	if err != nil {
+		return 0, fmt.Errorf("error on deleting deployment history of application: %v", err)
+	}
+
+	return deleteResult.RowsAffected(), nil
+}
+
+var _ AppScopedDisposableResource = &DeploymentHistory{}
+
+func (obj *DeploymentHistory) DisposeAppScoped(ctx context.Context, dbq ApplicationScopedQueries) error {
+	if dbq == nil {
+		return fmt.Errorf("missing database interface in deploymenthistory dispose")
+	}
+
+	_, err := dbq.DeleteDeploymentHistoryById(ctx, obj.DeploymentHistory_id)
+	return err
+}
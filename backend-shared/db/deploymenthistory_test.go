@@ -0,0 +1,123 @@
+package db_test
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	db "github.com/redhat-appstudio/managed-gitops/backend-shared/db"
+)
+
+var _ = Describe("DeploymentHistory Tests", func() {
+	var ctx context.Context
+	var dbq db.AllDatabaseQueries
+	var application *db.Application
+
+	BeforeEach(func() {
+		err := db.SetupForTestingDBGinkgo()
+		Expect(err).ToNot(HaveOccurred())
+
+		ctx = context.Background()
+
+		dbq, err = db.NewUnsafePostgresDBQueries(true, true)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, managedEnvironment, _, gitopsEngineInstance, _, err := db.CreateSampleData(dbq)
+		Expect(err).ToNot(HaveOccurred())
+
+		application = &db.Application{
+			Application_id:          "test-my-application",
+			Name:                    "my-application",
+			Spec_field:              "{}",
+			Engine_instance_inst_id: gitopsEngineInstance.Gitopsengineinstance_id,
+			Managed_environment_id:  managedEnvironment.Managedenvironment_id,
+		}
+
+		err = dbq.CreateApplication(ctx, application)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		dbq.CloseDatabase()
+	})
+
+	Context("It should execute all DB functions for DeploymentHistory", func() {
+
+		It("Should create, list, and delete DeploymentHistory rows", func() {
+
+			deployedAt := time.Date(2024, time.January, 1, 9, 30, 0, 0, time.UTC)
+
+			older := &db.DeploymentHistory{
+				DeploymentHistory_id: "test-deployment-history-1",
+				Application_id:       application.Application_id,
+				Revision:             "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+				Source:               `{"repoURL":"https://github.com/test/test","path":"environments/prod"}`,
+				DeployedAt:           deployedAt,
+				InitiatedBy:          db.DeploymentHistory_InitiatedBy_Automated,
+			}
+			err := dbq.CreateDeploymentHistory(ctx, older)
+			Expect(err).ToNot(HaveOccurred())
+
+			newer := &db.DeploymentHistory{
+				DeploymentHistory_id: "test-deployment-history-2",
+				Application_id:       application.Application_id,
+				Revision:             "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+				DeployedAt:           deployedAt.Add(time.Hour),
+				Sources:              `[{"repoURL":"https://github.com/test/test"},{"repoURL":"https://github.com/test/values","ref":"values"}]`,
+				Revisions:            `["bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb","cccccccccccccccccccccccccccccccccccccccc"]`,
+				InitiatedBy:          "admin",
+				SyncRunName:          "my-sync-run",
+			}
+			err = dbq.CreateDeploymentHistory(ctx, newer)
+			Expect(err).ToNot(HaveOccurred())
+
+			By("listing the rows of the Application, from oldest to newest")
+			var deploymentHistory []db.DeploymentHistory
+			err = dbq.ListDeploymentHistoryByApplicationId(ctx, application.Application_id, &deploymentHistory)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deploymentHistory).To(HaveLen(2))
+			Expect(deploymentHistory[0].DeploymentHistory_id).To(Equal(older.DeploymentHistory_id))
+			Expect(deploymentHistory[0].Source).To(Equal(older.Source))
+			Expect(deploymentHistory[0].DeployedAt.Equal(older.DeployedAt)).To(BeTrue())
+			Expect(deploymentHistory[1].DeploymentHistory_id).To(Equal(newer.DeploymentHistory_id))
+			Expect(deploymentHistory[1].SyncRunName).To(Equal(newer.SyncRunName))
+			Expect(deploymentHistory[1].Sources).To(Equal(newer.Sources))
+			Expect(deploymentHistory[1].Revisions).To(Equal(newer.Revisions))
+
+			By("deleting a single row")
+			rowsAffected, err := dbq.DeleteDeploymentHistoryById(ctx, older.DeploymentHistory_id)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rowsAffected).To(Equal(1))
+
+			By("deleting all the rows of the Application")
+			rowsAffected, err = dbq.DeleteDeploymentHistoryByApplicationId(ctx, application.Application_id)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rowsAffected).To(Equal(1))
+
+			deploymentHistory = []db.DeploymentHistory{}
+			err = dbq.ListDeploymentHistoryByApplicationId(ctx, application.Application_id, &deploymentHistory)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deploymentHistory).To(BeEmpty())
+		})
+
+		It("Should return an error if required fields are missing or too long", func() {
+
+			deploymentHistory := &db.DeploymentHistory{
+				Application_id: application.Application_id,
+				Revision:       "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+			}
+			err := dbq.CreateDeploymentHistory(ctx, deploymentHistory)
+			Expect(err).To(HaveOccurred())
+
+			deploymentHistory = &db.DeploymentHistory{
+				Application_id: application.Application_id,
+				Revision:       strings.Repeat("a", 257),
+				DeployedAt:     time.Now(),
+			}
+			err = dbq.CreateDeploymentHistory(ctx, deploymentHistory)
+			Expect(db.IsMaxLengthError(err)).To(BeTrue())
+		})
+	})
+})
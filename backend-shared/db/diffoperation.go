@@ -0,0 +1,196 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	DiffOperation_Phase_Pending   = "Pending"
+	DiffOperation_Phase_Running   = "Running"
+	DiffOperation_Phase_Succeeded = "Succeeded"
+	DiffOperation_Phase_Failed    = "Failed"
+)
+
+func (dbq *PostgreSQLDatabaseQueries) GetDiffOperationById(ctx context.Context, diffOperation *DiffOperation) error {
+
+	if err := validateQueryParamsEntity(diffOperation, dbq); err != nil {
+		return err
+	}
+
+	if IsEmpty(diffOperation.DiffOperation_id) {
+		return fmt.Errorf("diff operation id is empty")
+	}
+
+	var dbResults []DiffOperation
+
+	if err := dbq.dbConnection.Model(&dbResults).
+		Where("dfo.diffoperation_id = ?", diffOperation.DiffOperation_id).
+		Context(ctx).
+		Select(); err != nil {
+
+		return fmt.Errorf("error on retrieving GetDiffOperationById: %v", err)
+	}
+
+	if len(dbResults) >= 2 {
+		return fmt.Errorf("multiple results returned from GetDiffOperationById")
+	}
+
+	if len(dbResults) == 0 {
+		return NewResultNotFoundError("no results found for GetDiffOperationById")
+	}
+
+	*diffOperation = dbResults[0]
+
+	return nil
+}
+
+func (dbq *PostgreSQLDatabaseQueries) CreateDiffOperation(ctx context.Context, obj *DiffOperation) error {
+
+	if err := validateQueryParamsEntity(obj, dbq); err != nil {
+		return err
+	}
+
+	if dbq.allowTestUuids {
+		if IsEmpty(obj.DiffOperation_id) {
+			obj.DiffOperation_id = generateUuid()
+		}
+	} else {
+		if !IsEmpty(obj.DiffOperation_id) {
+			return fmt.Errorf("primary key should be empty")
+		}
+
+		obj.DiffOperation_id = generateUuid()
+	}
+
+	if err := isEmptyValues("CreateDiffOperation",
+		"Application_id", obj.Application_id,
+		"DeploymentNameField", obj.DeploymentNameField,
+		"Revision", obj.Revision); err != nil {
+		return err
+	}
+
+	if err := validateFieldLength(obj); err != nil {
+		return err
+	}
+
+	obj.Created_on = time.Now()
+
+	result, err := dbq.dbConnection.Model(obj).Context(ctx).Insert()
+	if err != nil {
+		return fmt.Errorf("error on inserting diff operation: %v", err)
+	}
+
+	if result.RowsAffected() != 1 {
+		return fmt.Errorf("unexpected number of rows affected: %d", result.RowsAffected())
+	}
+
+	return nil
+}
+
+func (dbq *PostgreSQLDatabaseQueries) DeleteDiffOperationById(ctx context.Context, id string) (int, error) {
+
+	if err := validateQueryParams(id, dbq); err != nil {
+		return 0, err
+	}
+
+	if IsEmpty(id) {
+		return 0, fmt.Errorf("diff operation id was empty in delete")
+	}
+
+	result := &DiffOperation{}
+
+	deleteResult, err := dbq.dbConnection.Model(result).
+		Where("dfo.diffoperation_id = ?", id).
+		Context(ctx).
+		Delete()
+
+	if err != nil {
+		return 0, fmt.Errorf("error on deleting diffoperation: %v", err)
+	}
+
+	return deleteResult.RowsAffected(), nil
+}
+
+// UpdateDiffOperationStatus updates only the status fields of the DiffOperation (phase, phase message, start/finish time,
+// resolved revision, resource diffs and truncated). Returns a ResultNotFoundError if the DiffOperation no longer exists.
+func (dbq *PostgreSQLDatabaseQueries) UpdateDiffOperationStatus(ctx context.Context, obj *DiffOperation) error {
+
+	if err := validateQueryParamsEntity(obj, dbq); err != nil {
+		return err
+	}
+
+	if err := isEmptyValues("UpdateDiffOperationStatus",
+		"diffoperation_id", obj.DiffOperation_id,
+	); err != nil {
+		return err
+	}
+
+	if err := validateFieldLength(obj); err != nil {
+		return err
+	}
+
+	result, err := dbq.dbConnection.Model(obj).
+		Column("phase", "phase_message", "started_at", "finished_at", "resolved_revision", "resource_diffs", "truncated").
+		WherePK().Context(ctx).Update()
+	if err != nil {
+		return fmt.Errorf("error on updating DiffOperation status: %v, %v", err, obj.DiffOperation_id)
+	}
+
+	if result.RowsAffected() != 1 {
+		return NewResultNotFoundError(fmt.Sprintf("unexpected number of rows affected: %d, %v", result.RowsAffected(), obj.DiffOperation_id))
+	}
+
+	return nil
+}
+
+// DeleteDiffOperationsByApplicationId deletes all the DiffOperation rows of an Application.
+func (dbq *PostgreSQLDatabaseQueries) DeleteDiffOperationsByApplicationId(ctx context.Context, applicationId string) (int, error) {
+
+	if err := validateQueryParamsNoPK(dbq); err != nil {
+		return 0, err
+	}
+
+	if err := isEmptyValues("DeleteDiffOperationsByApplicationId",
+		"applicationId", applicationId); err != nil {
+		return 0, err
+	}
+
+	result := &DiffOperation{}
+
+	deleteResult, err := dbq.dbConnection.Model(result).
+		Where("dfo.application_id = ?", applicationId).
+		Context(ctx).
+		Delete()
+
+	if err != nil {
+		return 0, fmt.Errorf("error on deleting diff operations of application: %v", err)
+	}
+
+	return deleteResult.RowsAffected(), nil
+}
+
+func (dbq *PostgreSQLDatabaseQueries) UnsafeListAllDiffOperations(ctx context.Context, diffOperations *[]DiffOperation) error {
+
+	if err := validateUnsafeQueryParamsNoPK(dbq); err != nil {
+		return err
+	}
+
+	if err := dbq.dbConnection.Model(diffOperations).Context(ctx).Select(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var _ AppScopedDisposableResource = &DiffOperation{}
+
+func (obj *DiffOperation) DisposeAppScoped(ctx context.Context, dbq ApplicationScopedQueries) error {
+	if dbq == nil {
+		return fmt.Errorf("missing database interface in diffoperation dispose")
+	}
+
+	_, err := dbq.DeleteDiffOperationById(ctx, obj.DiffOperation_id)
+	return err
+}
@@ -0,0 +1,133 @@
+package db_test
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	db "github.com/redhat-appstudio/managed-gitops/backend-shared/db"
+)
+
+var _ = Describe("DiffOperation Tests", func() {
+	var ctx context.Context
+	var dbq db.AllDatabaseQueries
+	var application *db.Application
+
+	BeforeEach(func() {
+		err := db.SetupForTestingDBGinkgo()
+		Expect(err).ToNot(HaveOccurred())
+
+		ctx = context.Background()
+
+		dbq, err = db.NewUnsafePostgresDBQueries(true, true)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, managedEnvironment, _, gitopsEngineInstance, _, err := db.CreateSampleData(dbq)
+		Expect(err).ToNot(HaveOccurred())
+
+		application = &db.Application{
+			Application_id:          "test-my-application",
+			Name:                    "my-application",
+			Spec_field:              "{}",
+			Engine_instance_inst_id: gitopsEngineInstance.Gitopsengineinstance_id,
+			Managed_environment_id:  managedEnvironment.Managedenvironment_id,
+		}
+
+		err = dbq.CreateApplication(ctx, application)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		dbq.CloseDatabase()
+	})
+
+	Context("It should execute all DB functions for DiffOperation", func() {
+
+		It("Should create, get, update the status of, and delete a DiffOperation", func() {
+
+			diffOperation := &db.DiffOperation{
+				DiffOperation_id:    "test-diff-operation-1",
+				Application_id:      application.Application_id,
+				DeploymentNameField: "my-gitops-depl",
+				Revision:            "main",
+			}
+			err := dbq.CreateDiffOperation(ctx, diffOperation)
+			Expect(err).ToNot(HaveOccurred())
+
+			fetched := &db.DiffOperation{DiffOperation_id: diffOperation.DiffOperation_id}
+			err = dbq.GetDiffOperationById(ctx, fetched)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fetched.Revision).To(Equal("main"))
+			Expect(fetched.Phase).To(BeEmpty())
+
+			By("updating the status of the DiffOperation")
+			finishedAt := time.Date(2024, time.January, 1, 9, 30, 0, 0, time.UTC)
+			fetched.Phase = db.DiffOperation_Phase_Succeeded
+			fetched.StartedAt = finishedAt.Add(-time.Minute)
+			fetched.FinishedAt = finishedAt
+			fetched.ResolvedRevision = "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+			fetched.ResourceDiffs = `[{"kind":"ConfigMap","name":"my-config-map","status":"Modified"}]`
+			fetched.Truncated = true
+			err = dbq.UpdateDiffOperationStatus(ctx, fetched)
+			Expect(err).ToNot(HaveOccurred())
+
+			updated := &db.DiffOperation{DiffOperation_id: diffOperation.DiffOperation_id}
+			err = dbq.GetDiffOperationById(ctx, updated)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updated.Phase).To(Equal(db.DiffOperation_Phase_Succeeded))
+			Expect(updated.FinishedAt.Equal(finishedAt)).To(BeTrue())
+			Expect(updated.ResolvedRevision).To(Equal(fetched.ResolvedRevision))
+			Expect(updated.ResourceDiffs).To(Equal(fetched.ResourceDiffs))
+			Expect(updated.Truncated).To(BeTrue())
+
+			By("deleting the DiffOperation")
+			rowsAffected, err := dbq.DeleteDiffOperationById(ctx, diffOperation.DiffOperation_id)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rowsAffected).To(Equal(1))
+
+			err = dbq.GetDiffOperationById(ctx, updated)
+			Expect(db.IsResultNotFoundError(err)).To(BeTrue())
+
+			By("updating the status of a DiffOperation that no longer exists")
+			err = dbq.UpdateDiffOperationStatus(ctx, updated)
+			Expect(db.IsResultNotFoundError(err)).To(BeTrue())
+		})
+
+		It("Should delete all the DiffOperations of an Application", func() {
+
+			for _, id := range []string{"test-diff-operation-1", "test-diff-operation-2"} {
+				err := dbq.CreateDiffOperation(ctx, &db.DiffOperation{
+					DiffOperation_id:    id,
+					Application_id:      application.Application_id,
+					DeploymentNameField: "my-gitops-depl",
+					Revision:            "main",
+				})
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			rowsAffected, err := dbq.DeleteDiffOperationsByApplicationId(ctx, application.Application_id)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(rowsAffected).To(Equal(2))
+		})
+
+		It("Should return an error if required fields are missing or too long", func() {
+
+			diffOperation := &db.DiffOperation{
+				Application_id:      application.Application_id,
+				DeploymentNameField: "my-gitops-depl",
+			}
+			err := dbq.CreateDiffOperation(ctx, diffOperation)
+			Expect(err).To(HaveOccurred())
+
+			diffOperation = &db.DiffOperation{
+				Application_id:      application.Application_id,
+				DeploymentNameField: "my-gitops-depl",
+				Revision:            strings.Repeat("a", 257),
+			}
+			err = dbq.CreateDiffOperation(ctx, diffOperation)
+			Expect(db.IsMaxLengthError(err)).To(BeTrue())
+		})
+	})
+})
@@ -60,6 +60,8 @@ type UnsafeDatabaseQueries interface {
 	UnsafeListAllAppProjectRepositories(ctx context.Context, appRepositories *[]AppProjectRepository) error
 	UnsafeListAllAppProjectManagedEnvironments(ctx context.Context, appProjectManagedEnv *[]AppProjectManagedEnvironment) error
 	UnsafeListAllApplicationOwners(ctx context.Context, obj *[]ApplicationOwner) error
+	UnsafeListAllDeploymentHistory(ctx context.Context, deploymentHistory *[]DeploymentHistory) error
+	UnsafeListAllDiffOperations(ctx context.Context, diffOperations *[]DiffOperation) error
 }
 
 type AllDatabaseQueries interface {
@@ -239,8 +241,10 @@ type DatabaseQueries interface {
 // ApplicationScopedQueries are the set of database queries that act on application DB resources:
 // - Application
 // - ApplicateState
+// - DeploymentHistory
 // - Operation
 // - SyncOperation
+// - DiffOperation
 // - APICRToDatabaseMapping
 // - DeploymentToApplicationMapping
 //
@@ -278,6 +282,21 @@ type ApplicationScopedQueries interface {
 	DeleteSyncOperationById(ctx context.Context, id string) (int, error)
 	UpdateSyncOperation(ctx context.Context, obj *SyncOperation) error
 
+	// UpdateSyncOperationStatus updates only the status fields of the SyncOperation: returns a ResultNotFoundError
+	// if the SyncOperation no longer exists.
+	UpdateSyncOperationStatus(ctx context.Context, obj *SyncOperation) error
+
+	CreateDiffOperation(ctx context.Context, obj *DiffOperation) error
+	GetDiffOperationById(ctx context.Context, diffOperation *DiffOperation) error
+	DeleteDiffOperationById(ctx context.Context, id string) (int, error)
+
+	// UpdateDiffOperationStatus updates only the status fields of the DiffOperation: returns a ResultNotFoundError
+	// if the DiffOperation no longer exists.
+	UpdateDiffOperationStatus(ctx context.Context, obj *DiffOperation) error
+
+	// DeleteDiffOperationsByApplicationId deletes all the DiffOperation rows of an Application.
+	DeleteDiffOperationsByApplicationId(ctx context.Context, applicationId string) (int, error)
+
 	CreateApplication(ctx context.Context, obj *Application) error
 	CheckedCreateApplication(ctx context.Context, obj *Application, ownerId string) error
 	GetApplicationById(ctx context.Context, application *Application) error
@@ -320,6 +339,16 @@ type ApplicationScopedQueries interface {
 	UpdateApplicationState(ctx context.Context, obj *ApplicationState) error
 	DeleteApplicationStateById(ctx context.Context, id string) (int, error)
 
+	CreateDeploymentHistory(ctx context.Context, obj *DeploymentHistory) error
+	DeleteDeploymentHistoryById(ctx context.Context, id string) (int, error)
+
+	// ListDeploymentHistoryByApplicationId returns the DeploymentHistory rows of an Application, ordered from the oldest
+	// deployment to the most recent.
+	ListDeploymentHistoryByApplicationId(ctx context.Context, applicationId string, deploymentHistory *[]DeploymentHistory) error
+
+	// DeleteDeploymentHistoryByApplicationId deletes all the DeploymentHistory rows of an Application.
+	DeleteDeploymentHistoryByApplicationId(ctx context.Context, applicationId string) (int, error)
+
 	GetManagedEnvironmentById(ctx context.Context, managedEnvironment *ManagedEnvironment) error
 
 	GetGitopsEngineInstanceById(ctx context.Context, engineInstanceParam *GitopsEngineInstance) error
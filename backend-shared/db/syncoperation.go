@@ -9,6 +9,15 @@ import (
 const (
 	SyncOperation_DesiredState_Running    = "Running"
 	SyncOperation_DesiredState_Terminated = "Terminated"
+
+	SyncOperation_SyncStrategy_Apply = "apply"
+	SyncOperation_SyncStrategy_Hook  = "hook"
+
+	SyncOperation_Phase_Pending    = "Pending"
+	SyncOperation_Phase_Running    = "Running"
+	SyncOperation_Phase_Succeeded  = "Succeeded"
+	SyncOperation_Phase_Failed     = "Failed"
+	SyncOperation_Phase_Terminated = "Terminated"
 )
 
 func (dbq *PostgreSQLDatabaseQueries) GetSyncOperationById(ctx context.Context, syncOperation *SyncOperation) error {
@@ -141,6 +150,39 @@ func (dbq *PostgreSQLDatabaseQueries) UpdateSyncOperation(ctx context.Context, o
 	return nil
 }
 
+// UpdateSyncOperationStatus updates only the status fields of the SyncOperation (phase, phase message, start/finish time,
+// synced revision and resource results), so that the cluster-agent does not overwrite changes that the backend makes
+// to the other fields of the SyncOperation (for example, the desired state).
+func (dbq *PostgreSQLDatabaseQueries) UpdateSyncOperationStatus(ctx context.Context, obj *SyncOperation) error {
+
+	if err := validateQueryParamsEntity(obj, dbq); err != nil {
+		return err
+	}
+
+	if err := isEmptyValues("UpdateSyncOperationStatus",
+		"syncoperation_id", obj.SyncOperation_id,
+	); err != nil {
+		return err
+	}
+
+	if err := validateFieldLength(obj); err != nil {
+		return err
+	}
+
+	result, err := dbq.dbConnection.Model(obj).
+		Column("phase", "phase_message", "started_at", "finished_at", "synced_revision", "resource_results").
+		WherePK().Context(ctx).Update()
+	if err != nil {
+		return fmt.Errorf("error on updating SyncOperation status: %v, %v", err, obj.SyncOperation_id)
+	}
+
+	if result.RowsAffected() != 1 {
+		return NewResultNotFoundError(fmt.Sprintf("unexpected number of rows affected: %d, %v", result.RowsAffected(), obj.SyncOperation_id))
+	}
+
+	return nil
+}
+
 // UpdateSyncOperationRemoveApplicationField locates any SyncOperations that reference 'applicationID', and sets the
 // applicationID field to nil.
 func (dbq *PostgreSQLDatabaseQueries) UpdateSyncOperationRemoveApplicationField(ctx context.Context, applicationId string) (int, error) {
@@ -112,6 +112,85 @@ var _ = Describe("SyncOperation Tests", func() {
 
 		})
 
+		It("Should store the sync options of a SyncOperation", func() {
+			syncOperation := db.SyncOperation{
+				SyncOperation_id:    "test-sync-options",
+				Application_id:      application.Application_id,
+				DeploymentNameField: "testDeployment",
+				Revision:            "testRev",
+				DesiredState:        db.SyncOperation_DesiredState_Running,
+				Prune:               true,
+				DryRun:              true,
+				Force:               true,
+				SyncStrategy:        db.SyncOperation_SyncStrategy_Apply,
+				Resources:           `[{"kind":"ConfigMap","namespace":"my-namespace","name":"my-config"}]`,
+			}
+
+			err := dbq.CreateSyncOperation(ctx, &syncOperation)
+			Expect(err).ToNot(HaveOccurred())
+
+			fetchRow := db.SyncOperation{SyncOperation_id: syncOperation.SyncOperation_id}
+			err = dbq.GetSyncOperationById(ctx, &fetchRow)
+			Expect(err).ToNot(HaveOccurred())
+			fetchRow.Created_on = syncOperation.Created_on
+			Expect(fetchRow).Should(Equal(syncOperation))
+
+			By("verify that the sync options can be cleared")
+			syncOperation.Prune = false
+			syncOperation.SyncStrategy = ""
+			err = dbq.UpdateSyncOperation(ctx, &syncOperation)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = dbq.GetSyncOperationById(ctx, &fetchRow)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(fetchRow.Prune).To(BeFalse())
+			Expect(fetchRow.SyncStrategy).To(BeEmpty())
+
+			By("verify that the sync strategy is checked for its maximum length")
+			syncOperation.SyncOperation_id = "test-sync-options-invalid"
+			syncOperation.SyncStrategy = strings.Repeat("a", db.SyncOperationSyncStrategyLength+1)
+			err = dbq.CreateSyncOperation(ctx, &syncOperation)
+			Expect(db.IsMaxLengthError(err)).To(BeTrue())
+		})
+
+		It("Should only update the status fields of a SyncOperation, when updating its status", func() {
+			statusUpdate := db.SyncOperation{
+				SyncOperation_id: insertRow.SyncOperation_id,
+				Phase:            db.SyncOperation_Phase_Succeeded,
+				PhaseMessage:     "successfully synced",
+				StartedAt:        time.Now().Add(-time.Minute).Truncate(time.Microsecond),
+				FinishedAt:       time.Now().Truncate(time.Microsecond),
+				SyncedRevision:   "0a1b2c3d",
+				ResourceResults:  `[{"kind":"ConfigMap","namespace":"my-namespace","name":"my-config","status":"Synced"}]`,
+			}
+
+			err := dbq.UpdateSyncOperationStatus(ctx, &statusUpdate)
+			Expect(err).ToNot(HaveOccurred())
+
+			fetchRow := db.SyncOperation{SyncOperation_id: insertRow.SyncOperation_id}
+			err = dbq.GetSyncOperationById(ctx, &fetchRow)
+			Expect(err).ToNot(HaveOccurred())
+
+			By("verify that the status fields were updated")
+			Expect(fetchRow.Phase).To(Equal(statusUpdate.Phase))
+			Expect(fetchRow.PhaseMessage).To(Equal(statusUpdate.PhaseMessage))
+			Expect(fetchRow.StartedAt.Equal(statusUpdate.StartedAt)).To(BeTrue())
+			Expect(fetchRow.FinishedAt.Equal(statusUpdate.FinishedAt)).To(BeTrue())
+			Expect(fetchRow.SyncedRevision).To(Equal(statusUpdate.SyncedRevision))
+			Expect(fetchRow.ResourceResults).To(Equal(statusUpdate.ResourceResults))
+
+			By("verify that the other fields were not modified")
+			Expect(fetchRow.Application_id).To(Equal(insertRow.Application_id))
+			Expect(fetchRow.DeploymentNameField).To(Equal(insertRow.DeploymentNameField))
+			Expect(fetchRow.Revision).To(Equal(insertRow.Revision))
+			Expect(fetchRow.DesiredState).To(Equal(insertRow.DesiredState))
+
+			By("verify that a ResultNotFoundError is returned if the SyncOperation does not exist")
+			statusUpdate.SyncOperation_id = "does-not-exist"
+			err = dbq.UpdateSyncOperationStatus(ctx, &statusUpdate)
+			Expect(db.IsResultNotFoundError(err)).To(BeTrue())
+		})
+
 		It("Should Get SyncOperation in batch.", func() {
 			var testClusterUser = &db.ClusterUser{
 				Clusteruser_id: "test-user",
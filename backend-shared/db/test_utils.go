@@ -135,6 +135,34 @@ func SetupForTestingDBGinkgo() error {
 		}
 	}
 
+	var diffOperations []DiffOperation
+	err = dbq.UnsafeListAllDiffOperations(ctx, &diffOperations)
+	Expect(err).ToNot(HaveOccurred())
+
+	for _, diffOperation := range diffOperations {
+		if strings.HasPrefix(diffOperation.DiffOperation_id, "test-") || strings.HasPrefix(diffOperation.Application_id, "test-") {
+			rowsAffected, err := dbq.DeleteDiffOperationById(ctx, diffOperation.DiffOperation_id)
+			Expect(err).ToNot(HaveOccurred())
+			if err == nil {
+				Expect(rowsAffected).Should(Equal(1))
+			}
+		}
+	}
+
+	var deploymentHistory []DeploymentHistory
+	err = dbq.UnsafeListAllDeploymentHistory(ctx, &deploymentHistory)
+	Expect(err).ToNot(HaveOccurred())
+
+	for _, history := range deploymentHistory {
+		if strings.HasPrefix(history.DeploymentHistory_id, "test-") || strings.HasPrefix(history.Application_id, "test-") {
+			rowsAffected, err := dbq.DeleteDeploymentHistoryById(ctx, history.DeploymentHistory_id)
+			Expect(err).ToNot(HaveOccurred())
+			if err == nil {
+				Expect(rowsAffected).Should(Equal(1))
+			}
+		}
+	}
+
 	var applicationStates []ApplicationState
 	err = dbq.UnsafeListAllApplicationStates(ctx, &applicationStates)
 	Expect(err).ToNot(HaveOccurred())
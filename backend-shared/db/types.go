@@ -122,6 +122,19 @@ type ClusterCredentials struct {
 	// -- - This corresponds to the Argo CD cluster secret field of the same name.
 	ClusterResources bool `pg:"cluster_resources"`
 
+	// -- The PEM-encoded CA bundle that is used to verify the TLS certificate of the cluster (may be empty)
+	// -- - This corresponds to the 'tlsClientConfig.caData' field of the Argo CD cluster secret.
+	Ca_bundle string `pg:"ca_bundle"`
+
+	// -- The PEM-encoded client certificate/key that is used to authenticate with the cluster (may be empty, if a bearer token is used instead)
+	// -- - These correspond to the 'tlsClientConfig.certData'/'tlsClientConfig.keyData' fields of the Argo CD cluster secret.
+	Client_certificate_data string `pg:"client_certificate_data"`
+	Client_key_data         string `pg:"client_key_data"`
+
+	// -- The URL of the HTTP proxy that is used to connect to the cluster (may be empty)
+	// -- - This corresponds to the 'proxyUrl' field of the Argo CD cluster secret.
+	Proxy_url string `pg:"proxy_url"`
+
 	// -- Created_on field will tell us how old resources are
 	Created_on time.Time `pg:"created_on"`
 }
@@ -183,6 +196,7 @@ const (
 	OperationResourceType_Application           OperationResourceType = "Application"
 	OperationResourceType_RepositoryCredentials OperationResourceType = "RepositoryCredentials"
 	OperationResourceType_GitOpsEngineInstance  OperationResourceType = "GitOpsEngineInstance"
+	OperationResourceType_DiffOperation         OperationResourceType = "DiffOperation"
 )
 
 // Operation
@@ -283,6 +297,49 @@ type ApplicationState struct {
 	Applicationstate_application_id string `pg:"applicationstate_application_id,pk"`
 
 	ArgoCD_Application_Status []byte `pg:"argocd_application_status"`
+
+	// Resource_tree contains the (compressed) resource tree and recent warning events of the Argo CD Application, if collected
+	Resource_tree []byte `pg:"resource_tree"`
+}
+
+// DeploymentHistory is a record of a previous deployment (sync) of an Application, based on the revision history
+// of the Argo CD Application. Unlike the Argo CD Application, this is preserved if the Argo CD Application is recreated.
+type DeploymentHistory struct {
+
+	//lint:ignore U1000 used by go-pg
+	tableName struct{} `pg:"deploymenthistory,alias:dh"` //nolint
+
+	DeploymentHistory_id string `pg:"deploymenthistory_id,pk"`
+
+	// -- Foreign key to Application.application_id
+	Application_id string `pg:"application_id"`
+
+	// Revision is the revision (for example, the Git commit SHA) that was deployed
+	Revision string `pg:"revision"`
+
+	// Source is the JSON representation of the source of the Application that was deployed
+	Source string `pg:"source"`
+
+	// Sources is the JSON representation of the sources of the Application that was deployed, for an Application with
+	// multiple sources
+	Sources string `pg:"sources"`
+
+	// Revisions is the JSON representation of the revisions that were deployed (one for each of the sources), for an
+	// Application with multiple sources
+	Revisions string `pg:"revisions"`
+
+	// DeployedAt is when the deployment completed
+	DeployedAt time.Time `pg:"deployed_at"`
+
+	// InitiatedBy is the name of the user that initiated the deployment, or 'automated' for an automated sync
+	InitiatedBy string `pg:"initiated_by"`
+
+	// SyncRunName is the name of the GitOpsDeploymentSyncRun that triggered the deployment, if any
+	SyncRunName string `pg:"sync_run_name"`
+
+	SeqID int64 `pg:"seq_id"`
+
+	Created_on time.Time `pg:"created_on"`
 }
 
 // DeploymentToApplicationMapping represents relationship from GitOpsDeployment CR in the namespace, to an Application table row
@@ -325,6 +382,7 @@ const (
 	APICRToDatabaseMapping_ResourceType_GitOpsDeploymentManagedEnvironment   APICRToDatabaseMapping_ResourceType = "GitOpsDeploymentManagedEnvironment"
 	APICRToDatabaseMapping_ResourceType_GitOpsDeploymentSyncRun              APICRToDatabaseMapping_ResourceType = "GitOpsDeploymentSyncRun"
 	APICRToDatabaseMapping_ResourceType_GitOpsDeploymentRepositoryCredential APICRToDatabaseMapping_ResourceType = "GitOpsDeploymentRepositoryCredential"
+	APICRToDatabaseMapping_ResourceType_GitOpsDeploymentDiff                 APICRToDatabaseMapping_ResourceType = "GitOpsDeploymentDiff"
 )
 
 // APICRToDatabaseMapping_DBRelationType: see 'db-schema.sql' for a description of these values.
@@ -334,6 +392,7 @@ const (
 	APICRToDatabaseMapping_DBRelationType_ManagedEnvironment   APICRToDatabaseMapping_DBRelationType = "ManagedEnvironment"
 	APICRToDatabaseMapping_DBRelationType_SyncOperation        APICRToDatabaseMapping_DBRelationType = "SyncOperation"
 	APICRToDatabaseMapping_DBRelationType_RepositoryCredential APICRToDatabaseMapping_DBRelationType = "RepositoryCredential"
+	APICRToDatabaseMapping_DBRelationType_DiffOperation        APICRToDatabaseMapping_DBRelationType = "DiffOperation"
 )
 
 // APICRToDatabaseMapping maps API custom resources on the workspace (such as GitOpsDeploymentSyncRun), to a corresponding entry in the database.
@@ -410,6 +469,83 @@ type SyncOperation struct {
 
 	DesiredState string `pg:"desired_state"`
 
+	// SyncWindows is the JSON representation of the sync windows of the GitOpsDeployment, at the time the SyncOperation was created.
+	// The sync will only be started while the sync windows allow it.
+	SyncWindows string `pg:"sync_windows"`
+
+	// Prune, DryRun, Force and SyncStrategy correspond to the fields of the same name in the GitOpsDeploymentSyncRun
+	Prune bool `pg:"prune"`
+
+	DryRun bool `pg:"dry_run"`
+
+	Force bool `pg:"force"`
+
+	// SyncStrategy is one of SyncOperation_SyncStrategy_*, or empty (equivalent to hook)
+	SyncStrategy string `pg:"sync_strategy"`
+
+	// Resources is the JSON representation of the resources to sync: if empty, all resources of the Application are synced.
+	Resources string `pg:"resources"`
+
+	// The fields below are the status of the sync, as reported by the cluster-agent via UpdateSyncOperationStatus.
+
+	// Phase is one of SyncOperation_Phase_*, or empty (equivalent to Pending)
+	Phase string `pg:"phase"`
+
+	PhaseMessage string `pg:"phase_message"`
+
+	// StartedAt and FinishedAt are zero if the sync has not yet started/finished
+	StartedAt time.Time `pg:"started_at"`
+
+	FinishedAt time.Time `pg:"finished_at"`
+
+	SyncedRevision string `pg:"synced_revision"`
+
+	// ResourceResults is the JSON representation of the result of the sync of each resource
+	ResourceResults string `pg:"resource_results"`
+
+	Created_on time.Time `pg:"created_on"`
+}
+
+// DiffOperation tracks a diff request from the API (a GitOpsDeploymentDiff CR): the cluster-agent renders the manifests
+// of the Application at a candidate revision, via the Argo CD repo server, and compares them against the live state.
+type DiffOperation struct {
+
+	//lint:ignore U1000 used by go-pg
+	tableName struct{} `pg:"diffoperation,alias:dfo"` //nolint
+
+	DiffOperation_id string `pg:"diffoperation_id,pk"`
+
+	// -- Foreign key to Application.application_id
+	Application_id string `pg:"application_id"`
+
+	DeploymentNameField string `pg:"deployment_name"`
+
+	// Revision is the candidate revision to render the manifests from, as specified in the GitOpsDeploymentDiff
+	Revision string `pg:"revision"`
+
+	// The fields below are the status of the diff, as reported by the cluster-agent via UpdateDiffOperationStatus.
+
+	// Phase is one of DiffOperation_Phase_*, or empty (equivalent to Pending)
+	Phase string `pg:"phase"`
+
+	PhaseMessage string `pg:"phase_message"`
+
+	// StartedAt and FinishedAt are zero if the diff has not yet started/finished
+	StartedAt time.Time `pg:"started_at"`
+
+	FinishedAt time.Time `pg:"finished_at"`
+
+	// ResolvedRevision is the revision (for example, the Git commit SHA) that the manifests were rendered from
+	ResolvedRevision string `pg:"resolved_revision"`
+
+	// ResourceDiffs is the JSON representation of the diff of each changed resource
+	ResourceDiffs string `pg:"resource_diffs"`
+
+	// Truncated is true if some of the changed resources were omitted from ResourceDiffs
+	Truncated bool `pg:"truncated"`
+
+	SeqID int64 `pg:"seq_id"`
+
 	Created_on time.Time `pg:"created_on"`
 }
 
@@ -47,6 +47,14 @@ var _ = Describe("Types Test", func() {
 			err = dbq.UnsafeListAllApplications(ctx, &applications)
 			Expect(err).ToNot(HaveOccurred())
 
+			var deploymentHistory []db.DeploymentHistory
+			err = dbq.UnsafeListAllDeploymentHistory(ctx, &deploymentHistory)
+			Expect(err).ToNot(HaveOccurred())
+
+			var diffOperations []db.DiffOperation
+			err = dbq.UnsafeListAllDiffOperations(ctx, &diffOperations)
+			Expect(err).ToNot(HaveOccurred())
+
 			var clusterAccess []db.ClusterAccess
 			err = dbq.UnsafeListAllClusterAccess(ctx, &clusterAccess)
 			Expect(err).ToNot(HaveOccurred())
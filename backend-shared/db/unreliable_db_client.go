@@ -143,6 +143,16 @@ func (cdb *ChaosDBClient) UpdateSyncOperation(ctx context.Context, obj *SyncOper
 
 }
 
+func (cdb *ChaosDBClient) UpdateSyncOperationStatus(ctx context.Context, obj *SyncOperation) error {
+
+	if err := shouldSimulateFailure("UpdateSyncOperationStatus", obj); err != nil {
+		return err
+	}
+
+	return cdb.InnerClient.UpdateSyncOperationStatus(ctx, obj)
+
+}
+
 func (cdb *ChaosDBClient) GetSyncOperationsBatch(ctx context.Context, syncOperations *[]SyncOperation, limit, offSet int) error {
 
 	if err := shouldSimulateFailure("GetSyncOperationsBatch", syncOperations, limit, offSet); err != nil {
@@ -375,6 +385,96 @@ func (cdb *ChaosDBClient) DeleteApplicationStateById(ctx context.Context, id str
 
 }
 
+func (cdb *ChaosDBClient) CreateDeploymentHistory(ctx context.Context, obj *DeploymentHistory) error {
+
+	if err := shouldSimulateFailure("CreateDeploymentHistory", obj); err != nil {
+		return err
+	}
+
+	return cdb.InnerClient.CreateDeploymentHistory(ctx, obj)
+
+}
+
+func (cdb *ChaosDBClient) DeleteDeploymentHistoryById(ctx context.Context, id string) (int, error) {
+
+	if err := shouldSimulateFailure("DeleteDeploymentHistoryById", id); err != nil {
+		return 0, err
+	}
+
+	return cdb.InnerClient.DeleteDeploymentHistoryById(ctx, id)
+
+}
+
+func (cdb *ChaosDBClient) ListDeploymentHistoryByApplicationId(ctx context.Context, applicationId string, deploymentHistory *[]DeploymentHistory) error {
+
+	if err := shouldSimulateFailure("ListDeploymentHistoryByApplicationId", applicationId, deploymentHistory); err != nil {
+		return err
+	}
+
+	return cdb.InnerClient.ListDeploymentHistoryByApplicationId(ctx, applicationId, deploymentHistory)
+
+}
+
+func (cdb *ChaosDBClient) DeleteDeploymentHistoryByApplicationId(ctx context.Context, applicationId string) (int, error) {
+
+	if err := shouldSimulateFailure("DeleteDeploymentHistoryByApplicationId", applicationId); err != nil {
+		return 0, err
+	}
+
+	return cdb.InnerClient.DeleteDeploymentHistoryByApplicationId(ctx, applicationId)
+
+}
+
+func (cdb *ChaosDBClient) CreateDiffOperation(ctx context.Context, obj *DiffOperation) error {
+
+	if err := shouldSimulateFailure("CreateDiffOperation", obj); err != nil {
+		return err
+	}
+
+	return cdb.InnerClient.CreateDiffOperation(ctx, obj)
+
+}
+
+func (cdb *ChaosDBClient) GetDiffOperationById(ctx context.Context, diffOperation *DiffOperation) error {
+
+	if err := shouldSimulateFailure("GetDiffOperationById", diffOperation); err != nil {
+		return err
+	}
+
+	return cdb.InnerClient.GetDiffOperationById(ctx, diffOperation)
+
+}
+
+func (cdb *ChaosDBClient) DeleteDiffOperationById(ctx context.Context, id string) (int, error) {
+
+	if err := shouldSimulateFailure("DeleteDiffOperationById", id); err != nil {
+		return 0, err
+	}
+
+	return cdb.InnerClient.DeleteDiffOperationById(ctx, id)
+
+}
+
+func (cdb *ChaosDBClient) UpdateDiffOperationStatus(ctx context.Context, obj *DiffOperation) error {
+
+	if err := shouldSimulateFailure("UpdateDiffOperationStatus", obj); err != nil {
+		return err
+	}
+
+	return cdb.InnerClient.UpdateDiffOperationStatus(ctx, obj)
+
+}
+
+func (cdb *ChaosDBClient) DeleteDiffOperationsByApplicationId(ctx context.Context, applicationId string) (int, error) {
+
+	if err := shouldSimulateFailure("DeleteDiffOperationsByApplicationId", applicationId); err != nil {
+		return 0, err
+	}
+
+	return cdb.InnerClient.DeleteDiffOperationsByApplicationId(ctx, applicationId)
+
+}
+
 func (cdb *ChaosDBClient) GetManagedEnvironmentById(ctx context.Context, managedEnvironment *ManagedEnvironment) error {
 
 	if err := shouldSimulateFailure("GetManagedEnvironmentById", managedEnvironment); err != nil {
@@ -70,10 +70,17 @@ func ConvertArgoCDClusterSecretNameToManagedIdDatabaseRowId(argoCDClusterSecretN
 
 type ClusterSecretTLSClientConfigJSON struct {
 	Insecure bool `json:"insecure"`
+	// CAData is the PEM-encoded CA bundle of the cluster (which is base64-encoded when marshalled to JSON)
+	CAData []byte `json:"caData,omitempty"`
+	// CertData/KeyData are the PEM-encoded client certificate/key, used to authenticate with the cluster instead of a bearer token
+	CertData []byte `json:"certData,omitempty"`
+	KeyData  []byte `json:"keyData,omitempty"`
 }
 type ClusterSecretConfigJSON struct {
 	BearerToken     string                           `json:"bearerToken"`
 	TLSClientConfig ClusterSecretTLSClientConfigJSON `json:"tlsClientConfig"`
+	// ProxyUrl is the URL of the HTTP(S) proxy that Argo CD uses to connect to the cluster (if any)
+	ProxyUrl string `json:"proxyUrl,omitempty"`
 }
 
 func GetArgoCDApplicationName(labels map[string]string) string {
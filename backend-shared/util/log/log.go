@@ -28,6 +28,7 @@ const (
 	Log_Component_Backend_ClusterReconciler          = "cluster-reconciler"
 	Log_Component_Backend_DatabaseMetricsReconciler  = "database-metrics-reconciler"
 	Log_Component_Backend_DatabaseReconciler         = "database-reconciler"
+	Log_Component_Backend_ManagedEnvProber           = "managed-environment-prober"
 	Log_Component_Backend_RepocredReconciler         = "repocred-reconciler" // #nosec G101
 	Log_Component_Backend_WorkspaceResourceEventLoop = "workspace_resource_event_loop"
 
@@ -462,6 +462,20 @@ func (mr *MockDatabaseQueriesMockRecorder) CreateClusterUser(arg0, arg1 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateClusterUser", reflect.TypeOf((*MockDatabaseQueries)(nil).CreateClusterUser), arg0, arg1)
 }
 
+// CreateDeploymentHistory mocks base method.
+func (m *MockDatabaseQueries) CreateDeploymentHistory(arg0 context.Context, arg1 *db.DeploymentHistory) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDeploymentHistory", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDeploymentHistory indicates an expected call of CreateDeploymentHistory.
+func (mr *MockDatabaseQueriesMockRecorder) CreateDeploymentHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDeploymentHistory", reflect.TypeOf((*MockDatabaseQueries)(nil).CreateDeploymentHistory), arg0, arg1)
+}
+
 // CreateDeploymentToApplicationMapping mocks base method.
 func (m *MockDatabaseQueries) CreateDeploymentToApplicationMapping(arg0 context.Context, arg1 *db.DeploymentToApplicationMapping) error {
 	m.ctrl.T.Helper()
@@ -476,6 +490,20 @@ func (mr *MockDatabaseQueriesMockRecorder) CreateDeploymentToApplicationMapping(
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDeploymentToApplicationMapping", reflect.TypeOf((*MockDatabaseQueries)(nil).CreateDeploymentToApplicationMapping), arg0, arg1)
 }
 
+// CreateDiffOperation mocks base method.
+func (m *MockDatabaseQueries) CreateDiffOperation(arg0 context.Context, arg1 *db.DiffOperation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDiffOperation", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateDiffOperation indicates an expected call of CreateDiffOperation.
+func (mr *MockDatabaseQueriesMockRecorder) CreateDiffOperation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDiffOperation", reflect.TypeOf((*MockDatabaseQueries)(nil).CreateDiffOperation), arg0, arg1)
+}
+
 // CreateGitopsEngineCluster mocks base method.
 func (m *MockDatabaseQueries) CreateGitopsEngineCluster(arg0 context.Context, arg1 *db.GitopsEngineCluster) error {
 	m.ctrl.T.Helper()
@@ -724,6 +752,36 @@ func (mr *MockDatabaseQueriesMockRecorder) DeleteClusterUserById(arg0, arg1 inte
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteClusterUserById", reflect.TypeOf((*MockDatabaseQueries)(nil).DeleteClusterUserById), arg0, arg1)
 }
 
+// DeleteDeploymentHistoryByApplicationId mocks base method.
+func (m *MockDatabaseQueries) DeleteDeploymentHistoryByApplicationId(arg0 context.Context, arg1 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDeploymentHistoryByApplicationId", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDeploymentHistoryByApplicationId indicates an expected call of DeleteDeploymentHistoryByApplicationId.
+func (mr *MockDatabaseQueriesMockRecorder) DeleteDeploymentHistoryByApplicationId(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeploymentHistoryByApplicationId", reflect.TypeOf((*MockDatabaseQueries)(nil).DeleteDeploymentHistoryByApplicationId), arg0, arg1)
+}
+
+// DeleteDeploymentHistoryById mocks base method.
+func (m *MockDatabaseQueries) DeleteDeploymentHistoryById(arg0 context.Context, arg1 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDeploymentHistoryById", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDeploymentHistoryById indicates an expected call of DeleteDeploymentHistoryById.
+func (mr *MockDatabaseQueriesMockRecorder) DeleteDeploymentHistoryById(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeploymentHistoryById", reflect.TypeOf((*MockDatabaseQueries)(nil).DeleteDeploymentHistoryById), arg0, arg1)
+}
+
 // DeleteDeploymentToApplicationMappingByDeplId mocks base method.
 func (m *MockDatabaseQueries) DeleteDeploymentToApplicationMappingByDeplId(arg0 context.Context, arg1 string) (int, error) {
 	m.ctrl.T.Helper()
@@ -754,6 +812,36 @@ func (mr *MockDatabaseQueriesMockRecorder) DeleteDeploymentToApplicationMappingB
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDeploymentToApplicationMappingByNamespaceAndName", reflect.TypeOf((*MockDatabaseQueries)(nil).DeleteDeploymentToApplicationMappingByNamespaceAndName), arg0, arg1, arg2, arg3)
 }
 
+// DeleteDiffOperationById mocks base method.
+func (m *MockDatabaseQueries) DeleteDiffOperationById(arg0 context.Context, arg1 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDiffOperationById", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDiffOperationById indicates an expected call of DeleteDiffOperationById.
+func (mr *MockDatabaseQueriesMockRecorder) DeleteDiffOperationById(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDiffOperationById", reflect.TypeOf((*MockDatabaseQueries)(nil).DeleteDiffOperationById), arg0, arg1)
+}
+
+// DeleteDiffOperationsByApplicationId mocks base method.
+func (m *MockDatabaseQueries) DeleteDiffOperationsByApplicationId(arg0 context.Context, arg1 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDiffOperationsByApplicationId", arg0, arg1)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDiffOperationsByApplicationId indicates an expected call of DeleteDiffOperationsByApplicationId.
+func (mr *MockDatabaseQueriesMockRecorder) DeleteDiffOperationsByApplicationId(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDiffOperationsByApplicationId", reflect.TypeOf((*MockDatabaseQueries)(nil).DeleteDiffOperationsByApplicationId), arg0, arg1)
+}
+
 // DeleteGitopsEngineClusterById mocks base method.
 func (m *MockDatabaseQueries) DeleteGitopsEngineClusterById(arg0 context.Context, arg1 string) (int, error) {
 	m.ctrl.T.Helper()
@@ -1139,6 +1227,20 @@ func (mr *MockDatabaseQueriesMockRecorder) GetDeploymentToApplicationMappingByDe
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeploymentToApplicationMappingByDeplId", reflect.TypeOf((*MockDatabaseQueries)(nil).GetDeploymentToApplicationMappingByDeplId), arg0, arg1)
 }
 
+// GetDiffOperationById mocks base method.
+func (m *MockDatabaseQueries) GetDiffOperationById(arg0 context.Context, arg1 *db.DiffOperation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDiffOperationById", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// GetDiffOperationById indicates an expected call of GetDiffOperationById.
+func (mr *MockDatabaseQueriesMockRecorder) GetDiffOperationById(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDiffOperationById", reflect.TypeOf((*MockDatabaseQueries)(nil).GetDiffOperationById), arg0, arg1)
+}
+
 // GetGitopsEngineClusterBatch mocks base method.
 func (m *MockDatabaseQueries) GetGitopsEngineClusterBatch(arg0 context.Context, arg1 *[]db.GitopsEngineCluster, arg2, arg3 int) error {
 	m.ctrl.T.Helper()
@@ -1407,6 +1509,20 @@ func (mr *MockDatabaseQueriesMockRecorder) ListClusterAccessesByManagedEnvironme
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListClusterAccessesByManagedEnvironmentID", reflect.TypeOf((*MockDatabaseQueries)(nil).ListClusterAccessesByManagedEnvironmentID), arg0, arg1, arg2)
 }
 
+// ListDeploymentHistoryByApplicationId mocks base method.
+func (m *MockDatabaseQueries) ListDeploymentHistoryByApplicationId(arg0 context.Context, arg1 string, arg2 *[]db.DeploymentHistory) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeploymentHistoryByApplicationId", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ListDeploymentHistoryByApplicationId indicates an expected call of ListDeploymentHistoryByApplicationId.
+func (mr *MockDatabaseQueriesMockRecorder) ListDeploymentHistoryByApplicationId(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeploymentHistoryByApplicationId", reflect.TypeOf((*MockDatabaseQueries)(nil).ListDeploymentHistoryByApplicationId), arg0, arg1, arg2)
+}
+
 // ListDeploymentToApplicationMappingByNamespaceAndName mocks base method.
 func (m *MockDatabaseQueries) ListDeploymentToApplicationMappingByNamespaceAndName(arg0 context.Context, arg1, arg2, arg3 string, arg4 *[]db.DeploymentToApplicationMapping) error {
 	m.ctrl.T.Helper()
@@ -1562,6 +1678,20 @@ func (mr *MockDatabaseQueriesMockRecorder) UpdateClusterUser(arg0, arg1 interfac
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateClusterUser", reflect.TypeOf((*MockDatabaseQueries)(nil).UpdateClusterUser), arg0, arg1)
 }
 
+// UpdateDiffOperationStatus mocks base method.
+func (m *MockDatabaseQueries) UpdateDiffOperationStatus(arg0 context.Context, arg1 *db.DiffOperation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDiffOperationStatus", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDiffOperationStatus indicates an expected call of UpdateDiffOperationStatus.
+func (mr *MockDatabaseQueriesMockRecorder) UpdateDiffOperationStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDiffOperationStatus", reflect.TypeOf((*MockDatabaseQueries)(nil).UpdateDiffOperationStatus), arg0, arg1)
+}
+
 // UpdateKubernetesResourceUIDForKubernetesToDBResourceMapping mocks base method.
 func (m *MockDatabaseQueries) UpdateKubernetesResourceUIDForKubernetesToDBResourceMapping(arg0 context.Context, arg1 *db.KubernetesToDBResourceMapping) error {
 	m.ctrl.T.Helper()
@@ -1646,3 +1776,17 @@ func (mr *MockDatabaseQueriesMockRecorder) UpdateSyncOperationRemoveApplicationF
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSyncOperationRemoveApplicationField", reflect.TypeOf((*MockDatabaseQueries)(nil).UpdateSyncOperationRemoveApplicationField), arg0, arg1)
 }
+
+// UpdateSyncOperationStatus mocks base method.
+func (m *MockDatabaseQueries) UpdateSyncOperationStatus(arg0 context.Context, arg1 *db.SyncOperation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSyncOperationStatus", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSyncOperationStatus indicates an expected call of UpdateSyncOperationStatus.
+func (mr *MockDatabaseQueriesMockRecorder) UpdateSyncOperationStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSyncOperationStatus", reflect.TypeOf((*MockDatabaseQueries)(nil).UpdateSyncOperationStatus), arg0, arg1)
+}
@@ -121,6 +121,73 @@ func getOrCreateServiceAccountBearerToken(ctx context.Context, k8sClient client.
 
 }
 
+// DeleteStaleServiceAccountTokenSecrets deletes the token Secrets of the ServiceAccount that was installed (via InstallServiceAccount)
+// for the given uuid, other than the Secret containing 'tokenInUse'. This is used to revoke the previous tokens of the
+// ServiceAccount, once its token has been rotated.
+func DeleteStaleServiceAccountTokenSecrets(ctx context.Context, k8sClient client.Client, uuid string, serviceAccountNS string,
+	tokenInUse string, log logr.Logger) error {
+
+	staleSecrets, err := GetStaleServiceAccountTokenSecrets(ctx, k8sClient, uuid, serviceAccountNS, tokenInUse)
+	if err != nil {
+		return err
+	}
+
+	serviceAccountName := GenerateServiceAccountName(uuid)
+
+	for idx := range staleSecrets {
+		secret := staleSecrets[idx]
+
+		log := log.WithValues("tokenSecretName", secret.Name, "tokenSecretNamespace", secret.Namespace)
+
+		if err := k8sClient.Delete(ctx, &secret); err != nil {
+			if apierr.IsNotFound(err) {
+				continue
+			}
+			log.Error(err, "Unable to delete stale ServiceAccountToken Secret")
+			return fmt.Errorf("unable to delete token secret '%s' of service account '%s': %w", secret.Name, serviceAccountName, err)
+		}
+		logutil.LogAPIResourceChangeEvent(secret.Namespace, secret.Name, secret, logutil.ResourceDeleted, log)
+	}
+
+	return nil
+}
+
+// GetStaleServiceAccountTokenSecrets returns the token Secrets of the ServiceAccount that was installed (via InstallServiceAccount)
+// for the given uuid, other than the Secret containing 'tokenInUse'.
+func GetStaleServiceAccountTokenSecrets(ctx context.Context, k8sClient client.Client, uuid string, serviceAccountNS string,
+	tokenInUse string) ([]corev1.Secret, error) {
+
+	if tokenInUse == "" {
+		// Sanity test the token, otherwise all the tokens of the ServiceAccount would be stale
+		return nil, fmt.Errorf("the token in use by the service account must not be empty")
+	}
+
+	serviceAccountName := GenerateServiceAccountName(uuid)
+
+	secrets := &corev1.SecretList{}
+	if err := k8sClient.List(ctx, secrets, client.InNamespace(serviceAccountNS)); err != nil {
+		return nil, fmt.Errorf("failed to retrieve secrets in namespace: %s: %w", serviceAccountNS, err)
+	}
+
+	var res []corev1.Secret
+
+	for idx := range secrets.Items {
+		secret := secrets.Items[idx]
+
+		if secret.Type != corev1.SecretTypeServiceAccountToken || secret.Annotations[corev1.ServiceAccountNameKey] != serviceAccountName {
+			continue
+		}
+
+		if string(secret.Data["token"]) == tokenInUse {
+			continue
+		}
+
+		res = append(res, secret)
+	}
+
+	return res, nil
+}
+
 func getServiceAccountTokenSecret(ctx context.Context, k8sClient client.Client, serviceAccount *corev1.ServiceAccount) (*corev1.Secret, error) {
 	secrets := &corev1.SecretList{}
 	ns := serviceAccount.Namespace
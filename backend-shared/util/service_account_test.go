@@ -8,10 +8,12 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -178,4 +180,68 @@ var _ = Describe("Service Account Tests", func() {
 			})
 		})
 	})
+
+	Context("Test DeleteStaleServiceAccountTokenSecrets function", func() {
+
+		ctx := context.Background()
+		log := log.FromContext(ctx)
+
+		const (
+			uuid             = "my-uuid"
+			serviceAccountNS = "kube-system"
+		)
+
+		newTokenSecret := func(name string, serviceAccountName string, token string) *corev1.Secret {
+			return &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      name,
+					Namespace: serviceAccountNS,
+					Annotations: map[string]string{
+						corev1.ServiceAccountNameKey: serviceAccountName,
+					},
+				},
+				Type: corev1.SecretTypeServiceAccountToken,
+				Data: map[string][]byte{
+					"token": []byte(token),
+				},
+			}
+		}
+
+		It("should delete the token secrets of the service account, other than the secret containing the token in use", func() {
+
+			serviceAccountName := GenerateServiceAccountName(uuid)
+
+			oldTokenSecret := newTokenSecret("old-token-secret", serviceAccountName, "old-token")
+			currentTokenSecret := newTokenSecret("current-token-secret", serviceAccountName, "current-token")
+			otherServiceAccountTokenSecret := newTokenSecret("other-token-secret", GenerateServiceAccountName("other-uuid"), "other-token")
+
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).
+				WithObjects(oldTokenSecret, currentTokenSecret, otherServiceAccountTokenSecret).Build()
+
+			err := DeleteStaleServiceAccountTokenSecrets(ctx, k8sClient, uuid, serviceAccountNS, "current-token", log)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(oldTokenSecret), oldTokenSecret)
+			Expect(apierr.IsNotFound(err)).To(BeTrue(), "the old token secret should have been deleted")
+
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(currentTokenSecret), currentTokenSecret)
+			Expect(err).ToNot(HaveOccurred(), "the token secret in use should not have been deleted")
+
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(otherServiceAccountTokenSecret), otherServiceAccountTokenSecret)
+			Expect(err).ToNot(HaveOccurred(), "the token secret of another service account should not have been deleted")
+		})
+
+		It("should return an error, and not delete any secrets, if the token in use is empty", func() {
+
+			oldTokenSecret := newTokenSecret("old-token-secret", GenerateServiceAccountName(uuid), "old-token")
+
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(oldTokenSecret).Build()
+
+			err := DeleteStaleServiceAccountTokenSecrets(ctx, k8sClient, uuid, serviceAccountNS, "", log)
+			Expect(err).To(HaveOccurred())
+
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(oldTokenSecret), oldTokenSecret)
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
 })
@@ -22,6 +22,19 @@ const (
 	ArgoCDDefaultDestinationInCluster = "in-cluster"
 
 	SelfHealIntervalEnVar = "SELF_HEAL_INTERVAL" // Interval in minutes between self-healing runs
+
+	// Interval in minutes after which the token of a ServiceAccount created by the GitOps Service (via a managed environment's
+	// '.spec.createNewServiceAccount' field) is rotated. Tokens are not rotated if unset, or 0.
+	ServiceAccountTokenRotationIntervalEnvVar = "SERVICE_ACCOUNT_TOKEN_ROTATION_INTERVAL" // #nosec G101
+
+	// Set to 'true' for the cluster-agent to collect the resource tree of each GitOpsDeployment from Argo CD. The resource
+	// tree is not collected if unset.
+	ResourceTreeCollectionEnvVar = "ENABLE_RESOURCE_TREE_COLLECTION"
+
+	// Comma-separated list of CIDRs, IP addresses and host names that the backend may send the notifications of a
+	// GitOpsDeploymentNotificationTarget to, even though they are loopback, link-local or private addresses (which are
+	// otherwise blocked). For example, '10.0.0.0/8,webhook-receiver.my-namespace.svc'.
+	NotificationAllowedAddressesEnvVar = "NOTIFICATION_ALLOWED_ADDRESSES"
 )
 
 const (
@@ -34,6 +47,9 @@ const (
 
 	ManagedEnvironmentSecretType = "managed-gitops.redhat.com/managed-environment"
 
+	// Secret type of a managed environment Secret containing a 'server', 'token' and (optional) 'ca.crt' field, rather than a kubeconfig
+	ManagedEnvironmentTokenSecretType = "managed-gitops.redhat.com/managed-environment-token" // #nosec G101
+
 	RepositoryCredentialSecretType = "managed-gitops.redhat.com/repository-credential"
 
 	Log_JobKey      = "job"                    // Clean up job key
@@ -184,6 +200,28 @@ func SelfHealInterval(defaultValue time.Duration, logger logr.Logger) time.Durat
 	return time.Duration(value) * time.Minute
 }
 
+// ServiceAccountTokenRotationInterval returns the interval after which the token of a ServiceAccount, created by the GitOps
+// Service on a managed environment cluster, should be rotated. Returns 0 if tokens should not be rotated.
+func ServiceAccountTokenRotationInterval(logger logr.Logger) time.Duration {
+	interval := os.Getenv(ServiceAccountTokenRotationIntervalEnvVar)
+	if interval == "" {
+		return 0
+	}
+	value, err := strconv.Atoi(interval)
+	if err != nil || value < 0 {
+		msg := fmt.Sprintf("value of env var %s can't be converted to a non-negative int", ServiceAccountTokenRotationIntervalEnvVar)
+		logger.Error(err, msg)
+		return 0
+	}
+	return time.Duration(value) * time.Minute
+}
+
+// ResourceTreeCollectionEnabled is a feature flag for collecting the resource tree of GitOpsDeployments: it is disabled
+// by default, as each collection is an additional request to the Argo CD API server.
+func ResourceTreeCollectionEnabled() bool {
+	return strings.EqualFold(os.Getenv(ResourceTreeCollectionEnvVar), "true")
+}
+
 // AppProjectIsolationEnabled is a feature flag for AppProject-based isolation. To enable it, set the environment variable on the controllers.
 func AppProjectIsolationEnabled() bool {
 
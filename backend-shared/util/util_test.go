@@ -50,4 +50,45 @@ var _ = Describe("General Util Unit Tests", func() {
 			})
 		})
 	})
+
+	Context("Testing the ServiceAccountTokenRotationInterval() function", func() {
+		var logger logr.Logger
+
+		BeforeEach(func() {
+			logger = log.FromContext(context.Background())
+		})
+
+		DescribeTable("should return the rotation interval based on the value of the SERVICE_ACCOUNT_TOKEN_ROTATION_INTERVAL environment variable",
+			func(value string, expected time.Duration) {
+				defer os.Unsetenv(ServiceAccountTokenRotationIntervalEnvVar)
+
+				if value != "" {
+					os.Setenv(ServiceAccountTokenRotationIntervalEnvVar, value)
+				}
+				Expect(ServiceAccountTokenRotationInterval(logger)).To(Equal(expected))
+			},
+			Entry("not set, so tokens are not rotated", "", time.Duration(0)),
+			Entry("set to a number of minutes", "1440", time.Duration(1440)*time.Minute),
+			Entry("set to something non-numeric, so tokens are not rotated", "one day", time.Duration(0)),
+			Entry("set to a negative number, so tokens are not rotated", "-5", time.Duration(0)),
+		)
+	})
+
+	Context("Testing the ResourceTreeCollectionEnabled() function", func() {
+
+		DescribeTable("should only enable resource tree collection if the ENABLE_RESOURCE_TREE_COLLECTION environment variable is true",
+			func(value string, expected bool) {
+				defer os.Unsetenv(ResourceTreeCollectionEnvVar)
+
+				if value != "" {
+					os.Setenv(ResourceTreeCollectionEnvVar, value)
+				}
+				Expect(ResourceTreeCollectionEnabled()).To(Equal(expected))
+			},
+			Entry("not set, so it is disabled by default", "", false),
+			Entry("set to true", "true", true),
+			Entry("set to true, regardless of case", "TRUE", true),
+			Entry("set to false", "false", false),
+		)
+	})
 })
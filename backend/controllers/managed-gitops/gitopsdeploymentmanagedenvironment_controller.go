@@ -19,15 +19,18 @@ package managedgitops
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
@@ -39,6 +42,10 @@ import (
 	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/preprocess_event_loop"
 )
 
+// maxServiceAccountTokenRotationRequeueInterval is the maximum interval between reconciles of a managed environment whose
+// service account token is rotated, in order to check whether the rotation is due.
+const maxServiceAccountTokenRotationRequeueInterval = 1 * time.Hour
+
 // GitOpsDeploymentManagedEnvironmentReconciler reconciles a GitOpsDeploymentManagedEnvironment object
 type GitOpsDeploymentManagedEnvironmentReconciler struct {
 	client.Client
@@ -49,6 +56,7 @@ type GitOpsDeploymentManagedEnvironmentReconciler struct {
 //+kubebuilder:rbac:groups=managed-gitops.redhat.com,resources=gitopsdeploymentmanagedenvironments,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=managed-gitops.redhat.com,resources=gitopsdeploymentmanagedenvironments/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=managed-gitops.redhat.com,resources=gitopsdeploymentmanagedenvironments/finalizers,verbs=update
+//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -57,12 +65,12 @@ type GitOpsDeploymentManagedEnvironmentReconciler struct {
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.11.2/pkg/reconcile
 func (r *GitOpsDeploymentManagedEnvironmentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 
-	_ = log.FromContext(ctx).
+	log := log.FromContext(ctx).
 		WithName(logutil.LogLogger_managed_gitops)
 
 	rClient := sharedutil.IfEnabledSimulateUnreliableClient(r.Client)
 
-	// The Reconcile function receives events for both ManagedEnv and Secrets.
+	// The Reconcile function receives events for ManagedEnv, Secrets and (CA bundle) ConfigMaps.
 	// Since the 'req' object doesn't tell us the type resource type (ManagedEnv or Secret), we need to check both cases.
 
 	namespace := corev1.Namespace{
@@ -76,6 +84,15 @@ func (r *GitOpsDeploymentManagedEnvironmentReconciler) Reconcile(ctx context.Con
 
 	r.PreprocessEventLoopProcessor.callPreprocessEventLoopForManagedEnvironment(req, rClient, namespace)
 
+	// If service account tokens are rotated, periodically requeue managed environments that use a service account created
+	// by the GitOps Service, so that the token is rotated once it is due.
+	if rotationInterval := sharedutil.ServiceAccountTokenRotationInterval(log); rotationInterval > 0 {
+		managedEnv := managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironment{}
+		if err := rClient.Get(ctx, req.NamespacedName, &managedEnv); err == nil && managedEnv.Spec.CreateNewServiceAccount {
+			return ctrl.Result{RequeueAfter: min(rotationInterval, maxServiceAccountTokenRotationRequeueInterval)}, nil
+		}
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -104,11 +121,19 @@ func (dppelp *DefaultPreProcessEventLoopProcessor) callPreprocessEventLoopForMan
 // SetupWithManager sets up the controller with the Manager.
 func (r *GitOpsDeploymentManagedEnvironmentReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironment{}).
+		// Status-only updates (such as the 'Reachable' condition written by the managed environment prober) do not require
+		// the managed environment to be reconciled, but changes to its labels and annotations do.
+		For(&managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironment{},
+			builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{}, predicate.LabelChangedPredicate{},
+				predicate.AnnotationChangedPredicate{}))).
 		Watches(
 			&corev1.Secret{},
 			handler.EnqueueRequestsFromMapFunc(r.findSecretsForManagedEnvironment),
 			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
+		Watches(
+			&corev1.ConfigMap{},
+			handler.EnqueueRequestsFromMapFunc(r.findCABundleConfigMapsForManagedEnvironment),
+			builder.WithPredicates(predicate.ResourceVersionChangedPredicate{})).
 		Complete(r)
 }
 
@@ -123,7 +148,7 @@ func (r *GitOpsDeploymentManagedEnvironmentReconciler) findSecretsForManagedEnvi
 		return []reconcile.Request{}
 	}
 
-	if secretObj.Type != sharedutil.ManagedEnvironmentSecretType {
+	if secretObj.Type != sharedutil.ManagedEnvironmentSecretType && secretObj.Type != sharedutil.ManagedEnvironmentTokenSecretType {
 		return []reconcile.Request{}
 	}
 
@@ -152,3 +177,38 @@ func (r *GitOpsDeploymentManagedEnvironmentReconciler) findSecretsForManagedEnvi
 
 	return requests
 }
+
+// CABundleConfigMapCacheOptions returns the cache options of ConfigMaps: only the ConfigMaps with the
+// ManagedEnvironmentCABundleLabel are cached (and thus watched by the managed environment controller), rather than every
+// ConfigMap in the cluster.
+// - ConfigMaps should be read directly from the API server, rather than the cache, as the cache doesn't contain the
+// ConfigMaps without the label.
+func CABundleConfigMapCacheOptions() cache.ByObject {
+	return cache.ByObject{
+		Label: labels.SelectorFromSet(labels.Set{managedgitopsv1alpha1.ManagedEnvironmentCABundleLabel: "true"}),
+	}
+}
+
+// findCABundleConfigMapsForManagedEnvironment returns the managed environments that reference the ConfigMap via .spec.caBundleConfigMap
+func (r *GitOpsDeploymentManagedEnvironmentReconciler) findCABundleConfigMapsForManagedEnvironment(ctx context.Context, configMap client.Object) []reconcile.Request {
+
+	managedEnvList := managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironmentList{}
+
+	if err := r.List(ctx, &managedEnvList, &client.ListOptions{Namespace: configMap.GetNamespace()}); err != nil {
+		return []reconcile.Request{}
+	}
+
+	requests := []reconcile.Request{}
+
+	for idx := range managedEnvList.Items {
+		managedEnvCR := managedEnvList.Items[idx]
+
+		if managedEnvCR.Namespace == configMap.GetNamespace() && managedEnvCR.Spec.CABundleConfigMap == configMap.GetName() {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: client.ObjectKeyFromObject(&managedEnvCR),
+			})
+		}
+	}
+
+	return requests
+}
@@ -19,6 +19,8 @@ package managedgitops
 import (
 	"context"
 	"fmt"
+	"os"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -27,6 +29,7 @@ import (
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/tests"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/uuid"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -90,6 +93,51 @@ var _ = Describe("GitOpsDeploymentManagedEnvironment Controller Test", func() {
 				Expect(mockProcessor.requestsReceived).Should(HaveLen(1))
 
 			})
+
+			It("requeues a managed-env that uses a service account created by the GitOps Service, only if service account tokens are rotated", func() {
+				secret := createSecretForManagedEnv("my-secret", true, *namespace, k8sClient)
+				managedEnv := createManagedEnvTargetingSecret("managed-env1", secret, *namespace, k8sClient)
+
+				managedEnv.Spec.CreateNewServiceAccount = true
+				err := k8sClient.Update(context.Background(), &managedEnv)
+				Expect(err).ToNot(HaveOccurred())
+
+				req := ctrl.Request{
+					NamespacedName: types.NamespacedName{
+						Namespace: managedEnv.Namespace,
+						Name:      managedEnv.Name,
+					},
+				}
+
+				By("verifying the managed-env is not requeued if service account tokens are not rotated")
+				res, err := reconciler.Reconcile(context.Background(), req)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res.RequeueAfter).To(BeZero())
+
+				By("verifying the managed-env is requeued if service account tokens are rotated")
+				defer os.Unsetenv(sharedutil.ServiceAccountTokenRotationIntervalEnvVar)
+				os.Setenv(sharedutil.ServiceAccountTokenRotationIntervalEnvVar, "10")
+
+				res, err = reconciler.Reconcile(context.Background(), req)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res.RequeueAfter).To(Equal(10 * time.Minute))
+
+				By("verifying the managed-env is requeued at most every hour, if the rotation interval is longer")
+				os.Setenv(sharedutil.ServiceAccountTokenRotationIntervalEnvVar, "1440")
+
+				res, err = reconciler.Reconcile(context.Background(), req)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res.RequeueAfter).To(Equal(maxServiceAccountTokenRotationRequeueInterval))
+
+				By("verifying the managed-env is not requeued if it doesn't use a service account created by the GitOps Service")
+				managedEnv.Spec.CreateNewServiceAccount = false
+				err = k8sClient.Update(context.Background(), &managedEnv)
+				Expect(err).ToNot(HaveOccurred())
+
+				res, err = reconciler.Reconcile(context.Background(), req)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(res.RequeueAfter).To(BeZero())
+			})
 		})
 
 		Context("Test findSecretsForManagedEnvironment function", func() {
@@ -108,6 +156,27 @@ var _ = Describe("GitOpsDeploymentManagedEnvironment Controller Test", func() {
 				})
 			})
 
+			When("GitOpsDeploymentManagedEnvironment references a token secret in the same namespace", func() {
+
+				It("should return a managed environment", func() {
+					By("create a managed environment token secret type")
+					secret := corev1.Secret{
+						ObjectMeta: metav1.ObjectMeta{
+							Name:      secretName,
+							Namespace: namespace.Name,
+						},
+						Type: sharedutil.ManagedEnvironmentTokenSecretType,
+					}
+					Expect(k8sClient.Create(context.Background(), &secret)).To(Succeed())
+
+					By("create a managed environment that references the secret")
+
+					managedEnv := createManagedEnvTargetingSecret("testManagedEnv", secret, *namespace, k8sClient)
+
+					Expect(reconciler.findSecretsForManagedEnvironment(context.Background(), &secret)).To(Equal([]reconcile.Request{{NamespacedName: client.ObjectKeyFromObject(&managedEnv)}}))
+				})
+			})
+
 			When("GitOpsDeploymentManagedEnvironment references a secret of a different type. Expect no Managed Environment to be reconciled", func() {
 
 				It("should return a managed environment", func() {
@@ -188,6 +257,42 @@ var _ = Describe("GitOpsDeploymentManagedEnvironment Controller Test", func() {
 				})
 			})
 		})
+
+		Context("Test findCABundleConfigMapsForManagedEnvironment function", func() {
+
+			It("should return only the managed environments that reference the ConfigMap as their CA bundle", func() {
+				secret := createSecretForManagedEnv(secretName, true, *namespace, k8sClient)
+
+				configMap := corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "my-ca-bundle",
+						Namespace: namespace.Name,
+					},
+				}
+				Expect(k8sClient.Create(context.Background(), &configMap)).To(Succeed())
+
+				By("creating a managed environment that references the ConfigMap, and one that doesn't")
+				managedEnv := createManagedEnvTargetingSecret("testManagedEnv", secret, *namespace, k8sClient)
+				managedEnv.Spec.CABundleConfigMap = configMap.Name
+				Expect(k8sClient.Update(context.Background(), &managedEnv)).To(Succeed())
+
+				createManagedEnvTargetingSecret("otherManagedEnv", secret, *namespace, k8sClient)
+
+				Expect(reconciler.findCABundleConfigMapsForManagedEnvironment(context.Background(), &configMap)).To(Equal(
+					[]reconcile.Request{{NamespacedName: client.ObjectKeyFromObject(&managedEnv)}}))
+			})
+		})
+
+		Context("Test CABundleConfigMapCacheOptions function", func() {
+
+			It("should only cache the ConfigMaps with the CA bundle label", func() {
+				selector := CABundleConfigMapCacheOptions().Label
+
+				Expect(selector.Matches(labels.Set{managedgitopsv1alpha1.ManagedEnvironmentCABundleLabel: "true"})).To(BeTrue())
+				Expect(selector.Matches(labels.Set{managedgitopsv1alpha1.ManagedEnvironmentCABundleLabel: "false"})).To(BeFalse())
+				Expect(selector.Matches(labels.Set{})).To(BeFalse())
+			})
+		})
 	})
 })
 
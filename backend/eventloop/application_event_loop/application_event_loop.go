@@ -11,6 +11,7 @@ import (
 	logutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/log"
 	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/eventlooptypes"
 	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/shared_resource_loop"
+	"github.com/redhat-appstudio/managed-gitops/backend/events"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -80,6 +81,12 @@ type ApplicationEventQueueLoop struct {
 	// SharedResourceEventLoop is a reference to the shared resource event loop
 	SharedResourceEventLoop *shared_resource_loop.SharedResourceEventLoop
 
+	// NotificationSender sends the notifications of the GitOpsDeployments of the namespace
+	NotificationSender *NotificationSender
+
+	// EventRecorder records K8s Events on the GitOpsDeployments and GitOpsDeploymentSyncRuns of the namespace
+	EventRecorder *events.Recorder
+
 	// InputChan is the channel that this Application Event Loop will listen for mesages on.
 	InputChan chan RequestMessage
 
@@ -98,6 +105,8 @@ func StartApplicationEventQueueLoop(ctx context.Context, aeqlParam ApplicationEv
 		aeqlParam.GitopsDeploymentNamespace,
 		aeqlParam.WorkspaceID,
 		aeqlParam.SharedResourceEventLoop,
+		aeqlParam.NotificationSender,
+		aeqlParam.EventRecorder,
 		defaultApplicationEventRunnerFactory{}, // use the default factory
 	)
 }
@@ -113,6 +122,8 @@ func startApplicationEventQueueLoopWithFactory(ctx context.Context, aeqlParam Ap
 		aeqlParam.GitopsDeploymentNamespace,
 		aeqlParam.WorkspaceID,
 		aeqlParam.SharedResourceEventLoop,
+		aeqlParam.NotificationSender,
+		aeqlParam.EventRecorder,
 		aerFactory, // use parameter-provided factory
 	)
 
@@ -151,6 +162,8 @@ func applicationEventQueueLoop(ctx context.Context, k8sClient client.Client,
 	gitopsDeploymentName string, gitopsDeploymentNamespace string,
 	workspaceID string,
 	sharedResourceEventLoop *shared_resource_loop.SharedResourceEventLoop,
+	notificationSender *NotificationSender,
+	eventRecorder *events.Recorder,
 	aerFactory applicationEventRunnerFactory) {
 
 	log := log.FromContext(ctx).
@@ -168,11 +181,11 @@ func applicationEventQueueLoop(ctx context.Context, k8sClient client.Client,
 		activeSyncOperationEvent:   nil,
 		waitingSyncOperationEvents: []*RequestMessage{},
 
-		deploymentEventRunner: aerFactory.createNewApplicationEventLoopRunner(ctx, input, sharedResourceEventLoop, gitopsDeploymentName,
+		deploymentEventRunner: aerFactory.createNewApplicationEventLoopRunner(ctx, input, sharedResourceEventLoop, notificationSender, eventRecorder, gitopsDeploymentName,
 			gitopsDeploymentNamespace, workspaceID, "deployment", ExistingK8sClientFactory{existingK8sClient: k8sClient}),
 		deploymentEventRunnerShutdown: false,
 
-		syncOperationEventRunner: aerFactory.createNewApplicationEventLoopRunner(ctx, input, sharedResourceEventLoop, gitopsDeploymentName,
+		syncOperationEventRunner: aerFactory.createNewApplicationEventLoopRunner(ctx, input, sharedResourceEventLoop, notificationSender, eventRecorder, gitopsDeploymentName,
 			gitopsDeploymentNamespace, workspaceID, "sync-operation", ExistingK8sClientFactory{existingK8sClient: k8sClient}),
 		syncOperationEventRunnerShutdown: false,
 	}
@@ -251,8 +264,10 @@ func processApplicationEventQueueLoopMessage(ctx context.Context, newEvent Reque
 				log.V(logutil.LogLevel_Debug).Info("Ignoring post-shutdown deployment event")
 			}
 
-		} else if eventLoopMessage.ReqResource == eventlooptypes.GitOpsDeploymentSyncRunTypeName {
+		} else if eventLoopMessage.ReqResource == eventlooptypes.GitOpsDeploymentSyncRunTypeName ||
+			eventLoopMessage.ReqResource == eventlooptypes.GitOpsDeploymentDiffTypeName {
 
+			// GitOpsDeploymentDiff events are handled by the same runner as GitOpsDeploymentSyncRun events
 			if !state.syncOperationEventRunnerShutdown {
 				state.waitingSyncOperationEvents = append(state.waitingSyncOperationEvents, &newEvent)
 			} else {
@@ -320,7 +335,8 @@ func processApplicationEventQueueLoopMessage(ctx context.Context, newEvent Reque
 				log.Info("Deployment signalled shutdown")
 			}
 
-		} else if eventLoopMessage.ReqResource == eventlooptypes.GitOpsDeploymentSyncRunTypeName {
+		} else if eventLoopMessage.ReqResource == eventlooptypes.GitOpsDeploymentSyncRunTypeName ||
+			eventLoopMessage.ReqResource == eventlooptypes.GitOpsDeploymentDiffTypeName {
 
 			if mismatchingField := eventlooptypes.EventsMatch(state.activeSyncOperationEvent.Message.Event, newEvent.Message.Event); mismatchingField != "" {
 				log.Error(nil, "SEVERE: unmatched sync operation event work item",
@@ -449,7 +465,7 @@ func startNewStatusUpdateTimer(ctx context.Context, k8sClient client.Client, inp
 // The defaultApplicationEventRunnerFactory should be used in all cases, except for when writing mocks for unit tests.
 type applicationEventRunnerFactory interface {
 	createNewApplicationEventLoopRunner(ctx context.Context, informWorkCompleteChan chan RequestMessage,
-		sharedResourceEventLoop *shared_resource_loop.SharedResourceEventLoop,
+		sharedResourceEventLoop *shared_resource_loop.SharedResourceEventLoop, notificationSender *NotificationSender, eventRecorder *events.Recorder,
 		gitopsDeplName string, gitopsDeplNamespace string, workspaceID string, debugContext string, k8sFactory shared_resource_loop.SRLK8sClientFactory) chan eventlooptypes.EventLoopEvent
 }
 
@@ -460,10 +476,10 @@ var _ applicationEventRunnerFactory = defaultApplicationEventRunnerFactory{}
 
 // createNewApplicationEventLoopRunner is a simple wrapper around the default function.
 func (defaultApplicationEventRunnerFactory) createNewApplicationEventLoopRunner(ctx context.Context, informWorkCompleteChan chan RequestMessage,
-	sharedResourceEventLoop *shared_resource_loop.SharedResourceEventLoop,
+	sharedResourceEventLoop *shared_resource_loop.SharedResourceEventLoop, notificationSender *NotificationSender, eventRecorder *events.Recorder,
 	gitopsDeplName string, gitopsDeplNamespace string, workspaceID string, debugContext string, k8sFactory shared_resource_loop.SRLK8sClientFactory) chan eventlooptypes.EventLoopEvent {
 
-	return startNewApplicationEventLoopRunner(ctx, informWorkCompleteChan, sharedResourceEventLoop, gitopsDeplName, gitopsDeplNamespace,
+	return startNewApplicationEventLoopRunner(ctx, informWorkCompleteChan, sharedResourceEventLoop, notificationSender, eventRecorder, gitopsDeplName, gitopsDeplNamespace,
 		workspaceID, debugContext, k8sFactory)
 }
 
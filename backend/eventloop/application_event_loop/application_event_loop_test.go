@@ -11,6 +11,7 @@ import (
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/tests"
 	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/eventlooptypes"
 	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/shared_resource_loop"
+	"github.com/redhat-appstudio/managed-gitops/backend/events"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	fake "sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -591,8 +592,8 @@ type mockApplicationEventLoopRunnerFactory struct {
 var _ applicationEventRunnerFactory = &mockApplicationEventLoopRunnerFactory{}
 
 func (fact *mockApplicationEventLoopRunnerFactory) createNewApplicationEventLoopRunner(ctx context.Context, informWorkCompleteChan chan RequestMessage,
-	sharedResourceEventLoop *shared_resource_loop.SharedResourceEventLoop, gitopsDeplName string, gitopsDeplNamespace string,
-	workspaceID string, debugContext string, k8sFactory shared_resource_loop.SRLK8sClientFactory) chan eventlooptypes.EventLoopEvent {
+	sharedResourceEventLoop *shared_resource_loop.SharedResourceEventLoop, notificationSender *NotificationSender, eventRecorder *events.Recorder, gitopsDeplName string,
+	gitopsDeplNamespace string, workspaceID string, debugContext string, k8sFactory shared_resource_loop.SRLK8sClientFactory) chan eventlooptypes.EventLoopEvent {
 
 	return fact.mockChannel
 
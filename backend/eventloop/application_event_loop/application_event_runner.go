@@ -7,6 +7,7 @@ import (
 
 	"github.com/redhat-appstudio/managed-gitops/backend/condition"
 	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/shared_resource_loop"
+	"github.com/redhat-appstudio/managed-gitops/backend/events"
 	"github.com/redhat-appstudio/managed-gitops/backend/metrics"
 
 	"github.com/go-logr/logr"
@@ -18,6 +19,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	apierr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -56,20 +59,20 @@ import (
 
 // Cardinality: 2 instances of the Application Event Runner exist per GitOpsDeployment CR:
 // - 1 ApplicationEventRunner responsible for handling events related to the GitOpsDeployment CR
-// - 1 ApplicationEventRunner responsible for handling events related to the GitOpsDeploymentSyncRun CR
+// - 1 ApplicationEventRunner responsible for handling events related to the GitOpsDeploymentSyncRun and GitOpsDeploymentDiff CRs
 
 // For more information on how events are distributed between goroutines by event loop, see:
 // https://miro.com/app/board/o9J_lgiqJAs=/?moveToWidget=3458764514216218600&cot=14
 
 func startNewApplicationEventLoopRunner(ctx context.Context, informWorkCompleteChan chan RequestMessage,
-	sharedResourceEventLoop *shared_resource_loop.SharedResourceEventLoop,
+	sharedResourceEventLoop *shared_resource_loop.SharedResourceEventLoop, notificationSender *NotificationSender, eventRecorder *events.Recorder,
 	gitopsDeplName string, gitopsDeplNamespace, workspaceID string, debugContext string, k8sFactory shared_resource_loop.SRLK8sClientFactory) chan eventlooptypes.EventLoopEvent {
 
 	inputChannel := make(chan eventlooptypes.EventLoopEvent)
 
 	go func() {
-		applicationEventLoopRunner(ctx, inputChannel, informWorkCompleteChan, sharedResourceEventLoop, gitopsDeplName, gitopsDeplNamespace,
-			workspaceID, debugContext, k8sFactory)
+		applicationEventLoopRunner(ctx, inputChannel, informWorkCompleteChan, sharedResourceEventLoop, notificationSender, eventRecorder, gitopsDeplName,
+			gitopsDeplNamespace, workspaceID, debugContext, k8sFactory)
 	}()
 
 	return inputChannel
@@ -78,7 +81,7 @@ func startNewApplicationEventLoopRunner(ctx context.Context, informWorkCompleteC
 
 func applicationEventLoopRunner(outerContext context.Context, inputChannel chan eventlooptypes.EventLoopEvent,
 	informWorkCompleteChan chan RequestMessage,
-	sharedResourceEventLoop *shared_resource_loop.SharedResourceEventLoop, gitopsDeploymentName string,
+	sharedResourceEventLoop *shared_resource_loop.SharedResourceEventLoop, notificationSender *NotificationSender, eventRecorder *events.Recorder, gitopsDeploymentName string,
 	gitopsDeploymentNamespace string, namespaceID string, debugContext string, k8sFactory shared_resource_loop.SRLK8sClientFactory) {
 
 	log := log.FromContext(outerContext).
@@ -133,6 +136,9 @@ func applicationEventLoopRunner(outerContext context.Context, inputChannel chan
 					log:                     log,
 					workspaceID:             namespaceID,
 					k8sClientFactory:        k8sFactory,
+					eventLoopInputChan:      informWorkCompleteChan,
+					notificationSender:      notificationSender,
+					eventRecorder:           eventRecorder,
 				}
 
 				var err error
@@ -165,6 +171,11 @@ func applicationEventLoopRunner(outerContext context.Context, inputChannel chan
 					// Handle all SyncRun related events
 					err = action.applicationEventRunner_handleSyncRunModified(ctx, scopedDBQueries)
 
+				} else if newEvent.EventType == eventlooptypes.DiffModified {
+
+					// Handle all GitOpsDeploymentDiff related events
+					err = action.applicationEventRunner_handleDiffModified(ctx, scopedDBQueries)
+
 				} else if newEvent.EventType == eventlooptypes.UpdateDeploymentStatusTick {
 					_, err = action.applicationEventRunner_handleUpdateDeploymentStatusTick(ctx, gitopsDeploymentName, gitopsDeploymentNamespace, scopedDBQueries)
 
@@ -357,6 +368,9 @@ func handleManagedEnvironmentModified(ctx context.Context, expectedResourceName
 			log:                     action.log,
 			workspaceID:             action.workspaceID,
 			k8sClientFactory:        shared_resource_loop.DefaultK8sClientFactory{},
+			eventLoopInputChan:      action.eventLoopInputChan,
+			notificationSender:      action.notificationSender,
+			eventRecorder:           action.eventRecorder,
 		}
 
 		signalledShutdown, err := handleDeploymentModified(ctx, newEvent, newAction, dbQueries, log)
@@ -435,4 +449,48 @@ type applicationEventLoopRunner_Action struct {
 
 	// k8sClientFactory enabled the creation of K8s API clients to target various environments
 	k8sClientFactory shared_resource_loop.SRLK8sClientFactory
+
+	// eventLoopInputChan is the input channel of the application event loop that the runner belongs to: it is used to
+	// queue events for the other runner of the application event loop. May be nil in unit tests.
+	eventLoopInputChan chan RequestMessage
+
+	// notificationSender sends the notifications of the GitOpsDeployments of the namespace, and is owned by the
+	// workspace event loop. May be nil in unit tests.
+	notificationSender *NotificationSender
+
+	// eventRecorder records K8s Events on the GitOpsDeployments and GitOpsDeploymentSyncRuns. May be nil in unit tests.
+	eventRecorder *events.Recorder
+}
+
+// queueEvent queues an event on the application event loop, which will pass it to the runner that handles events for
+// resources of type 'reqResource'. This is used when a runner needs a resource to be reconciled by the other runner
+// (for example, the GitOpsDeployment runner requesting that a GitOpsDeploymentSyncRun be synced), as a runner must
+// not process the resources of the other runner itself.
+func (a *applicationEventLoopRunner_Action) queueEvent(eventType eventlooptypes.EventLoopEventType, reqResource eventlooptypes.GitOpsResourceType,
+	name string, namespace string) {
+
+	if a.eventLoopInputChan == nil {
+		a.log.V(logutil.LogLevel_Debug).Info("unable to queue event, as there is no application event loop", "eventType", eventType, "name", name)
+		return
+	}
+
+	event := RequestMessage{
+		Message: eventlooptypes.EventLoopMessage{
+			MessageType: eventlooptypes.ApplicationEventLoopMessageType_Event,
+			Event: &eventlooptypes.EventLoopEvent{
+				EventType:   eventType,
+				Request:     ctrl.Request{NamespacedName: types.NamespacedName{Namespace: namespace, Name: name}},
+				Client:      a.workspaceClient,
+				ReqResource: reqResource,
+				WorkspaceID: a.workspaceID,
+			},
+		},
+		ResponseChan: nil,
+	}
+
+	// Send from a separate goroutine, so that the runner is never blocked waiting on the application event loop
+	inputChan := a.eventLoopInputChan
+	go func() {
+		inputChan <- event
+	}()
 }
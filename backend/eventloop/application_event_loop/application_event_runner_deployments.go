@@ -6,7 +6,9 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
+	"time"
 
 	"sigs.k8s.io/yaml"
 
@@ -24,11 +26,15 @@ import (
 	"github.com/redhat-appstudio/managed-gitops/backend/condition"
 	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/eventlooptypes"
 	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/shared_resource_loop"
+	"github.com/redhat-appstudio/managed-gitops/backend/events"
 	"github.com/redhat-appstudio/managed-gitops/backend/metrics"
 	goyaml "gopkg.in/yaml.v2"
 	corev1 "k8s.io/api/core/v1"
 	apierr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/retry"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -43,6 +49,10 @@ const (
 	deploymentModifiedResult_Updated  deploymentModifiedResult = "updatedApp"
 	deploymentModifiedResult_NoChange deploymentModifiedResult = "noChangeInApp"
 
+	// deploymentModifiedResult_WaitingOnDependencies: the Application was not created/updated, because the
+	// GitOpsDeployment is waiting on the GitOpsDeployments in its .spec.dependsOn
+	deploymentModifiedResult_WaitingOnDependencies deploymentModifiedResult = "waitingOnDependencies"
+
 	prunePropagationPolicy = "PrunePropagationPolicy=background"
 	appProjectPrefix       = "app-project-"
 )
@@ -113,16 +123,9 @@ func (a *applicationEventLoopRunner_Action) applicationEventRunner_handleDeploym
 	if !isGitOpsDeploymentDeleted(gitopsDeployment) {
 		// Perform basic validation of GitOpsDeployment values
 
-		if gitopsDeployment.Spec.Source.Path == "" {
-			userError := managedgitopsv1alpha1.GitOpsDeploymentUserError_PathIsRequired
-			return signalledShutdown_false, nil, nil, deploymentModifiedResult_Failed,
-				gitopserrors.NewUserDevError(userError, errors.New(userError))
-
-		} else if gitopsDeployment.Spec.Source.Path == "/" {
-			userError := managedgitopsv1alpha1.GitOpsDeploymentUserError_InvalidPathSlash
+		if userError := validateGitOpsDeploymentSources(gitopsDeployment.Spec); userError != "" {
 			return signalledShutdown_false, nil, nil, deploymentModifiedResult_Failed,
 				gitopserrors.NewUserDevError(userError, errors.New(userError))
-
 		}
 	}
 
@@ -131,6 +134,9 @@ func (a *applicationEventLoopRunner_Action) applicationEventRunner_handleDeploym
 		metrics.AddOrUpdateGitOpsDeployment(deplName, deplNamespace, string(gitopsDeplNamespace.UID))
 	} else {
 		metrics.RemoveGitOpsDeployment(deplName, deplNamespace, string(gitopsDeplNamespace.UID))
+		if a.notificationSender != nil {
+			a.notificationSender.forgetNotificationState(deplName, deplNamespace)
+		}
 	}
 
 	// 2) Look for any DTAMs that point(ed) to a K8s resource with the same name and namespace as this request
@@ -181,6 +187,35 @@ func (a *applicationEventLoopRunner_Action) applicationEventRunner_handleDeploym
 	if !isGitOpsDeploymentDeleted(gitopsDeployment) {
 		// If the GitOpsDeployment resource exists in the namespace
 
+		// A GitOpsDeployment with the Foreground or Orphan deletion policy must not be deleted before its deletion has been
+		// handled here, so the deletion finalizer is added to it (and removed again if the deletion policy changes back to Cascade).
+		if err := reconcileDeletionFinalizerOfDeletionPolicy(ctx, a.workspaceClient, gitopsDeployment); err != nil {
+			a.log.Error(err, "failed to update the deletion finalizer of GitOpsDeployment, for its deletion policy")
+			return signalledShutdown_false, nil, nil, deploymentModifiedResult_Failed, gitopserrors.NewDevOnlyError(err)
+		}
+
+		// An automated GitOpsDeployment is not created/updated until the GitOpsDeployments it depends on are ready: the
+		// deployment status tick will reconcile the GitOpsDeployment again once they are.
+		// - Manual GitOpsDeployments are not synced until a GitOpsDeploymentSyncRun is created, so instead the sync is held.
+		if strings.EqualFold(gitopsDeployment.Spec.Type, managedgitopsv1alpha1.GitOpsDeploymentSpecType_Automated) &&
+			len(gitopsDeployment.Spec.DependsOn) > 0 {
+
+			unreadyDependencies, err := getUnreadyDependenciesOfGitOpsDeployment(ctx, a.workspaceClient, *gitopsDeployment)
+			if err != nil {
+				return signalledShutdown_false, nil, nil, deploymentModifiedResult_Failed, gitopserrors.NewDevOnlyError(err)
+			}
+
+			if len(unreadyDependencies) > 0 {
+				a.log.Info("GitOpsDeployment is waiting on its dependencies", "dependencies", unreadyDependencies)
+
+				if _, err := a.updateWaitingOnDependenciesCondition(ctx, gitopsDeployment, newWaitingOnDependenciesCondition(unreadyDependencies)); err != nil {
+					return signalledShutdown_false, nil, nil, deploymentModifiedResult_Failed, gitopserrors.NewDevOnlyError(err)
+				}
+
+				return signalledShutdown_false, nil, nil, deploymentModifiedResult_WaitingOnDependencies, nil
+			}
+		}
+
 		if currentDeplToAppMapping == nil {
 			// 5a) If the gitopsdepl CR exists, but the database entry doesn't,
 			// then this is the first time we have seen the GitOpsDepl CR.
@@ -287,9 +322,18 @@ func (a applicationEventLoopRunner_Action) handleNewGitOpsDeplEvent(ctx context.
 		sourceRepoURL:        gitopsDeployment.Spec.Source.RepoURL,
 		sourcePath:           gitopsDeployment.Spec.Source.Path,
 		sourceTargetRevision: gitopsDeployment.Spec.Source.TargetRevision,
+		sourceChart:          gitopsDeployment.Spec.Source.Chart,
+		sourceHelm:           convertToFauxApplicationSourceHelm(gitopsDeployment.Spec.Source.Helm),
+		sourceKustomize:      convertToFauxApplicationSourceKustomize(gitopsDeployment.Spec.Source.Kustomize),
+		sources:              convertToFauxApplicationSources(gitopsDeployment.Spec.Sources),
 		// syncOptions:       if non-empty, it gets updated below.
-		automated: strings.EqualFold(gitopsDeployment.Spec.Type, managedgitopsv1alpha1.GitOpsDeploymentSpecType_Automated),
-		project:   appProjectPrefix + clusterUser.Clusteruser_id,
+		automated:         strings.EqualFold(gitopsDeployment.Spec.Type, managedgitopsv1alpha1.GitOpsDeploymentSpecType_Automated),
+		automatedPolicy:   convertToFauxSyncPolicyAutomated(gitopsDeployment.Spec.SyncPolicy),
+		retry:             convertToFauxRetryStrategy(gitopsDeployment.Spec.SyncPolicy),
+		ignoreDifferences: convertToFauxIgnoreDifferences(gitopsDeployment.Spec.IgnoreDifferences),
+		project:           appProjectPrefix + clusterUser.Clusteruser_id,
+		suspended:         gitopsDeployment.Spec.Suspend,
+		deletionPolicy:    gitopsDeployment.Spec.DeletionPolicy,
 	}
 
 	// If AppProject-based isolation is disabled, then just default to using 'default' as the project field in the Argo CD Application
@@ -308,6 +352,10 @@ func (a applicationEventLoopRunner_Action) handleNewGitOpsDeplEvent(ctx context.
 
 	}
 
+	if userErr := disableAutomatedSyncOutsideSyncWindows(&specFieldInput, &gitopsDeployment, time.Now()); userErr != nil {
+		return nil, nil, deploymentModifiedResult_Failed, userErr
+	}
+
 	specFieldText, err := createSpecField(specFieldInput)
 	if err != nil {
 		a.log.Error(err, "SEVERE: unable to marshal generated YAML")
@@ -430,6 +478,15 @@ func (a applicationEventLoopRunner_Action) handleDeleteGitOpsDeplEvent(ctx conte
 		return false, gitopserrors.NewUserDevError(userError, devError)
 	}
 
+	// The deployed resources of a GitOpsDeployment with the Orphan deletion policy are marked as orphaned before the
+	// Argo CD Application is deleted, so that they are not deleted by the orphaned resource clean-up.
+	if gitopsDepl != nil && isGitOpsDeploymentDeleted(gitopsDepl) && gitopsDepl.Spec.DeletionPolicy == managedgitopsv1alpha1.DeletionPolicy_Orphan {
+		if err := annotateOrphanedResources(ctx, a.workspaceClient, *gitopsDepl, a.log); err != nil {
+			a.log.Error(err, "failed to mark the resources of the GitOpsDeployment as orphaned")
+			return false, gitopserrors.NewDevOnlyError(err)
+		}
+	}
+
 	var allErrors error
 
 	signalShutdown := true
@@ -497,6 +554,107 @@ func removeFinalizerIfExists(ctx context.Context, k8sClient client.Client, gitop
 	})
 }
 
+// reconcileDeletionFinalizerOfDeletionPolicy adds the deletion finalizer to a GitOpsDeployment whose deletion policy
+// requires it (Foreground and Orphan), and removes it again once the deletion policy no longer requires it.
+// - The finalizer is only removed if it was added here, as recorded by DeletionFinalizerAddedAnnotation: the user may
+// also add the finalizer to a GitOpsDeployment themselves.
+// - The GitOpsDeployment is only updated if the presence of the finalizer does not match the deletion policy.
+func reconcileDeletionFinalizerOfDeletionPolicy(ctx context.Context, k8sClient client.Client, gitopsDepl *managedgitopsv1alpha1.GitOpsDeployment) error {
+	if gitopsDepl == nil || !deletionFinalizerMismatchesDeletionPolicy(*gitopsDepl) {
+		return nil
+	}
+
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(gitopsDepl), gitopsDepl); err != nil {
+			return err
+		}
+
+		// The GitOpsDeployment may have been modified since it was retrieved by the caller
+		if !deletionFinalizerMismatchesDeletionPolicy(*gitopsDepl) {
+			return nil
+		}
+
+		if requiresDeletionFinalizer(*gitopsDepl) {
+			gitopsDepl.Finalizers = append(gitopsDepl.Finalizers, managedgitopsv1alpha1.DeletionFinalizer)
+			if gitopsDepl.Annotations == nil {
+				gitopsDepl.Annotations = map[string]string{}
+			}
+			gitopsDepl.Annotations[managedgitopsv1alpha1.DeletionFinalizerAddedAnnotation] = "true"
+		} else {
+			gitopsDepl.Finalizers = removeItemFromSlice(managedgitopsv1alpha1.DeletionFinalizer, gitopsDepl.Finalizers)
+			delete(gitopsDepl.Annotations, managedgitopsv1alpha1.DeletionFinalizerAddedAnnotation)
+		}
+
+		return k8sClient.Update(ctx, gitopsDepl)
+	})
+}
+
+// requiresDeletionFinalizer returns true if the deletion policy of the GitOpsDeployment requires the deletion finalizer.
+func requiresDeletionFinalizer(gitopsDepl managedgitopsv1alpha1.GitOpsDeployment) bool {
+	return gitopsDepl.Spec.DeletionPolicy == managedgitopsv1alpha1.DeletionPolicy_Foreground ||
+		gitopsDepl.Spec.DeletionPolicy == managedgitopsv1alpha1.DeletionPolicy_Orphan
+}
+
+// deletionFinalizerMismatchesDeletionPolicy returns true if the deletion finalizer needs to be added to the
+// GitOpsDeployment for its deletion policy, or if the finalizer was added for a deletion policy that no longer requires it.
+func deletionFinalizerMismatchesDeletionPolicy(gitopsDepl managedgitopsv1alpha1.GitOpsDeployment) bool {
+
+	hasFinalizer := slices.Contains(gitopsDepl.Finalizers, managedgitopsv1alpha1.DeletionFinalizer)
+
+	if requiresDeletionFinalizer(gitopsDepl) {
+		return !hasFinalizer
+	}
+
+	addedForPolicy := gitopsDepl.Annotations[managedgitopsv1alpha1.DeletionFinalizerAddedAnnotation] == "true"
+
+	return hasFinalizer && addedForPolicy
+}
+
+// annotateOrphanedResources sets the OrphanedResourceAnnotation on the deployed resources of a GitOpsDeployment (as
+// listed in its .status.resources), so that the orphaned resource clean-up of the cluster reconciler will skip them.
+// - The orphaned resource clean-up only scans the local cluster, so only the resources of GitOpsDeployments that target
+// their own namespace are annotated: the resources deployed to a remote environment are left running, but are not
+// annotated (this is described in docs/api.md).
+// - Resources that cannot be annotated due to RBAC can likewise not be deleted by the clean-up, so they are skipped.
+func annotateOrphanedResources(ctx context.Context, k8sClient client.Client, gitopsDepl managedgitopsv1alpha1.GitOpsDeployment, log logr.Logger) error {
+
+	if gitopsDepl.Spec.Destination.Environment != "" {
+		return nil
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]string{managedgitopsv1alpha1.OrphanedResourceAnnotation: "true"},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, resource := range gitopsDepl.Status.Resources {
+
+		// Cluster-scoped resources are not cleaned up by the orphaned resource clean-up
+		if resource.Namespace == "" {
+			continue
+		}
+
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(schema.GroupVersionKind{Group: resource.Group, Version: resource.Version, Kind: resource.Kind})
+		obj.SetNamespace(resource.Namespace)
+		obj.SetName(resource.Name)
+
+		if err := k8sClient.Patch(ctx, obj, client.RawPatch(types.MergePatchType, patch)); err != nil {
+			if apierr.IsNotFound(err) || apierr.IsForbidden(err) || meta.IsNoMatchError(err) {
+				log.V(logutil.LogLevel_Debug).Info("skipping orphaned resource that could not be annotated", "resource", resource, "error", err.Error())
+				continue
+			}
+			return fmt.Errorf("unable to annotate orphaned resource %s/%s of kind %s: %w", resource.Namespace, resource.Name, resource.Kind, err)
+		}
+	}
+
+	return nil
+}
+
 // we consider the GitOpsDeployment as deleted if:
 // 1. the GitOpsDeployment object is not found.
 // 2. the GitOpsDeployment is under deletion and the deletiontimestamp is set.
@@ -654,9 +812,18 @@ func (a applicationEventLoopRunner_Action) handleUpdatedGitOpsDeplEvent(ctx cont
 		sourceRepoURL:        gitopsDeployment.Spec.Source.RepoURL,
 		sourcePath:           gitopsDeployment.Spec.Source.Path,
 		sourceTargetRevision: gitopsDeployment.Spec.Source.TargetRevision,
+		sourceChart:          gitopsDeployment.Spec.Source.Chart,
+		sourceHelm:           convertToFauxApplicationSourceHelm(gitopsDeployment.Spec.Source.Helm),
+		sourceKustomize:      convertToFauxApplicationSourceKustomize(gitopsDeployment.Spec.Source.Kustomize),
+		sources:              convertToFauxApplicationSources(gitopsDeployment.Spec.Sources),
 		// syncOptions:       if non-empty, it gets updated below.
-		automated: strings.EqualFold(gitopsDeployment.Spec.Type, managedgitopsv1alpha1.GitOpsDeploymentSpecType_Automated),
-		project:   appProjectPrefix + clusterUser.Clusteruser_id,
+		automated:         strings.EqualFold(gitopsDeployment.Spec.Type, managedgitopsv1alpha1.GitOpsDeploymentSpecType_Automated),
+		automatedPolicy:   convertToFauxSyncPolicyAutomated(gitopsDeployment.Spec.SyncPolicy),
+		retry:             convertToFauxRetryStrategy(gitopsDeployment.Spec.SyncPolicy),
+		ignoreDifferences: convertToFauxIgnoreDifferences(gitopsDeployment.Spec.IgnoreDifferences),
+		project:           appProjectPrefix + clusterUser.Clusteruser_id,
+		suspended:         gitopsDeployment.Spec.Suspend,
+		deletionPolicy:    gitopsDeployment.Spec.DeletionPolicy,
 	}
 
 	// If AppProject-based isolation is disabled, then just default to using 'default' as the project field in the Argo CD Application
@@ -672,6 +839,15 @@ func (a applicationEventLoopRunner_Action) handleUpdatedGitOpsDeplEvent(ctx cont
 		specFieldInput.syncOptions = managedgitopsv1alpha1.SyncOptionToStringSlice(gitopsDeployment.Spec.SyncPolicy.SyncOptions)
 	}
 
+	if userErr := disableAutomatedSyncOutsideSyncWindows(&specFieldInput, &gitopsDeployment, time.Now()); userErr != nil {
+		return nil, nil, deploymentModifiedResult_Failed, userErr
+	}
+
+	rollbackInEffect, userErr := a.applyRollbackInEffect(ctx, &specFieldInput, gitopsDeployment, application.Application_id, dbQueries)
+	if userErr != nil {
+		return nil, nil, deploymentModifiedResult_Failed, userErr
+	}
+
 	shouldUpdateApplication := false
 
 	if appProjectDBRowsUpdated {
@@ -773,6 +949,13 @@ func (a applicationEventLoopRunner_Action) handleUpdatedGitOpsDeplEvent(ctx cont
 		return nil, nil, deploymentModifiedResult_Failed, gitopserrors.NewDevOnlyError(err)
 	}
 
+	// Now that the rollback has been applied to the Application, the rollback GitOpsDeploymentSyncRuns that were held
+	// waiting on it can be synced. (They are only queued when the Application changes, so that a GitOpsDeploymentSyncRun
+	// which is still held does not queue events back and forth with this runner.)
+	if rollbackInEffect != nil {
+		a.queueHeldRollbackSyncRuns(ctx, gitopsDeployment, *rollbackInEffect, dbQueries)
+	}
+
 	return application, engineInstance, deploymentModifiedResult_Updated, nil
 
 }
@@ -866,7 +1049,25 @@ func (a applicationEventLoopRunner_Action) cleanOldGitOpsDeploymentEntry(ctx con
 
 	// If the Application table entry still exists, finish the cleanup...
 
-	// 5) Remove the Application from the database
+	// 5) Remove the DeploymentHistory of the Application from the database
+	rowsDeleted, err = dbQueries.DeleteDeploymentHistoryByApplicationId(ctx, deplToAppMapping.Application_id)
+	if err != nil {
+		log.Error(err, "unable to delete deployment history by application id")
+		return signalledShutdown_false, err
+	} else if rowsDeleted > 0 {
+		log.Info("GitOpsDeployment was deleted, so deleted DeploymentHistory rows from database", "rowsDeleted", rowsDeleted)
+	}
+
+	// Likewise, remove the DiffOperations of the Application
+	rowsDeleted, err = dbQueries.DeleteDiffOperationsByApplicationId(ctx, deplToAppMapping.Application_id)
+	if err != nil {
+		log.Error(err, "unable to delete diff operations by application id")
+		return signalledShutdown_false, err
+	} else if rowsDeleted > 0 {
+		log.Info("GitOpsDeployment was deleted, so deleted DiffOperation rows from database", "rowsDeleted", rowsDeleted)
+	}
+
+	// 6) Remove the Application from the database
 	log.Info("GitOpsDeployment was deleted, so deleting Application row from database")
 	rowsDeleted, err = dbQueries.DeleteApplicationById(ctx, deplToAppMapping.Application_id)
 	if err != nil {
@@ -903,7 +1104,7 @@ func (a applicationEventLoopRunner_Action) cleanOldGitOpsDeploymentEntry(ctx con
 		}
 	}
 
-	// 6) Now that we've deleted the Application row, create the operation that will cause the Argo CD application
+	// 7) Now that we've deleted the Application row, create the operation that will cause the Argo CD application
 	// to be deleted.
 	gitopsEngineClient, err := a.k8sClientFactory.GetK8sClientForGitOpsEngineInstance(ctx, gitopsEngineInstance)
 	if err != nil {
@@ -934,7 +1135,7 @@ func (a applicationEventLoopRunner_Action) cleanOldGitOpsDeploymentEntry(ctx con
 		return signalledShutdown_false, err
 	}
 
-	// 7) Finally, clean up the operation
+	// 8) Finally, clean up the operation
 	if err := operations.CleanupOperation(ctx, *dbOperation, *k8sOperation, dbQueries, gitopsEngineClient, !a.testOnlySkipCreateOperation, log); err != nil {
 		log.Error(err, "unable to cleanup operation", "operation", dbOperationInput.ShortString())
 		return signalledShutdown_false, err
@@ -975,22 +1176,56 @@ func (a *applicationEventLoopRunner_Action) applicationEventRunner_handleUpdateD
 	// Copy of the GitOpsDeployment we retrieved, before its modified below
 	originalGitOpsDeployment := *gitopsDeployment.DeepCopy()
 
-	// 2) Retrieve the DTAM for the GitOpsDeployment, if it exists.
+	// The GitOpsDeploymentSyncRuns that target the GitOpsDeployment are listed once, and are then used by each of the
+	// steps below. They are required to determine whether a rollback is in effect, so the tick cannot continue without them.
+	syncRuns, err := listSyncRunsOfGitOpsDeployment(ctx, a.workspaceClient, *gitopsDeployment)
+	if err != nil {
+		a.log.Error(err, "unable to list GitOpsDeploymentSyncRuns in tick status update")
+		return crUpdated_false, err
+	}
+
+	// 2) If the GitOpsDeployment depends on other GitOpsDeployments, check whether they are ready. Once they are, the
+	// GitOpsDeployment (and its GitOpsDeploymentSyncRuns) that were waiting on them are reconciled again.
+	// - A failure to check the dependencies is reported as a condition, and does not prevent the rest of the status
+	//   from being updated.
+	dependenciesCondition := a.reconcileDependenciesOfGitOpsDeployment(ctx, *gitopsDeployment, syncRuns)
+
+	// 3) Retrieve the DTAM for the GitOpsDeployment, if it exists.
 	mapping := db.DeploymentToApplicationMapping{
 		Deploymenttoapplicationmapping_uid_id: string(gitopsDeployment.UID),
 	}
 	if err := dbQueries.GetDeploymentToApplicationMappingByDeplId(ctx, &mapping); err != nil {
 
 		if db.IsResultNotFoundError(err) {
-			// No Application associated with this GitOpsDeployment, so no work to do
-			return crUpdated_false, nil
+			// No Application associated with this GitOpsDeployment (for example, because it is waiting on its
+			// dependencies), so the only status to report is whether it is waiting on its dependencies
+			return a.updateWaitingOnDependenciesCondition(ctx, gitopsDeployment, dependenciesCondition)
 		} else {
 			a.log.Error(err, "unable to retrieve DeploymentToApplicationMapping in tick status update")
 			return crUpdated_false, err
 		}
 	}
 
-	// 3) Retrieve the application state for the application pointed to by the DTAM
+	// 4) If the GitOpsDeployment has sync windows, ensure that automated sync of the Argo CD Application is only enabled
+	// while a sync window is open.
+	syncWindowCondition, err := a.reconcileSyncWindowsOfGitOpsDeployment(ctx, *gitopsDeployment, mapping, syncRuns, dbQueries)
+	if err != nil {
+		a.log.Error(err, "unable to reconcile sync windows in tick status update")
+		return crUpdated_false, err
+	}
+
+	// 5) Publish the status of the syncs of the GitOpsDeploymentSyncRuns that target the GitOpsDeployment.
+	// - Failures are logged, but should not prevent the status of the GitOpsDeployment from being updated.
+	if err := a.publishSyncRunStatusesOfGitOpsDeployment(ctx, syncRuns, dbQueries); err != nil {
+		a.log.Error(err, "unable to update the status of GitOpsDeploymentSyncRuns in tick status update")
+	}
+
+	// Likewise for the GitOpsDeploymentDiffs that target the GitOpsDeployment.
+	if err := a.publishDiffStatusesOfGitOpsDeployment(ctx, *gitopsDeployment, dbQueries); err != nil {
+		a.log.Error(err, "unable to update the status of GitOpsDeploymentDiffs in tick status update")
+	}
+
+	// 6) Retrieve the application state for the application pointed to by the DTAM
 	applicationState := db.ApplicationState{Applicationstate_application_id: mapping.Application_id}
 	if err := dbQueries.GetApplicationStateById(ctx, &applicationState); err != nil {
 
@@ -1008,7 +1243,7 @@ func (a *applicationEventLoopRunner_Action) applicationEventRunner_handleUpdateD
 		return crUpdated_false, err
 	}
 
-	// 4) Update the health and status field of the GitOpsDepl CR
+	// 7) Update the health and status field of the GitOpsDepl CR
 
 	// Update the gitopsDeployment instance with health and status values (fetched from the database)
 	gitopsDeployment.Status.Health.Status = managedgitopsv1alpha1.HealthStatusCode(appStatus.Health.Status)
@@ -1028,6 +1263,22 @@ func (a *applicationEventLoopRunner_Action) applicationEventRunner_handleUpdateD
 		}
 	}
 
+	if syncWindowCondition != nil {
+		newGitopsDeplConditions = append(newGitopsDeplConditions, *syncWindowCondition)
+	}
+
+	if dependenciesCondition != nil {
+		newGitopsDeplConditions = append(newGitopsDeplConditions, *dependenciesCondition)
+	}
+
+	if gitopsDeployment.Spec.Suspend {
+		newGitopsDeplConditions = append(newGitopsDeplConditions, managedgitopsv1alpha1.GitOpsDeploymentCondition{
+			Type:    managedgitopsv1alpha1.GitOpsDeploymentConditionSuspended,
+			Message: "reconciliation of the GitOpsDeployment is suspended: automated sync is disabled until .spec.suspend is set to false",
+			Reason:  managedgitopsv1alpha1.GitopsDeploymentReasonSuspended,
+		})
+	}
+
 	conditionManager := condition.NewConditionManager()
 	for _, c := range newGitopsDeplConditions {
 		// If the new condition already exists, then update it with the latest values.
@@ -1044,12 +1295,27 @@ func (a *applicationEventLoopRunner_Action) applicationEventRunner_handleUpdateD
 
 	gitopsDeployment.Status.Resources = extractResourceStatus(appStatus.Resources)
 
+	// The resource tree is informational only, so a failure to decompress it should not prevent the status from being updated
+	gitopsDeployment.Status.ResourceTree, err = decompressResourceTree(applicationState.Resource_tree)
+	if err != nil {
+		a.log.Error(err, "unable to decompress resource tree received from table.")
+	}
+
 	gitopsDeployment.Status.OperationState, err = extractOperationState(appStatus.OperationState)
 	if err != nil {
 		a.log.Error(err, "unable to extract operationState from ApplicationState table.")
 		return crUpdated_false, err
 	}
 
+	// Update the deployment history from the DeploymentHistory table, which (unlike the revision history of the Argo CD
+	// Application) is preserved if the Argo CD Application is recreated.
+	var deploymentHistory []db.DeploymentHistory
+	if err := dbQueries.ListDeploymentHistoryByApplicationId(ctx, mapping.Application_id, &deploymentHistory); err != nil {
+		a.log.Error(err, "unable to retrieve deployment history in tick status update")
+		return crUpdated_false, err
+	}
+	gitopsDeployment.Status.History = convertDeploymentHistoryToStatus(deploymentHistory, a.log)
+
 	comparedTo := appStatus.Sync.ComparedTo
 
 	// If the `comparedTo` value from Argo CD has a non-empty destination name field, then retrieve the corresponding `GitOpsDeploymentManagedEnvironment` resource that has that name,
@@ -1077,6 +1343,23 @@ func (a *applicationEventLoopRunner_Action) applicationEventRunner_handleUpdateD
 	gitopsDeployment.Status.ReconciledState.Destination.Name = comparedTo.Destination.Name
 	gitopsDeployment.Status.ReconciledState.Destination.Namespace = comparedTo.Destination.Namespace
 
+	// For GitOpsDeployments with multiple sources, report the state of each source
+	gitopsDeployment.Status.ReconciledState.Sources = nil
+	for idx, source := range comparedTo.Sources {
+		reconciledSource := managedgitopsv1alpha1.GitOpsDeploymentSource{
+			Path:    source.Path,
+			RepoURL: source.RepoURL,
+			Branch:  source.TargetRevision,
+			Chart:   source.Chart,
+			Ref:     source.Ref,
+		}
+		// Argo CD reports the revision of each source in the same order as the sources
+		if idx < len(appStatus.Sync.Revisions) {
+			reconciledSource.Revision = appStatus.Sync.Revisions[idx]
+		}
+		gitopsDeployment.Status.ReconciledState.Sources = append(gitopsDeployment.Status.ReconciledState.Sources, reconciledSource)
+	}
+
 	// If nothing has changed in the status field, our work is done.
 	if reflect.DeepEqual(gitopsDeployment.Status, originalGitOpsDeployment.Status) {
 		return crUpdated_false, nil
@@ -1089,12 +1372,67 @@ func (a *applicationEventLoopRunner_Action) applicationEventRunner_handleUpdateD
 
 	a.log.V(logutil.LogLevel_Debug).Info("Updated status in deploymentStatusTick")
 
+	// Record K8s Events, and notify any GitOpsDeploymentNotificationTargets, of changes to the health of the GitOpsDeployment
+	// or to its syncs. This is only done once the status has been updated, so that each change is only reported once.
+	recordGitOpsDeploymentEvents(gitopsDeployment, originalGitOpsDeployment.Status, a.eventRecorder)
+	a.sendNotificationsOfStatusChanges(ctx, *gitopsDeployment, originalGitOpsDeployment.Status)
+
 	// NOTE: make sure to preserve the existing conditions fields that are in the status field of the CR, when updating the status!
 
 	return crUpdated_true, nil
 
 }
 
+// recordGitOpsDeploymentEvents records a K8s Event on the GitOpsDeployment for each change to its health, and for each
+// sync operation that has started or finished, between the old and new status.
+func recordGitOpsDeploymentEvents(gitopsDeployment *managedgitopsv1alpha1.GitOpsDeployment, oldStatus managedgitopsv1alpha1.GitOpsDeploymentStatus, eventRecorder *events.Recorder) {
+
+	newStatus := gitopsDeployment.Status
+
+	if oldStatus.Health.Status != newStatus.Health.Status && newStatus.Health.Status != "" {
+
+		message := fmt.Sprintf("Health status changed from '%s' to '%s'", oldStatus.Health.Status, newStatus.Health.Status)
+		if oldStatus.Health.Status == "" {
+			message = fmt.Sprintf("Health status is '%s'", newStatus.Health.Status)
+		}
+		if newStatus.Health.Message != "" {
+			message += ": " + newStatus.Health.Message
+		}
+
+		if newStatus.Health.Status == managedgitopsv1alpha1.HeathStatusCodeDegraded || newStatus.Health.Status == managedgitopsv1alpha1.HeathStatusCodeMissing {
+			eventRecorder.RecordWarning(gitopsDeployment, events.ReasonHealthStatusChanged, message)
+		} else {
+			eventRecorder.RecordNormal(gitopsDeployment, events.ReasonHealthStatusChanged, message)
+		}
+	}
+
+	newOperation := newStatus.OperationState
+	if newOperation == nil {
+		return
+	}
+
+	oldOperation := oldStatus.OperationState
+	if oldOperation != nil && oldOperation.Phase == newOperation.Phase && oldOperation.StartedAt.Equal(&newOperation.StartedAt) {
+		return
+	}
+
+	revision := ""
+	if newOperation.SyncResult != nil && newOperation.SyncResult.Revision != "" {
+		revision = fmt.Sprintf(" (revision '%s')", newOperation.SyncResult.Revision)
+	}
+
+	switch newOperation.Phase {
+	case managedgitopsv1alpha1.OperationRunning:
+		eventRecorder.RecordNormal(gitopsDeployment, events.ReasonSyncStarted, "Sync started"+revision)
+
+	case managedgitopsv1alpha1.OperationSucceeded:
+		eventRecorder.RecordNormal(gitopsDeployment, events.ReasonSyncSucceeded, "Sync succeeded"+revision)
+
+	case managedgitopsv1alpha1.OperationFailed, managedgitopsv1alpha1.OperationError:
+		eventRecorder.RecordWarning(gitopsDeployment, events.ReasonSyncFailed, "Sync failed"+revision+": "+newOperation.Message)
+	}
+}
+
 // gitOpsDeploymentAdapter is an "adapter" for GitOpsDeployment allowing you to easily plug any other related
 // API component (i.e. for adding Conditions, look at setGitOpsDeploymentCondition() method)
 // Same principle can be used for others, e.g. Finalizers, or any other field which is part of the GitOpsDeployment CRD
@@ -1173,20 +1511,226 @@ func (g *gitOpsDeploymentAdapter) setGitOpsDeploymentCondition(conditionType man
 	return nil
 }
 
-func checkValidSyncOption(syncOptions []managedgitopsv1alpha1.SyncOption) gitopserrors.UserError {
+// reconcileSyncWindowsOfGitOpsDeployment queues an event to enable or disable automated sync of the Argo CD Application,
+// when whether the sync windows of the GitOpsDeployment currently allow a sync no longer matches the Application.
+//
+// Returns a SyncWindowClosed condition if a sync is not currently allowed, or nil otherwise.
+func (a *applicationEventLoopRunner_Action) reconcileSyncWindowsOfGitOpsDeployment(ctx context.Context,
+	gitopsDeployment managedgitopsv1alpha1.GitOpsDeployment, mapping db.DeploymentToApplicationMapping,
+	syncRuns []managedgitopsv1alpha1.GitOpsDeploymentSyncRun, dbQueries db.ApplicationScopedQueries) (*managedgitopsv1alpha1.GitOpsDeploymentCondition, error) {
 
-	for _, syncOptionString := range syncOptions {
+	if gitopsDeployment.Spec.SyncPolicy == nil || len(gitopsDeployment.Spec.SyncPolicy.SyncWindows) == 0 {
+		return nil, nil
+	}
+
+	allowed, message, err := syncWindowsAllowSync(&gitopsDeployment, time.Now())
+	if err != nil {
+		// Invalid sync windows are reported to the user when the GitOpsDeployment is reconciled, so there is nothing to do here
+		return nil, nil
+	}
+
+	if strings.EqualFold(gitopsDeployment.Spec.Type, managedgitopsv1alpha1.GitOpsDeploymentSpecType_Automated) {
+
+		application := db.Application{Application_id: mapping.Application_id}
+		if err := dbQueries.GetApplicationById(ctx, &application); err != nil {
+			if db.IsResultNotFoundError(err) {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("unable to retrieve Application '%s' for sync windows: %v", mapping.Application_id, err)
+		}
+
+		automatedSyncEnabled, err := isAutomatedSyncEnabledForApplication(application)
+		if err != nil {
+			return nil, err
+		}
+
+		// Automated sync remains disabled while the GitOpsDeployment is suspended or a rollback is in effect, even if a sync window is open
+		expectedAutomatedSync := allowed && !gitopsDeployment.Spec.Suspend
+		if expectedAutomatedSync {
+			rollbackInEffect, err := findRollbackInEffect(ctx, syncRuns, dbQueries, application.Application_id, a.log)
+			if err != nil {
+				return nil, err
+			}
+			expectedAutomatedSync = rollbackInEffect == nil
+		}
+
+		// A sync window has opened or closed since the Application was last updated, so queue an event to update it: the
+		// event is processed after this status tick, so the GitOpsDeployment that the tick is updating is not modified under it.
+		if automatedSyncEnabled != expectedAutomatedSync {
+			a.log.Info("sync window state changed, queueing an event to update the Application", "allowed", allowed, "application", application.Application_id)
+			a.queueEvent(eventlooptypes.DeploymentModified, eventlooptypes.GitOpsDeploymentTypeName, gitopsDeployment.Name, gitopsDeployment.Namespace)
+		}
+	}
+
+	if allowed {
+		return nil, nil
+	}
+
+	return &managedgitopsv1alpha1.GitOpsDeploymentCondition{
+		Type:    managedgitopsv1alpha1.GitOpsDeploymentConditionSyncWindowClosed,
+		Message: message,
+		Reason:  managedgitopsv1alpha1.GitopsDeploymentReasonSyncWindowClosed,
+	}, nil
+}
+
+// reconcileDependenciesOfGitOpsDeployment checks whether the GitOpsDeployments in .spec.dependsOn are ready. Once they
+// are, an event is queued to reconcile a GitOpsDeployment that was waiting on them, and an event is queued for each
+// GitOpsDeploymentSyncRun that was held, so that it is synced by the sync run runner.
+//
+// Returns a WaitingOnDependencies condition if one or more dependencies are not yet ready, or could not be retrieved,
+// or nil otherwise.
+func (a *applicationEventLoopRunner_Action) reconcileDependenciesOfGitOpsDeployment(ctx context.Context,
+	gitopsDeployment managedgitopsv1alpha1.GitOpsDeployment, syncRuns []managedgitopsv1alpha1.GitOpsDeploymentSyncRun) *managedgitopsv1alpha1.GitOpsDeploymentCondition {
+
+	if len(gitopsDeployment.Spec.DependsOn) == 0 {
+		return nil
+	}
+
+	unreadyDependencies, err := getUnreadyDependenciesOfGitOpsDeployment(ctx, a.workspaceClient, gitopsDeployment)
+	if err != nil {
+		a.log.Error(err, "unable to check the dependencies of GitOpsDeployment")
+		return newDependenciesUnavailableCondition()
+	}
 
-		match := true
+	if len(unreadyDependencies) > 0 {
+		return newWaitingOnDependenciesCondition(unreadyDependencies)
+	}
+
+	// The dependencies are ready: if the GitOpsDeployment was waiting on them, then queue an event to create/update its
+	// Application. The event is processed after this status tick, so the GitOpsDeployment that the tick is updating is
+	// not modified under it.
+	for _, cond := range gitopsDeployment.Status.Conditions {
+		if cond.Type != managedgitopsv1alpha1.GitOpsDeploymentConditionWaitingOnDependencies ||
+			cond.Status != managedgitopsv1alpha1.GitOpsConditionStatusTrue {
+			continue
+		}
 
-		// Check for SyncOption string
-		if !(syncOptionString == managedgitopsv1alpha1.SyncOptions_CreateNamespace_true ||
-			syncOptionString == managedgitopsv1alpha1.SyncOptions_CreateNamespace_false) {
-			match = false
+		a.log.Info("dependencies of GitOpsDeployment are ready, queueing an event to reconcile GitOpsDeployment")
+		a.queueEvent(eventlooptypes.DeploymentModified, eventlooptypes.GitOpsDeploymentTypeName, gitopsDeployment.Name, gitopsDeployment.Namespace)
+	}
+
+	// Sync the GitOpsDeploymentSyncRuns that were held waiting on the dependencies
+	for _, syncRun := range syncRuns {
+
+		if syncRun.DeletionTimestamp != nil || !isSyncRunWaitingOnDependencies(syncRun) {
+			continue
+		}
+
+		a.log.Info("dependencies of GitOpsDeployment are ready, queueing held GitOpsDeploymentSyncRun", "syncRun", syncRun.Name)
+
+		// GitOpsDeploymentSyncRuns are processed by the sync run runner, so queue an event for it to sync the held GitOpsDeploymentSyncRun
+		a.queueEvent(eventlooptypes.SyncRunModified, eventlooptypes.GitOpsDeploymentSyncRunTypeName, syncRun.Name, syncRun.Namespace)
+	}
+
+	return nil
+}
+
+// getUnreadyDependenciesOfGitOpsDeployment returns a user-facing description of each of the GitOpsDeployments in
+// .spec.dependsOn that does not exist, or is not yet both Synced and Healthy.
+func getUnreadyDependenciesOfGitOpsDeployment(ctx context.Context, k8sClient client.Client,
+	gitopsDeployment managedgitopsv1alpha1.GitOpsDeployment) ([]string, error) {
+
+	unreadyDependencies := []string{}
+
+	for _, dependencyName := range gitopsDeployment.Spec.DependsOn {
+
+		dependency := managedgitopsv1alpha1.GitOpsDeployment{}
+		if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: gitopsDeployment.Namespace, Name: dependencyName}, &dependency); err != nil {
+			if apierr.IsNotFound(err) {
+				unreadyDependencies = append(unreadyDependencies, fmt.Sprintf("'%s' (not found)", dependencyName))
+				continue
+			}
+			return nil, fmt.Errorf("unable to retrieve GitOpsDeployment dependency '%s': %v", dependencyName, err)
 		}
 
+		syncStatus := dependency.Status.Sync.Status
+		healthStatus := dependency.Status.Health.Status
+
+		if syncStatus == managedgitopsv1alpha1.SyncStatusCodeSynced && healthStatus == managedgitopsv1alpha1.HeathStatusCodeHealthy {
+			continue
+		}
+
+		if syncStatus == "" {
+			syncStatus = managedgitopsv1alpha1.SyncStatusCodeUnknown
+		}
+		if healthStatus == "" {
+			healthStatus = managedgitopsv1alpha1.HeathStatusCodeUnknown
+		}
+
+		unreadyDependencies = append(unreadyDependencies, fmt.Sprintf("'%s' (sync: %s, health: %s)", dependencyName, syncStatus, healthStatus))
+	}
+
+	return unreadyDependencies, nil
+}
+
+// newWaitingOnDependenciesCondition returns a WaitingOnDependencies condition that lists the given dependencies.
+func newWaitingOnDependenciesCondition(unreadyDependencies []string) *managedgitopsv1alpha1.GitOpsDeploymentCondition {
+	return &managedgitopsv1alpha1.GitOpsDeploymentCondition{
+		Type:    managedgitopsv1alpha1.GitOpsDeploymentConditionWaitingOnDependencies,
+		Message: "waiting on GitOpsDeployments in .spec.dependsOn to be Synced and Healthy: " + strings.Join(unreadyDependencies, ", "),
+		Reason:  managedgitopsv1alpha1.GitopsDeploymentReasonWaitingOnDependencies,
+	}
+}
+
+// newDependenciesUnavailableCondition returns a WaitingOnDependencies condition for a GitOpsDeployment whose
+// dependencies could not be checked: the dependencies are checked again on the next tick.
+func newDependenciesUnavailableCondition() *managedgitopsv1alpha1.GitOpsDeploymentCondition {
+	return &managedgitopsv1alpha1.GitOpsDeploymentCondition{
+		Type:    managedgitopsv1alpha1.GitOpsDeploymentConditionWaitingOnDependencies,
+		Message: "unable to check whether the GitOpsDeployments in .spec.dependsOn are Synced and Healthy: this will be retried",
+		Reason:  managedgitopsv1alpha1.GitopsDeploymentReasonWaitingOnDependencies,
+	}
+}
+
+// updateWaitingOnDependenciesCondition sets the WaitingOnDependencies condition of the GitOpsDeployment, or, if
+// waitingCondition is nil, marks an existing WaitingOnDependencies condition as resolved. The status of the
+// GitOpsDeployment is only updated if the condition changed.
+//
+// Returns true if the status of the GitOpsDeployment was updated, false otherwise.
+func (a *applicationEventLoopRunner_Action) updateWaitingOnDependenciesCondition(ctx context.Context,
+	gitopsDeployment *managedgitopsv1alpha1.GitOpsDeployment, waitingCondition *managedgitopsv1alpha1.GitOpsDeploymentCondition) (bool, error) {
+
+	conditionType := managedgitopsv1alpha1.GitOpsDeploymentConditionWaitingOnDependencies
+
+	status := managedgitopsv1alpha1.GitOpsConditionStatusTrue
+	reason := managedgitopsv1alpha1.GitopsDeploymentReasonWaitingOnDependencies
+	message := ""
+
+	if waitingCondition != nil {
+		message = waitingCondition.Message
+	} else {
+		status = managedgitopsv1alpha1.GitOpsConditionStatusFalse
+		reason = reason + "Resolved"
+	}
+
+	conditionManager := condition.NewConditionManager()
+
+	if !conditionManager.HasCondition(&gitopsDeployment.Status.Conditions, conditionType) {
+		if waitingCondition == nil {
+			// Nothing to resolve
+			return false, nil
+		}
+	} else if cond, _ := conditionManager.FindCondition(&gitopsDeployment.Status.Conditions, conditionType); cond.Status == status &&
+		cond.Reason == reason && cond.Message == message {
+		// The condition is unchanged
+		return false, nil
+	}
+
+	conditionManager.SetCondition(&gitopsDeployment.Status.Conditions, conditionType, status, reason, message)
+
+	if err := a.workspaceClient.Status().Update(ctx, gitopsDeployment, &client.SubResourceUpdateOptions{}); err != nil {
+		return false, fmt.Errorf("unable to update WaitingOnDependencies condition of GitOpsDeployment: %v", err)
+	}
+
+	return true, nil
+}
+
+func checkValidSyncOption(syncOptions []managedgitopsv1alpha1.SyncOption) gitopserrors.UserError {
+
+	for _, syncOptionString := range syncOptions {
+
 		// If at least one of the options don't match, return a user  error
-		if !match {
+		if !managedgitopsv1alpha1.IsValidSyncOption(syncOptionString) {
 			userError := "the specified sync option in .spec.syncPolicy.syncOptions is either mispelled or is not supported by GitOpsDeployment"
 			devError := fmt.Errorf("invalid SyncOption : %s", syncOptionString)
 
@@ -1196,6 +1740,286 @@ func checkValidSyncOption(syncOptions []managedgitopsv1alpha1.SyncOption) gitops
 	return nil
 }
 
+// syncWindowsAllowSync returns true if the sync windows of the GitOpsDeployment (if any) allow a sync at the given time.
+// If not, a user-facing message that describes when the next sync window opens is also returned.
+func syncWindowsAllowSync(gitopsDeployment *managedgitopsv1alpha1.GitOpsDeployment, now time.Time) (bool, string, error) {
+
+	if gitopsDeployment.Spec.SyncPolicy == nil || len(gitopsDeployment.Spec.SyncPolicy.SyncWindows) == 0 {
+		return true, "", nil
+	}
+
+	allowed, nextOpen, err := gitopsDeployment.Spec.SyncPolicy.SyncWindows.SyncAllowed(now)
+	if err != nil || allowed {
+		return allowed, "", err
+	}
+
+	if nextOpen.IsZero() {
+		return false, "the sync windows of the GitOpsDeployment do not currently allow a sync, and no upcoming sync window was found", nil
+	}
+
+	return false, fmt.Sprintf("the sync windows of the GitOpsDeployment do not currently allow a sync: the next sync window opens at %s",
+		nextOpen.UTC().Format(time.RFC3339)), nil
+}
+
+// disableAutomatedSyncOutsideSyncWindows disables automated sync of the Argo CD Application while the sync windows of the
+// GitOpsDeployment do not allow a sync. The deployment status tick will update the Application once a sync window opens.
+func disableAutomatedSyncOutsideSyncWindows(specFieldInput *argoCDSpecInput, gitopsDeployment *managedgitopsv1alpha1.GitOpsDeployment, now time.Time) gitopserrors.UserError {
+
+	if !specFieldInput.automated {
+		return nil
+	}
+
+	allowed, _, err := syncWindowsAllowSync(gitopsDeployment, now)
+	if err != nil {
+		return gitopserrors.NewUserDevError(fmt.Sprintf("invalid sync window in .spec.syncPolicy.syncWindows: %v", err), err)
+	}
+
+	specFieldInput.automated = allowed
+
+	return nil
+}
+
+// applyRollbackInEffect updates the Argo CD Application while a GitOpsDeploymentSyncRun is rolling back the GitOpsDeployment:
+// - automated sync is disabled: otherwise Argo CD would immediately sync the Application back to its target revision.
+// - the source(s) of the Application are those of the deployment history entry that is rolled back to, pinned to the
+// revision(s) that were deployed.
+//
+// Returns the deployment history entry that is rolled back to, or nil if no rollback is in effect.
+func (a *applicationEventLoopRunner_Action) applyRollbackInEffect(ctx context.Context, specFieldInput *argoCDSpecInput,
+	gitopsDeployment managedgitopsv1alpha1.GitOpsDeployment, applicationID string, dbQueries db.ApplicationScopedQueries) (*managedgitopsv1alpha1.DeploymentHistory, gitopserrors.UserError) {
+
+	rollbackInEffect, err := getRollbackInEffect(ctx, a.workspaceClient, dbQueries, applicationID, gitopsDeployment, a.log)
+	if err != nil {
+		return nil, gitopserrors.NewDevOnlyError(err)
+	}
+
+	if rollbackInEffect == nil {
+		return nil, nil
+	}
+
+	if specFieldInput.automated {
+		a.log.Info("disabling automated sync of GitOpsDeployment while a rollback is in effect")
+		specFieldInput.automated = false
+	}
+
+	rollBackSourcesOfSpecInput(specFieldInput, *rollbackInEffect)
+
+	return rollbackInEffect, nil
+}
+
+// rollBackSourcesOfSpecInput replaces the source(s) of the Argo CD Application with those of the deployment history entry,
+// pinned to the revision(s) that were deployed. The Helm and Kustomize parameters are not part of the deployment history,
+// so those of the current source(s) are kept.
+func rollBackSourcesOfSpecInput(specFieldInput *argoCDSpecInput, history managedgitopsv1alpha1.DeploymentHistory) {
+
+	if len(history.Sources) == 0 {
+
+		// The source of the deployment history entry is unknown, so only the revision can be rolled back
+		if history.Source.RepoURL == "" {
+			specFieldInput.sourceTargetRevision = history.Revision
+			return
+		}
+
+		if len(specFieldInput.sources) > 0 {
+			// The GitOpsDeployment has since moved from a single source to multiple sources
+			specFieldInput.sourceHelm = specFieldInput.sources[0].Helm
+			specFieldInput.sourceKustomize = specFieldInput.sources[0].Kustomize
+			specFieldInput.sources = nil
+		}
+
+		specFieldInput.sourceRepoURL = history.Source.RepoURL
+		specFieldInput.sourcePath = history.Source.Path
+		specFieldInput.sourceChart = history.Source.Chart
+		specFieldInput.sourceTargetRevision = history.Revision
+		return
+	}
+
+	sources := convertToFauxApplicationSources(history.Sources)
+	for i := range sources {
+		if i < len(history.Revisions) {
+			sources[i].TargetRevision = history.Revisions[i]
+		}
+		if i < len(specFieldInput.sources) {
+			sources[i].Helm = specFieldInput.sources[i].Helm
+			sources[i].Kustomize = specFieldInput.sources[i].Kustomize
+		}
+	}
+
+	specFieldInput.sources = sources
+	specFieldInput.sourceRepoURL = ""
+	specFieldInput.sourcePath = ""
+	specFieldInput.sourceTargetRevision = ""
+	specFieldInput.sourceChart = ""
+	specFieldInput.sourceHelm = nil
+	specFieldInput.sourceKustomize = nil
+}
+
+// queueHeldRollbackSyncRuns queues an event for each GitOpsDeploymentSyncRun of the GitOpsDeployment that rolls back to
+// the given deployment history entry (by ID or by revision), and that has not yet been synced: the sync of a rollback is
+// held until the rollback has been applied to the Application, which is done by this runner.
+func (a *applicationEventLoopRunner_Action) queueHeldRollbackSyncRuns(ctx context.Context, gitopsDeployment managedgitopsv1alpha1.GitOpsDeployment,
+	rollbackInEffect managedgitopsv1alpha1.DeploymentHistory, dbQueries db.ApplicationScopedQueries) {
+
+	syncRuns, err := listSyncRunsOfGitOpsDeployment(ctx, a.workspaceClient, gitopsDeployment)
+	if err != nil {
+		a.log.Error(err, "unable to list GitOpsDeploymentSyncRuns, to sync held rollbacks")
+		return
+	}
+
+	for _, syncRun := range syncRuns {
+
+		rollsBackToHistory := (syncRun.Spec.RollbackToHistoryID != "" && syncRun.Spec.RollbackToHistoryID == rollbackInEffect.ID) ||
+			(syncRun.Spec.RollbackToRevision != "" && syncRun.Spec.RollbackToRevision == rollbackInEffect.Revision)
+
+		if !rollsBackToHistory || syncRun.DeletionTimestamp != nil {
+			continue
+		}
+
+		// A GitOpsDeploymentSyncRun that already has a SyncOperation is not held
+		mapping := db.APICRToDatabaseMapping{
+			APIResourceType: db.APICRToDatabaseMapping_ResourceType_GitOpsDeploymentSyncRun,
+			APIResourceUID:  string(syncRun.UID),
+			DBRelationType:  db.APICRToDatabaseMapping_DBRelationType_SyncOperation,
+		}
+		if err := dbQueries.GetDatabaseMappingForAPICR(ctx, &mapping); err == nil {
+			continue
+		} else if !db.IsResultNotFoundError(err) {
+			a.log.Error(err, "unable to retrieve APICRToDatabaseMapping of GitOpsDeploymentSyncRun, to sync held rollbacks", "syncRun", syncRun.Name)
+			continue
+		}
+
+		a.log.Info("rollback has been applied to the GitOpsDeployment, queueing held rollback GitOpsDeploymentSyncRun", "syncRun", syncRun.Name)
+		a.queueEvent(eventlooptypes.SyncRunModified, eventlooptypes.GitOpsDeploymentSyncRunTypeName, syncRun.Name, syncRun.Namespace)
+	}
+}
+
+// isAutomatedSyncEnabledForApplication returns true if automated sync is enabled in the spec field of the Application row.
+func isAutomatedSyncEnabledForApplication(application db.Application) (bool, error) {
+
+	var fauxApplication fauxargocd.FauxApplication
+	if err := goyaml.Unmarshal([]byte(application.Spec_field), &fauxApplication); err != nil {
+		return false, fmt.Errorf("unable to unmarshal spec field of Application '%s': %v", application.Application_id, err)
+	}
+
+	return fauxApplication.Spec.SyncPolicy != nil && fauxApplication.Spec.SyncPolicy.Automated != nil, nil
+}
+
+// listSyncRunsOfGitOpsDeployment returns the GitOpsDeploymentSyncRuns in the namespace of the GitOpsDeployment that target it.
+func listSyncRunsOfGitOpsDeployment(ctx context.Context, k8sClient client.Client,
+	gitopsDeployment managedgitopsv1alpha1.GitOpsDeployment) ([]managedgitopsv1alpha1.GitOpsDeploymentSyncRun, error) {
+
+	var syncRunList managedgitopsv1alpha1.GitOpsDeploymentSyncRunList
+	if err := k8sClient.List(ctx, &syncRunList, &client.ListOptions{Namespace: gitopsDeployment.Namespace}); err != nil {
+		return nil, fmt.Errorf("unable to list GitOpsDeploymentSyncRuns in namespace '%s': %v", gitopsDeployment.Namespace, err)
+	}
+
+	var res []managedgitopsv1alpha1.GitOpsDeploymentSyncRun
+	for _, syncRun := range syncRunList.Items {
+		if syncRun.Spec.GitopsDeploymentName == gitopsDeployment.Name {
+			res = append(res, syncRun)
+		}
+	}
+
+	return res, nil
+}
+
+// getRollbackInEffect returns the deployment history entry that the GitOpsDeployment is rolled back to, if a
+// GitOpsDeploymentSyncRun exists in the namespace that rolls back the GitOpsDeployment to an entry of the deployment
+// history of its Application. If there are several, the most recently created GitOpsDeploymentSyncRun is in effect.
+// Returns nil if no rollback is in effect.
+//
+// The deployment history is read from the database, rather than from .status.history of the GitOpsDeployment, which
+// may be modified by the user.
+func getRollbackInEffect(ctx context.Context, k8sClient client.Client, dbQueries db.ApplicationScopedQueries, applicationID string,
+	gitopsDeployment managedgitopsv1alpha1.GitOpsDeployment, log logr.Logger) (*managedgitopsv1alpha1.DeploymentHistory, error) {
+
+	syncRuns, err := listSyncRunsOfGitOpsDeployment(ctx, k8sClient, gitopsDeployment)
+	if err != nil {
+		return nil, err
+	}
+
+	return findRollbackInEffect(ctx, syncRuns, dbQueries, applicationID, log)
+}
+
+// findRollbackInEffect returns the deployment history entry that is rolled back to by the given GitOpsDeploymentSyncRuns
+// of a GitOpsDeployment, as described in getRollbackInEffect.
+func findRollbackInEffect(ctx context.Context, syncRuns []managedgitopsv1alpha1.GitOpsDeploymentSyncRun, dbQueries db.ApplicationScopedQueries,
+	applicationID string, log logr.Logger) (*managedgitopsv1alpha1.DeploymentHistory, error) {
+
+	var deploymentHistory []managedgitopsv1alpha1.DeploymentHistory
+	deploymentHistoryRead := false
+
+	var rollbackSyncRun *managedgitopsv1alpha1.GitOpsDeploymentSyncRun
+	var rollbackInEffect *managedgitopsv1alpha1.DeploymentHistory
+
+	for i := range syncRuns {
+		syncRun := &syncRuns[i]
+
+		if !syncRun.Spec.IsRollback() || syncRun.DeletionTimestamp != nil {
+			continue
+		}
+
+		// The deployment history is only read once a rollback GitOpsDeploymentSyncRun is found
+		if !deploymentHistoryRead {
+			var err error
+			if deploymentHistory, err = getDeploymentHistoryOfApplication(ctx, dbQueries, applicationID, log); err != nil {
+				return nil, err
+			}
+			deploymentHistoryRead = true
+		}
+
+		// A rollback to an entry that is not in the deployment history is rejected, and thus is not in effect
+		history := findDeploymentHistoryOfRollback(deploymentHistory, syncRun.Spec)
+		if history == nil {
+			continue
+		}
+
+		if rollbackSyncRun == nil || rollbackSyncRun.CreationTimestamp.Before(&syncRun.CreationTimestamp) {
+			rollbackSyncRun = syncRun
+			rollbackInEffect = history
+		}
+	}
+
+	return rollbackInEffect, nil
+}
+
+// getDeploymentHistoryOfApplication returns the deployment history of the Application, as reported in .status.history
+// of its GitOpsDeployment: only these entries may be rolled back to.
+func getDeploymentHistoryOfApplication(ctx context.Context, dbQueries db.ApplicationScopedQueries, applicationID string,
+	log logr.Logger) ([]managedgitopsv1alpha1.DeploymentHistory, error) {
+
+	if applicationID == "" {
+		// The Application has not been created yet, so nothing has been deployed
+		return nil, nil
+	}
+
+	var deploymentHistory []db.DeploymentHistory
+	if err := dbQueries.ListDeploymentHistoryByApplicationId(ctx, applicationID, &deploymentHistory); err != nil {
+		return nil, fmt.Errorf("unable to list deployment history of Application '%s': %v", applicationID, err)
+	}
+
+	return convertDeploymentHistoryToStatus(deploymentHistory, log), nil
+}
+
+// findDeploymentHistoryOfRollback returns the entry of the deployment history that a rollback GitOpsDeploymentSyncRun
+// rolls back to: either the entry with the given ID, or the most recent entry that deployed the given revision. Returns
+// nil if there is no such entry.
+func findDeploymentHistoryOfRollback(deploymentHistory []managedgitopsv1alpha1.DeploymentHistory,
+	syncRunSpec managedgitopsv1alpha1.GitOpsDeploymentSyncRunSpec) *managedgitopsv1alpha1.DeploymentHistory {
+
+	for i := len(deploymentHistory) - 1; i >= 0; i-- {
+		if syncRunSpec.RollbackToHistoryID != "" {
+			if deploymentHistory[i].ID == syncRunSpec.RollbackToHistoryID {
+				return &deploymentHistory[i]
+			}
+		} else if syncRunSpec.RollbackToRevision != "" && deploymentHistory[i].Revision == syncRunSpec.RollbackToRevision {
+			return &deploymentHistory[i]
+		}
+	}
+
+	return nil
+}
+
 type argoCDSpecInput struct {
 	// MAKE SURE YOU SANITIZE ANY NEW FIELDS THAT ARE ADDED!!!!
 	crName      string
@@ -1207,15 +2031,183 @@ type argoCDSpecInput struct {
 	sourceRepoURL        string
 	sourcePath           string
 	sourceTargetRevision string
-	syncOptions          []string
+	sourceChart          string
+	sourceHelm           *fauxargocd.ApplicationSourceHelm
+	sourceKustomize      *fauxargocd.ApplicationSourceKustomize
+	// sources is used instead of the single source fields above, for applications with multiple sources
+	sources     []fauxargocd.ApplicationSource
+	syncOptions []string
 	// MAKE SURE YOU SANITIZE ANY NEW FIELDS THAT ARE ADDED!!!!
 	automated bool
+	// automatedPolicy is only used when automated is true: if nil, automated sync will prune, self-heal, and allow empty.
+	automatedPolicy *fauxargocd.SyncPolicyAutomated
+	// retry, if non-nil, replaces the default retry strategy of automated sync, and is also used for manual syncs.
+	retry             *fauxargocd.RetryStrategy
+	ignoreDifferences []fauxargocd.ResourceIgnoreDifferences
 	// MAKE SURE YOU SANITIZE ANY NEW FIELDS THAT ARE ADDED!!!!
 	project string
+	// suspended is true if reconciliation of the GitOpsDeployment is suspended: automated sync is then disabled, regardless of 'automated'.
+	suspended bool
+	// deletionPolicy is the .spec.deletionPolicy of the GitOpsDeployment: it is an enum, so there is nothing to sanitize.
+	deletionPolicy managedgitopsv1alpha1.DeletionPolicy
 
 	// Hopefully you are getting the message, here :)
 }
 
+// validateGitOpsDeploymentSources performs basic validation of the .spec.source/.spec.sources fields of a GitOpsDeployment.
+// Returns a non-empty user error if the field(s) are invalid.
+func validateGitOpsDeploymentSources(spec managedgitopsv1alpha1.GitOpsDeploymentSpec) string {
+
+	if len(spec.Sources) == 0 {
+		if spec.Source.Path != "" && spec.Source.Chart != "" {
+			return managedgitopsv1alpha1.GitOpsDeploymentUserError_PathAndChartAreExclusive
+
+		} else if spec.Source.Path == "" && spec.Source.Chart == "" {
+			// Path is only optional when the GitOpsDeployment is sourced from a Helm chart
+			return managedgitopsv1alpha1.GitOpsDeploymentUserError_PathIsRequired
+
+		} else if spec.Source.Path == "/" {
+			return managedgitopsv1alpha1.GitOpsDeploymentUserError_InvalidPathSlash
+		}
+
+		return ""
+	}
+
+	if !reflect.DeepEqual(spec.Source, managedgitopsv1alpha1.ApplicationSource{}) {
+		return managedgitopsv1alpha1.GitOpsDeploymentUserError_SourceAndSourcesAreExclusive
+	}
+
+	for _, source := range spec.Sources {
+		if source.Path != "" && source.Chart != "" {
+			return managedgitopsv1alpha1.GitOpsDeploymentUserError_PathAndChartAreExclusive
+
+		} else if source.Path == "" && source.Chart == "" && source.Ref == "" {
+			// A source that only provides files to other sources, via 'ref', does not require a path
+			return managedgitopsv1alpha1.GitOpsDeploymentUserError_SourcesPathIsRequired
+
+		} else if source.Path == "/" {
+			return managedgitopsv1alpha1.GitOpsDeploymentUserError_InvalidPathSlash
+		}
+	}
+
+	return ""
+}
+
+// convertToFauxApplicationSources converts the .spec.sources field of a GitOpsDeployment into the equivalent Argo CD Application field
+func convertToFauxApplicationSources(sources []managedgitopsv1alpha1.ApplicationSource) []fauxargocd.ApplicationSource {
+
+	var res []fauxargocd.ApplicationSource
+
+	for _, source := range sources {
+		res = append(res, fauxargocd.ApplicationSource{
+			RepoURL:        source.RepoURL,
+			Path:           source.Path,
+			TargetRevision: source.TargetRevision,
+			Chart:          source.Chart,
+			Helm:           convertToFauxApplicationSourceHelm(source.Helm),
+			Kustomize:      convertToFauxApplicationSourceKustomize(source.Kustomize),
+			Ref:            source.Ref,
+		})
+	}
+
+	return res
+}
+
+// convertToFauxSyncPolicyAutomated converts the .spec.syncPolicy.automated field of a GitOpsDeployment into the equivalent
+// Argo CD Application field, applying the defaults for any unspecified values. Returns nil if the field is not specified.
+func convertToFauxSyncPolicyAutomated(syncPolicy *managedgitopsv1alpha1.SyncPolicy) *fauxargocd.SyncPolicyAutomated {
+	if syncPolicy == nil || syncPolicy.Automated == nil {
+		return nil
+	}
+
+	return &fauxargocd.SyncPolicyAutomated{
+		Prune:      syncPolicy.Automated.IsPrune(),
+		SelfHeal:   syncPolicy.Automated.IsSelfHeal(),
+		AllowEmpty: syncPolicy.Automated.IsAllowEmpty(),
+	}
+}
+
+// convertToFauxRetryStrategy converts the retry strategy of a GitOpsDeployment into the equivalent Argo CD Application field.
+// Returns nil if no retry strategy is specified, in which case the default retry behaviour is used.
+func convertToFauxRetryStrategy(syncPolicy *managedgitopsv1alpha1.SyncPolicy) *fauxargocd.RetryStrategy {
+	if syncPolicy == nil || syncPolicy.Retry == nil {
+		return nil
+	}
+
+	res := &fauxargocd.RetryStrategy{
+		Limit: syncPolicy.Retry.Limit,
+	}
+
+	if backoff := syncPolicy.Retry.Backoff; backoff != nil {
+		res.Backoff = &fauxargocd.Backoff{
+			Duration:    backoff.Duration,
+			MaxDuration: backoff.MaxDuration,
+		}
+		if backoff.Factor != nil {
+			factor := *backoff.Factor
+			res.Backoff.Factor = &factor
+		}
+	}
+
+	return res
+}
+
+// convertToFauxIgnoreDifferences converts the ignoreDifferences field of a GitOpsDeployment into the equivalent Argo CD Application field
+func convertToFauxIgnoreDifferences(ignoreDifferences []managedgitopsv1alpha1.ResourceIgnoreDifferences) []fauxargocd.ResourceIgnoreDifferences {
+	var res []fauxargocd.ResourceIgnoreDifferences
+
+	for _, ignoreDifference := range ignoreDifferences {
+		res = append(res, fauxargocd.ResourceIgnoreDifferences{
+			Group:             ignoreDifference.Group,
+			Kind:              ignoreDifference.Kind,
+			Name:              ignoreDifference.Name,
+			Namespace:         ignoreDifference.Namespace,
+			JSONPointers:      ignoreDifference.JSONPointers,
+			JQPathExpressions: ignoreDifference.JQPathExpressions,
+		})
+	}
+
+	return res
+}
+
+// convertToFauxApplicationSourceHelm converts the Helm options of a GitOpsDeployment into the equivalent Argo CD Application field
+func convertToFauxApplicationSourceHelm(helm *managedgitopsv1alpha1.ApplicationSourceHelm) *fauxargocd.ApplicationSourceHelm {
+	if helm == nil {
+		return nil
+	}
+
+	res := &fauxargocd.ApplicationSourceHelm{
+		ReleaseName: helm.ReleaseName,
+		Values:      helm.Values,
+		ValueFiles:  helm.ValueFiles,
+	}
+
+	for _, parameter := range helm.Parameters {
+		res.Parameters = append(res.Parameters, fauxargocd.HelmParameter{
+			Name:        parameter.Name,
+			Value:       parameter.Value,
+			ForceString: parameter.ForceString,
+		})
+	}
+
+	return res
+}
+
+// convertToFauxApplicationSourceKustomize converts the Kustomize overrides of a GitOpsDeployment into the equivalent Argo CD Application field
+func convertToFauxApplicationSourceKustomize(kustomize *managedgitopsv1alpha1.ApplicationSourceKustomize) *fauxargocd.ApplicationSourceKustomize {
+	if kustomize == nil {
+		return nil
+	}
+
+	return &fauxargocd.ApplicationSourceKustomize{
+		NamePrefix:        kustomize.NamePrefix,
+		NameSuffix:        kustomize.NameSuffix,
+		Images:            kustomize.Images,
+		CommonLabels:      kustomize.CommonLabels,
+		CommonAnnotations: kustomize.CommonAnnotations,
+	}
+}
+
 func createSpecField(fieldsParam argoCDSpecInput) (string, error) {
 
 	sanitize := func(input string) string {
@@ -1239,6 +2231,120 @@ func createSpecField(fieldsParam argoCDSpecInput) (string, error) {
 		return res
 	}
 
+	sanitizeHelm := func(input *fauxargocd.ApplicationSourceHelm) *fauxargocd.ApplicationSourceHelm {
+		if input == nil {
+			return nil
+		}
+
+		res := &fauxargocd.ApplicationSourceHelm{
+			ReleaseName: sanitize(input.ReleaseName),
+			// Values is an inline YAML block, so newlines and quotes are significant and cannot be stripped:
+			// the block is instead emitted as a quoted YAML scalar by the marshaller below.
+			Values: strings.ReplaceAll(input.Values, "\r", ""),
+		}
+
+		if len(input.ValueFiles) > 0 {
+			res.ValueFiles = sanitizeArray(input.ValueFiles)
+		}
+
+		for _, parameter := range input.Parameters {
+			res.Parameters = append(res.Parameters, fauxargocd.HelmParameter{
+				Name:        sanitize(parameter.Name),
+				Value:       sanitize(parameter.Value),
+				ForceString: parameter.ForceString,
+			})
+		}
+
+		return res
+	}
+
+	sanitizeMap := func(input map[string]string) map[string]string {
+		if len(input) == 0 {
+			return nil
+		}
+		res := map[string]string{}
+		for key, value := range input {
+			// Label and annotation values may legitimately contain any of the sanitized characters, and are emitted as
+			// quoted YAML scalars by the marshaller below, so (like Helm values) they are stored verbatim. The keys are
+			// validated as qualified names by the webhook.
+			res[sanitize(key)] = value
+		}
+		return res
+	}
+
+	sanitizeKustomize := func(input *fauxargocd.ApplicationSourceKustomize) *fauxargocd.ApplicationSourceKustomize {
+		if input == nil {
+			return nil
+		}
+
+		res := &fauxargocd.ApplicationSourceKustomize{
+			// The name prefix and suffix are validated by the webhook, so are stored verbatim
+			NamePrefix:        input.NamePrefix,
+			NameSuffix:        input.NameSuffix,
+			CommonLabels:      sanitizeMap(input.CommonLabels),
+			CommonAnnotations: sanitizeMap(input.CommonAnnotations),
+		}
+
+		if len(input.Images) > 0 {
+			res.Images = sanitizeArray(input.Images)
+		}
+
+		return res
+	}
+
+	sanitizeRetry := func(input *fauxargocd.RetryStrategy) *fauxargocd.RetryStrategy {
+		if input == nil || input.Backoff == nil {
+			return input
+		}
+
+		return &fauxargocd.RetryStrategy{
+			Limit: input.Limit,
+			Backoff: &fauxargocd.Backoff{
+				Duration:    sanitize(input.Backoff.Duration),
+				Factor:      input.Backoff.Factor,
+				MaxDuration: sanitize(input.Backoff.MaxDuration),
+			},
+		}
+	}
+
+	sanitizeIgnoreDifferences := func(input []fauxargocd.ResourceIgnoreDifferences) []fauxargocd.ResourceIgnoreDifferences {
+		var res []fauxargocd.ResourceIgnoreDifferences
+		for _, ignoreDifference := range input {
+			entry := fauxargocd.ResourceIgnoreDifferences{
+				Group:     sanitize(ignoreDifference.Group),
+				Kind:      sanitize(ignoreDifference.Kind),
+				Name:      sanitize(ignoreDifference.Name),
+				Namespace: sanitize(ignoreDifference.Namespace),
+			}
+			if len(ignoreDifference.JSONPointers) > 0 {
+				entry.JSONPointers = sanitizeArray(ignoreDifference.JSONPointers)
+			}
+			// JQ path expressions may legitimately contain quotes (for example, within 'select(.name == "value")'),
+			// so only line breaks are removed from them.
+			for _, expression := range ignoreDifference.JQPathExpressions {
+				entry.JQPathExpressions = append(entry.JQPathExpressions, strings.NewReplacer("\r", "", "\n", "").Replace(expression))
+			}
+			res = append(res, entry)
+		}
+		return res
+	}
+
+	sanitizeSources := func(input []fauxargocd.ApplicationSource) []fauxargocd.ApplicationSource {
+		var res []fauxargocd.ApplicationSource
+		for _, source := range input {
+			res = append(res, fauxargocd.ApplicationSource{
+				RepoURL:        sanitize(source.RepoURL),
+				Path:           sanitize(source.Path),
+				TargetRevision: sanitize(source.TargetRevision),
+				Chart:          sanitize(source.Chart),
+				Helm:           sanitizeHelm(source.Helm),
+				Kustomize:      sanitizeKustomize(source.Kustomize),
+				Ref:            sanitize(source.Ref),
+			})
+		}
+		return res
+	}
+
 	fields := argoCDSpecInput{
 		// MAKE SURE YOU SANITIZE ANY NEW FIELDS THAT ARE ADDED!!!!
 		crName:               sanitize(fieldsParam.crName),
@@ -1249,9 +2355,18 @@ func createSpecField(fieldsParam argoCDSpecInput) (string, error) {
 		sourceRepoURL:        sanitize(fieldsParam.sourceRepoURL),
 		sourcePath:           sanitize(fieldsParam.sourcePath),
 		sourceTargetRevision: sanitize(fieldsParam.sourceTargetRevision),
+		sourceChart:          sanitize(fieldsParam.sourceChart),
+		sourceHelm:           sanitizeHelm(fieldsParam.sourceHelm),
+		sourceKustomize:      sanitizeKustomize(fieldsParam.sourceKustomize),
+		sources:              sanitizeSources(fieldsParam.sources),
 		syncOptions:          sanitizeArray(fieldsParam.syncOptions),
 		automated:            fieldsParam.automated,
+		automatedPolicy:      fieldsParam.automatedPolicy, // contains only booleans, so there is nothing to sanitize
+		retry:                sanitizeRetry(fieldsParam.retry),
+		ignoreDifferences:    sanitizeIgnoreDifferences(fieldsParam.ignoreDifferences),
 		project:              sanitize(fieldsParam.project),
+		suspended:            fieldsParam.suspended,
+		deletionPolicy:       fieldsParam.deletionPolicy,
 		// MAKE SURE YOU SANITIZE ANY NEW FIELDS THAT ARE ADDED!!!!
 		// Hopefully you are getting the message, here :)
 	}
@@ -1270,22 +2385,56 @@ func createSpecField(fieldsParam argoCDSpecInput) (string, error) {
 				RepoURL:        fields.sourceRepoURL,
 				Path:           fields.sourcePath,
 				TargetRevision: fields.sourceTargetRevision,
+				Chart:          fields.sourceChart,
+				Helm:           fields.sourceHelm,
+				Kustomize:      fields.sourceKustomize,
 			},
 			Destination: fauxargocd.ApplicationDestination{
 				Name:      fields.destinationName,
 				Namespace: fields.destinationNamespace,
 			},
-			Project: fields.project,
+			Project:           fields.project,
+			IgnoreDifferences: fields.ignoreDifferences,
 		},
 	}
 
-	if fields.automated {
+	// When the application has multiple sources, they replace the single source (which is then omitted from the YAML)
+	if len(fields.sources) > 0 {
+		application.Spec.Source = fauxargocd.ApplicationSource{}
+		application.Spec.Sources = fields.sources
+	}
+
+	if fields.suspended {
+		// Inform the cluster agent that changes to the Argo CD Application should not be reverted
+		application.Annotations = map[string]string{fauxargocd.SuspendedAnnotation: "true"}
+	}
+
+	// Inform the cluster agent of a non-default deletion policy: 'Cascade' (or empty) is not included, so that the spec
+	// field of existing GitOpsDeployments is unchanged.
+	if fields.deletionPolicy == managedgitopsv1alpha1.DeletionPolicy_Orphan || fields.deletionPolicy == managedgitopsv1alpha1.DeletionPolicy_Foreground {
+		if application.Annotations == nil {
+			application.Annotations = map[string]string{}
+		}
+		application.Annotations[fauxargocd.DeletionPolicyAnnotation] = string(fields.deletionPolicy)
+	}
+
+	if fields.automated && !fields.suspended {
+
+		automatedPolicy := &fauxargocd.SyncPolicyAutomated{
+			Prune:      true,
+			SelfHeal:   true,
+			AllowEmpty: true,
+		}
+		if fields.automatedPolicy != nil {
+			automatedPolicy = &fauxargocd.SyncPolicyAutomated{
+				Prune:      fields.automatedPolicy.Prune,
+				SelfHeal:   fields.automatedPolicy.SelfHeal,
+				AllowEmpty: fields.automatedPolicy.AllowEmpty,
+			}
+		}
+
 		application.Spec.SyncPolicy = &fauxargocd.SyncPolicy{
-			Automated: &fauxargocd.SyncPolicyAutomated{
-				Prune:      true,
-				SelfHeal:   true,
-				AllowEmpty: true,
-			},
+			Automated: automatedPolicy,
 			SyncOptions: fauxargocd.SyncOptions{
 				prunePropagationPolicy,
 			},
@@ -1299,9 +2448,19 @@ func createSpecField(fieldsParam argoCDSpecInput) (string, error) {
 			},
 		}
 
+		if fields.retry != nil {
+			application.Spec.SyncPolicy.Retry = fields.retry
+		}
+
 	} else {
 		// !fields.automated
 		application.Spec.SyncPolicy = nil
+
+		if fields.retry != nil {
+			application.Spec.SyncPolicy = &fauxargocd.SyncPolicy{
+				Retry: fields.retry,
+			}
+		}
 	}
 
 	if len(fields.syncOptions) > 0 {
@@ -1315,6 +2474,18 @@ func createSpecField(fieldsParam argoCDSpecInput) (string, error) {
 		}
 
 		for _, syncOptionString := range fields.syncOptions {
+
+			// A user-specified PrunePropagationPolicy replaces the default policy of automated sync
+			if strings.HasPrefix(syncOptionString, managedgitopsv1alpha1.SyncOptions_PrunePropagationPolicy_prefix) {
+				syncOptions := fauxargocd.SyncOptions{}
+				for _, existingSyncOption := range application.Spec.SyncPolicy.SyncOptions {
+					if existingSyncOption != prunePropagationPolicy {
+						syncOptions = append(syncOptions, existingSyncOption)
+					}
+				}
+				application.Spec.SyncPolicy.SyncOptions = syncOptions
+			}
+
 			application.Spec.SyncPolicy.SyncOptions = append(application.Spec.SyncPolicy.SyncOptions,
 				syncOptionString)
 		}
@@ -1344,6 +2515,39 @@ func decompressApplicationStatus(statusBytes []byte) (*fauxargocd.FauxApplicatio
 	return appStatus, nil
 }
 
+// decompressResourceTree converts the resource tree of the ApplicationState table into the .status.resourceTree field of
+// a GitOpsDeployment. Returns nil if the resource tree has not been collected.
+func decompressResourceTree(resourceTreeBytes []byte) (*managedgitopsv1alpha1.ResourceTree, error) {
+	if len(resourceTreeBytes) == 0 {
+		return nil, nil
+	}
+
+	decompressedBytes, err := sharedutil.DecompressObject(resourceTreeBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress resource tree: %v", err)
+	}
+
+	resourceTree := managedgitopsv1alpha1.ResourceTree{}
+	if err := goyaml.Unmarshal(decompressedBytes, &resourceTree); err != nil {
+		return nil, fmt.Errorf("unable to Unmarshal resource tree: %v", err)
+	}
+
+	// Round-trip the resource tree through JSON, so that it matches a resource tree that has been read from the K8s API
+	// (empty lists are omitted, and times have the same precision and location), and thus the status is not seen as
+	// modified on every tick
+	resourceTreeJSON, err := json.Marshal(resourceTree)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &managedgitopsv1alpha1.ResourceTree{}
+	if err := json.Unmarshal(resourceTreeJSON, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
 func extractResourceStatus(resources []fauxargocd.ResourceStatus) []managedgitopsv1alpha1.ResourceStatus {
 	resourceStatus := make([]managedgitopsv1alpha1.ResourceStatus, len(resources))
 
@@ -1367,6 +2571,56 @@ func extractResourceStatus(resources []fauxargocd.ResourceStatus) []managedgitop
 	return resourceStatus
 }
 
+// convertDeploymentHistoryToStatus converts DeploymentHistory rows (ordered from oldest to newest) into the .status.history
+// field of a GitOpsDeployment, keeping only the most recent MaxDeploymentHistory entries.
+func convertDeploymentHistoryToStatus(deploymentHistory []db.DeploymentHistory, log logr.Logger) []managedgitopsv1alpha1.DeploymentHistory {
+
+	if len(deploymentHistory) > managedgitopsv1alpha1.MaxDeploymentHistory {
+		deploymentHistory = deploymentHistory[len(deploymentHistory)-managedgitopsv1alpha1.MaxDeploymentHistory:]
+	}
+
+	var res []managedgitopsv1alpha1.DeploymentHistory
+	for _, history := range deploymentHistory {
+
+		source := managedgitopsv1alpha1.ApplicationSource{}
+		if history.Source != "" {
+			if err := json.Unmarshal([]byte(history.Source), &source); err != nil {
+				// Report the rest of the entry, even if the source could not be parsed
+				log.Error(err, "unable to unmarshal source of deployment history", "deploymentHistoryID", history.DeploymentHistory_id)
+			}
+		}
+
+		var sources []managedgitopsv1alpha1.ApplicationSource
+		if history.Sources != "" {
+			if err := json.Unmarshal([]byte(history.Sources), &sources); err != nil {
+				log.Error(err, "unable to unmarshal sources of deployment history", "deploymentHistoryID", history.DeploymentHistory_id)
+			}
+		}
+
+		var revisions []string
+		if history.Revisions != "" {
+			if err := json.Unmarshal([]byte(history.Revisions), &revisions); err != nil {
+				log.Error(err, "unable to unmarshal revisions of deployment history", "deploymentHistoryID", history.DeploymentHistory_id)
+			}
+		}
+
+		res = append(res, managedgitopsv1alpha1.DeploymentHistory{
+			ID:        history.DeploymentHistory_id,
+			Revision:  history.Revision,
+			Source:    source,
+			Sources:   sources,
+			Revisions: revisions,
+			// Match the precision and location of a time that has been read from the K8s API, so that the status is
+			// not seen as modified on every tick
+			DeployedAt:  metav1.NewTime(history.DeployedAt.Truncate(time.Second).Local()),
+			InitiatedBy: history.InitiatedBy,
+			SyncRun:     history.SyncRunName,
+		})
+	}
+
+	return res
+}
+
 func extractOperationState(fauxOpState *fauxargocd.OperationState) (*managedgitopsv1alpha1.OperationState, error) {
 	if fauxOpState == nil {
 		return nil, nil
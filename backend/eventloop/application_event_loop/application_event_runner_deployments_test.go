@@ -6,22 +6,28 @@ import (
 	"strings"
 	"time"
 
+	"github.com/golang/mock/gomock"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
 	db "github.com/redhat-appstudio/managed-gitops/backend-shared/db"
 	sharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/fauxargocd"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/mocks"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/tests"
+	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/eventlooptypes"
 	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/shared_resource_loop"
+	"github.com/redhat-appstudio/managed-gitops/backend/events"
 	"gopkg.in/yaml.v2"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	fake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -119,9 +125,975 @@ var _ = Describe("Application Event Runner Deployments", func() {
 			Expect(err).ToNot(HaveOccurred())
 			Expect(application).To(Equal(getValidApplication(true)))
 		})
+
+		It("Input spec with automated enabled and an automated policy should use the policy's prune/selfHeal/allowEmpty values", func() {
+			input := getFakeArgoCDSpecInput(true, false)
+			input.automatedPolicy = &fauxargocd.SyncPolicyAutomated{
+				Prune:      false,
+				SelfHeal:   true,
+				AllowEmpty: false,
+			}
+
+			application, err := createSpecField(input)
+			Expect(err).ToNot(HaveOccurred())
+
+			fauxApp := fauxargocd.FauxApplication{}
+			Expect(yaml.Unmarshal([]byte(application), &fauxApp)).To(Succeed())
+
+			Expect(fauxApp.Spec.SyncPolicy).ToNot(BeNil())
+			Expect(fauxApp.Spec.SyncPolicy.Automated).To(Equal(&fauxargocd.SyncPolicyAutomated{
+				Prune:      false,
+				SelfHeal:   true,
+				AllowEmpty: false,
+			}))
+		})
+
+		It("Input spec with automated disabled should ignore the automated policy", func() {
+			input := getFakeArgoCDSpecInput(false, false)
+			input.automatedPolicy = &fauxargocd.SyncPolicyAutomated{Prune: false}
+
+			application, err := createSpecField(input)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(application).To(Equal(getValidApplication(false)))
+		})
+
+		It("Input spec of a suspended GitOpsDeployment should disable automated sync and set the suspended annotation", func() {
+			input := getFakeArgoCDSpecInput(true, false)
+			input.suspended = true
+
+			application, err := createSpecField(input)
+			Expect(err).ToNot(HaveOccurred())
+
+			fauxApp := fauxargocd.FauxApplication{}
+			Expect(yaml.Unmarshal([]byte(application), &fauxApp)).To(Succeed())
+
+			Expect(fauxApp.Spec.SyncPolicy).To(BeNil())
+			Expect(fauxApp.Annotations).To(Equal(map[string]string{fauxargocd.SuspendedAnnotation: "true"}))
+		})
+
+		It("Input spec with a non-default deletion policy should set the deletion policy annotation", func() {
+			for _, deletionPolicy := range []managedgitopsv1alpha1.DeletionPolicy{managedgitopsv1alpha1.DeletionPolicy_Orphan,
+				managedgitopsv1alpha1.DeletionPolicy_Foreground} {
+
+				input := getFakeArgoCDSpecInput(true, false)
+				input.deletionPolicy = deletionPolicy
+
+				application, err := createSpecField(input)
+				Expect(err).ToNot(HaveOccurred())
+
+				fauxApp := fauxargocd.FauxApplication{}
+				Expect(yaml.Unmarshal([]byte(application), &fauxApp)).To(Succeed())
+				Expect(fauxApp.Annotations).To(Equal(map[string]string{fauxargocd.DeletionPolicyAnnotation: string(deletionPolicy)}))
+			}
+		})
+
+		It("Input spec with the Cascade deletion policy should be unchanged from the default", func() {
+			input := getFakeArgoCDSpecInput(true, false)
+			input.deletionPolicy = managedgitopsv1alpha1.DeletionPolicy_Cascade
+
+			application, err := createSpecField(input)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(application).To(Equal(getValidApplication(true)))
+		})
+
+		It("Input spec with automated enabled and a retry strategy should replace the default retry strategy", func() {
+			input := getFakeArgoCDSpecInput(true, false)
+			factor := int64(3)
+			input.retry = &fauxargocd.RetryStrategy{
+				Limit: 5,
+				Backoff: &fauxargocd.Backoff{
+					Duration:    "10s;",
+					Factor:      &factor,
+					MaxDuration: "10m",
+				},
+			}
+
+			application, err := createSpecField(input)
+			Expect(err).ToNot(HaveOccurred())
+
+			fauxApp := fauxargocd.FauxApplication{}
+			Expect(yaml.Unmarshal([]byte(application), &fauxApp)).To(Succeed())
+
+			Expect(fauxApp.Spec.SyncPolicy).ToNot(BeNil())
+			Expect(fauxApp.Spec.SyncPolicy.Automated).ToNot(BeNil())
+			Expect(fauxApp.Spec.SyncPolicy.Retry).To(Equal(&fauxargocd.RetryStrategy{
+				Limit: 5,
+				Backoff: &fauxargocd.Backoff{
+					Duration:    "10s",
+					Factor:      &factor,
+					MaxDuration: "10m",
+				},
+			}))
+		})
+
+		It("Input spec with automated disabled and a retry strategy should only set the retry strategy", func() {
+			input := getFakeArgoCDSpecInput(false, false)
+			input.retry = &fauxargocd.RetryStrategy{Limit: 2}
+
+			application, err := createSpecField(input)
+			Expect(err).ToNot(HaveOccurred())
+
+			fauxApp := fauxargocd.FauxApplication{}
+			Expect(yaml.Unmarshal([]byte(application), &fauxApp)).To(Succeed())
+
+			Expect(fauxApp.Spec.SyncPolicy).ToNot(BeNil())
+			Expect(fauxApp.Spec.SyncPolicy.Automated).To(BeNil())
+			Expect(fauxApp.Spec.SyncPolicy.Retry).To(Equal(&fauxargocd.RetryStrategy{Limit: 2}))
+		})
+
+		It("Input spec with automated enabled and sync options should append them to the default sync options", func() {
+			input := getFakeArgoCDSpecInput(true, false)
+			input.syncOptions = []string{
+				string(managedgitopsv1alpha1.SyncOptions_ServerSideApply_true),
+				string(managedgitopsv1alpha1.SyncOptions_PruneLast_true),
+			}
+
+			application, err := createSpecField(input)
+			Expect(err).ToNot(HaveOccurred())
+
+			fauxApp := fauxargocd.FauxApplication{}
+			Expect(yaml.Unmarshal([]byte(application), &fauxApp)).To(Succeed())
+
+			Expect(fauxApp.Spec.SyncPolicy).ToNot(BeNil())
+			Expect(fauxApp.Spec.SyncPolicy.SyncOptions).To(Equal(fauxargocd.SyncOptions{
+				prunePropagationPolicy,
+				string(managedgitopsv1alpha1.SyncOptions_ServerSideApply_true),
+				string(managedgitopsv1alpha1.SyncOptions_PruneLast_true),
+			}))
+		})
+
+		It("Input spec with automated enabled and a PrunePropagationPolicy sync option should replace the default policy", func() {
+			input := getFakeArgoCDSpecInput(true, false)
+			input.syncOptions = []string{
+				string(managedgitopsv1alpha1.SyncOptions_Replace_true),
+				string(managedgitopsv1alpha1.SyncOptions_PrunePropagationPolicy_foreground),
+			}
+
+			application, err := createSpecField(input)
+			Expect(err).ToNot(HaveOccurred())
+
+			fauxApp := fauxargocd.FauxApplication{}
+			Expect(yaml.Unmarshal([]byte(application), &fauxApp)).To(Succeed())
+
+			Expect(fauxApp.Spec.SyncPolicy).ToNot(BeNil())
+			Expect(fauxApp.Spec.SyncPolicy.SyncOptions).To(Equal(fauxargocd.SyncOptions{
+				string(managedgitopsv1alpha1.SyncOptions_Replace_true),
+				string(managedgitopsv1alpha1.SyncOptions_PrunePropagationPolicy_foreground),
+			}))
+		})
+
+		It("Input spec with ignoreDifferences should set the ignoreDifferences field, preserving quotes in JQ path expressions", func() {
+			input := getFakeArgoCDSpecInput(true, false)
+			input.ignoreDifferences = []fauxargocd.ResourceIgnoreDifferences{
+				{Group: "apps", Kind: "Deployment", JSONPointers: []string{"/spec/replicas;"}},
+				{Kind: "Pod", JQPathExpressions: []string{".spec.containers[] | select(.name == \"istio-proxy\")\n"}},
+			}
+
+			application, err := createSpecField(input)
+			Expect(err).ToNot(HaveOccurred())
+
+			fauxApp := fauxargocd.FauxApplication{}
+			Expect(yaml.Unmarshal([]byte(application), &fauxApp)).To(Succeed())
+
+			Expect(fauxApp.Spec.IgnoreDifferences).To(Equal([]fauxargocd.ResourceIgnoreDifferences{
+				{Group: "apps", Kind: "Deployment", JSONPointers: []string{"/spec/replicas"}},
+				{Kind: "Pod", JQPathExpressions: []string{".spec.containers[] | select(.name == \"istio-proxy\")"}},
+			}))
+		})
+
+		It("Input spec without ignoreDifferences should not include the ignoreDifferences field", func() {
+			input := getFakeArgoCDSpecInput(false, false)
+			application, err := createSpecField(input)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(application).ToNot(ContainSubstring("ignoredifferences"))
+		})
+
+		It("Input spec with a Helm chart source should set the chart and helm fields, preserving inline values", func() {
+			input := getFakeArgoCDSpecInput(false, false)
+			input.sourcePath = ""
+			input.sourceChart = "my-chart"
+			input.sourceHelm = &fauxargocd.ApplicationSourceHelm{
+				ReleaseName: "my-release",
+				Values:      "image:\r\n  tag: \"v1\"\r\nreplicaCount: 2\r\n",
+				ValueFiles:  []string{"values-prod.yaml"},
+				Parameters: []fauxargocd.HelmParameter{
+					{Name: "service.type", Value: "`ClusterIP`;", ForceString: true},
+				},
+			}
+
+			application, err := createSpecField(input)
+			Expect(err).ToNot(HaveOccurred())
+
+			fauxApp := fauxargocd.FauxApplication{}
+			Expect(yaml.Unmarshal([]byte(application), &fauxApp)).To(Succeed())
+
+			Expect(fauxApp.Spec.Source.Path).To(BeEmpty())
+			Expect(fauxApp.Spec.Source.Chart).To(Equal("my-chart"))
+			Expect(fauxApp.Spec.Source.Helm).ToNot(BeNil())
+			Expect(fauxApp.Spec.Source.Helm.ReleaseName).To(Equal("my-release"))
+			Expect(fauxApp.Spec.Source.Helm.Values).To(Equal("image:\n  tag: \"v1\"\nreplicaCount: 2\n"))
+			Expect(fauxApp.Spec.Source.Helm.ValueFiles).To(Equal([]string{"values-prod.yaml"}))
+			Expect(fauxApp.Spec.Source.Helm.Parameters).To(Equal([]fauxargocd.HelmParameter{
+				{Name: "service.type", Value: "ClusterIP", ForceString: true},
+			}))
+		})
+
+		It("Input spec with multiple sources should set the sources field, and omit the single source field", func() {
+			input := getFakeArgoCDSpecInput(false, false)
+			input.sources = []fauxargocd.ApplicationSource{
+				{
+					RepoURL:        "https://charts.example.com",
+					Chart:          "my-chart",
+					TargetRevision: "1.2.3",
+					Helm: &fauxargocd.ApplicationSourceHelm{
+						ValueFiles: []string{"$values/environments/prod/values.yaml"},
+					},
+				},
+				{
+					RepoURL: "https://github.com/test/values`",
+					Ref:     "values",
+				},
+			}
+
+			application, err := createSpecField(input)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(application).ToNot(MatchRegexp("(?m)^  source:"))
+
+			fauxApp := fauxargocd.FauxApplication{}
+			Expect(yaml.Unmarshal([]byte(application), &fauxApp)).To(Succeed())
+
+			Expect(fauxApp.Spec.Source).To(Equal(fauxargocd.ApplicationSource{}))
+			Expect(fauxApp.Spec.Sources).To(HaveLen(2))
+			Expect(fauxApp.Spec.Sources[0].Chart).To(Equal("my-chart"))
+			Expect(fauxApp.Spec.Sources[0].Helm.ValueFiles).To(Equal([]string{"$values/environments/prod/values.yaml"}))
+			Expect(fauxApp.Spec.Sources[1].RepoURL).To(Equal("https://github.com/test/values"))
+			Expect(fauxApp.Spec.Sources[1].Ref).To(Equal("values"))
+		})
+
+		It("Input spec with a single source should not include the sources field", func() {
+			input := getFakeArgoCDSpecInput(false, false)
+			application, err := createSpecField(input)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(application).ToNot(MatchRegexp("(?m)^  sources:"))
+		})
+
+		It("Input spec with Kustomize overrides should set the kustomize field, storing label and annotation values verbatim", func() {
+			input := getFakeArgoCDSpecInput(false, false)
+			input.sourceKustomize = &fauxargocd.ApplicationSourceKustomize{
+				NamePrefix: "prod-",
+				NameSuffix: "-v2",
+				Images:     []string{"quay.io/org/image:v2\n"},
+				CommonLabels: map[string]string{
+					"env": "prod",
+				},
+				CommonAnnotations: map[string]string{
+					"owner":       "team-a",
+					"description": "Team A's \"prod\" app; 100% `managed` & monitored\nsecond line",
+				},
+			}
+
+			application, err := createSpecField(input)
+			Expect(err).ToNot(HaveOccurred())
+
+			fauxApp := fauxargocd.FauxApplication{}
+			Expect(yaml.Unmarshal([]byte(application), &fauxApp)).To(Succeed())
+
+			Expect(fauxApp.Spec.Source.Kustomize).To(Equal(&fauxargocd.ApplicationSourceKustomize{
+				NamePrefix:   "prod-",
+				NameSuffix:   "-v2",
+				Images:       []string{"quay.io/org/image:v2"},
+				CommonLabels: map[string]string{"env": "prod"},
+				CommonAnnotations: map[string]string{
+					"owner":       "team-a",
+					"description": "Team A's \"prod\" app; 100% `managed` & monitored\nsecond line",
+				},
+			}))
+		})
+	})
+})
+
+var _ = Describe("convertToFauxSyncPolicyAutomated", func() {
+
+	It("should return nil if the automated field is not specified", func() {
+		Expect(convertToFauxSyncPolicyAutomated(nil)).To(BeNil())
+		Expect(convertToFauxSyncPolicyAutomated(&managedgitopsv1alpha1.SyncPolicy{})).To(BeNil())
+	})
+
+	It("should default unspecified values to true", func() {
+		prune := false
+		res := convertToFauxSyncPolicyAutomated(&managedgitopsv1alpha1.SyncPolicy{
+			Automated: &managedgitopsv1alpha1.SyncPolicyAutomated{Prune: &prune},
+		})
+		Expect(res).To(Equal(&fauxargocd.SyncPolicyAutomated{
+			Prune:      false,
+			SelfHeal:   true,
+			AllowEmpty: true,
+		}))
+	})
+})
+
+var _ = Describe("convertToFauxRetryStrategy", func() {
+
+	It("should return nil if the retry field is not specified", func() {
+		Expect(convertToFauxRetryStrategy(nil)).To(BeNil())
+		Expect(convertToFauxRetryStrategy(&managedgitopsv1alpha1.SyncPolicy{})).To(BeNil())
+	})
+
+	It("should convert the limit and backoff fields", func() {
+		factor := int64(2)
+		res := convertToFauxRetryStrategy(&managedgitopsv1alpha1.SyncPolicy{
+			Retry: &managedgitopsv1alpha1.RetryStrategy{
+				Limit: 3,
+				Backoff: &managedgitopsv1alpha1.Backoff{
+					Duration:    "30s",
+					Factor:      &factor,
+					MaxDuration: "5m",
+				},
+			},
+		})
+		Expect(res).To(Equal(&fauxargocd.RetryStrategy{
+			Limit: 3,
+			Backoff: &fauxargocd.Backoff{
+				Duration:    "30s",
+				Factor:      &factor,
+				MaxDuration: "5m",
+			},
+		}))
+	})
+})
+
+var _ = Describe("checkValidSyncOption", func() {
+
+	It("should accept the supported Argo CD sync options", func() {
+		Expect(checkValidSyncOption([]managedgitopsv1alpha1.SyncOption{
+			managedgitopsv1alpha1.SyncOptions_CreateNamespace_false,
+			managedgitopsv1alpha1.SyncOptions_ServerSideApply_true,
+			managedgitopsv1alpha1.SyncOptions_ApplyOutOfSyncOnly_true,
+			managedgitopsv1alpha1.SyncOptions_Validate_false,
+			managedgitopsv1alpha1.SyncOptions_RespectIgnoreDifferences_true,
+			managedgitopsv1alpha1.SyncOptions_PrunePropagationPolicy_orphan,
+		})).To(BeNil())
+	})
+
+	It("should return a user error for an unsupported sync option", func() {
+		userErr := checkValidSyncOption([]managedgitopsv1alpha1.SyncOption{
+			managedgitopsv1alpha1.SyncOptions_PruneLast_true,
+			"PrunePropagationPolicy=sometimes",
+		})
+		Expect(userErr).ToNot(BeNil())
+		Expect(userErr.UserError()).To(ContainSubstring("is either mispelled or is not supported"))
+	})
+})
+
+var _ = Describe("syncWindowsAllowSync", func() {
+
+	// Monday, 9:30 AM UTC
+	monday0930 := time.Date(2024, time.January, 1, 9, 30, 0, 0, time.UTC)
+
+	gitopsDeplWithWindows := func(syncWindows ...managedgitopsv1alpha1.SyncWindow) *managedgitopsv1alpha1.GitOpsDeployment {
+		return &managedgitopsv1alpha1.GitOpsDeployment{
+			Spec: managedgitopsv1alpha1.GitOpsDeploymentSpec{
+				Type:       managedgitopsv1alpha1.GitOpsDeploymentSpecType_Automated,
+				SyncPolicy: &managedgitopsv1alpha1.SyncPolicy{SyncWindows: syncWindows},
+			},
+		}
+	}
+
+	businessHours := managedgitopsv1alpha1.SyncWindow{
+		Kind:     managedgitopsv1alpha1.SyncWindowKind_Allow,
+		Schedule: "0 9 * * 1-5",
+		Duration: "8h",
+	}
+
+	It("should allow a sync if the GitOpsDeployment has no sync windows", func() {
+		allowed, message, err := syncWindowsAllowSync(&managedgitopsv1alpha1.GitOpsDeployment{}, monday0930)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allowed).To(BeTrue())
+		Expect(message).To(BeEmpty())
+	})
+
+	It("should report when the next sync window opens, if a sync is not allowed", func() {
+		allowed, message, err := syncWindowsAllowSync(gitopsDeplWithWindows(businessHours), monday0930.Add(8*time.Hour))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allowed).To(BeFalse())
+		Expect(message).To(ContainSubstring("the next sync window opens at 2024-01-02T09:00:00Z"))
+	})
+
+	It("should only disable automated sync while no sync window is open", func() {
+		gitopsDepl := gitopsDeplWithWindows(businessHours)
+
+		specFieldInput := argoCDSpecInput{automated: true}
+		Expect(disableAutomatedSyncOutsideSyncWindows(&specFieldInput, gitopsDepl, monday0930)).To(BeNil())
+		Expect(specFieldInput.automated).To(BeTrue())
+
+		Expect(disableAutomatedSyncOutsideSyncWindows(&specFieldInput, gitopsDepl, monday0930.Add(8*time.Hour))).To(BeNil())
+		Expect(specFieldInput.automated).To(BeFalse())
+	})
+
+	It("should return a user error if a sync window is invalid", func() {
+		gitopsDepl := gitopsDeplWithWindows(managedgitopsv1alpha1.SyncWindow{
+			Kind:     managedgitopsv1alpha1.SyncWindowKind_Deny,
+			Schedule: "not a schedule",
+			Duration: "1h",
+		})
+
+		specFieldInput := argoCDSpecInput{automated: true}
+		userErr := disableAutomatedSyncOutsideSyncWindows(&specFieldInput, gitopsDepl, monday0930)
+		Expect(userErr).ToNot(BeNil())
+		Expect(userErr.UserError()).To(ContainSubstring("invalid sync window"))
+	})
+})
+
+var _ = Describe("reconcileSyncWindowsOfGitOpsDeployment", func() {
+
+	It("should queue an event to update the Application, instead of updating it from the status tick, when the sync window state changes", func() {
+		scheme, _, _, _, err := tests.GenericTestSetup()
+		Expect(err).ToNot(HaveOccurred())
+
+		gitopsDepl := managedgitopsv1alpha1.GitOpsDeployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-gitops-depl", Namespace: "my-namespace"},
+			Spec: managedgitopsv1alpha1.GitOpsDeploymentSpec{
+				Type: managedgitopsv1alpha1.GitOpsDeploymentSpecType_Automated,
+				SyncPolicy: &managedgitopsv1alpha1.SyncPolicy{SyncWindows: managedgitopsv1alpha1.SyncWindows{{
+					// A deny window that is always open: a sync is never allowed
+					Kind:     managedgitopsv1alpha1.SyncWindowKind_Deny,
+					Schedule: "* * * * *",
+					Duration: "1h",
+				}}},
+			},
+		}
+
+		// The Application still has automated sync enabled
+		specField, err := createSpecField(argoCDSpecInput{crName: "my-application", crNamespace: "argocd", automated: true})
+		Expect(err).ToNot(HaveOccurred())
+
+		mockCtrl := gomock.NewController(GinkgoT())
+		defer mockCtrl.Finish()
+
+		mockDBQueries := mocks.NewMockDatabaseQueries(mockCtrl)
+		mockDBQueries.EXPECT().GetApplicationById(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, application *db.Application) error {
+			application.Spec_field = specField
+			return nil
+		})
+
+		eventLoopInputChan := make(chan RequestMessage, 1)
+
+		action := applicationEventLoopRunner_Action{
+			workspaceClient:    fake.NewClientBuilder().WithScheme(scheme).WithObjects(&gitopsDepl).Build(),
+			log:                log.FromContext(context.Background()),
+			eventLoopInputChan: eventLoopInputChan,
+		}
+
+		condition, err := action.reconcileSyncWindowsOfGitOpsDeployment(context.Background(), gitopsDepl,
+			db.DeploymentToApplicationMapping{Application_id: "test-application"}, nil, mockDBQueries)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Type).To(Equal(managedgitopsv1alpha1.GitOpsDeploymentConditionSyncWindowClosed))
+
+		var queued RequestMessage
+		Eventually(eventLoopInputChan).Should(Receive(&queued))
+		Expect(queued.Message.Event.EventType).To(Equal(eventlooptypes.DeploymentModified))
+		Expect(queued.Message.Event.ReqResource).To(Equal(eventlooptypes.GitOpsDeploymentTypeName))
+		Expect(queued.Message.Event.Request.Name).To(Equal(gitopsDepl.Name))
+	})
+})
+
+var _ = Describe("getRollbackInEffect", func() {
+
+	const applicationID = "test-application"
+
+	var gitopsDepl managedgitopsv1alpha1.GitOpsDeployment
+	var scheme *runtime.Scheme
+	var mockDBQueries *mocks.MockDatabaseQueries
+
+	BeforeEach(func() {
+		var err error
+		scheme, _, _, _, err = tests.GenericTestSetup()
+		Expect(err).ToNot(HaveOccurred())
+
+		gitopsDepl = managedgitopsv1alpha1.GitOpsDeployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-gitops-depl", Namespace: "my-namespace"},
+			Spec:       managedgitopsv1alpha1.GitOpsDeploymentSpec{Type: managedgitopsv1alpha1.GitOpsDeploymentSpecType_Automated},
+			Status: managedgitopsv1alpha1.GitOpsDeploymentStatus{
+				// The status is ignored: the deployment history is read from the database
+				History: []managedgitopsv1alpha1.DeploymentHistory{{ID: "status-only", Revision: "8c9d0e1f"}},
+			},
+		}
+
+		mockCtrl := gomock.NewController(GinkgoT())
+		mockDBQueries = mocks.NewMockDatabaseQueries(mockCtrl)
+		mockDBQueries.EXPECT().ListDeploymentHistoryByApplicationId(gomock.Any(), applicationID, gomock.Any()).DoAndReturn(
+			func(ctx context.Context, applicationId string, deploymentHistory *[]db.DeploymentHistory) error {
+				*deploymentHistory = []db.DeploymentHistory{
+					{DeploymentHistory_id: "history-1", Application_id: applicationID, Revision: "0a1b2c3d", DeployedAt: time.Now().Add(-2 * time.Hour)},
+					{DeploymentHistory_id: "history-2", Application_id: applicationID, Revision: "4e5f6a7b", DeployedAt: time.Now().Add(-time.Hour)},
+					{DeploymentHistory_id: "history-3", Application_id: applicationID, Revision: "0a1b2c3d", DeployedAt: time.Now()},
+				}
+				return nil
+			}).AnyTimes()
+	})
+
+	syncRunWithSpec := func(name string, spec managedgitopsv1alpha1.GitOpsDeploymentSyncRunSpec) *managedgitopsv1alpha1.GitOpsDeploymentSyncRun {
+		return &managedgitopsv1alpha1.GitOpsDeploymentSyncRun{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: gitopsDepl.Namespace},
+			Spec:       spec,
+		}
+	}
+
+	It("should return the history entry only if a SyncRun rolls back the GitOpsDeployment to a revision in its history", func() {
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			syncRunWithSpec("sync", managedgitopsv1alpha1.GitOpsDeploymentSyncRunSpec{GitopsDeploymentName: gitopsDepl.Name, RevisionID: "0a1b2c3d"}),
+			syncRunWithSpec("other-depl", managedgitopsv1alpha1.GitOpsDeploymentSyncRunSpec{GitopsDeploymentName: "other-depl", RollbackToRevision: "0a1b2c3d"}),
+		).Build()
+
+		rollbackInEffect, err := getRollbackInEffect(context.Background(), k8sClient, mockDBQueries, applicationID, gitopsDepl, log.FromContext(context.Background()))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rollbackInEffect).To(BeNil())
+
+		Expect(k8sClient.Create(context.Background(), syncRunWithSpec("rollback",
+			managedgitopsv1alpha1.GitOpsDeploymentSyncRunSpec{GitopsDeploymentName: gitopsDepl.Name, RollbackToRevision: "0a1b2c3d"}))).To(Succeed())
+
+		By("returning the most recent deployment of the revision")
+		rollbackInEffect, err = getRollbackInEffect(context.Background(), k8sClient, mockDBQueries, applicationID, gitopsDepl, log.FromContext(context.Background()))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rollbackInEffect).ToNot(BeNil())
+		Expect(rollbackInEffect.ID).To(Equal("history-3"))
+		Expect(rollbackInEffect.Revision).To(Equal("0a1b2c3d"))
+	})
+
+	It("should not return a history entry that is only in the status of the GitOpsDeployment", func() {
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			syncRunWithSpec("rollback-revision", managedgitopsv1alpha1.GitOpsDeploymentSyncRunSpec{GitopsDeploymentName: gitopsDepl.Name, RollbackToRevision: "8c9d0e1f"}),
+		).Build()
+
+		rollbackInEffect, err := getRollbackInEffect(context.Background(), k8sClient, mockDBQueries, applicationID, gitopsDepl, log.FromContext(context.Background()))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rollbackInEffect).To(BeNil())
+
+		k8sClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			syncRunWithSpec("rollback-id", managedgitopsv1alpha1.GitOpsDeploymentSyncRunSpec{GitopsDeploymentName: gitopsDepl.Name, RollbackToHistoryID: "status-only"}),
+		).Build()
+
+		rollbackInEffect, err = getRollbackInEffect(context.Background(), k8sClient, mockDBQueries, applicationID, gitopsDepl, log.FromContext(context.Background()))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rollbackInEffect).To(BeNil())
+	})
+
+	It("should return the history entry with the ID of a SyncRun that rolls back to a history ID", func() {
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			syncRunWithSpec("rollback", managedgitopsv1alpha1.GitOpsDeploymentSyncRunSpec{GitopsDeploymentName: gitopsDepl.Name, RollbackToHistoryID: "history-1"}),
+		).Build()
+
+		rollbackInEffect, err := getRollbackInEffect(context.Background(), k8sClient, mockDBQueries, applicationID, gitopsDepl, log.FromContext(context.Background()))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rollbackInEffect).ToNot(BeNil())
+		Expect(rollbackInEffect.ID).To(Equal("history-1"))
+		Expect(rollbackInEffect.Revision).To(Equal("0a1b2c3d"))
+	})
+
+	It("should return the history entry of the most recently created rollback SyncRun", func() {
+		older := syncRunWithSpec("older-rollback", managedgitopsv1alpha1.GitOpsDeploymentSyncRunSpec{GitopsDeploymentName: gitopsDepl.Name, RollbackToRevision: "0a1b2c3d"})
+		older.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+		newer := syncRunWithSpec("newer-rollback", managedgitopsv1alpha1.GitOpsDeploymentSyncRunSpec{GitopsDeploymentName: gitopsDepl.Name, RollbackToRevision: "4e5f6a7b"})
+		newer.CreationTimestamp = metav1.NewTime(time.Now())
+
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(newer, older).Build()
+
+		rollbackInEffect, err := getRollbackInEffect(context.Background(), k8sClient, mockDBQueries, applicationID, gitopsDepl, log.FromContext(context.Background()))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rollbackInEffect).ToNot(BeNil())
+		Expect(rollbackInEffect.Revision).To(Equal("4e5f6a7b"))
 	})
 })
 
+var _ = Describe("rollBackSourcesOfSpecInput", func() {
+
+	It("should replace the source with the source of the deployment history entry, pinned to the deployed revision", func() {
+		helm := &fauxargocd.ApplicationSourceHelm{ReleaseName: "my-release"}
+		specFieldInput := argoCDSpecInput{
+			sourceRepoURL:        "https://github.com/test/new-repo",
+			sourcePath:           "environments/new",
+			sourceTargetRevision: "main",
+			sourceHelm:           helm,
+		}
+
+		rollBackSourcesOfSpecInput(&specFieldInput, managedgitopsv1alpha1.DeploymentHistory{
+			Revision: "0a1b2c3d",
+			Source:   managedgitopsv1alpha1.ApplicationSource{RepoURL: "https://github.com/test/old-repo", Path: "environments/old", TargetRevision: "main"},
+		})
+
+		Expect(specFieldInput.sourceRepoURL).To(Equal("https://github.com/test/old-repo"))
+		Expect(specFieldInput.sourcePath).To(Equal("environments/old"))
+		Expect(specFieldInput.sourceTargetRevision).To(Equal("0a1b2c3d"))
+		Expect(specFieldInput.sourceHelm).To(Equal(helm))
+		Expect(specFieldInput.sources).To(BeEmpty())
+	})
+
+	It("should replace every source with the sources of the deployment history entry, pinned to the deployed revisions", func() {
+		specFieldInput := argoCDSpecInput{
+			sources: []fauxargocd.ApplicationSource{
+				{RepoURL: "https://github.com/test/test", Path: "environments/new", TargetRevision: "main"},
+			},
+		}
+
+		rollBackSourcesOfSpecInput(&specFieldInput, managedgitopsv1alpha1.DeploymentHistory{
+			Revision: "0a1b2c3d",
+			Source:   managedgitopsv1alpha1.ApplicationSource{RepoURL: "https://github.com/test/test", Path: "environments/old"},
+			Sources: []managedgitopsv1alpha1.ApplicationSource{
+				{RepoURL: "https://github.com/test/test", Path: "environments/old", TargetRevision: "main"},
+				{RepoURL: "https://github.com/test/values", TargetRevision: "main", Ref: "values"},
+			},
+			Revisions: []string{"0a1b2c3d", "4e5f6a7b"},
+		})
+
+		Expect(specFieldInput.sourceRepoURL).To(BeEmpty())
+		Expect(specFieldInput.sources).To(Equal([]fauxargocd.ApplicationSource{
+			{RepoURL: "https://github.com/test/test", Path: "environments/old", TargetRevision: "0a1b2c3d"},
+			{RepoURL: "https://github.com/test/values", TargetRevision: "4e5f6a7b", Ref: "values"},
+		}))
+	})
+
+	It("should only pin the revision if the source of the deployment history entry is unknown", func() {
+		specFieldInput := argoCDSpecInput{sourceRepoURL: "https://github.com/test/test", sourcePath: "environments/prod", sourceTargetRevision: "main"}
+
+		rollBackSourcesOfSpecInput(&specFieldInput, managedgitopsv1alpha1.DeploymentHistory{Revision: "0a1b2c3d"})
+
+		Expect(specFieldInput.sourceRepoURL).To(Equal("https://github.com/test/test"))
+		Expect(specFieldInput.sourcePath).To(Equal("environments/prod"))
+		Expect(specFieldInput.sourceTargetRevision).To(Equal("0a1b2c3d"))
+	})
+})
+
+var _ = Describe("GitOpsDeployment dependencies", func() {
+
+	var gitopsDepl managedgitopsv1alpha1.GitOpsDeployment
+	var scheme *runtime.Scheme
+
+	BeforeEach(func() {
+		var err error
+		scheme, _, _, _, err = tests.GenericTestSetup()
+		Expect(err).ToNot(HaveOccurred())
+
+		gitopsDepl = managedgitopsv1alpha1.GitOpsDeployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "api", Namespace: "my-namespace"},
+			Spec: managedgitopsv1alpha1.GitOpsDeploymentSpec{
+				Type:      managedgitopsv1alpha1.GitOpsDeploymentSpecType_Automated,
+				DependsOn: []string{"database", "cache", "storage"},
+			},
+		}
+	})
+
+	dependencyWithStatus := func(name string, syncStatus managedgitopsv1alpha1.SyncStatusCode,
+		healthStatus managedgitopsv1alpha1.HealthStatusCode) *managedgitopsv1alpha1.GitOpsDeployment {
+
+		return &managedgitopsv1alpha1.GitOpsDeployment{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: gitopsDepl.Namespace},
+			Status: managedgitopsv1alpha1.GitOpsDeploymentStatus{
+				Sync:   managedgitopsv1alpha1.SyncStatus{Status: syncStatus},
+				Health: managedgitopsv1alpha1.HealthStatus{Status: healthStatus},
+			},
+		}
+	}
+
+	It("should return the dependencies that do not exist, or are not both Synced and Healthy", func() {
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			dependencyWithStatus("database", managedgitopsv1alpha1.SyncStatusCodeSynced, managedgitopsv1alpha1.HeathStatusCodeHealthy),
+			dependencyWithStatus("cache", managedgitopsv1alpha1.SyncStatusCodeSynced, managedgitopsv1alpha1.HeathStatusCodeProgressing),
+		).Build()
+
+		unreadyDependencies, err := getUnreadyDependenciesOfGitOpsDeployment(context.Background(), k8sClient, gitopsDepl)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(unreadyDependencies).To(Equal([]string{"'cache' (sync: Synced, health: Progressing)", "'storage' (not found)"}))
+
+		By("creating the missing dependency, and waiting for the others to be ready")
+		Expect(k8sClient.Create(context.Background(), dependencyWithStatus("storage", "", ""))).To(Succeed())
+
+		unreadyDependencies, err = getUnreadyDependenciesOfGitOpsDeployment(context.Background(), k8sClient, gitopsDepl)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(unreadyDependencies).To(ContainElement("'storage' (sync: Unknown, health: Unknown)"))
+
+		gitopsDepl.Spec.DependsOn = []string{"database"}
+		unreadyDependencies, err = getUnreadyDependenciesOfGitOpsDeployment(context.Background(), k8sClient, gitopsDepl)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(unreadyDependencies).To(BeEmpty())
+	})
+
+	It("should set the WaitingOnDependencies condition, and mark it as resolved once the dependencies are ready", func() {
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&gitopsDepl).WithStatusSubresource(&gitopsDepl).Build()
+
+		action := applicationEventLoopRunner_Action{
+			workspaceClient: k8sClient,
+			log:             log.FromContext(context.Background()),
+		}
+
+		By("not adding a resolved condition to a GitOpsDeployment that was never waiting")
+		updated, err := action.updateWaitingOnDependenciesCondition(context.Background(), &gitopsDepl, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeFalse())
+		Expect(gitopsDepl.Status.Conditions).To(BeEmpty())
+
+		By("setting the condition while waiting on dependencies")
+		waitingCondition := newWaitingOnDependenciesCondition([]string{"'storage' (not found)"})
+		updated, err = action.updateWaitingOnDependenciesCondition(context.Background(), &gitopsDepl, waitingCondition)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeTrue())
+
+		updated, err = action.updateWaitingOnDependenciesCondition(context.Background(), &gitopsDepl, waitingCondition)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeFalse())
+
+		Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(&gitopsDepl), &gitopsDepl)).To(Succeed())
+		Expect(gitopsDepl.Status.Conditions).To(HaveLen(1))
+		Expect(gitopsDepl.Status.Conditions[0].Type).To(Equal(managedgitopsv1alpha1.GitOpsDeploymentConditionWaitingOnDependencies))
+		Expect(gitopsDepl.Status.Conditions[0].Status).To(Equal(managedgitopsv1alpha1.GitOpsConditionStatusTrue))
+		Expect(gitopsDepl.Status.Conditions[0].Message).To(ContainSubstring("'storage' (not found)"))
+
+		By("resolving the condition once the dependencies are ready")
+		updated, err = action.updateWaitingOnDependenciesCondition(context.Background(), &gitopsDepl, nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(updated).To(BeTrue())
+
+		Expect(k8sClient.Get(context.Background(), client.ObjectKeyFromObject(&gitopsDepl), &gitopsDepl)).To(Succeed())
+		Expect(gitopsDepl.Status.Conditions[0].Status).To(Equal(managedgitopsv1alpha1.GitOpsConditionStatusFalse))
+		Expect(gitopsDepl.Status.Conditions[0].Reason).To(Equal(managedgitopsv1alpha1.GitopsDeploymentReasonWaitingOnDependencies + "Resolved"))
+	})
+
+	It("should queue an event for each held GitOpsDeploymentSyncRun, once the dependencies are ready", func() {
+		gitopsDepl.Spec.DependsOn = []string{"database"}
+
+		heldSyncRun := &managedgitopsv1alpha1.GitOpsDeploymentSyncRun{
+			ObjectMeta: metav1.ObjectMeta{Name: "held-sync-run", Namespace: gitopsDepl.Namespace},
+			Spec:       managedgitopsv1alpha1.GitOpsDeploymentSyncRunSpec{GitopsDeploymentName: gitopsDepl.Name},
+			Status: managedgitopsv1alpha1.GitOpsDeploymentSyncRunStatus{
+				Conditions: []managedgitopsv1alpha1.GitOpsDeploymentSyncRunCondition{{
+					Type:   managedgitopsv1alpha1.GitOpsDeploymentSyncRunConditionErrorOccurred,
+					Status: managedgitopsv1alpha1.GitOpsConditionStatusTrue,
+					Reason: managedgitopsv1alpha1.SyncRunReasonWaitingOnDependencies,
+				}},
+			},
+		}
+
+		otherSyncRun := &managedgitopsv1alpha1.GitOpsDeploymentSyncRun{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-sync-run", Namespace: gitopsDepl.Namespace},
+			Spec:       managedgitopsv1alpha1.GitOpsDeploymentSyncRunSpec{GitopsDeploymentName: gitopsDepl.Name},
+		}
+
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&gitopsDepl, heldSyncRun, otherSyncRun,
+			dependencyWithStatus("database", managedgitopsv1alpha1.SyncStatusCodeSynced, managedgitopsv1alpha1.HeathStatusCodeHealthy)).Build()
+
+		eventLoopInputChan := make(chan RequestMessage, 1)
+
+		action := applicationEventLoopRunner_Action{
+			workspaceClient:    k8sClient,
+			workspaceID:        "test-workspace-id",
+			log:                log.FromContext(context.Background()),
+			eventLoopInputChan: eventLoopInputChan,
+		}
+
+		condition := action.reconcileDependenciesOfGitOpsDeployment(context.Background(), gitopsDepl,
+			[]managedgitopsv1alpha1.GitOpsDeploymentSyncRun{*heldSyncRun, *otherSyncRun})
+		Expect(condition).To(BeNil())
+
+		var queued RequestMessage
+		Eventually(eventLoopInputChan).Should(Receive(&queued))
+		Expect(queued.Message.MessageType).To(Equal(eventlooptypes.ApplicationEventLoopMessageType_Event))
+		Expect(queued.Message.Event.EventType).To(Equal(eventlooptypes.SyncRunModified))
+		Expect(queued.Message.Event.ReqResource).To(Equal(eventlooptypes.GitOpsDeploymentSyncRunTypeName))
+		Expect(queued.Message.Event.Request.Name).To(Equal(heldSyncRun.Name))
+		Expect(queued.Message.Event.Request.Namespace).To(Equal(heldSyncRun.Namespace))
+		Expect(queued.Message.Event.WorkspaceID).To(Equal("test-workspace-id"))
+
+		Consistently(eventLoopInputChan).ShouldNot(Receive())
+	})
+
+	It("should queue an event to reconcile a GitOpsDeployment that was waiting on its dependencies, once they are ready", func() {
+		gitopsDepl.Spec.DependsOn = []string{"database"}
+		gitopsDepl.Status.Conditions = []managedgitopsv1alpha1.GitOpsDeploymentCondition{{
+			Type:   managedgitopsv1alpha1.GitOpsDeploymentConditionWaitingOnDependencies,
+			Status: managedgitopsv1alpha1.GitOpsConditionStatusTrue,
+			Reason: managedgitopsv1alpha1.GitopsDeploymentReasonWaitingOnDependencies,
+		}}
+
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&gitopsDepl,
+			dependencyWithStatus("database", managedgitopsv1alpha1.SyncStatusCodeSynced, managedgitopsv1alpha1.HeathStatusCodeHealthy)).Build()
+
+		eventLoopInputChan := make(chan RequestMessage, 1)
+
+		action := applicationEventLoopRunner_Action{
+			workspaceClient:    k8sClient,
+			log:                log.FromContext(context.Background()),
+			eventLoopInputChan: eventLoopInputChan,
+		}
+
+		condition := action.reconcileDependenciesOfGitOpsDeployment(context.Background(), gitopsDepl, nil)
+		Expect(condition).To(BeNil())
+
+		var queued RequestMessage
+		Eventually(eventLoopInputChan).Should(Receive(&queued))
+		Expect(queued.Message.Event.EventType).To(Equal(eventlooptypes.DeploymentModified))
+		Expect(queued.Message.Event.ReqResource).To(Equal(eventlooptypes.GitOpsDeploymentTypeName))
+		Expect(queued.Message.Event.Request.Name).To(Equal(gitopsDepl.Name))
+	})
+
+	It("should return a WaitingOnDependencies condition, rather than an error, if a dependency cannot be retrieved", func() {
+		gitopsDepl.Spec.DependsOn = []string{"database"}
+
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&gitopsDepl).WithInterceptorFuncs(interceptor.Funcs{
+			Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+				return fmt.Errorf("simulated error")
+			},
+		}).Build()
+
+		action := applicationEventLoopRunner_Action{
+			workspaceClient: k8sClient,
+			log:             log.FromContext(context.Background()),
+		}
+
+		condition := action.reconcileDependenciesOfGitOpsDeployment(context.Background(), gitopsDepl, nil)
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Type).To(Equal(managedgitopsv1alpha1.GitOpsDeploymentConditionWaitingOnDependencies))
+		Expect(condition.Reason).To(Equal(managedgitopsv1alpha1.GitopsDeploymentReasonWaitingOnDependencies))
+		Expect(condition.Message).To(ContainSubstring("this will be retried"))
+	})
+})
+
+var _ = Describe("convertDeploymentHistoryToStatus", func() {
+
+	It("should convert the most recent DeploymentHistory rows into the status of a GitOpsDeployment", func() {
+
+		deployedAt := time.Date(2024, time.January, 1, 9, 30, 0, 0, time.UTC)
+
+		var deploymentHistory []db.DeploymentHistory
+		for i := 0; i < managedgitopsv1alpha1.MaxDeploymentHistory+2; i++ {
+			deploymentHistory = append(deploymentHistory, db.DeploymentHistory{
+				DeploymentHistory_id: fmt.Sprintf("test-deployment-history-%d", i),
+				Revision:             fmt.Sprintf("revision-%d", i),
+				Source:               `{"repoURL":"https://github.com/test/test","path":"environments/prod"}`,
+				DeployedAt:           deployedAt.Add(time.Duration(i) * time.Hour),
+				InitiatedBy:          db.DeploymentHistory_InitiatedBy_Automated,
+			})
+		}
+		deploymentHistory[len(deploymentHistory)-1].InitiatedBy = "admin"
+		deploymentHistory[len(deploymentHistory)-1].SyncRunName = "my-sync-run"
+
+		history := convertDeploymentHistoryToStatus(deploymentHistory, log.FromContext(context.Background()))
+		Expect(history).To(HaveLen(managedgitopsv1alpha1.MaxDeploymentHistory))
+
+		By("keeping only the most recent entries, from oldest to newest")
+		Expect(history[0].ID).To(Equal("test-deployment-history-2"))
+		Expect(history[0].Revision).To(Equal("revision-2"))
+		Expect(history[0].Source).To(Equal(managedgitopsv1alpha1.ApplicationSource{
+			RepoURL: "https://github.com/test/test",
+			Path:    "environments/prod",
+		}))
+
+		last := history[len(history)-1]
+		Expect(last.Revision).To(Equal(fmt.Sprintf("revision-%d", managedgitopsv1alpha1.MaxDeploymentHistory+1)))
+		Expect(last.InitiatedBy).To(Equal("admin"))
+		Expect(last.SyncRun).To(Equal("my-sync-run"))
+		Expect(last.DeployedAt.Time.Equal(deploymentHistory[len(deploymentHistory)-1].DeployedAt)).To(BeTrue())
+	})
+
+	It("should convert every source of a DeploymentHistory row of an Application with multiple sources", func() {
+
+		history := convertDeploymentHistoryToStatus([]db.DeploymentHistory{{
+			DeploymentHistory_id: "test-deployment-history",
+			Revision:             "revision-0",
+			Source:               `{"repoURL":"https://github.com/test/test","path":"environments/prod"}`,
+			Sources:              `[{"repoURL":"https://github.com/test/test","path":"environments/prod"},{"repoURL":"https://github.com/test/values","ref":"values"}]`,
+			Revisions:            `["revision-0","revision-1"]`,
+			DeployedAt:           time.Date(2024, time.January, 1, 9, 30, 0, 0, time.UTC),
+		}}, log.FromContext(context.Background()))
+		Expect(history).To(HaveLen(1))
+
+		Expect(history[0].Sources).To(Equal([]managedgitopsv1alpha1.ApplicationSource{
+			{RepoURL: "https://github.com/test/test", Path: "environments/prod"},
+			{RepoURL: "https://github.com/test/values", Ref: "values"},
+		}))
+		Expect(history[0].Revisions).To(Equal([]string{"revision-0", "revision-1"}))
+	})
+
+	It("should return nil if there is no deployment history", func() {
+		Expect(convertDeploymentHistoryToStatus(nil, log.FromContext(context.Background()))).To(BeNil())
+	})
+})
+
+var _ = Describe("decompressResourceTree", func() {
+
+	It("should convert the compressed resource tree of an ApplicationState into the status of a GitOpsDeployment", func() {
+
+		lastTimestamp := time.Date(2024, time.January, 1, 9, 30, 0, 500, time.UTC)
+
+		deploymentRef := managedgitopsv1alpha1.ResourceRef{Group: "apps", Version: "v1", Kind: "Deployment", Namespace: "my-namespace", Name: "my-deployment"}
+		podRef := managedgitopsv1alpha1.ResourceRef{Version: "v1", Kind: "Pod", Namespace: "my-namespace", Name: "my-deployment-abcde"}
+
+		resourceTree := managedgitopsv1alpha1.ResourceTree{
+			Nodes: []managedgitopsv1alpha1.ResourceNode{
+				{ResourceRef: deploymentRef, Health: &managedgitopsv1alpha1.HealthStatus{Status: managedgitopsv1alpha1.HeathStatusCodeProgressing}},
+				{ResourceRef: podRef, ParentRefs: []managedgitopsv1alpha1.ResourceRef{deploymentRef},
+					Health: &managedgitopsv1alpha1.HealthStatus{Status: managedgitopsv1alpha1.HeathStatusCodeDegraded, Message: "Back-off pulling image"}},
+			},
+			Events: []managedgitopsv1alpha1.ResourceEvent{
+				{InvolvedObject: podRef, Reason: "BackOff", Message: "Back-off pulling image", Count: 3, LastTimestamp: metav1.NewTime(lastTimestamp)},
+			},
+			Truncated: true,
+		}
+
+		resourceTreeBytes, err := sharedutil.CompressObject(resourceTree)
+		Expect(err).ToNot(HaveOccurred())
+
+		res, err := decompressResourceTree(resourceTreeBytes)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(res.Nodes).To(Equal(resourceTree.Nodes))
+		Expect(res.Truncated).To(BeTrue())
+		Expect(res.Events).To(HaveLen(1))
+		Expect(res.Events[0].InvolvedObject).To(Equal(podRef))
+		Expect(res.Events[0].Reason).To(Equal("BackOff"))
+		Expect(res.Events[0].Count).To(Equal(int32(3)))
+		Expect(res.Events[0].LastTimestamp.Time.Equal(lastTimestamp.Truncate(time.Second))).To(BeTrue())
+	})
+
+	It("should return nil if the resource tree has not been collected", func() {
+		res, err := decompressResourceTree(nil)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(res).To(BeNil())
+	})
+})
+
+var _ = Describe("validateGitOpsDeploymentSources", func() {
+
+	DescribeTable("should validate the source and sources fields of a GitOpsDeployment",
+		func(spec managedgitopsv1alpha1.GitOpsDeploymentSpec, expectedUserError string) {
+			Expect(validateGitOpsDeploymentSources(spec)).To(Equal(expectedUserError))
+		},
+		Entry("single source with a path", managedgitopsv1alpha1.GitOpsDeploymentSpec{
+			Source: managedgitopsv1alpha1.ApplicationSource{RepoURL: "https://github.com/test/test", Path: "environments/prod"},
+		}, ""),
+		Entry("single source without a path or chart", managedgitopsv1alpha1.GitOpsDeploymentSpec{
+			Source: managedgitopsv1alpha1.ApplicationSource{RepoURL: "https://github.com/test/test"},
+		}, managedgitopsv1alpha1.GitOpsDeploymentUserError_PathIsRequired),
+		Entry("multiple sources, one of which is only a ref", managedgitopsv1alpha1.GitOpsDeploymentSpec{
+			Sources: []managedgitopsv1alpha1.ApplicationSource{
+				{RepoURL: "https://charts.example.com", Chart: "my-chart"},
+				{RepoURL: "https://github.com/test/values", Ref: "values"},
+			},
+		}, ""),
+		Entry("multiple sources, one of which has no path, chart, or ref", managedgitopsv1alpha1.GitOpsDeploymentSpec{
+			Sources: []managedgitopsv1alpha1.ApplicationSource{
+				{RepoURL: "https://github.com/test/test", Path: "environments/prod"},
+				{RepoURL: "https://github.com/test/values"},
+			},
+		}, managedgitopsv1alpha1.GitOpsDeploymentUserError_SourcesPathIsRequired),
+		Entry("both source and sources", managedgitopsv1alpha1.GitOpsDeploymentSpec{
+			Source: managedgitopsv1alpha1.ApplicationSource{RepoURL: "https://github.com/test/test", Path: "environments/prod"},
+			Sources: []managedgitopsv1alpha1.ApplicationSource{
+				{RepoURL: "https://github.com/test/test", Path: "environments/prod"},
+			},
+		}, managedgitopsv1alpha1.GitOpsDeploymentUserError_SourceAndSourcesAreExclusive),
+	)
+})
+
 var _ = Describe("Application Event Runner Deployments to check SyncPolicy.SyncOption", func() {
 	Context("Handle SyncPolicy.SyncOption in GitopsDeployment for CreateNamespace=true", func() {
 		var err error
@@ -183,7 +1155,7 @@ var _ = Describe("Application Event Runner Deployments to check SyncPolicy.SyncO
 				eventResourceNamespace:      gitopsDepl.Namespace,
 				workspaceClient:             k8sClient,
 				log:                         log.FromContext(context.Background()),
-				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(),
+				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(nil),
 				workspaceID:                 workspaceID,
 				testOnlySkipCreateOperation: true,
 				k8sClientFactory: MockSRLK8sClientFactory{
@@ -390,7 +1362,7 @@ var _ = Describe("ApplicationEventLoop Handle deployment modified Test", func()
 				eventResourceNamespace:      gitopsDepl.Namespace,
 				workspaceClient:             k8sClient,
 				log:                         log.FromContext(context.Background()),
-				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(),
+				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(nil),
 				workspaceID:                 workspaceID,
 				testOnlySkipCreateOperation: true,
 				k8sClientFactory: MockSRLK8sClientFactory{
@@ -454,7 +1426,7 @@ var _ = Describe("ApplicationEventLoop Handle deployment modified Test", func()
 				eventResourceNamespace:      gitopsDepl.Namespace,
 				workspaceClient:             k8sClient,
 				log:                         log.FromContext(context.Background()),
-				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(),
+				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(nil),
 				workspaceID:                 workspaceID,
 				testOnlySkipCreateOperation: true,
 				k8sClientFactory: MockSRLK8sClientFactory{
@@ -652,7 +1624,7 @@ var _ = Describe("ApplicationEventLoop Handle deployment modified Test", func()
 				eventResourceName:           gitopsDepl.Name,
 				workspaceClient:             k8sClient,
 				log:                         log.FromContext(context.Background()),
-				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(),
+				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(nil),
 				workspaceID:                 workspaceID,
 				testOnlySkipCreateOperation: true,
 				k8sClientFactory: MockSRLK8sClientFactory{
@@ -714,7 +1686,7 @@ var _ = Describe("ApplicationEventLoop Handle deployment modified Test", func()
 				eventResourceNamespace:      gitopsDepl.Namespace,
 				workspaceClient:             k8sClient,
 				log:                         log.FromContext(context.Background()),
-				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(),
+				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(nil),
 				workspaceID:                 workspaceID,
 				testOnlySkipCreateOperation: true,
 				k8sClientFactory: MockSRLK8sClientFactory{
@@ -896,7 +1868,7 @@ var _ = Describe("ApplicationEventLoop Handle deployment modified Test", func()
 				eventResourceNamespace:      gitopsDepl.Namespace,
 				workspaceClient:             k8sClient,
 				log:                         log.FromContext(context.Background()),
-				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(),
+				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(nil),
 				workspaceID:                 workspaceID,
 				testOnlySkipCreateOperation: true,
 				k8sClientFactory: MockSRLK8sClientFactory{
@@ -949,7 +1921,7 @@ var _ = Describe("ApplicationEventLoop Handle deployment modified Test", func()
 				eventResourceName:           gitopsDepl.Name,
 				workspaceClient:             k8sClient,
 				log:                         log.FromContext(context.Background()),
-				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(),
+				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(nil),
 				workspaceID:                 workspaceID,
 				eventResourceNamespace:      workspace.Name,
 				testOnlySkipCreateOperation: true,
@@ -1117,3 +2089,175 @@ var _ = Describe("isManagedEnvironmentConnectionUserError function Test", func()
 		})
 	})
 })
+
+var _ = Describe("recordGitOpsDeploymentEvents function Test", func() {
+
+	Context("Testing recordGitOpsDeploymentEvents function.", func() {
+
+		var fakeRecorder *record.FakeRecorder
+		var eventRecorder *events.Recorder
+
+		BeforeEach(func() {
+			fakeRecorder = record.NewFakeRecorder(10)
+			eventRecorder = events.NewRecorder(fakeRecorder)
+		})
+
+		It("should record an event for each change to the health, and for each sync that has started or finished", func() {
+
+			gitopsDepl := &managedgitopsv1alpha1.GitOpsDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-gitops-depl",
+					Namespace: "my-namespace",
+					UID:       uuid.NewUUID(),
+				},
+			}
+			startedAt := metav1.Now()
+
+			By("recording the start of a sync")
+			oldStatus := gitopsDepl.Status
+			gitopsDepl.Status.Health.Status = managedgitopsv1alpha1.HeathStatusCodeHealthy
+			gitopsDepl.Status.OperationState = &managedgitopsv1alpha1.OperationState{Phase: managedgitopsv1alpha1.OperationRunning, StartedAt: startedAt}
+			recordGitOpsDeploymentEvents(gitopsDepl, oldStatus, eventRecorder)
+			Expect(fakeRecorder.Events).To(Receive(Equal("Normal HealthStatusChanged Health status is 'Healthy'")))
+			Expect(fakeRecorder.Events).To(Receive(Equal("Normal SyncStarted Sync started")))
+			Expect(fakeRecorder.Events).ToNot(Receive())
+
+			By("not recording anything if the status is unchanged")
+			recordGitOpsDeploymentEvents(gitopsDepl, *gitopsDepl.Status.DeepCopy(), eventRecorder)
+			Expect(fakeRecorder.Events).ToNot(Receive())
+
+			By("recording a failed sync, and the change of health")
+			oldStatus = *gitopsDepl.Status.DeepCopy()
+			gitopsDepl.Status.Health = managedgitopsv1alpha1.HealthStatus{Status: managedgitopsv1alpha1.HeathStatusCodeDegraded, Message: "Back-off pulling image"}
+			gitopsDepl.Status.OperationState = &managedgitopsv1alpha1.OperationState{Phase: managedgitopsv1alpha1.OperationFailed, StartedAt: startedAt,
+				Message: "one or more objects failed to apply", SyncResult: &managedgitopsv1alpha1.SyncOperationResult{Revision: "0a1b2c3d"}}
+			recordGitOpsDeploymentEvents(gitopsDepl, oldStatus, eventRecorder)
+			Expect(fakeRecorder.Events).To(Receive(Equal("Warning HealthStatusChanged Health status changed from 'Healthy' to 'Degraded': Back-off pulling image")))
+			Expect(fakeRecorder.Events).To(Receive(Equal("Warning SyncFailed Sync failed (revision '0a1b2c3d'): one or more objects failed to apply")))
+			Expect(fakeRecorder.Events).ToNot(Receive())
+		})
+	})
+})
+
+var _ = Describe("GitOpsDeployment deletion policy", func() {
+
+	var gitopsDepl managedgitopsv1alpha1.GitOpsDeployment
+	var service *corev1.Service
+	var k8sClient client.Client
+
+	BeforeEach(func() {
+		scheme, _, _, _, err := tests.GenericTestSetup()
+		Expect(err).ToNot(HaveOccurred())
+
+		gitopsDepl = managedgitopsv1alpha1.GitOpsDeployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "my-gitops-depl", Namespace: "my-namespace"},
+			Spec: managedgitopsv1alpha1.GitOpsDeploymentSpec{
+				Type:           managedgitopsv1alpha1.GitOpsDeploymentSpecType_Automated,
+				DeletionPolicy: managedgitopsv1alpha1.DeletionPolicy_Orphan,
+			},
+			Status: managedgitopsv1alpha1.GitOpsDeploymentStatus{
+				Resources: []managedgitopsv1alpha1.ResourceStatus{
+					{Version: "v1", Kind: "Service", Namespace: "my-namespace", Name: "my-service"},
+					{Version: "v1", Kind: "ConfigMap", Namespace: "my-namespace", Name: "no-longer-exists"},
+					{Version: "v1", Kind: "Namespace", Name: "cluster-scoped"},
+				},
+			},
+		}
+
+		service = &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "my-service", Namespace: "my-namespace"}}
+
+		k8sClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(&gitopsDepl, service).Build()
+	})
+
+	It("should add the deletion finalizer only once, and remove it once the deletion policy is changed back to Cascade", func() {
+		ctx := context.Background()
+
+		Expect(reconcileDeletionFinalizerOfDeletionPolicy(ctx, k8sClient, &gitopsDepl)).To(Succeed())
+		Expect(reconcileDeletionFinalizerOfDeletionPolicy(ctx, k8sClient, &gitopsDepl)).To(Succeed())
+
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(&gitopsDepl), &gitopsDepl)).To(Succeed())
+		Expect(gitopsDepl.Finalizers).To(Equal([]string{managedgitopsv1alpha1.DeletionFinalizer}))
+		Expect(gitopsDepl.Annotations).To(HaveKeyWithValue(managedgitopsv1alpha1.DeletionFinalizerAddedAnnotation, "true"))
+
+		By("changing the deletion policy to Foreground, which also requires the finalizer")
+		gitopsDepl.Spec.DeletionPolicy = managedgitopsv1alpha1.DeletionPolicy_Foreground
+		Expect(k8sClient.Update(ctx, &gitopsDepl)).To(Succeed())
+		Expect(reconcileDeletionFinalizerOfDeletionPolicy(ctx, k8sClient, &gitopsDepl)).To(Succeed())
+		Expect(gitopsDepl.Finalizers).To(Equal([]string{managedgitopsv1alpha1.DeletionFinalizer}))
+
+		By("changing the deletion policy back to Cascade")
+		gitopsDepl.Spec.DeletionPolicy = managedgitopsv1alpha1.DeletionPolicy_Cascade
+		Expect(k8sClient.Update(ctx, &gitopsDepl)).To(Succeed())
+		Expect(reconcileDeletionFinalizerOfDeletionPolicy(ctx, k8sClient, &gitopsDepl)).To(Succeed())
+
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(&gitopsDepl), &gitopsDepl)).To(Succeed())
+		Expect(gitopsDepl.Finalizers).To(BeEmpty())
+		Expect(gitopsDepl.Annotations).ToNot(HaveKey(managedgitopsv1alpha1.DeletionFinalizerAddedAnnotation))
+	})
+
+	It("should not remove a deletion finalizer that was added by the user", func() {
+		ctx := context.Background()
+
+		gitopsDepl.Spec.DeletionPolicy = ""
+		gitopsDepl.Finalizers = []string{managedgitopsv1alpha1.DeletionFinalizer}
+		Expect(k8sClient.Update(ctx, &gitopsDepl)).To(Succeed())
+
+		Expect(reconcileDeletionFinalizerOfDeletionPolicy(ctx, k8sClient, &gitopsDepl)).To(Succeed())
+
+		By("verifying the finalizer is not marked as added for the deletion policy, when the deletion policy requires it")
+		gitopsDepl.Spec.DeletionPolicy = managedgitopsv1alpha1.DeletionPolicy_Foreground
+		Expect(k8sClient.Update(ctx, &gitopsDepl)).To(Succeed())
+		Expect(reconcileDeletionFinalizerOfDeletionPolicy(ctx, k8sClient, &gitopsDepl)).To(Succeed())
+
+		gitopsDepl.Spec.DeletionPolicy = managedgitopsv1alpha1.DeletionPolicy_Cascade
+		Expect(k8sClient.Update(ctx, &gitopsDepl)).To(Succeed())
+		Expect(reconcileDeletionFinalizerOfDeletionPolicy(ctx, k8sClient, &gitopsDepl)).To(Succeed())
+
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(&gitopsDepl), &gitopsDepl)).To(Succeed())
+		Expect(gitopsDepl.Finalizers).To(Equal([]string{managedgitopsv1alpha1.DeletionFinalizer}))
+	})
+
+	It("should not update the GitOpsDeployment if the presence of the finalizer already matches the deletion policy", func() {
+		ctx := context.Background()
+
+		scheme, _, _, _, err := tests.GenericTestSetup()
+		Expect(err).ToNot(HaveOccurred())
+
+		gitopsDepl.Finalizers = []string{managedgitopsv1alpha1.DeletionFinalizer}
+
+		updates := 0
+		k8sClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(&gitopsDepl).WithInterceptorFuncs(interceptor.Funcs{
+			Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+				updates++
+				return c.Update(ctx, obj, opts...)
+			},
+		}).Build()
+
+		Expect(reconcileDeletionFinalizerOfDeletionPolicy(ctx, k8sClient, &gitopsDepl)).To(Succeed())
+
+		By("changing the deletion policy to Cascade, when the finalizer was not added for the deletion policy")
+		gitopsDepl.Spec.DeletionPolicy = managedgitopsv1alpha1.DeletionPolicy_Cascade
+		Expect(reconcileDeletionFinalizerOfDeletionPolicy(ctx, k8sClient, &gitopsDepl)).To(Succeed())
+
+		Expect(updates).To(Equal(0))
+	})
+
+	It("should annotate the resources of the GitOpsDeployment that still exist as orphaned", func() {
+		ctx := context.Background()
+
+		Expect(annotateOrphanedResources(ctx, k8sClient, gitopsDepl, log.FromContext(ctx))).To(Succeed())
+
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(service), service)).To(Succeed())
+		Expect(service.Annotations).To(HaveKeyWithValue(managedgitopsv1alpha1.OrphanedResourceAnnotation, "true"))
+	})
+
+	It("should not annotate the resources of a GitOpsDeployment that targets a managed environment", func() {
+		ctx := context.Background()
+
+		gitopsDepl.Spec.Destination.Environment = "my-managed-environment"
+		Expect(annotateOrphanedResources(ctx, k8sClient, gitopsDepl, log.FromContext(ctx))).To(Succeed())
+
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(service), service)).To(Succeed())
+		Expect(service.Annotations).ToNot(HaveKey(managedgitopsv1alpha1.OrphanedResourceAnnotation))
+	})
+})
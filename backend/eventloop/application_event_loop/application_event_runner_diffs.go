@@ -0,0 +1,520 @@
+package application_event_loop
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/go-logr/logr"
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
+	db "github.com/redhat-appstudio/managed-gitops/backend-shared/db"
+	dbutil "github.com/redhat-appstudio/managed-gitops/backend-shared/db/util"
+	sharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/gitopserrors"
+	logutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/log"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/operations"
+	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/eventlooptypes"
+	corev1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// This file is responsible for processing events related to GitOpsDeploymentDiff CR.
+//
+// A GitOpsDeploymentDiff is handled much like a GitOpsDeploymentSyncRun: a DiffOperation row is created for the CR,
+// and the cluster-agent is informed of it via an Operation. The cluster-agent renders the manifests of the
+// GitOpsDeployment at the requested revision, compares them against the live state, and writes the result to the
+// DiffOperation row, which is then published to the status of the GitOpsDeploymentDiff.
+
+func (action *applicationEventLoopRunner_Action) applicationEventRunner_handleDiffModified(ctx context.Context, dbQueries db.ApplicationScopedQueries) error {
+
+	// Handle all GitOpsDeploymentDiff related events
+	err := action.applicationEventRunner_handleDiffModifiedInternal(ctx, dbQueries)
+	if err == nil {
+		return nil
+	}
+
+	diffCR, clientErr := getGitOpsDeploymentDiff(ctx, action.workspaceClient, action.eventResourceName, action.eventResourceNamespace)
+	if clientErr != nil {
+		if !apierr.IsNotFound(clientErr) {
+			return fmt.Errorf("unable to get GitOpsDeploymentDiff: %v", clientErr)
+		}
+		return nil
+	}
+
+	// Report the error to the user in the status of the GitOpsDeploymentDiff
+	errMsg := err.UserError()
+	if errMsg == "" {
+		errMsg = gitopserrors.UnknownError
+	}
+
+	if diffCR.Status.Phase != managedgitopsv1alpha1.DiffPhase_Failed || diffCR.Status.Message != errMsg {
+		now := metav1.Now()
+		diffCR.Status.Phase = managedgitopsv1alpha1.DiffPhase_Failed
+		diffCR.Status.Message = errMsg
+		diffCR.Status.FinishedAt = &now
+
+		if err := action.workspaceClient.Status().Update(ctx, diffCR); err != nil {
+			return fmt.Errorf("failed to update the status of GitOpsDeploymentDiff: %v", err)
+		}
+	}
+
+	return err.DevError()
+}
+
+func getGitOpsDeploymentDiff(ctx context.Context, k8sClient client.Client, name, namespace string) (*managedgitopsv1alpha1.GitOpsDeploymentDiff, error) {
+	diffCR := &managedgitopsv1alpha1.GitOpsDeploymentDiff{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+
+	if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(diffCR), diffCR); err != nil {
+		return nil, err
+	}
+
+	return diffCR, nil
+}
+
+func (a *applicationEventLoopRunner_Action) applicationEventRunner_handleDiffModifiedInternal(ctx context.Context,
+	dbQueries db.ApplicationScopedQueries) gitopserrors.UserError {
+
+	log := a.log
+
+	namespace := corev1.Namespace{}
+	if err := a.workspaceClient.Get(ctx, types.NamespacedName{Name: a.eventResourceNamespace}, &namespace); err != nil {
+		userError := fmt.Sprintf("unable to retrieve the contents of the namespace '%s' containing the API resource '%s'. Does it exist?",
+			a.eventResourceNamespace, a.eventResourceName)
+		devError := fmt.Errorf("unable to retrieve namespace '%s': %v", a.eventResourceNamespace, err)
+		return gitopserrors.NewUserDevError(userError, devError)
+	}
+
+	clusterUser, _, err := a.sharedResourceEventLoop.GetOrCreateClusterUserByNamespaceUID(ctx, a.workspaceClient, namespace, log)
+	if err != nil {
+		userError := "unable to locate managed environment for new application in diff modified"
+		devError := fmt.Errorf("unable to retrieve cluster user in applicationEventRunner_handleDiffModifiedInternal, '%s': %v",
+			string(namespace.UID), err)
+		return gitopserrors.NewUserDevError(userError, devError)
+	}
+
+	// Retrieve the GitOpsDeploymentDiff from the namespace
+	diffCRExists := true
+	diffCR, err := getGitOpsDeploymentDiff(ctx, a.workspaceClient, a.eventResourceName, a.eventResourceNamespace)
+	if err != nil {
+		if !apierr.IsNotFound(err) {
+			userError := "unable to retrieve the GitOpsDeploymentDiff object from the namespace, due to unknown error."
+			log.Error(err, "unable to locate object in handleDiffModified")
+			return gitopserrors.NewUserDevError(userError, err)
+		}
+		diffCRExists = false
+	}
+
+	// Retrieve the APICRToDatabaseMapping of the DiffOperation row that corresponds to the GitOpsDeploymentDiff
+	var apiCRToDBList []db.APICRToDatabaseMapping
+
+	if diffCRExists {
+		mapping := db.APICRToDatabaseMapping{
+			APIResourceType: db.APICRToDatabaseMapping_ResourceType_GitOpsDeploymentDiff,
+			APIResourceUID:  string(diffCR.UID),
+			DBRelationType:  db.APICRToDatabaseMapping_DBRelationType_DiffOperation,
+		}
+
+		if err := dbQueries.GetDatabaseMappingForAPICR(ctx, &mapping); err != nil {
+			if !db.IsResultNotFoundError(err) {
+				userError := "unable to retrieve GitOpsDeploymentDiff metadata from the internal database, due to an unknown error"
+				log.Error(err, "unable to resource APICRToDatabaseMapping", "uid", string(diffCR.UID))
+				return gitopserrors.NewUserDevError(userError, err)
+			}
+		} else {
+			apiCRToDBList = append(apiCRToDBList, mapping)
+		}
+
+	} else {
+
+		// The CR no longer exists, so instead we retrieve the mapping of the GitOpsDeploymentDiff by name/namespace/namespace uid.
+		if err := dbQueries.ListAPICRToDatabaseMappingByAPINamespaceAndName(ctx, db.APICRToDatabaseMapping_ResourceType_GitOpsDeploymentDiff,
+			a.eventResourceName, a.eventResourceNamespace, eventlooptypes.GetWorkspaceIDFromNamespaceID(namespace),
+			db.APICRToDatabaseMapping_DBRelationType_DiffOperation, &apiCRToDBList); err != nil {
+			userError := "unable to retrieve data related to previous GitOpsDeploymentDiff in the namespace, due to an unknown error"
+			log.Error(err, "unable to find API CR to DB Mapping, by API name/namespace/uid", logutil.Log_K8s_Request_NamespaceID, string(namespace.UID))
+			return gitopserrors.NewUserDevError(userError, err)
+		}
+	}
+
+	dbEntryExists := len(apiCRToDBList) > 0
+
+	log.Info("workspaceEventLoopRunner_handleDiffModified", "diffCRExists", diffCRExists, "dbEntryExists", dbEntryExists)
+
+	if !diffCRExists {
+		// Handle delete: if the GitOpsDeploymentDiff CR doesn't exist, clean up any database rows that remain for it
+		for idx := range apiCRToDBList {
+			if err := a.cleanupOldDiffDBEntry(ctx, &apiCRToDBList[idx], *clusterUser, dbQueries); err != nil {
+				return gitopserrors.NewDevOnlyError(err)
+			}
+		}
+		return nil
+	}
+
+	if dbEntryExists {
+
+		if len(apiCRToDBList) != 1 {
+			err := fmt.Errorf("SEVERE - Update only supports one operation parameter")
+			log.Error(err, err.Error())
+			return gitopserrors.NewDevOnlyError(err)
+		}
+
+		diffOperation := db.DiffOperation{DiffOperation_id: apiCRToDBList[0].DBRelationKey}
+		if err := dbQueries.GetDiffOperationById(ctx, &diffOperation); err != nil && !db.IsResultNotFoundError(err) {
+			log.Error(err, "unable to retrieve diff operation by id on modified", "diffOperationID", diffOperation.DiffOperation_id)
+			return gitopserrors.NewDevOnlyError(err)
+
+		} else if err == nil &&
+			diffOperation.DeploymentNameField == diffCR.Spec.GitopsDeploymentName && diffOperation.Revision == diffCR.Spec.Revision {
+
+			// The diff for the current spec was already requested: its status is published as it progresses.
+			return nil
+		}
+
+		// Otherwise, the spec of the GitOpsDeploymentDiff has changed (or the DiffOperation was deleted along with
+		// its Application), so replace the previous DiffOperation with a new one.
+		log.Info("Spec of GitOpsDeploymentDiff has changed, so the diff will be recalculated")
+		if err := a.cleanupOldDiffDBEntry(ctx, &apiCRToDBList[0], *clusterUser, dbQueries); err != nil {
+			return gitopserrors.NewDevOnlyError(err)
+		}
+	}
+
+	// Handle create: retrieve the GitOpsDeployment referenced by the GitOpsDeploymentDiff, and its Application
+	gitopsDepl := &managedgitopsv1alpha1.GitOpsDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      diffCR.Spec.GitopsDeploymentName,
+			Namespace: diffCR.Namespace,
+		},
+	}
+	if err := a.workspaceClient.Get(ctx, client.ObjectKeyFromObject(gitopsDepl), gitopsDepl); err != nil {
+		if apierr.IsNotFound(err) {
+			userError := fmt.Sprintf("Unable to retrieve GitOpsDeployment '%s' referenced by the GitOpsDeploymentDiff", gitopsDepl.Name)
+			return gitopserrors.NewUserDevError(userError, fmt.Errorf("unable to retrieve gitopsdeployment referenced in diff: %v", err))
+		}
+		log.Error(err, "unable to retrieve gitopsdeployment referenced in diff")
+		return gitopserrors.NewDevOnlyError(err)
+	}
+
+	deplToAppMapping := &db.DeploymentToApplicationMapping{Deploymenttoapplicationmapping_uid_id: string(gitopsDepl.UID)}
+	if err := dbQueries.GetDeploymentToApplicationMappingByDeplId(ctx, deplToAppMapping); err != nil {
+		log.Error(err, "unable to retrieve deployment to application mapping, on diff modified", logutil.Log_K8s_Request_UID, string(gitopsDepl.UID))
+		return gitopserrors.NewDevOnlyError(err)
+	}
+
+	application := &db.Application{Application_id: deplToAppMapping.Application_id}
+	if err := dbQueries.GetApplicationById(ctx, application); err != nil {
+		log.Error(err, "unable to retrieve application, on diff modified", logutil.Log_ApplicationID, deplToAppMapping.Application_id)
+		return gitopserrors.NewDevOnlyError(err)
+	}
+
+	gitopsEngineInstance, err := a.sharedResourceEventLoop.GetGitopsEngineInstanceById(ctx, application.Engine_instance_inst_id,
+		a.workspaceClient, namespace, log)
+	if err != nil {
+		log.Error(err, "unable to retrieve gitopsengineinstance, on diff modified", "gitopsEngineInstanceID", application.Engine_instance_inst_id)
+		return gitopserrors.NewDevOnlyError(err)
+	}
+
+	return a.handleNewGitOpsDeplDiffEvent(ctx, diffCR, dbQueries, application, gitopsEngineInstance, namespace, *clusterUser)
+}
+
+// handleNewGitOpsDeplDiffEvent creates the DiffOperation and APICRToDBMapping rows for a GitOpsDeploymentDiff, informs the
+// cluster-agent of it (via Operation), and publishes the status of the diff while waiting for the cluster-agent to complete it.
+func (a *applicationEventLoopRunner_Action) handleNewGitOpsDeplDiffEvent(ctx context.Context, diffCRParam *managedgitopsv1alpha1.GitOpsDeploymentDiff,
+	dbQueries db.ApplicationScopedQueries, application *db.Application, gitopsEngineInstance *db.GitopsEngineInstance,
+	namespace corev1.Namespace, clusterUser db.ClusterUser) gitopserrors.UserError {
+
+	log := a.log
+	log.Info("Received GitOpsDeploymentDiff event for a new GitOpsDeploymentDiff resource")
+
+	if application == nil || gitopsEngineInstance == nil {
+		err := fmt.Errorf("app or engine instance were nil in handleDiffModified app: %v, instance: %v", application, gitopsEngineInstance)
+		log.Error(err, "unexpected nil value of required objects")
+		return gitopserrors.NewDevOnlyError(err)
+	}
+	if gitopsEngineInstance.Namespace_name == "" {
+		return gitopserrors.NewDevOnlyError(fmt.Errorf("gitopsengineinstance namespace is empty"))
+	}
+
+	// createdResources is a list of database entries created in this function; if an error occurs, we delete them
+	// in reverse order.
+	var createdResources []db.AppScopedDisposableResource
+
+	diffOperation := &db.DiffOperation{
+		Application_id:      application.Application_id,
+		DeploymentNameField: diffCRParam.Spec.GitopsDeploymentName,
+		Revision:            diffCRParam.Spec.Revision,
+		Phase:               db.DiffOperation_Phase_Pending,
+	}
+	if err := dbQueries.CreateDiffOperation(ctx, diffOperation); err != nil {
+		if db.IsMaxLengthError(err) {
+			userErr := fmt.Sprintf("invalid GitOpsDeploymentDiff '%s': %v", diffCRParam.Name, err)
+			return gitopserrors.NewUserDevError(userErr, err)
+		}
+		log.Error(err, "unable to create diff operation in database")
+		return gitopserrors.NewDevOnlyError(err)
+	}
+	createdResources = append(createdResources, diffOperation)
+	log.Info("Created a Diff Operation: " + diffOperation.DiffOperation_id)
+
+	newApiCRToDBMapping := db.APICRToDatabaseMapping{
+		APIResourceType: db.APICRToDatabaseMapping_ResourceType_GitOpsDeploymentDiff,
+		APIResourceUID:  string(diffCRParam.UID),
+		DBRelationType:  db.APICRToDatabaseMapping_DBRelationType_DiffOperation,
+		DBRelationKey:   diffOperation.DiffOperation_id,
+
+		APIResourceName:      diffCRParam.Name,
+		APIResourceNamespace: diffCRParam.Namespace,
+		NamespaceUID:         eventlooptypes.GetWorkspaceIDFromNamespaceID(namespace),
+	}
+	if err := dbQueries.CreateAPICRToDatabaseMapping(ctx, &newApiCRToDBMapping); err != nil {
+		log.Error(err, "unable to create api to db mapping in database")
+
+		if disposeErr := dbutil.DisposeApplicationScopedResources(ctx, createdResources, dbQueries, log); disposeErr != nil {
+			log.Error(disposeErr, "unable to dispose of old resources on diff create")
+		}
+
+		return gitopserrors.NewDevOnlyError(err)
+	}
+	createdResources = append(createdResources, &newApiCRToDBMapping)
+
+	operationClient, err := a.k8sClientFactory.GetK8sClientForGitOpsEngineInstance(ctx, gitopsEngineInstance)
+	if err != nil {
+		log.Error(err, "unable to retrieve gitopsengine instance from handleDiffModified")
+
+		if disposeErr := dbutil.DisposeApplicationScopedResources(ctx, createdResources, dbQueries, log); disposeErr != nil {
+			log.Error(disposeErr, "unable to dispose of old resources on diff create")
+		}
+
+		return gitopserrors.NewDevOnlyError(err)
+	}
+
+	dbOperationInput := db.Operation{
+		Instance_id:   gitopsEngineInstance.Gitopsengineinstance_id,
+		Resource_id:   diffOperation.DiffOperation_id,
+		Resource_type: db.OperationResourceType_DiffOperation,
+	}
+
+	k8sOperation, dbOperation, err := operations.CreateOperation(ctx, false, dbOperationInput, clusterUser.Clusteruser_id,
+		gitopsEngineInstance.Namespace_name, dbQueries, operationClient, log)
+	if err != nil {
+		log.Error(err, "could not create operation", "namespace", gitopsEngineInstance.Namespace_name)
+
+		if disposeErr := dbutil.DisposeApplicationScopedResources(ctx, createdResources, dbQueries, log); disposeErr != nil {
+			log.Error(disposeErr, "unable to dispose of old resources on diff create")
+		}
+
+		return gitopserrors.NewDevOnlyError(err)
+	}
+
+	// Publish the Pending phase, before waiting on the cluster-agent
+	if err := a.publishDiffStatus(ctx, diffCRParam, dbQueries); err != nil {
+		log.Error(err, "unable to update the status of the GitOpsDeploymentDiff, after the diff was requested")
+	}
+
+	backoff := sharedutil.ExponentialBackoff{Factor: 1.3, Min: time.Millisecond * 1000, Max: time.Second * 10, Jitter: true}
+
+outer_for:
+	for {
+
+		if a.testOnlySkipCreateOperation {
+			break outer_for
+		}
+
+		if isComplete, err := operations.IsOperationComplete(ctx, dbOperation, dbQueries); err != nil {
+			log.Error(err, "an error occurred on retrieving operation status")
+			break outer_for
+
+		} else if isComplete {
+			break outer_for
+		}
+
+		currentDiffCR, err := getGitOpsDeploymentDiff(ctx, a.workspaceClient, diffCRParam.Name, diffCRParam.Namespace)
+		if err != nil {
+			if apierr.IsNotFound(err) {
+				log.Info("the GitOpsDeploymentDiff that we were watching is no longer present, exiting the diff process.")
+				break outer_for
+			}
+			// continue for unexpected errors: we expect them to be transient, not permanent
+			log.Error(err, "an unexpected error occurred while attempting to retrieve GitOpsDeploymentDiff CR")
+
+		} else if currentDiffCR.UID != diffCRParam.UID || currentDiffCR.Generation != diffCRParam.Generation {
+			// The GitOpsDeploymentDiff was recreated or its spec was modified: exit, to allow the new event to be processed by the runner.
+			log.Info("The GitOpsDeploymentDiff CR has changed, versus the CR that we began with, exiting the diff process")
+			break outer_for
+		}
+
+		backoff.DelayOnFail(ctx)
+	}
+
+	if currentDiffCR, err := getGitOpsDeploymentDiff(ctx, a.workspaceClient, diffCRParam.Name, diffCRParam.Namespace); err == nil &&
+		currentDiffCR.UID == diffCRParam.UID {
+
+		if err := a.publishDiffStatus(ctx, currentDiffCR, dbQueries); err != nil {
+			log.Error(err, "unable to update the status of the GitOpsDeploymentDiff, after the diff completed")
+		}
+	}
+
+	if err := operations.CleanupOperation(ctx, *dbOperation, *k8sOperation, dbQueries, operationClient, !a.testOnlySkipCreateOperation, log); err != nil {
+		return gitopserrors.NewDevOnlyError(err)
+	}
+
+	return nil
+}
+
+// cleanupOldDiffDBEntry deletes the DiffOperation that is referenced by the APICRToDatabaseMapping, any Operations that
+// point to it, and the APICRToDatabaseMapping itself.
+func (a *applicationEventLoopRunner_Action) cleanupOldDiffDBEntry(ctx context.Context, apiCRToDB *db.APICRToDatabaseMapping,
+	clusterUser db.ClusterUser, dbQueries db.ApplicationScopedQueries) error {
+
+	log := a.log
+
+	if apiCRToDB.DBRelationType != db.APICRToDatabaseMapping_DBRelationType_DiffOperation {
+		err := fmt.Errorf("SEVERE: unexpected DBRelationKey, should be DiffOperation")
+		log.Error(err, err.Error())
+		return err
+	}
+
+	var operations []db.Operation
+	if err := dbQueries.ListOperationsByResourceIdAndTypeAndOwnerId(ctx, apiCRToDB.DBRelationKey, db.OperationResourceType_DiffOperation,
+		&operations, clusterUser.Clusteruser_id); err != nil {
+
+		log.Error(err, "unable to retrieve operations pointing to diff operation", "key", apiCRToDB.DBRelationKey)
+		return err
+	}
+
+	// Delete the operations that reference this DiffOperation
+	for idx := range operations {
+		operationId := operations[idx].Operation_id
+
+		if _, err := dbQueries.CheckedDeleteOperationById(ctx, operationId, clusterUser.Clusteruser_id); err != nil {
+			log.Error(err, "unable to delete old operation", "operationId", operationId)
+			return err
+		}
+		log.Info("Operation deleted with ID: " + operationId)
+	}
+
+	rowsDeleted, err := dbQueries.DeleteDiffOperationById(ctx, apiCRToDB.DBRelationKey)
+	if err != nil {
+		log.Error(err, "unable to delete diff operation db entry on diff delete", "key", apiCRToDB.DBRelationKey)
+		return err
+	} else if rowsDeleted > 0 {
+		log.Info("Diff Operation deleted with ID: " + apiCRToDB.DBRelationKey)
+	}
+
+	rowsDeleted, err = dbQueries.DeleteAPICRToDatabaseMapping(ctx, apiCRToDB)
+	if err != nil {
+		log.Error(err, "unable to delete apiCRToDBmapping", "mapping", apiCRToDB.APIResourceUID)
+		return err
+	} else if rowsDeleted == 0 {
+		log.V(logutil.LogLevel_Warn).Info("unexpected number of rows deleted of apiCRToDBmapping", "mapping", apiCRToDB.APIResourceUID)
+	} else {
+		log.Info("Deleted APICRToDatabaseMapping")
+	}
+
+	return nil
+}
+
+// publishDiffStatus updates the status of the GitOpsDeploymentDiff with the result of the diff, as reported by the
+// cluster-agent in the corresponding DiffOperation row.
+func (a *applicationEventLoopRunner_Action) publishDiffStatus(ctx context.Context, diffCR *managedgitopsv1alpha1.GitOpsDeploymentDiff,
+	dbQueries db.ApplicationScopedQueries) error {
+
+	mapping := db.APICRToDatabaseMapping{
+		APIResourceType: db.APICRToDatabaseMapping_ResourceType_GitOpsDeploymentDiff,
+		APIResourceUID:  string(diffCR.UID),
+		DBRelationType:  db.APICRToDatabaseMapping_DBRelationType_DiffOperation,
+	}
+	if err := dbQueries.GetDatabaseMappingForAPICR(ctx, &mapping); err != nil {
+		if db.IsResultNotFoundError(err) {
+			// The GitOpsDeploymentDiff has not yet been processed, or was rejected
+			return nil
+		}
+		return fmt.Errorf("unable to retrieve APICRToDatabaseMapping of GitOpsDeploymentDiff '%s': %v", diffCR.Name, err)
+	}
+
+	diffOperation := db.DiffOperation{DiffOperation_id: mapping.DBRelationKey}
+	if err := dbQueries.GetDiffOperationById(ctx, &diffOperation); err != nil {
+		if db.IsResultNotFoundError(err) {
+			return nil
+		}
+		return fmt.Errorf("unable to retrieve DiffOperation of GitOpsDeploymentDiff '%s': %v", diffCR.Name, err)
+	}
+
+	newStatus := diffCR.Status.DeepCopy()
+	convertDiffOperationToDiffStatus(diffOperation, newStatus, a.log)
+
+	if reflect.DeepEqual(*newStatus, diffCR.Status) {
+		return nil
+	}
+
+	diffCR.Status = *newStatus
+
+	return a.workspaceClient.Status().Update(ctx, diffCR)
+}
+
+// publishDiffStatusesOfGitOpsDeployment updates the status of each GitOpsDeploymentDiff that targets the
+// GitOpsDeployment, and that has not yet finished.
+func (a *applicationEventLoopRunner_Action) publishDiffStatusesOfGitOpsDeployment(ctx context.Context, gitopsDeployment managedgitopsv1alpha1.GitOpsDeployment,
+	dbQueries db.ApplicationScopedQueries) error {
+
+	var diffList managedgitopsv1alpha1.GitOpsDeploymentDiffList
+	if err := a.workspaceClient.List(ctx, &diffList, &client.ListOptions{Namespace: gitopsDeployment.Namespace}); err != nil {
+		return fmt.Errorf("unable to list GitOpsDeploymentDiffs in namespace '%s': %v", gitopsDeployment.Namespace, err)
+	}
+
+	for idx := range diffList.Items {
+		diff := diffList.Items[idx]
+
+		if diff.Spec.GitopsDeploymentName != gitopsDeployment.Name || diff.Status.IsFinished() {
+			continue
+		}
+
+		if err := a.publishDiffStatus(ctx, &diff, dbQueries); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// convertDiffOperationToDiffStatus updates the status of the GitOpsDeploymentDiff from the status fields of the DiffOperation.
+func convertDiffOperationToDiffStatus(diffOperation db.DiffOperation, status *managedgitopsv1alpha1.GitOpsDeploymentDiffStatus, log logr.Logger) {
+
+	// Match the precision and location of a time that has been read from the K8s API, so that the status is
+	// not seen as modified every time it is published
+	convertTime := func(t time.Time) *metav1.Time {
+		if t.IsZero() {
+			return nil
+		}
+		res := metav1.NewTime(t.Truncate(time.Second).Local())
+		return &res
+	}
+
+	status.Phase = managedgitopsv1alpha1.DiffPhase(diffOperation.Phase)
+	if status.Phase == "" {
+		status.Phase = managedgitopsv1alpha1.DiffPhase_Pending
+	}
+	status.Message = diffOperation.PhaseMessage
+	status.StartedAt = convertTime(diffOperation.StartedAt)
+	status.FinishedAt = convertTime(diffOperation.FinishedAt)
+	status.Revision = diffOperation.ResolvedRevision
+	status.Truncated = diffOperation.Truncated
+
+	status.Resources = nil
+	if diffOperation.ResourceDiffs != "" {
+		if err := json.Unmarshal([]byte(diffOperation.ResourceDiffs), &status.Resources); err != nil {
+			// Report the rest of the status, even if the resource diffs could not be parsed
+			log.Error(err, "unable to unmarshal resource diffs of DiffOperation", "diffOperationID", diffOperation.DiffOperation_id)
+			status.Resources = nil
+		}
+	}
+}
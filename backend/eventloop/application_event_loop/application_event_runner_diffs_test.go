@@ -0,0 +1,248 @@
+package application_event_loop
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
+	db "github.com/redhat-appstudio/managed-gitops/backend-shared/db"
+	sharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/tests"
+	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/shared_resource_loop"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("Application Event Runner Diffs", func() {
+
+	Context("Handle GitOpsDeploymentDiff", func() {
+
+		var (
+			dbQueries         db.AllDatabaseQueries
+			k8sClient         *sharedutil.ProxyClient
+			gitopsDepl        *managedgitopsv1alpha1.GitOpsDeployment
+			gitopsDeplDiff    *managedgitopsv1alpha1.GitOpsDeploymentDiff
+			applicationAction applicationEventLoopRunner_Action
+			informer          sharedutil.ListEventReceiver
+		)
+		ctx := context.Background()
+
+		getDiffOperation := func(diff managedgitopsv1alpha1.GitOpsDeploymentDiff) (db.DiffOperation, error) {
+			mapping := db.APICRToDatabaseMapping{
+				APIResourceType: db.APICRToDatabaseMapping_ResourceType_GitOpsDeploymentDiff,
+				APIResourceUID:  string(diff.UID),
+				DBRelationType:  db.APICRToDatabaseMapping_DBRelationType_DiffOperation,
+			}
+			if err := dbQueries.GetDatabaseMappingForAPICR(ctx, &mapping); err != nil {
+				return db.DiffOperation{}, err
+			}
+
+			diffOperation := db.DiffOperation{DiffOperation_id: mapping.DBRelationKey}
+			err := dbQueries.GetDiffOperationById(ctx, &diffOperation)
+			return diffOperation, err
+		}
+
+		BeforeEach(func() {
+			scheme, argocdNamespace, kubesystemNamespace, workspace, err := tests.GenericTestSetup()
+			Expect(err).ToNot(HaveOccurred())
+
+			gitopsDepl = &managedgitopsv1alpha1.GitOpsDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-gitops-depl",
+					Namespace: workspace.Name,
+					UID:       uuid.NewUUID(),
+				},
+				Spec: managedgitopsv1alpha1.GitOpsDeploymentSpec{
+					Type: managedgitopsv1alpha1.GitOpsDeploymentSpecType_Automated,
+					Source: managedgitopsv1alpha1.ApplicationSource{
+						Path:    "resources/test-data/sample-gitops-repository/environments/overlays/dev",
+						RepoURL: "https://github.com/test/test",
+					},
+				},
+			}
+
+			gitopsDeplDiff = &managedgitopsv1alpha1.GitOpsDeploymentDiff{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "gitops-diff",
+					Namespace: gitopsDepl.Namespace,
+					UID:       uuid.NewUUID(),
+				},
+				Spec: managedgitopsv1alpha1.GitOpsDeploymentDiffSpec{
+					GitopsDeploymentName: gitopsDepl.Name,
+					Revision:             "my-feature-branch",
+				},
+			}
+
+			k8sClientOuter := fake.NewClientBuilder().WithScheme(scheme).WithObjects(workspace, argocdNamespace, kubesystemNamespace, gitopsDepl, gitopsDeplDiff).Build()
+
+			informer = sharedutil.ListEventReceiver{}
+			k8sClient = &sharedutil.ProxyClient{
+				InnerClient: k8sClientOuter,
+				Informer:    &informer,
+			}
+
+			dbQueries, err = db.NewUnsafePostgresDBQueries(true, false)
+			Expect(err).ToNot(HaveOccurred())
+
+			applicationAction = applicationEventLoopRunner_Action{
+				eventResourceName:           gitopsDepl.Name,
+				eventResourceNamespace:      gitopsDepl.Namespace,
+				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(nil),
+				workspaceClient:             k8sClient,
+				log:                         log.FromContext(ctx),
+				workspaceID:                 string(workspace.UID),
+				testOnlySkipCreateOperation: true,
+				k8sClientFactory: MockSRLK8sClientFactory{
+					fakeClient: k8sClient,
+				},
+			}
+
+			_, _, _, _, userDevErr := applicationAction.applicationEventRunner_handleDeploymentModified(ctx, dbQueries)
+			Expect(userDevErr).To(BeNil())
+
+			applicationAction.eventResourceName = gitopsDeplDiff.Name
+		})
+
+		It("should create a DiffOperation and an Operation for a new GitOpsDeploymentDiff, and publish its status", func() {
+			err := applicationAction.applicationEventRunner_handleDiffModified(ctx, dbQueries)
+			Expect(err).ToNot(HaveOccurred())
+
+			By("checking the DiffOperation was created")
+			diffOperation, err := getDiffOperation(*gitopsDeplDiff)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(diffOperation.DeploymentNameField).To(Equal(gitopsDepl.Name))
+			Expect(diffOperation.Revision).To(Equal("my-feature-branch"))
+			Expect(diffOperation.Phase).To(Equal(db.DiffOperation_Phase_Pending))
+
+			By("checking an Operation was created for the DiffOperation")
+			operationCreated := false
+			for _, event := range informer.Events {
+				if event.Action == sharedutil.Create && event.ObjectTypeOf() == "Operation" {
+					operationCreated = true
+				}
+			}
+			Expect(operationCreated).To(BeTrue())
+
+			By("checking the status of the GitOpsDeploymentDiff is Pending")
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(gitopsDeplDiff), gitopsDeplDiff)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(gitopsDeplDiff.Status.Phase).To(Equal(managedgitopsv1alpha1.DiffPhase_Pending))
+
+			By("simulating the cluster-agent completing the diff, and publishing the result on the status tick")
+			diffOperation.Phase = db.DiffOperation_Phase_Succeeded
+			diffOperation.ResolvedRevision = "0a1b2c3d"
+			diffOperation.FinishedAt = time.Now()
+			diffOperation.ResourceDiffs = `[{"kind":"ConfigMap","name":"my-config","status":"Modified","diff":"-a\n+b\n"}]`
+			err = dbQueries.UpdateDiffOperationStatus(ctx, &diffOperation)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = applicationAction.publishDiffStatusesOfGitOpsDeployment(ctx, *gitopsDepl, dbQueries)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(gitopsDeplDiff), gitopsDeplDiff)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(gitopsDeplDiff.Status.Phase).To(Equal(managedgitopsv1alpha1.DiffPhase_Succeeded))
+			Expect(gitopsDeplDiff.Status.Revision).To(Equal("0a1b2c3d"))
+			Expect(gitopsDeplDiff.Status.Resources).To(HaveLen(1))
+			Expect(gitopsDeplDiff.Status.Resources[0].Status).To(Equal(managedgitopsv1alpha1.ResourceDiffStatus_Modified))
+		})
+
+		It("should replace the DiffOperation when the revision of the GitOpsDeploymentDiff is modified", func() {
+			err := applicationAction.applicationEventRunner_handleDiffModified(ctx, dbQueries)
+			Expect(err).ToNot(HaveOccurred())
+
+			oldDiffOperation, err := getDiffOperation(*gitopsDeplDiff)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(gitopsDeplDiff), gitopsDeplDiff)
+			Expect(err).ToNot(HaveOccurred())
+			gitopsDeplDiff.Spec.Revision = "another-branch"
+			err = k8sClient.Update(ctx, gitopsDeplDiff)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = applicationAction.applicationEventRunner_handleDiffModified(ctx, dbQueries)
+			Expect(err).ToNot(HaveOccurred())
+
+			newDiffOperation, err := getDiffOperation(*gitopsDeplDiff)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(newDiffOperation.Revision).To(Equal("another-branch"))
+
+			err = dbQueries.GetDiffOperationById(ctx, &oldDiffOperation)
+			Expect(db.IsResultNotFoundError(err)).To(BeTrue())
+		})
+
+		It("should delete the DiffOperation when the GitOpsDeploymentDiff is deleted", func() {
+			err := applicationAction.applicationEventRunner_handleDiffModified(ctx, dbQueries)
+			Expect(err).ToNot(HaveOccurred())
+
+			diffOperation, err := getDiffOperation(*gitopsDeplDiff)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = k8sClient.Delete(ctx, gitopsDeplDiff)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = applicationAction.applicationEventRunner_handleDiffModified(ctx, dbQueries)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = dbQueries.GetDiffOperationById(ctx, &diffOperation)
+			Expect(db.IsResultNotFoundError(err)).To(BeTrue())
+		})
+
+		It("should report an error in the status if the GitOpsDeployment does not exist", func() {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(gitopsDeplDiff), gitopsDeplDiff)
+			Expect(err).ToNot(HaveOccurred())
+			gitopsDeplDiff.Spec.GitopsDeploymentName = "does-not-exist"
+			err = k8sClient.Update(ctx, gitopsDeplDiff)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = applicationAction.applicationEventRunner_handleDiffModified(ctx, dbQueries)
+			Expect(err).To(HaveOccurred())
+
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(gitopsDeplDiff), gitopsDeplDiff)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(gitopsDeplDiff.Status.Phase).To(Equal(managedgitopsv1alpha1.DiffPhase_Failed))
+			Expect(gitopsDeplDiff.Status.Message).To(ContainSubstring("does-not-exist"))
+		})
+	})
+})
+
+var _ = Describe("convertDiffOperationToDiffStatus", func() {
+
+	It("should report a DiffOperation that has not yet started as Pending", func() {
+		status := managedgitopsv1alpha1.GitOpsDeploymentDiffStatus{}
+		convertDiffOperationToDiffStatus(db.DiffOperation{}, &status, log.FromContext(context.Background()))
+
+		Expect(status.Phase).To(Equal(managedgitopsv1alpha1.DiffPhase_Pending))
+		Expect(status.StartedAt).To(BeNil())
+		Expect(status.FinishedAt).To(BeNil())
+		Expect(status.Resources).To(BeNil())
+	})
+
+	It("should convert the status of a completed DiffOperation", func() {
+		startedAt := time.Date(2024, time.January, 1, 9, 30, 0, 500, time.UTC)
+
+		status := managedgitopsv1alpha1.GitOpsDeploymentDiffStatus{}
+		convertDiffOperationToDiffStatus(db.DiffOperation{
+			Phase:            db.DiffOperation_Phase_Succeeded,
+			StartedAt:        startedAt,
+			FinishedAt:       startedAt.Add(time.Second),
+			ResolvedRevision: "0a1b2c3d",
+			ResourceDiffs:    `[{"kind":"ConfigMap","namespace":"my-namespace","name":"my-config","status":"Added","diff":"+a: b\n","truncated":true}]`,
+			Truncated:        true,
+		}, &status, log.FromContext(context.Background()))
+
+		Expect(status.Phase).To(Equal(managedgitopsv1alpha1.DiffPhase_Succeeded))
+		Expect(status.StartedAt.Time.Equal(startedAt.Truncate(time.Second))).To(BeTrue())
+		Expect(status.FinishedAt.Time.Equal(startedAt.Add(time.Second).Truncate(time.Second))).To(BeTrue())
+		Expect(status.Revision).To(Equal("0a1b2c3d"))
+		Expect(status.Truncated).To(BeTrue())
+		Expect(status.Resources).To(Equal([]managedgitopsv1alpha1.ResourceDiff{
+			{Kind: "ConfigMap", Namespace: "my-namespace", Name: "my-config", Status: managedgitopsv1alpha1.ResourceDiffStatus_Added, Diff: "+a: b\n", Truncated: true},
+		}))
+	})
+})
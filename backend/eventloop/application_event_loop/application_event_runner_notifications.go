@@ -0,0 +1,573 @@
+package application_event_loop
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
+	sharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//+kubebuilder:rbac:groups=managed-gitops.redhat.com,resources=gitopsdeploymentnotificationtargets,verbs=get;list;watch
+
+const (
+	// NotificationHeader_Event is the HTTP header containing the type of event of the notification
+	NotificationHeader_Event = "X-GitOps-Event"
+
+	// NotificationHeader_Delivery is the HTTP header containing a unique ID for the notification, which is the same
+	// for each attempt to deliver it: receivers may use it to ignore duplicate notifications.
+	NotificationHeader_Delivery = "X-GitOps-Delivery"
+
+	// NotificationHeader_Signature is the HTTP header containing the HMAC-SHA256 signature of the payload (as
+	// 'sha256=(hex value)'), if the GitOpsDeploymentNotificationTarget references an HMAC secret.
+	NotificationHeader_Signature = "X-GitOps-Signature"
+
+	// maxNotificationAttempts is the number of times the delivery of a notification is attempted, before it is discarded.
+	maxNotificationAttempts = 5
+
+	notificationRequestTimeout = 10 * time.Second
+)
+
+var (
+	// sharedAddressSpace is the carrier-grade NAT range (RFC 6598), which is not covered by net.IP.IsPrivate()
+	sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+	// errNotificationAddressNotAllowed is returned when the URL of a GitOpsDeploymentNotificationTarget resolves to an
+	// address that notifications may not be sent to.
+	errNotificationAddressNotAllowed = errors.New("notifications may not be sent to loopback, link-local or private addresses")
+)
+
+// NotificationSender delivers the notifications of the GitOpsDeployments of a namespace, and keeps the notification
+// state of each GitOpsDeployment between its status updates. A NotificationSender is owned by the workspace event loop
+// of the namespace, and is shared by the application event loops of the namespace.
+type NotificationSender struct {
+	// retryLoop delivers the notifications, retrying failed deliveries with backoff.
+	retryLoop *sharedutil.TaskRetryLoop
+
+	httpClient *http.Client
+
+	// startTime is the time the NotificationSender was created: notification states are only kept in memory, so a sync
+	// operation that finished before then may already have been notified.
+	startTime time.Time
+
+	// states contains the notificationState of each GitOpsDeployment, by namespace/name
+	states      map[types.NamespacedName]notificationState
+	statesMutex sync.Mutex
+}
+
+// NewNotificationSender returns a NotificationSender for the namespace. The addresses that notifications may be sent to
+// are read from the NotificationAllowedAddressesEnvVar environment variable.
+func NewNotificationSender(namespaceName string, namespaceID string, log logr.Logger) *NotificationSender {
+
+	allowlist := parseNotificationAddressAllowlist(os.Getenv(sharedutil.NotificationAllowedAddressesEnvVar), log)
+
+	return newNotificationSender(sharedutil.NewTaskRetryLoop("notification-retry-loop-"+namespaceName+"-"+namespaceID),
+		newNotificationHTTPClient(allowlist))
+}
+
+func newNotificationSender(retryLoop *sharedutil.TaskRetryLoop, httpClient *http.Client) *NotificationSender {
+	return &NotificationSender{
+		retryLoop:  retryLoop,
+		httpClient: httpClient,
+		startTime:  time.Now(),
+		states:     map[types.NamespacedName]notificationState{},
+	}
+}
+
+// notificationAddressAllowlist contains the addresses that notifications may be sent to, even though they are blocked
+// by isNotificationAddressAllowed: for example, a webhook receiver that runs within the cluster.
+type notificationAddressAllowlist struct {
+	networks []*net.IPNet
+
+	// hosts contains the (lowercase) host names that are allowed, regardless of the addresses they resolve to
+	hosts map[string]bool
+}
+
+// parseNotificationAddressAllowlist parses a comma-separated list of CIDRs (for example, '10.0.0.0/8'), IP addresses
+// and host names. Invalid entries are logged, and ignored.
+func parseNotificationAddressAllowlist(value string, log logr.Logger) notificationAddressAllowlist {
+
+	res := notificationAddressAllowlist{hosts: map[string]bool{}}
+
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if strings.Contains(entry, "/") {
+			_, network, err := net.ParseCIDR(entry)
+			if err != nil {
+				log.Error(err, "ignoring invalid CIDR in env var "+sharedutil.NotificationAllowedAddressesEnvVar, "entry", entry)
+				continue
+			}
+			res.networks = append(res.networks, network)
+
+		} else if ip := net.ParseIP(entry); ip != nil {
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				bits = 8 * net.IPv4len
+			}
+			res.networks = append(res.networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+
+		} else {
+			res.hosts[strings.ToLower(strings.TrimSuffix(entry, "."))] = true
+		}
+	}
+
+	return res
+}
+
+func (allowlist notificationAddressAllowlist) containsIP(ip net.IP) bool {
+	for _, network := range allowlist.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func (allowlist notificationAddressAllowlist) containsHost(host string) bool {
+	return allowlist.hosts[strings.ToLower(strings.TrimSuffix(host, "."))]
+}
+
+// newNotificationHTTPClient returns the HTTP client that delivers notifications:
+// - Only the addresses allowed by isNotificationAddressAllowed, or by the allowlist, are dialed. The address is checked
+// after the host name of the URL has been resolved, so a host name that resolves to an address that is not allowed is
+// also rejected (unless the host name itself is in the allowlist).
+// - Redirects are not followed: the redirect response is treated as a failed delivery.
+func newNotificationHTTPClient(allowlist notificationAddressAllowlist) *http.Client {
+
+	dialer := &net.Dialer{
+		Timeout: notificationRequestTimeout,
+		Control: func(network string, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !(isNotificationAddressAllowed(ip) || allowlist.containsIP(ip)) {
+				return fmt.Errorf("%w: '%s'", errNotificationAddressNotAllowed, host)
+			}
+			return nil
+		},
+	}
+
+	// allowedHostDialer dials the host names in the allowlist, without checking the addresses they resolve to
+	allowedHostDialer := &net.Dialer{Timeout: notificationRequestTimeout}
+
+	return &http.Client{
+		Timeout: notificationRequestTimeout,
+		Transport: &http.Transport{
+			// No proxy is used, so that the address of the webhook itself is what is checked
+			Proxy: nil,
+			DialContext: func(ctx context.Context, network string, address string) (net.Conn, error) {
+				if host, _, err := net.SplitHostPort(address); err == nil && allowlist.containsHost(host) {
+					return allowedHostDialer.DialContext(ctx, network, address)
+				}
+				return dialer.DialContext(ctx, network, address)
+			},
+			TLSHandshakeTimeout: notificationRequestTimeout,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// isNotificationAddressAllowed returns false for the addresses that notifications may not be sent to: loopback,
+// link-local (which includes cloud metadata services, such as 169.254.169.254), private, and other non-public addresses.
+// Any tenant may create a GitOpsDeploymentNotificationTarget, so these would otherwise allow tenants to send requests to
+// services within the cluster and its network, unless they are in the allowlist of the operator.
+func isNotificationAddressAllowed(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsPrivate() || ip.IsUnspecified() || ip.IsMulticast() || sharedAddressSpace.Contains(ip))
+}
+
+// NotificationPayload is the JSON body POSTed to the URL of a GitOpsDeploymentNotificationTarget.
+type NotificationPayload struct {
+	// Text is a human-readable summary of the event: it is named 'text' so that the payload is accepted as-is by
+	// Slack-compatible incoming webhooks.
+	Text string `json:"text"`
+
+	Event            managedgitopsv1alpha1.NotificationEventType `json:"event"`
+	GitOpsDeployment NotificationGitOpsDeployment                `json:"gitopsDeployment"`
+	Health           managedgitopsv1alpha1.HealthStatus          `json:"health"`
+	Sync             managedgitopsv1alpha1.SyncStatus            `json:"sync"`
+
+	// Revision is the revision of the sync operation, for sync events
+	Revision string `json:"revision,omitempty"`
+
+	// Message is the message of the health status, or of the sync operation, for health and sync events respectively
+	Message string `json:"message,omitempty"`
+
+	Timestamp metav1.Time `json:"timestamp"`
+}
+
+// NotificationGitOpsDeployment identifies the GitOpsDeployment that a notification is about.
+type NotificationGitOpsDeployment struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	UID       string `json:"uid"`
+}
+
+// notificationEvent is a change to the status of a GitOpsDeployment that notifications should be sent for.
+type notificationEvent struct {
+	eventType managedgitopsv1alpha1.NotificationEventType
+	revision  string
+	message   string
+	text      string
+}
+
+// notificationState is the state of the notifications of a GitOpsDeployment, which is kept between its status updates.
+type notificationState struct {
+	// uid of the GitOpsDeployment: the state is discarded if a GitOpsDeployment of the same name is recreated
+	uid types.UID
+
+	// degraded is true if the GitOpsDeployment has been Degraded since it was last Healthy
+	degraded bool
+
+	// lastStatusUpdate is the time of the previous status update of the GitOpsDeployment
+	lastStatusUpdate time.Time
+}
+
+// getNotificationState returns the notificationState of a GitOpsDeployment. If there is none (for example, because the
+// backend has restarted since the previous status update), it is initialized from the previous status.
+func (sender *NotificationSender) getNotificationState(gitopsDeployment managedgitopsv1alpha1.GitOpsDeployment,
+	oldStatus managedgitopsv1alpha1.GitOpsDeploymentStatus) notificationState {
+
+	sender.statesMutex.Lock()
+	defer sender.statesMutex.Unlock()
+
+	state, exists := sender.states[types.NamespacedName{Namespace: gitopsDeployment.Namespace, Name: gitopsDeployment.Name}]
+	if exists && state.uid == gitopsDeployment.UID {
+		return state
+	}
+
+	state = notificationState{
+		uid:              gitopsDeployment.UID,
+		degraded:         oldStatus.Health.Status == managedgitopsv1alpha1.HeathStatusCodeDegraded,
+		lastStatusUpdate: sender.startTime,
+	}
+	if gitopsDeployment.CreationTimestamp.Time.After(state.lastStatusUpdate) {
+		state.lastStatusUpdate = gitopsDeployment.CreationTimestamp.Time
+	}
+
+	return state
+}
+
+func (sender *NotificationSender) setNotificationState(gitopsDeployment managedgitopsv1alpha1.GitOpsDeployment, state notificationState) {
+	sender.statesMutex.Lock()
+	defer sender.statesMutex.Unlock()
+
+	sender.states[types.NamespacedName{Namespace: gitopsDeployment.Namespace, Name: gitopsDeployment.Name}] = state
+}
+
+// forgetNotificationState discards the notificationState of a GitOpsDeployment that has been deleted.
+func (sender *NotificationSender) forgetNotificationState(name string, namespace string) {
+	sender.statesMutex.Lock()
+	defer sender.statesMutex.Unlock()
+
+	delete(sender.states, types.NamespacedName{Namespace: namespace, Name: name})
+}
+
+// getNotificationEvents compares the previous status of a GitOpsDeployment with its new status, and returns the
+// events for the changes that notifications should be sent for, along with the new notification state.
+// - statusUpdateTime is the time of the new status update.
+func getNotificationEvents(gitopsDeployment managedgitopsv1alpha1.GitOpsDeployment,
+	oldStatus managedgitopsv1alpha1.GitOpsDeploymentStatus, state notificationState,
+	statusUpdateTime time.Time) ([]notificationEvent, notificationState) {
+
+	var res []notificationEvent
+
+	newStatus := gitopsDeployment.Status
+	name := gitopsDeployment.Namespace + "/" + gitopsDeployment.Name
+
+	newState := state
+	newState.lastStatusUpdate = statusUpdateTime
+
+	// 1) Changes to the health of the GitOpsDeployment: a GitOpsDeployment usually recovers via Progressing, so it is
+	// recovered once it is Healthy after having been Degraded, even if it was not Degraded in the previous status.
+	if oldStatus.Health.Status != newStatus.Health.Status {
+
+		if newStatus.Health.Status == managedgitopsv1alpha1.HeathStatusCodeDegraded {
+			newState.degraded = true
+			res = append(res, notificationEvent{
+				eventType: managedgitopsv1alpha1.NotificationEvent_HealthDegraded,
+				message:   newStatus.Health.Message,
+				text:      fmt.Sprintf("GitOpsDeployment '%s' is Degraded", name),
+			})
+
+		} else if newStatus.Health.Status == managedgitopsv1alpha1.HeathStatusCodeHealthy && state.degraded {
+			newState.degraded = false
+			res = append(res, notificationEvent{
+				eventType: managedgitopsv1alpha1.NotificationEvent_HealthRecovered,
+				message:   newStatus.Health.Message,
+				text:      fmt.Sprintf("GitOpsDeployment '%s' is Healthy again", name),
+			})
+		}
+	}
+
+	// 2) Sync operations that have finished since the previous status: an operation is identified by its start time.
+	newOperation := newStatus.OperationState
+	if newOperation == nil {
+		return res, newState
+	}
+
+	oldOperation := oldStatus.OperationState
+	if oldOperation != nil && oldOperation.Phase == newOperation.Phase && oldOperation.StartedAt.Equal(&newOperation.StartedAt) {
+		return res, newState
+	}
+
+	// If the previous status had no operation, the operation may have finished long before (for example, before the
+	// OperationState was first reported), in which case it is not notified.
+	if oldOperation == nil && newOperation.FinishedAt != nil && !newOperation.FinishedAt.Time.After(state.lastStatusUpdate) {
+		return res, newState
+	}
+
+	revision := ""
+	if newOperation.SyncResult != nil {
+		revision = newOperation.SyncResult.Revision
+	}
+
+	switch newOperation.Phase {
+	case managedgitopsv1alpha1.OperationFailed, managedgitopsv1alpha1.OperationError:
+		res = append(res, notificationEvent{
+			eventType: managedgitopsv1alpha1.NotificationEvent_SyncFailed,
+			revision:  revision,
+			message:   newOperation.Message,
+			text:      fmt.Sprintf("Sync of GitOpsDeployment '%s' failed", name),
+		})
+
+	case managedgitopsv1alpha1.OperationSucceeded:
+		res = append(res, notificationEvent{
+			eventType: managedgitopsv1alpha1.NotificationEvent_SyncSucceeded,
+			revision:  revision,
+			message:   newOperation.Message,
+			text:      fmt.Sprintf("Sync of GitOpsDeployment '%s' succeeded", name),
+		})
+	}
+
+	return res, newState
+}
+
+// sendNotificationsOfStatusChanges queues a notification to each GitOpsDeploymentNotificationTarget in the namespace of
+// the GitOpsDeployment that matches a change between the old and new status of the GitOpsDeployment.
+// - Notifications are delivered asynchronously, and failures are only logged: they should never prevent the
+// status of the GitOpsDeployment from being updated.
+// - No notifications are sent by an action without a NotificationSender (such as the actions of unit tests).
+func (a *applicationEventLoopRunner_Action) sendNotificationsOfStatusChanges(ctx context.Context,
+	gitopsDeployment managedgitopsv1alpha1.GitOpsDeployment, oldStatus managedgitopsv1alpha1.GitOpsDeploymentStatus) {
+
+	sender := a.notificationSender
+	if sender == nil {
+		return
+	}
+
+	notificationEvents, state := getNotificationEvents(gitopsDeployment, oldStatus,
+		sender.getNotificationState(gitopsDeployment, oldStatus), time.Now())
+	sender.setNotificationState(gitopsDeployment, state)
+
+	if len(notificationEvents) == 0 {
+		return
+	}
+
+	var targetList managedgitopsv1alpha1.GitOpsDeploymentNotificationTargetList
+	if err := a.workspaceClient.List(ctx, &targetList, &client.ListOptions{Namespace: gitopsDeployment.Namespace}); err != nil {
+		a.log.Error(err, "unable to list GitOpsDeploymentNotificationTargets")
+		return
+	}
+
+	for _, target := range targetList.Items {
+
+		var hmacKey []byte
+
+		for _, event := range notificationEvents {
+
+			if !target.Spec.Matches(gitopsDeployment.Name, event.eventType) {
+				continue
+			}
+
+			log := a.log.WithValues("notificationTarget", target.Name, "event", event.eventType)
+
+			if target.Spec.HMACSecretRef != nil && hmacKey == nil {
+				var err error
+				if hmacKey, err = getNotificationHMACKey(ctx, target, a.workspaceClient); err != nil {
+					log.Error(err, "unable to retrieve the HMAC secret of GitOpsDeploymentNotificationTarget")
+					break
+				}
+			}
+
+			task, err := newNotificationTask(target, gitopsDeployment, event, hmacKey, sender.httpClient, log)
+			if err != nil {
+				log.Error(err, "unable to create notification")
+				continue
+			}
+
+			sender.retryLoop.AddTaskIfNotPresent("notification-"+task.deliveryID, task,
+				sharedutil.ExponentialBackoff{Factor: 2, Min: time.Second * 1, Max: time.Second * 30, Jitter: true})
+		}
+	}
+}
+
+// getNotificationHMACKey returns the value of the key of the Secret referenced by the HMACSecretRef of the target.
+func getNotificationHMACKey(ctx context.Context, target managedgitopsv1alpha1.GitOpsDeploymentNotificationTarget,
+	k8sClient client.Client) ([]byte, error) {
+
+	secretRef := target.Spec.HMACSecretRef
+
+	secret := corev1.Secret{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: target.Namespace, Name: secretRef.Name}, &secret); err != nil {
+		return nil, fmt.Errorf("unable to retrieve Secret '%s': %v", secretRef.Name, err)
+	}
+
+	hmacKey, exists := secret.Data[secretRef.Key]
+	if !exists || len(hmacKey) == 0 {
+		return nil, fmt.Errorf("key '%s' of Secret '%s' is missing or empty", secretRef.Key, secretRef.Name)
+	}
+
+	return hmacKey, nil
+}
+
+// notificationTask delivers a single notification to a GitOpsDeploymentNotificationTarget, within the notification retry loop.
+type notificationTask struct {
+	url        string
+	eventType  managedgitopsv1alpha1.NotificationEventType
+	deliveryID string
+	body       []byte
+
+	// signature is the HMAC signature of the body, or empty if the target does not sign its notifications
+	signature string
+
+	// attempts is the number of delivery attempts so far
+	attempts int
+
+	httpClient *http.Client
+
+	log logr.Logger
+}
+
+func newNotificationTask(target managedgitopsv1alpha1.GitOpsDeploymentNotificationTarget, gitopsDeployment managedgitopsv1alpha1.GitOpsDeployment,
+	event notificationEvent, hmacKey []byte, httpClient *http.Client, log logr.Logger) (*notificationTask, error) {
+
+	payload := NotificationPayload{
+		Text:  event.text,
+		Event: event.eventType,
+		GitOpsDeployment: NotificationGitOpsDeployment{
+			Name:      gitopsDeployment.Name,
+			Namespace: gitopsDeployment.Namespace,
+			UID:       string(gitopsDeployment.UID),
+		},
+		Health:    gitopsDeployment.Status.Health,
+		Sync:      gitopsDeployment.Status.Sync,
+		Revision:  event.revision,
+		Message:   event.message,
+		Timestamp: metav1.Now(),
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	task := &notificationTask{
+		url:        target.Spec.URL,
+		eventType:  event.eventType,
+		deliveryID: uuid.New().String(),
+		body:       body,
+		httpClient: httpClient,
+		log:        log,
+	}
+
+	if len(hmacKey) > 0 {
+		task.signature = signNotificationPayload(body, hmacKey)
+	}
+
+	return task, nil
+}
+
+// signNotificationPayload returns the value of the signature header for the given body.
+func signNotificationPayload(body []byte, hmacKey []byte) string {
+	mac := hmac.New(sha256.New, hmacKey)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (task *notificationTask) PerformTask(taskContext context.Context) (bool, error) {
+
+	task.attempts++
+
+	retry, err := task.deliver(taskContext)
+	if err == nil {
+		task.log.Info("Delivered notification", "deliveryID", task.deliveryID)
+		return false, nil
+	}
+
+	if retry && task.attempts < maxNotificationAttempts {
+		return true, fmt.Errorf("unable to deliver notification '%s', attempt %d: %v", task.deliveryID, task.attempts, err)
+	}
+
+	// The notification is discarded
+	task.log.Error(err, "unable to deliver notification, giving up", "deliveryID", task.deliveryID, "attempts", task.attempts)
+	return false, nil
+}
+
+// deliver POSTs the notification to the URL of the target, and returns an error if it was not accepted, along with
+// whether a later attempt may succeed.
+func (task *notificationTask) deliver(ctx context.Context) (bool, error) {
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, task.url, bytes.NewReader(task.body))
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(NotificationHeader_Event, string(task.eventType))
+	req.Header.Set(NotificationHeader_Delivery, task.deliveryID)
+	if task.signature != "" {
+		req.Header.Set(NotificationHeader_Signature, task.signature)
+	}
+
+	resp, err := task.httpClient.Do(req)
+	if err != nil {
+		// The address of the webhook will not change by retrying the same request
+		return !errors.Is(err, errNotificationAddressNotAllowed), err
+	}
+	defer resp.Body.Close()
+
+	// Drain the body, so that the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	err = fmt.Errorf("webhook responded with status code %d", resp.StatusCode)
+	if resp.StatusCode >= 300 && resp.StatusCode < 400 {
+		err = fmt.Errorf("webhook responded with a redirect (status code %d), which is not followed", resp.StatusCode)
+	}
+
+	// Other client errors will not be resolved by retrying the same request
+	retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests
+
+	return retry, err
+}
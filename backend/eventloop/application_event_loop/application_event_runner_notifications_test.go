@@ -0,0 +1,440 @@
+package application_event_loop
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
+	sharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/tests"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("Application Event Runner Notifications", func() {
+
+	var sender *NotificationSender
+
+	BeforeEach(func() {
+		// The webhooks of these tests are httptest servers, which listen on a loopback address
+		allowlist := parseNotificationAddressAllowlist("127.0.0.0/8,::1", log.FromContext(context.Background()))
+		sender = newNotificationSender(sharedutil.NewTaskRetryLoop("test-notification-retry-loop"), newNotificationHTTPClient(allowlist))
+	})
+
+	gitopsDepl := managedgitopsv1alpha1.GitOpsDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-gitops-depl",
+			Namespace: "my-namespace",
+			UID:       uuid.NewUUID(),
+		},
+	}
+
+	withStatus := func(health managedgitopsv1alpha1.HealthStatusCode, operationState *managedgitopsv1alpha1.OperationState) managedgitopsv1alpha1.GitOpsDeployment {
+		res := *gitopsDepl.DeepCopy()
+		res.Status.Health.Status = health
+		res.Status.OperationState = operationState
+		return res
+	}
+
+	startedAt := metav1.NewTime(time.Date(2024, time.January, 1, 9, 30, 0, 0, time.UTC))
+
+	operation := func(phase managedgitopsv1alpha1.OperationPhase, startedAt metav1.Time) *managedgitopsv1alpha1.OperationState {
+		return &managedgitopsv1alpha1.OperationState{
+			Phase:      phase,
+			Message:    "sync message",
+			StartedAt:  startedAt,
+			SyncResult: &managedgitopsv1alpha1.SyncOperationResult{Revision: "0a1b2c3d"},
+		}
+	}
+
+	finishedOperation := func(phase managedgitopsv1alpha1.OperationPhase, finishedAt time.Time) *managedgitopsv1alpha1.OperationState {
+		res := operation(phase, metav1.NewTime(finishedAt.Add(-time.Minute)))
+		res.FinishedAt = &metav1.Time{Time: finishedAt}
+		return res
+	}
+
+	Context("getNotificationEvents", func() {
+
+		DescribeTable("should return the events for the changes between the old and new status",
+			func(oldDepl managedgitopsv1alpha1.GitOpsDeployment, newDepl managedgitopsv1alpha1.GitOpsDeployment, expected []managedgitopsv1alpha1.NotificationEventType) {
+
+				var actual []managedgitopsv1alpha1.NotificationEventType
+				events, _ := getNotificationEvents(newDepl, oldDepl.Status, sender.getNotificationState(newDepl, oldDepl.Status), time.Now())
+				for _, event := range events {
+					actual = append(actual, event.eventType)
+				}
+				Expect(actual).To(Equal(expected))
+			},
+			Entry("no change", withStatus(managedgitopsv1alpha1.HeathStatusCodeHealthy, nil),
+				withStatus(managedgitopsv1alpha1.HeathStatusCodeHealthy, nil), nil),
+			Entry("becomes Degraded", withStatus(managedgitopsv1alpha1.HeathStatusCodeHealthy, nil),
+				withStatus(managedgitopsv1alpha1.HeathStatusCodeDegraded, nil),
+				[]managedgitopsv1alpha1.NotificationEventType{managedgitopsv1alpha1.NotificationEvent_HealthDegraded}),
+			Entry("recovers from Degraded", withStatus(managedgitopsv1alpha1.HeathStatusCodeDegraded, nil),
+				withStatus(managedgitopsv1alpha1.HeathStatusCodeHealthy, nil),
+				[]managedgitopsv1alpha1.NotificationEventType{managedgitopsv1alpha1.NotificationEvent_HealthRecovered}),
+			Entry("becomes Healthy, without having been Degraded", withStatus(managedgitopsv1alpha1.HeathStatusCodeProgressing, nil),
+				withStatus(managedgitopsv1alpha1.HeathStatusCodeHealthy, nil), nil),
+			Entry("sync is still running", withStatus(managedgitopsv1alpha1.HeathStatusCodeHealthy, nil),
+				withStatus(managedgitopsv1alpha1.HeathStatusCodeHealthy, operation(managedgitopsv1alpha1.OperationRunning, startedAt)), nil),
+			Entry("sync has failed", withStatus(managedgitopsv1alpha1.HeathStatusCodeHealthy, operation(managedgitopsv1alpha1.OperationRunning, startedAt)),
+				withStatus(managedgitopsv1alpha1.HeathStatusCodeDegraded, operation(managedgitopsv1alpha1.OperationFailed, startedAt)),
+				[]managedgitopsv1alpha1.NotificationEventType{managedgitopsv1alpha1.NotificationEvent_HealthDegraded, managedgitopsv1alpha1.NotificationEvent_SyncFailed}),
+			Entry("sync has already been reported as succeeded", withStatus(managedgitopsv1alpha1.HeathStatusCodeHealthy, operation(managedgitopsv1alpha1.OperationSucceeded, startedAt)),
+				withStatus(managedgitopsv1alpha1.HeathStatusCodeHealthy, operation(managedgitopsv1alpha1.OperationSucceeded, startedAt)), nil),
+			Entry("a new sync has succeeded", withStatus(managedgitopsv1alpha1.HeathStatusCodeHealthy, operation(managedgitopsv1alpha1.OperationSucceeded, startedAt)),
+				withStatus(managedgitopsv1alpha1.HeathStatusCodeHealthy, operation(managedgitopsv1alpha1.OperationSucceeded, metav1.NewTime(startedAt.Add(time.Hour)))),
+				[]managedgitopsv1alpha1.NotificationEventType{managedgitopsv1alpha1.NotificationEvent_SyncSucceeded}),
+			Entry("a sync that finished before the previous status is reported for the first time", withStatus(managedgitopsv1alpha1.HeathStatusCodeHealthy, nil),
+				withStatus(managedgitopsv1alpha1.HeathStatusCodeHealthy, finishedOperation(managedgitopsv1alpha1.OperationSucceeded, time.Now().Add(-time.Hour))), nil),
+			Entry("a sync that finished after the previous status is reported for the first time", withStatus(managedgitopsv1alpha1.HeathStatusCodeHealthy, nil),
+				withStatus(managedgitopsv1alpha1.HeathStatusCodeHealthy, finishedOperation(managedgitopsv1alpha1.OperationFailed, time.Now().Add(time.Minute))),
+				[]managedgitopsv1alpha1.NotificationEventType{managedgitopsv1alpha1.NotificationEvent_SyncFailed}),
+		)
+
+		It("should report a GitOpsDeployment that recovers from Degraded via Progressing as recovered, once", func() {
+
+			statuses := []managedgitopsv1alpha1.HealthStatusCode{
+				managedgitopsv1alpha1.HeathStatusCodeHealthy,
+				managedgitopsv1alpha1.HeathStatusCodeDegraded,
+				managedgitopsv1alpha1.HeathStatusCodeProgressing,
+				managedgitopsv1alpha1.HeathStatusCodeHealthy,
+				managedgitopsv1alpha1.HeathStatusCodeProgressing,
+				managedgitopsv1alpha1.HeathStatusCodeHealthy,
+			}
+
+			var actual []managedgitopsv1alpha1.NotificationEventType
+
+			oldDepl := withStatus(statuses[0], nil)
+			state := sender.getNotificationState(oldDepl, oldDepl.Status)
+
+			for _, health := range statuses[1:] {
+				newDepl := withStatus(health, nil)
+
+				var events []notificationEvent
+				events, state = getNotificationEvents(newDepl, oldDepl.Status, state, time.Now())
+				for _, event := range events {
+					actual = append(actual, event.eventType)
+				}
+				oldDepl = newDepl
+			}
+
+			Expect(actual).To(Equal([]managedgitopsv1alpha1.NotificationEventType{
+				managedgitopsv1alpha1.NotificationEvent_HealthDegraded, managedgitopsv1alpha1.NotificationEvent_HealthRecovered}))
+		})
+
+		It("should keep the notification state of a GitOpsDeployment between status updates, until it is deleted", func() {
+
+			oldDepl := withStatus(managedgitopsv1alpha1.HeathStatusCodeProgressing, nil)
+			Expect(sender.getNotificationState(oldDepl, oldDepl.Status).degraded).To(BeFalse())
+
+			sender.setNotificationState(oldDepl, notificationState{uid: oldDepl.UID, degraded: true})
+			Expect(sender.getNotificationState(oldDepl, oldDepl.Status).degraded).To(BeTrue())
+
+			By("discarding the state of a previous GitOpsDeployment of the same name")
+			recreatedDepl := *oldDepl.DeepCopy()
+			recreatedDepl.UID = uuid.NewUUID()
+			Expect(sender.getNotificationState(recreatedDepl, recreatedDepl.Status).degraded).To(BeFalse())
+
+			sender.forgetNotificationState(oldDepl.Name, oldDepl.Namespace)
+			Expect(sender.getNotificationState(oldDepl, oldDepl.Status).degraded).To(BeFalse())
+		})
+	})
+
+	Context("notificationTask", func() {
+
+		It("should retry failed deliveries, until the webhook accepts the notification", func() {
+
+			var requests []*http.Request
+			var bodies [][]byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				requests = append(requests, r)
+				bodies = append(bodies, body)
+				if len(requests) == 1 {
+					w.WriteHeader(http.StatusServiceUnavailable)
+				}
+			}))
+			defer server.Close()
+
+			target := managedgitopsv1alpha1.GitOpsDeploymentNotificationTarget{
+				Spec: managedgitopsv1alpha1.GitOpsDeploymentNotificationTargetSpec{URL: server.URL},
+			}
+			degradedDepl := withStatus(managedgitopsv1alpha1.HeathStatusCodeDegraded, nil)
+			events, _ := getNotificationEvents(degradedDepl, managedgitopsv1alpha1.GitOpsDeploymentStatus{},
+				sender.getNotificationState(degradedDepl, managedgitopsv1alpha1.GitOpsDeploymentStatus{}), time.Now())
+			event := events[0]
+
+			task, err := newNotificationTask(target, withStatus(managedgitopsv1alpha1.HeathStatusCodeDegraded, nil), event, []byte("my-hmac-key"), sender.httpClient, log.FromContext(context.Background()))
+			Expect(err).ToNot(HaveOccurred())
+
+			retry, err := task.PerformTask(context.Background())
+			Expect(retry).To(BeTrue())
+			Expect(err).To(HaveOccurred())
+
+			retry, err = task.PerformTask(context.Background())
+			Expect(retry).To(BeFalse())
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(requests).To(HaveLen(2))
+
+			By("sending the same delivery ID and signature on each attempt")
+			Expect(requests[0].Header.Get(NotificationHeader_Delivery)).To(Equal(requests[1].Header.Get(NotificationHeader_Delivery)))
+			Expect(requests[1].Header.Get(NotificationHeader_Event)).To(Equal(string(managedgitopsv1alpha1.NotificationEvent_HealthDegraded)))
+			Expect(requests[1].Header.Get(NotificationHeader_Signature)).To(Equal(signNotificationPayload(bodies[1], []byte("my-hmac-key"))))
+
+			var payload NotificationPayload
+			Expect(json.Unmarshal(bodies[1], &payload)).To(Succeed())
+			Expect(payload.Event).To(Equal(managedgitopsv1alpha1.NotificationEvent_HealthDegraded))
+			Expect(payload.GitOpsDeployment.Name).To(Equal(gitopsDepl.Name))
+			Expect(payload.GitOpsDeployment.UID).To(Equal(string(gitopsDepl.UID)))
+			Expect(payload.Health.Status).To(Equal(managedgitopsv1alpha1.HeathStatusCodeDegraded))
+			Expect(payload.Text).To(ContainSubstring("is Degraded"))
+		})
+
+		It("should give up on client errors, and after the maximum number of attempts", func() {
+
+			statusCode := http.StatusBadRequest
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(statusCode)
+			}))
+			defer server.Close()
+
+			target := managedgitopsv1alpha1.GitOpsDeploymentNotificationTarget{
+				Spec: managedgitopsv1alpha1.GitOpsDeploymentNotificationTargetSpec{URL: server.URL},
+			}
+			event := notificationEvent{eventType: managedgitopsv1alpha1.NotificationEvent_SyncFailed}
+
+			task, err := newNotificationTask(target, gitopsDepl, event, nil, sender.httpClient, log.FromContext(context.Background()))
+			Expect(err).ToNot(HaveOccurred())
+
+			retry, err := task.PerformTask(context.Background())
+			Expect(retry).To(BeFalse())
+			Expect(err).ToNot(HaveOccurred())
+
+			statusCode = http.StatusInternalServerError
+			task, err = newNotificationTask(target, gitopsDepl, event, nil, sender.httpClient, log.FromContext(context.Background()))
+			Expect(err).ToNot(HaveOccurred())
+
+			for i := 1; i < maxNotificationAttempts; i++ {
+				retry, _ = task.PerformTask(context.Background())
+				Expect(retry).To(BeTrue())
+			}
+			retry, _ = task.PerformTask(context.Background())
+			Expect(retry).To(BeFalse())
+		})
+
+		It("should not follow redirects", func() {
+
+			redirectedRequests := 0
+			redirectTarget := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				redirectedRequests++
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer redirectTarget.Close()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, redirectTarget.URL, http.StatusTemporaryRedirect)
+			}))
+			defer server.Close()
+
+			target := managedgitopsv1alpha1.GitOpsDeploymentNotificationTarget{
+				Spec: managedgitopsv1alpha1.GitOpsDeploymentNotificationTargetSpec{URL: server.URL},
+			}
+			event := notificationEvent{eventType: managedgitopsv1alpha1.NotificationEvent_SyncFailed}
+
+			task, err := newNotificationTask(target, gitopsDepl, event, nil, sender.httpClient, log.FromContext(context.Background()))
+			Expect(err).ToNot(HaveOccurred())
+
+			retry, err := task.deliver(context.Background())
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("redirect"))
+			Expect(retry).To(BeFalse())
+			Expect(redirectedRequests).To(BeZero())
+		})
+
+		It("should not deliver notifications to loopback addresses, and should not retry them", func() {
+
+			defaultHTTPClient := newNotificationHTTPClient(parseNotificationAddressAllowlist("", log.FromContext(context.Background())))
+
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			_, port, err := net.SplitHostPort(server.Listener.Addr().String())
+			Expect(err).ToNot(HaveOccurred())
+
+			event := notificationEvent{eventType: managedgitopsv1alpha1.NotificationEvent_SyncFailed}
+
+			// Both an IP address, and a host name which resolves to a loopback address, are rejected
+			for _, url := range []string{server.URL, "http://localhost:" + port} {
+
+				target := managedgitopsv1alpha1.GitOpsDeploymentNotificationTarget{
+					Spec: managedgitopsv1alpha1.GitOpsDeploymentNotificationTargetSpec{URL: url},
+				}
+
+				task, err := newNotificationTask(target, gitopsDepl, event, nil, defaultHTTPClient, log.FromContext(context.Background()))
+				Expect(err).ToNot(HaveOccurred())
+
+				retry, err := task.deliver(context.Background())
+				Expect(err).To(MatchError(errNotificationAddressNotAllowed))
+				Expect(retry).To(BeFalse())
+			}
+
+			Expect(requests).To(BeZero())
+		})
+
+		It("should deliver notifications to the addresses and host names in the allowlist", func() {
+
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			host, port, err := net.SplitHostPort(server.Listener.Addr().String())
+			Expect(err).ToNot(HaveOccurred())
+
+			event := notificationEvent{eventType: managedgitopsv1alpha1.NotificationEvent_SyncFailed}
+
+			for allowlist, url := range map[string]string{
+				host:        server.URL,
+				"LocalHost": "http://localhost:" + port,
+			} {
+				httpClient := newNotificationHTTPClient(parseNotificationAddressAllowlist(allowlist, log.FromContext(context.Background())))
+
+				target := managedgitopsv1alpha1.GitOpsDeploymentNotificationTarget{
+					Spec: managedgitopsv1alpha1.GitOpsDeploymentNotificationTargetSpec{URL: url},
+				}
+
+				task, err := newNotificationTask(target, gitopsDepl, event, nil, httpClient, log.FromContext(context.Background()))
+				Expect(err).ToNot(HaveOccurred())
+
+				_, err = task.deliver(context.Background())
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			Expect(requests).To(Equal(2))
+		})
+	})
+
+	DescribeTable("isNotificationAddressAllowed",
+		func(address string, expected bool) {
+			Expect(isNotificationAddressAllowed(net.ParseIP(address))).To(Equal(expected))
+		},
+		Entry("IPv4 loopback", "127.0.0.1", false),
+		Entry("IPv6 loopback", "::1", false),
+		Entry("link-local, such as a cloud metadata service", "169.254.169.254", false),
+		Entry("IPv6 link-local", "fe80::1", false),
+		Entry("private, 10.0.0.0/8", "10.96.0.1", false),
+		Entry("private, 172.16.0.0/12", "172.16.0.1", false),
+		Entry("private, 192.168.0.0/16", "192.168.1.1", false),
+		Entry("IPv6 unique local", "fd00::1", false),
+		Entry("shared address space", "100.64.0.1", false),
+		Entry("unspecified", "0.0.0.0", false),
+		Entry("multicast", "224.0.0.1", false),
+		Entry("public IPv4", "203.0.114.10", true),
+		Entry("public IPv6", "2606:4700::1111", true),
+	)
+
+	It("should parse the notification address allowlist, ignoring invalid entries", func() {
+
+		allowlist := parseNotificationAddressAllowlist(" 10.0.0.0/8, 192.168.1.1 ,fd00::/8, not/a/cidr, Webhook.My-Namespace.svc. ,,",
+			log.FromContext(context.Background()))
+
+		Expect(allowlist.networks).To(HaveLen(3))
+		Expect(allowlist.containsIP(net.ParseIP("10.96.0.1"))).To(BeTrue())
+		Expect(allowlist.containsIP(net.ParseIP("192.168.1.1"))).To(BeTrue())
+		Expect(allowlist.containsIP(net.ParseIP("192.168.1.2"))).To(BeFalse())
+		Expect(allowlist.containsIP(net.ParseIP("fd00::1"))).To(BeTrue())
+		Expect(allowlist.containsIP(net.ParseIP("127.0.0.1"))).To(BeFalse())
+
+		Expect(allowlist.containsHost("webhook.my-namespace.svc")).To(BeTrue())
+		Expect(allowlist.containsHost("other.my-namespace.svc")).To(BeFalse())
+	})
+
+	Context("sendNotificationsOfStatusChanges", func() {
+
+		It("should only send notifications to the targets that match the GitOpsDeployment and event", func() {
+
+			scheme, _, _, _, err := tests.GenericTestSetup()
+			Expect(err).ToNot(HaveOccurred())
+
+			var mutex sync.Mutex
+			receivedEvents := map[string][]string{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mutex.Lock()
+				defer mutex.Unlock()
+				receivedEvents[r.URL.Path] = append(receivedEvents[r.URL.Path], r.Header.Get(NotificationHeader_Event)+" "+r.Header.Get(NotificationHeader_Signature))
+			}))
+			defer server.Close()
+
+			newTarget := func(name string, spec managedgitopsv1alpha1.GitOpsDeploymentNotificationTargetSpec) *managedgitopsv1alpha1.GitOpsDeploymentNotificationTarget {
+				spec.URL = server.URL + "/" + name
+				return &managedgitopsv1alpha1.GitOpsDeploymentNotificationTarget{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: gitopsDepl.Namespace},
+					Spec:       spec,
+				}
+			}
+
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "my-secret", Namespace: gitopsDepl.Namespace},
+				Data:       map[string][]byte{"key": []byte("my-hmac-key")},
+			}
+
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				secret,
+				newTarget("all-events", managedgitopsv1alpha1.GitOpsDeploymentNotificationTargetSpec{
+					HMACSecretRef: &managedgitopsv1alpha1.SecretKeyReference{Name: "my-secret", Key: "key"},
+				}),
+				newTarget("sync-failed", managedgitopsv1alpha1.GitOpsDeploymentNotificationTargetSpec{
+					Events: []managedgitopsv1alpha1.NotificationEventType{managedgitopsv1alpha1.NotificationEvent_SyncFailed},
+				}),
+				newTarget("other-gitopsdepl", managedgitopsv1alpha1.GitOpsDeploymentNotificationTargetSpec{
+					GitOpsDeploymentNames: []string{"another-gitops-depl"},
+				}),
+			).Build()
+
+			a := applicationEventLoopRunner_Action{
+				workspaceClient:    k8sClient,
+				log:                log.FromContext(context.Background()),
+				notificationSender: sender,
+			}
+
+			a.sendNotificationsOfStatusChanges(context.Background(), withStatus(managedgitopsv1alpha1.HeathStatusCodeDegraded, nil),
+				withStatus(managedgitopsv1alpha1.HeathStatusCodeHealthy, nil).Status)
+
+			getReceivedEvents := func() map[string][]string {
+				mutex.Lock()
+				defer mutex.Unlock()
+				res := map[string][]string{}
+				for k, v := range receivedEvents {
+					res[k] = append([]string{}, v...)
+				}
+				return res
+			}
+
+			Eventually(getReceivedEvents, "10s", "100ms").Should(HaveKey("/all-events"))
+			Consistently(getReceivedEvents, "1s", "100ms").Should(HaveLen(1))
+
+			received := getReceivedEvents()["/all-events"]
+			Expect(received).To(HaveLen(1))
+			Expect(received[0]).To(HavePrefix(string(managedgitopsv1alpha1.NotificationEvent_HealthDegraded) + " sha256="))
+		})
+	})
+})
@@ -2,18 +2,24 @@ package application_event_loop
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
 	"time"
 
+	"github.com/go-logr/logr"
 	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
 	db "github.com/redhat-appstudio/managed-gitops/backend-shared/db"
 	dbutil "github.com/redhat-appstudio/managed-gitops/backend-shared/db/util"
 	sharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/fauxargocd"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/gitopserrors"
 	logutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/log"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/operations"
 	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/eventlooptypes"
+	"github.com/redhat-appstudio/managed-gitops/backend/events"
+	goyaml "gopkg.in/yaml.v2"
 	corev1 "k8s.io/api/core/v1"
 	apierr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -25,6 +31,8 @@ const (
 	ErrDeploymentNameIsImmutable = "deployment name field is immutable: changing it from its initial value is not supported"
 
 	ErrRevisionIsImmutable = "revision change is not supported: changing it from its initial value is not supported"
+
+	ErrSyncOptionsAreImmutable = "sync options are immutable: changing prune, dryRun, force, syncStrategy or resources from their initial values is not supported"
 )
 
 // This file is responsible for processing events related to GitOpsDeploymentSyncRun CR.
@@ -45,6 +53,22 @@ func (action *applicationEventLoopRunner_Action) applicationEventRunner_handleSy
 	conditionType := managedgitopsv1alpha1.GitOpsDeploymentSyncRunConditionErrorOccurred
 	if err != nil {
 
+		// A sync that is blocked by the sync windows of the GitOpsDeployment is reported to the user, but is not retried.
+		// Likewise for a sync that is held waiting on the dependencies of the GitOpsDeployment: it is synced by the
+		// deployment status tick once the dependencies are ready.
+		if conditionErr, ok := err.(gitopserrors.ConditionError); ok &&
+			(conditionErr.ConditionReason() == string(managedgitopsv1alpha1.SyncRunReasonSyncWindowClosed) ||
+				conditionErr.ConditionReason() == string(managedgitopsv1alpha1.SyncRunReasonWaitingOnDependencies)) {
+
+			reason := managedgitopsv1alpha1.SyncRunReasonType(conditionErr.ConditionReason())
+			if err := setGitOpsDeploymentSyncRunCondition(ctx, action.workspaceClient, syncRunCR, conditionType, reason, managedgitopsv1alpha1.GitOpsConditionStatusTrue, err.UserError()); err != nil {
+				return fmt.Errorf("failed to update the status of GitOpsDeploymentSyncRun: %v", err)
+			}
+			action.eventRecorder.RecordWarning(syncRunCR, string(reason), err.UserError())
+
+			return nil
+		}
+
 		errMsg := err.UserError()
 		if errMsg == "" {
 			errMsg = gitopserrors.UnknownError
@@ -53,6 +77,7 @@ func (action *applicationEventLoopRunner_Action) applicationEventRunner_handleSy
 		if err := setGitOpsDeploymentSyncRunCondition(ctx, action.workspaceClient, syncRunCR, conditionType, managedgitopsv1alpha1.SyncRunReasonType(conditionType), managedgitopsv1alpha1.GitOpsConditionStatusTrue, errMsg); err != nil {
 			return fmt.Errorf("failed to update the status of GitOpsDeploymentSyncRun: %v", err)
 		}
+		action.eventRecorder.RecordWarning(syncRunCR, string(conditionType), errMsg)
 
 		return err.DevError()
 	}
@@ -108,6 +133,21 @@ func findConditionIndex(conditions []managedgitopsv1alpha1.GitOpsDeploymentSyncR
 	return -1
 }
 
+// isSyncRunWaitingOnDependencies returns true if the sync of the GitOpsDeploymentSyncRun is held, waiting on the
+// dependencies of its GitOpsDeployment.
+func isSyncRunWaitingOnDependencies(syncRun managedgitopsv1alpha1.GitOpsDeploymentSyncRun) bool {
+
+	conditionIndex := findConditionIndex(syncRun.Status.Conditions, managedgitopsv1alpha1.GitOpsDeploymentSyncRunConditionErrorOccurred)
+	if conditionIndex == -1 {
+		return false
+	}
+
+	condition := syncRun.Status.Conditions[conditionIndex]
+
+	return condition.Status == managedgitopsv1alpha1.GitOpsConditionStatusTrue &&
+		condition.Reason == managedgitopsv1alpha1.SyncRunReasonWaitingOnDependencies
+}
+
 func getGitOpsDeploymentSyncRun(ctx context.Context, k8sClient client.Client, name, namespace string) (*managedgitopsv1alpha1.GitOpsDeploymentSyncRun, error) {
 	syncRunCR := &managedgitopsv1alpha1.GitOpsDeploymentSyncRun{
 		ObjectMeta: metav1.ObjectMeta{
@@ -289,7 +329,8 @@ func (a *applicationEventLoopRunner_Action) applicationEventRunner_handleSyncRun
 		}
 
 		// return an error if 'Automated' sync policy is enabled. Argo CD doesn't allow syncing an Application with automated sync policy.
-		if gitopsDepl.Spec.Type != managedgitopsv1alpha1.GitOpsDeploymentSpecType_Manual {
+		// - A rollback is the exception: automated sync is disabled for as long as the rollback is in effect.
+		if gitopsDepl.Spec.Type != managedgitopsv1alpha1.GitOpsDeploymentSpecType_Manual && !syncRunCR.Spec.IsRollback() {
 			userErr := fmt.Sprintf("invalid GitOpsDeploymentSyncRun '%s'. Syncing a GitOpsDeployment with Automated sync policy is not allowed", syncRunCR.Name)
 			devErr := errors.New(userErr)
 			log.Error(devErr, "failed to process GitOpsDeploymentSyncRun")
@@ -328,7 +369,50 @@ func (a *applicationEventLoopRunner_Action) applicationEventRunner_handleSyncRun
 			// have seen the GitOpsDeplSyncRun CR.
 			// Create it in the DB and create the operation.
 
-			return a.handleNewGitOpsDeplSyncRunEvent(ctx, syncRunCR, dbQueries, application, gitopsEngineInstance, namespace, *clusterUser)
+			// Don't create the SyncOperation if the sync windows of the GitOpsDeployment do not currently allow a sync
+			allowed, message, err := syncWindowsAllowSync(gitopsDepl, time.Now())
+			if err != nil {
+				userErr := fmt.Sprintf("invalid sync window in .spec.syncPolicy.syncWindows of GitOpsDeployment '%s': %v", gitopsDepl.Name, err)
+				return gitopserrors.NewUserDevError(userErr, err)
+			}
+			if !allowed {
+				devErr := fmt.Errorf("sync of GitOpsDeploymentSyncRun '%s' is blocked by sync windows: %s", syncRunCR.Name, message)
+				return gitopserrors.NewUserConditionError(message, devErr, string(managedgitopsv1alpha1.SyncRunReasonSyncWindowClosed))
+			}
+
+			// Don't create the SyncOperation until the dependencies of the GitOpsDeployment are ready
+			unreadyDependencies, err := getUnreadyDependenciesOfGitOpsDeployment(ctx, a.workspaceClient, *gitopsDepl)
+			if err != nil {
+				return gitopserrors.NewDevOnlyError(err)
+			}
+			if len(unreadyDependencies) > 0 {
+				message := newWaitingOnDependenciesCondition(unreadyDependencies).Message
+				devErr := fmt.Errorf("sync of GitOpsDeploymentSyncRun '%s' is held waiting on dependencies: %s", syncRunCR.Name, message)
+				return gitopserrors.NewUserConditionError(message, devErr, string(managedgitopsv1alpha1.SyncRunReasonWaitingOnDependencies))
+			}
+
+			revision := syncRunCR.Spec.GetRevision()
+
+			if syncRunCR.Spec.IsRollback() {
+				rollbackToHistory, userErr := a.prepareRollbackOfGitOpsDeployment(ctx, syncRunCR, *gitopsDepl, *application, dbQueries)
+				if userErr != nil {
+					return userErr
+				}
+				if rollbackToHistory == nil {
+					// The deployment runner will queue an event for the GitOpsDeploymentSyncRun, once automated sync is disabled
+					return nil
+				}
+
+				// A rollback to a deployment history ID syncs the revision of that entry
+				revision = rollbackToHistory.Revision
+			}
+
+			var syncWindows managedgitopsv1alpha1.SyncWindows
+			if gitopsDepl.Spec.SyncPolicy != nil {
+				syncWindows = gitopsDepl.Spec.SyncPolicy.SyncWindows
+			}
+
+			return a.handleNewGitOpsDeplSyncRunEvent(ctx, syncRunCR, revision, dbQueries, application, gitopsEngineInstance, namespace, *clusterUser, syncWindows)
 		}
 
 	}
@@ -472,6 +556,12 @@ func (a *applicationEventLoopRunner_Action) handleDeletedGitOpsDeplSyncRunEvent(
 		return gitopserrors.NewDevOnlyError(allErrors)
 	}
 
+	// 4) If the GitOpsDeploymentSyncRun was a rollback, the GitOpsDeployment can now return to its own source(s) and sync policy
+	if err := a.restoreGitOpsDeploymentAfterRollback(ctx, syncOperation.DeploymentNameField); err != nil {
+		log.Error(err, "unable to re-enable automated sync after GitOpsDeploymentSyncRun was deleted")
+		return gitopserrors.NewDevOnlyError(err)
+	}
+
 	// Success: the CR no longer exists, and we have completed cleanup.
 	return nil
 
@@ -484,7 +574,7 @@ func (a *applicationEventLoopRunner_Action) handleDeletedGitOpsDeplSyncRunEvent(
 //
 // Returns:
 // - error is non-nil, if an error occurred
-func (a *applicationEventLoopRunner_Action) handleNewGitOpsDeplSyncRunEvent(ctx context.Context, syncRunCRParam *managedgitopsv1alpha1.GitOpsDeploymentSyncRun, dbQueries db.ApplicationScopedQueries, application *db.Application, gitopsEngineInstance *db.GitopsEngineInstance, namespace corev1.Namespace, clusterUser db.ClusterUser) gitopserrors.UserError {
+func (a *applicationEventLoopRunner_Action) handleNewGitOpsDeplSyncRunEvent(ctx context.Context, syncRunCRParam *managedgitopsv1alpha1.GitOpsDeploymentSyncRun, revision string, dbQueries db.ApplicationScopedQueries, application *db.Application, gitopsEngineInstance *db.GitopsEngineInstance, namespace corev1.Namespace, clusterUser db.ClusterUser, syncWindows managedgitopsv1alpha1.SyncWindows) gitopserrors.UserError {
 
 	log := a.log
 	log.Info("Received GitOpsDeploymentSyncRun event for a new GitOpsDeploymentSyncRun resource")
@@ -507,9 +597,39 @@ func (a *applicationEventLoopRunner_Action) handleNewGitOpsDeplSyncRunEvent(ctx
 	syncOperation := &db.SyncOperation{
 		Application_id:      application.Application_id,
 		DeploymentNameField: syncRunCRParam.Spec.GitopsDeploymentName,
-		Revision:            syncRunCRParam.Spec.RevisionID,
+		Revision:            revision,
 		DesiredState:        db.SyncOperation_DesiredState_Running,
+		Prune:               syncRunCRParam.Spec.Prune,
+		DryRun:              syncRunCRParam.Spec.DryRun,
+		Force:               syncRunCRParam.Spec.Force,
+		SyncStrategy:        string(syncRunCRParam.Spec.SyncStrategy),
+		Phase:               db.SyncOperation_Phase_Pending,
 	}
+
+	// Store the selected resources alongside the SyncOperation, so that the cluster-agent only syncs those resources
+	if len(syncRunCRParam.Spec.Resources) != 0 {
+		resourcesJSON, err := json.Marshal(syncRunCRParam.Spec.Resources)
+		if err != nil {
+			log.Error(err, "unable to marshal resources")
+			return gitopserrors.NewDevOnlyError(err)
+		}
+		if len(resourcesJSON) > db.SyncOperationResourcesLength {
+			userErr := fmt.Sprintf("the resources in .spec.resources of GitOpsDeploymentSyncRun '%s' exceed the maximum supported length", syncRunCRParam.Name)
+			return gitopserrors.NewUserDevError(userErr, errors.New(userErr))
+		}
+		syncOperation.Resources = string(resourcesJSON)
+	}
+
+	// Store the sync windows alongside the SyncOperation, so that the cluster-agent can also enforce them
+	if len(syncWindows) != 0 {
+		syncWindowsJSON, err := json.Marshal(syncWindows)
+		if err != nil {
+			log.Error(err, "unable to marshal sync windows")
+			return gitopserrors.NewDevOnlyError(err)
+		}
+		syncOperation.SyncWindows = string(syncWindowsJSON)
+	}
+
 	if err := dbQueries.CreateSyncOperation(ctx, syncOperation); err != nil {
 		log.Error(err, "unable to create sync operation in database")
 
@@ -575,6 +695,9 @@ func (a *applicationEventLoopRunner_Action) handleNewGitOpsDeplSyncRunEvent(ctx
 
 	backoff := sharedutil.ExponentialBackoff{Factor: 1.3, Min: time.Millisecond * 1000, Max: time.Second * 10, Jitter: true}
 
+	// operationComplete is true if the cluster-agent finished processing the Operation
+	operationComplete := false
+
 outer_for:
 
 	for {
@@ -589,6 +712,7 @@ outer_for:
 			break outer_for
 		} else if isComplete {
 			// Our work is done: the operation is complete
+			operationComplete = true
 			break outer_for
 		}
 
@@ -613,12 +737,33 @@ outer_for:
 				log.Info("The SyncRun CR UID has changed, versus the SyncRun CR that we began with, exiting the sync process")
 				break outer_for
 			}
+
+			// Publish the progress of the sync, while we wait for it to complete
+			if err := a.publishSyncRunStatus(ctx, currentSyncRunCR, dbQueries); err != nil {
+				log.Error(err, "unable to update the status of the GitOpsDeploymentSyncRun, while waiting for the sync to complete")
+			}
 		}
 
 		backoff.DelayOnFail(ctx)
 
 	}
 
+	if operationComplete {
+		if currentSyncRunCR, err := getGitOpsDeploymentSyncRun(ctx, a.workspaceClient, syncRunCRParam.Name, syncRunCRParam.Namespace); err == nil &&
+			currentSyncRunCR.UID == syncRunCRParam.UID {
+
+			if err := a.publishSyncRunStatus(ctx, currentSyncRunCR, dbQueries); err != nil {
+				log.Error(err, "unable to update the status of the GitOpsDeploymentSyncRun, after the sync completed")
+			}
+		}
+	}
+
+	if operationComplete && syncRunCRParam.Spec.IsRollback() {
+		if err := a.setRollbackCompletedCondition(ctx, syncRunCRParam, *dbOperation); err != nil {
+			log.Error(err, "unable to report the completion of the rollback in the GitOpsDeploymentSyncRun status")
+		}
+	}
+
 	if err := operations.CleanupOperation(ctx, *dbOperation, *k8sOperation, dbQueries, operationClient, !a.testOnlySkipCreateOperation, log); err != nil {
 		return gitopserrors.NewDevOnlyError(err)
 	}
@@ -626,8 +771,284 @@ outer_for:
 	return nil
 }
 
+// publishSyncRunStatus updates the status of the GitOpsDeploymentSyncRun with the status of the sync, as reported by the
+// cluster-agent in the corresponding SyncOperation row.
+func (a *applicationEventLoopRunner_Action) publishSyncRunStatus(ctx context.Context, syncRunCR *managedgitopsv1alpha1.GitOpsDeploymentSyncRun,
+	dbQueries db.ApplicationScopedQueries) error {
+
+	mapping := db.APICRToDatabaseMapping{
+		APIResourceType: db.APICRToDatabaseMapping_ResourceType_GitOpsDeploymentSyncRun,
+		APIResourceUID:  string(syncRunCR.UID),
+		DBRelationType:  db.APICRToDatabaseMapping_DBRelationType_SyncOperation,
+	}
+	if err := dbQueries.GetDatabaseMappingForAPICR(ctx, &mapping); err != nil {
+		if db.IsResultNotFoundError(err) {
+			// The GitOpsDeploymentSyncRun has not yet been processed, or was rejected
+			return nil
+		}
+		return fmt.Errorf("unable to retrieve APICRToDatabaseMapping of GitOpsDeploymentSyncRun '%s': %v", syncRunCR.Name, err)
+	}
+
+	syncOperation := db.SyncOperation{SyncOperation_id: mapping.DBRelationKey}
+	if err := dbQueries.GetSyncOperationById(ctx, &syncOperation); err != nil {
+		if db.IsResultNotFoundError(err) {
+			return nil
+		}
+		return fmt.Errorf("unable to retrieve SyncOperation of GitOpsDeploymentSyncRun '%s': %v", syncRunCR.Name, err)
+	}
+
+	newStatus := syncRunCR.Status.DeepCopy()
+	convertSyncOperationToSyncRunStatus(syncOperation, newStatus, a.log)
+
+	if reflect.DeepEqual(*newStatus, syncRunCR.Status) {
+		return nil
+	}
+
+	oldPhase := syncRunCR.Status.Phase
+	syncRunCR.Status = *newStatus
+
+	if err := a.workspaceClient.Status().Update(ctx, syncRunCR); err != nil {
+		return err
+	}
+
+	if oldPhase != newStatus.Phase {
+		recordSyncRunPhaseEvent(syncRunCR, a.eventRecorder)
+	}
+
+	return nil
+}
+
+// recordSyncRunPhaseEvent records a K8s Event on the GitOpsDeploymentSyncRun for the phase it has transitioned to.
+func recordSyncRunPhaseEvent(syncRunCR *managedgitopsv1alpha1.GitOpsDeploymentSyncRun, eventRecorder *events.Recorder) {
+
+	status := syncRunCR.Status
+
+	revision := ""
+	if status.Revision != "" {
+		revision = fmt.Sprintf(" (revision '%s')", status.Revision)
+	}
+
+	message := ""
+	if status.Message != "" {
+		message = ": " + status.Message
+	}
+
+	switch status.Phase {
+	case managedgitopsv1alpha1.SyncRunPhase_Running:
+		eventRecorder.RecordNormal(syncRunCR, events.ReasonSyncStarted, "Sync of GitOpsDeployment '"+syncRunCR.Spec.GitopsDeploymentName+"' started")
+
+	case managedgitopsv1alpha1.SyncRunPhase_Succeeded:
+		eventRecorder.RecordNormal(syncRunCR, events.ReasonSyncSucceeded, "Sync succeeded"+revision)
+
+	case managedgitopsv1alpha1.SyncRunPhase_Failed:
+		eventRecorder.RecordWarning(syncRunCR, events.ReasonSyncFailed, "Sync failed"+revision+message)
+
+	case managedgitopsv1alpha1.SyncRunPhase_Terminated:
+		eventRecorder.RecordWarning(syncRunCR, events.ReasonSyncTerminated, "Sync was terminated"+message)
+	}
+}
+
+// publishSyncRunStatusesOfGitOpsDeployment updates the status of each of the given GitOpsDeploymentSyncRuns of a
+// GitOpsDeployment that has not yet finished.
+func (a *applicationEventLoopRunner_Action) publishSyncRunStatusesOfGitOpsDeployment(ctx context.Context, syncRuns []managedgitopsv1alpha1.GitOpsDeploymentSyncRun,
+	dbQueries db.ApplicationScopedQueries) error {
+
+	for idx := range syncRuns {
+		syncRun := syncRuns[idx]
+
+		if syncRun.Status.IsFinished() {
+			continue
+		}
+
+		if err := a.publishSyncRunStatus(ctx, &syncRun, dbQueries); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// convertSyncOperationToSyncRunStatus updates the phase, timing, revision and resource results of the
+// GitOpsDeploymentSyncRun status from the status fields of the SyncOperation. The conditions are not modified.
+func convertSyncOperationToSyncRunStatus(syncOperation db.SyncOperation, status *managedgitopsv1alpha1.GitOpsDeploymentSyncRunStatus, log logr.Logger) {
+
+	// Match the precision and location of a time that has been read from the K8s API, so that the status is
+	// not seen as modified every time it is published
+	convertTime := func(t time.Time) *metav1.Time {
+		if t.IsZero() {
+			return nil
+		}
+		res := metav1.NewTime(t.Truncate(time.Second).Local())
+		return &res
+	}
+
+	status.Phase = managedgitopsv1alpha1.SyncRunPhase(syncOperation.Phase)
+	if status.Phase == "" {
+		status.Phase = managedgitopsv1alpha1.SyncRunPhase_Pending
+	}
+	status.Message = syncOperation.PhaseMessage
+	status.StartedAt = convertTime(syncOperation.StartedAt)
+	status.FinishedAt = convertTime(syncOperation.FinishedAt)
+	status.Revision = syncOperation.SyncedRevision
+
+	status.Resources = nil
+	if syncOperation.ResourceResults != "" {
+		if err := json.Unmarshal([]byte(syncOperation.ResourceResults), &status.Resources); err != nil {
+			// Report the rest of the status, even if the resource results could not be parsed
+			log.Error(err, "unable to unmarshal resource results of SyncOperation", "syncOperationID", syncOperation.SyncOperation_id)
+			status.Resources = nil
+		}
+	}
+}
+
+// prepareRollbackOfGitOpsDeployment verifies that a rollback GitOpsDeploymentSyncRun rolls back to an entry of the
+// deployment history of the Application, and that the rollback has been applied to the Argo CD Application before it
+// begins: automated sync is disabled, and the source(s) of the Application are those of the deployment history entry.
+//
+// Returns the deployment history entry if the rollback can begin. Otherwise, nil is returned, an event is queued for the
+// deployment runner to apply the rollback to the Application, and the rollback is held: the deployment runner queues an
+// event for the GitOpsDeploymentSyncRun once it has done so.
+func (a *applicationEventLoopRunner_Action) prepareRollbackOfGitOpsDeployment(ctx context.Context, syncRunCR *managedgitopsv1alpha1.GitOpsDeploymentSyncRun,
+	gitopsDepl managedgitopsv1alpha1.GitOpsDeployment, application db.Application, dbQueries db.ApplicationScopedQueries) (*managedgitopsv1alpha1.DeploymentHistory, gitopserrors.UserError) {
+
+	deploymentHistory, err := getDeploymentHistoryOfApplication(ctx, dbQueries, application.Application_id, a.log)
+	if err != nil {
+		return nil, gitopserrors.NewDevOnlyError(err)
+	}
+
+	history := findDeploymentHistoryOfRollback(deploymentHistory, syncRunCR.Spec)
+	if history == nil {
+		userErr := fmt.Sprintf("unable to roll back GitOpsDeployment '%s': %s is not in the deployment history of the GitOpsDeployment",
+			gitopsDepl.Name, describeRollbackTarget(syncRunCR.Spec))
+		return nil, gitopserrors.NewUserDevError(userErr, errors.New(userErr))
+	}
+
+	rollbackApplied, err := isRollbackAppliedToApplication(application, *history)
+	if err != nil {
+		return nil, gitopserrors.NewDevOnlyError(err)
+	}
+
+	if rollbackApplied {
+		return history, nil
+	}
+
+	// The Application is updated by the deployment runner, which will now apply the rollback, since the rollback
+	// GitOpsDeploymentSyncRun exists
+	a.log.Info("holding rollback GitOpsDeploymentSyncRun until the rollback is applied to the GitOpsDeployment", "syncRun", syncRunCR.Name)
+	a.queueEvent(eventlooptypes.DeploymentModified, eventlooptypes.GitOpsDeploymentTypeName, gitopsDepl.Name, gitopsDepl.Namespace)
+
+	return nil, nil
+}
+
+// describeRollbackTarget returns a description of the deployment history entry that a rollback GitOpsDeploymentSyncRun
+// rolls back to, for use in user-facing messages.
+func describeRollbackTarget(syncRunSpec managedgitopsv1alpha1.GitOpsDeploymentSyncRunSpec) string {
+	if syncRunSpec.RollbackToHistoryID != "" {
+		return fmt.Sprintf("deployment history entry '%s'", syncRunSpec.RollbackToHistoryID)
+	}
+	return fmt.Sprintf("revision '%s'", syncRunSpec.RollbackToRevision)
+}
+
+// isRollbackAppliedToApplication returns true if the spec field of the Application row has automated sync disabled, and
+// has the source(s) of the deployment history entry, pinned to the revision(s) that were deployed.
+func isRollbackAppliedToApplication(application db.Application, history managedgitopsv1alpha1.DeploymentHistory) (bool, error) {
+
+	var fauxApplication fauxargocd.FauxApplication
+	if err := goyaml.Unmarshal([]byte(application.Spec_field), &fauxApplication); err != nil {
+		return false, fmt.Errorf("unable to unmarshal spec field of Application '%s': %v", application.Application_id, err)
+	}
+
+	if fauxApplication.Spec.SyncPolicy != nil && fauxApplication.Spec.SyncPolicy.Automated != nil {
+		return false, nil
+	}
+
+	isSourceOfHistory := func(source fauxargocd.ApplicationSource, historySource managedgitopsv1alpha1.ApplicationSource, revision string) bool {
+		// Only the revision is rolled back, if the source of the deployment history entry is unknown
+		if historySource.RepoURL == "" {
+			return source.TargetRevision == revision
+		}
+		return source.RepoURL == historySource.RepoURL && source.Path == historySource.Path &&
+			source.Chart == historySource.Chart && source.TargetRevision == revision
+	}
+
+	if len(history.Sources) == 0 {
+		return len(fauxApplication.Spec.Sources) == 0 && isSourceOfHistory(fauxApplication.Spec.Source, history.Source, history.Revision), nil
+	}
+
+	if len(fauxApplication.Spec.Sources) != len(history.Sources) {
+		return false, nil
+	}
+
+	for i := range history.Sources {
+		revision := fauxApplication.Spec.Sources[i].TargetRevision
+		if i < len(history.Revisions) {
+			revision = history.Revisions[i]
+		}
+		if !isSourceOfHistory(fauxApplication.Spec.Sources[i], history.Sources[i], revision) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// restoreGitOpsDeploymentAfterRollback queues an event for the deployment runner to update the Application of the
+// GitOpsDeployment after a GitOpsDeploymentSyncRun was deleted: if the GitOpsDeploymentSyncRun was a rollback, this
+// restores the source(s) of the GitOpsDeployment, and re-enables the automated sync that was disabled by the rollback.
+func (a *applicationEventLoopRunner_Action) restoreGitOpsDeploymentAfterRollback(ctx context.Context, gitopsDeplName string) error {
+
+	gitopsDepl := &managedgitopsv1alpha1.GitOpsDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      gitopsDeplName,
+			Namespace: a.eventResourceNamespace,
+		},
+	}
+	if err := a.workspaceClient.Get(ctx, client.ObjectKeyFromObject(gitopsDepl), gitopsDepl); err != nil {
+		if apierr.IsNotFound(err) {
+			// The GitOpsDeployment was deleted, so there is nothing to restore
+			return nil
+		}
+		return fmt.Errorf("unable to retrieve GitOpsDeployment '%s': %v", gitopsDeplName, err)
+	}
+
+	a.queueEvent(eventlooptypes.DeploymentModified, eventlooptypes.GitOpsDeploymentTypeName, gitopsDepl.Name, gitopsDepl.Namespace)
+
+	return nil
+}
+
+// setRollbackCompletedCondition reports the result of a rollback, once the cluster-agent has completed the Operation that
+// synchronized the Argo CD Application to the rollback revision.
+func (a *applicationEventLoopRunner_Action) setRollbackCompletedCondition(ctx context.Context, syncRunCRParam *managedgitopsv1alpha1.GitOpsDeploymentSyncRun, dbOperation db.Operation) error {
+
+	syncRunCR, err := getGitOpsDeploymentSyncRun(ctx, a.workspaceClient, syncRunCRParam.Name, syncRunCRParam.Namespace)
+	if err != nil {
+		if apierr.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if syncRunCR.UID != syncRunCRParam.UID {
+		// The GitOpsDeploymentSyncRun was deleted and recreated, so the result no longer applies
+		return nil
+	}
+
+	reason := managedgitopsv1alpha1.SyncRunReasonRollbackSucceeded
+	message := fmt.Sprintf("GitOpsDeployment '%s' was rolled back to %s", syncRunCR.Spec.GitopsDeploymentName, describeRollbackTarget(syncRunCR.Spec))
+
+	if dbOperation.State == db.OperationState_Failed {
+		reason = managedgitopsv1alpha1.SyncRunReasonRollbackFailed
+		message = fmt.Sprintf("unable to roll back GitOpsDeployment '%s' to %s: %s", syncRunCR.Spec.GitopsDeploymentName,
+			describeRollbackTarget(syncRunCR.Spec), dbOperation.Human_readable_state)
+	}
+
+	return setGitOpsDeploymentSyncRunCondition(ctx, a.workspaceClient, syncRunCR, managedgitopsv1alpha1.GitOpsDeploymentSyncRunConditionRollbackCompleted,
+		reason, managedgitopsv1alpha1.GitOpsConditionStatusTrue, message)
+}
+
 // handleUpdatedGitOpsDeplSyncRunEvent handles GitOpsDeploymentSyncRun events where the user has just updated an existing GitOpsDeploymentSyncRun resource.
-// In this case, we need to ensure that the immutable fields GitOpsDeploymentName and RevisionID are not updated.
+// In this case, we need to ensure that the immutable fields GitOpsDeploymentName, RevisionID (or RollbackToRevision/RollbackToHistoryID) and the
+// sync options (Prune, DryRun, Force, SyncStrategy and Resources) are not updated.
 //
 // Returns:
 // - error is non-nil, if an error occurred
@@ -641,15 +1062,57 @@ func (a *applicationEventLoopRunner_Action) handleUpdatedGitOpsDeplSyncRunEvent(
 		return gitopserrors.NewUserDevError(ErrDeploymentNameIsImmutable, err)
 	}
 
-	if syncOperation.Revision != syncRunCR.Spec.RevisionID {
+	revision := syncRunCR.Spec.GetRevision()
+	if syncRunCR.Spec.RollbackToHistoryID != "" {
+		// The revision of a rollback to a deployment history ID is that of the deployment history entry
+		deploymentHistory, err := getDeploymentHistoryOfApplication(ctx, dbQueries, syncOperation.Application_id, log)
+		if err != nil {
+			return gitopserrors.NewDevOnlyError(err)
+		}
+
+		if history := findDeploymentHistoryOfRollback(deploymentHistory, syncRunCR.Spec); history == nil {
+			// The entry has since left the deployment history: the ID itself is immutable, as enforced by the webhook
+			revision = syncOperation.Revision
+		} else {
+			revision = history.Revision
+		}
+	}
+
+	if syncOperation.Revision != revision {
 		err := errors.New(ErrRevisionIsImmutable)
 		log.Error(err, ErrRevisionIsImmutable)
 		return gitopserrors.NewUserDevError(ErrRevisionIsImmutable, err)
 	}
 
+	if !syncOperationHasSyncOptionsOfSyncRun(syncOperation, *syncRunCR) {
+		err := errors.New(ErrSyncOptionsAreImmutable)
+		log.Error(err, ErrSyncOptionsAreImmutable)
+		return gitopserrors.NewUserDevError(ErrSyncOptionsAreImmutable, err)
+	}
+
 	return nil
 }
 
+// syncOperationHasSyncOptionsOfSyncRun returns true if the sync options (prune, dryRun, force, syncStrategy and resources)
+// that were stored in the SyncOperation match those of the GitOpsDeploymentSyncRun.
+func syncOperationHasSyncOptionsOfSyncRun(syncOperation db.SyncOperation, syncRunCR managedgitopsv1alpha1.GitOpsDeploymentSyncRun) bool {
+
+	var resources []managedgitopsv1alpha1.SyncRunResource
+	if syncOperation.Resources != "" {
+		if err := json.Unmarshal([]byte(syncOperation.Resources), &resources); err != nil {
+			return false
+		}
+	}
+
+	return syncRunCR.Spec.HasSameSyncOptions(managedgitopsv1alpha1.GitOpsDeploymentSyncRunSpec{
+		Prune:        syncOperation.Prune,
+		DryRun:       syncOperation.DryRun,
+		Force:        syncOperation.Force,
+		SyncStrategy: managedgitopsv1alpha1.SyncRunSyncStrategy(syncOperation.SyncStrategy),
+		Resources:    resources,
+	})
+}
+
 func (a *applicationEventLoopRunner_Action) cleanupOldSyncDBEntry(ctx context.Context, apiCRToDB *db.APICRToDatabaseMapping,
 	clusterUser db.ClusterUser, dbQueries db.ApplicationScopedQueries) error {
 
@@ -2,7 +2,9 @@ package application_event_loop
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -10,7 +12,9 @@ import (
 	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
 	db "github.com/redhat-appstudio/managed-gitops/backend-shared/db"
 	sharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/fauxargocd"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/tests"
+	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/eventlooptypes"
 	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/shared_resource_loop"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/uuid"
@@ -78,7 +82,7 @@ var _ = Describe("Application Event Runner SyncRuns", func() {
 			applicationAction = applicationEventLoopRunner_Action{
 				eventResourceName:           gitopsDepl.Name,
 				eventResourceNamespace:      gitopsDepl.Namespace,
-				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(),
+				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(nil),
 				workspaceClient:             k8sClient,
 				log:                         log.FromContext(ctx),
 				workspaceID:                 string(workspace.UID),
@@ -116,6 +120,7 @@ var _ = Describe("Application Event Runner SyncRuns", func() {
 			Expect(err).ToNot(HaveOccurred())
 			Expect(syncOperation.DeploymentNameField).Should(Equal(gitopsDeplSyncRun.Spec.GitopsDeploymentName))
 			Expect(syncOperation.Revision).Should(Equal(gitopsDeplSyncRun.Spec.RevisionID))
+			Expect(syncOperation.Phase).Should(Equal(db.SyncOperation_Phase_Pending))
 
 			By("verify if an Operation CR is created")
 			operationCreated, operationDeleted := false, false
@@ -131,6 +136,54 @@ var _ = Describe("Application Event Runner SyncRuns", func() {
 			Expect(operationDeleted).To(BeTrue())
 		})
 
+		It("should store the sync options of the GitOpsDeploymentSyncRun in the SyncOperation", func() {
+
+			By("create a SyncRun CR with sync options")
+			syncRun := managedgitopsv1alpha1.GitOpsDeploymentSyncRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "syncrun-with-options",
+					Namespace: gitopsDepl.Namespace,
+					UID:       uuid.NewUUID(),
+				},
+				Spec: managedgitopsv1alpha1.GitOpsDeploymentSyncRunSpec{
+					GitopsDeploymentName: gitopsDepl.Name,
+					RevisionID:           "HEAD",
+					Prune:                true,
+					DryRun:               true,
+					Force:                true,
+					SyncStrategy:         managedgitopsv1alpha1.SyncRunSyncStrategy_Apply,
+					Resources: []managedgitopsv1alpha1.SyncRunResource{
+						{Kind: "ConfigMap", Namespace: gitopsDepl.Namespace, Name: "my-config"},
+					},
+				},
+			}
+
+			err := k8sClient.Create(ctx, &syncRun)
+			Expect(err).ToNot(HaveOccurred())
+
+			applicationAction.eventResourceName = syncRun.Name
+			userDevErr := applicationAction.applicationEventRunner_handleSyncRunModifiedInternal(ctx, dbQueries)
+			Expect(userDevErr).To(BeNil())
+
+			By("check if the sync options are stored in the SyncOperation")
+			mapping := db.APICRToDatabaseMapping{
+				APIResourceType: db.APICRToDatabaseMapping_ResourceType_GitOpsDeploymentSyncRun,
+				APIResourceUID:  string(syncRun.UID),
+				DBRelationType:  db.APICRToDatabaseMapping_DBRelationType_SyncOperation,
+			}
+			err = dbQueries.GetDatabaseMappingForAPICR(ctx, &mapping)
+			Expect(err).ToNot(HaveOccurred())
+
+			syncOperation := db.SyncOperation{SyncOperation_id: mapping.DBRelationKey}
+			err = dbQueries.GetSyncOperationById(ctx, &syncOperation)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(syncOperation.Prune).To(BeTrue())
+			Expect(syncOperation.DryRun).To(BeTrue())
+			Expect(syncOperation.Force).To(BeTrue())
+			Expect(syncOperation.SyncStrategy).To(Equal(db.SyncOperation_SyncStrategy_Apply))
+			Expect(syncOperation.Resources).To(Equal(fmt.Sprintf(`[{"kind":"ConfigMap","namespace":"%s","name":"my-config"}]`, gitopsDepl.Namespace)))
+		})
+
 		It("should throw an error when users try to update immutable fields", func() {
 			By("create a new GitOpsDeployment that needs to be synced")
 			newGitOpsDepl := managedgitopsv1alpha1.GitOpsDeployment{
@@ -181,6 +234,21 @@ var _ = Describe("Application Event Runner SyncRuns", func() {
 			userDevErr = applicationAction.applicationEventRunner_handleSyncRunModifiedInternal(ctx, dbQueries)
 			Expect(userDevErr.DevError().Error()).Should(Equal(ErrRevisionIsImmutable))
 			Expect(userDevErr.UserError()).Should(Equal(ErrRevisionIsImmutable))
+
+			gitopsDeplSyncRun.Spec.RevisionID = "HEAD"
+			err = k8sClient.Update(ctx, gitopsDeplSyncRun)
+			Expect(err).ToNot(HaveOccurred())
+
+			By("verify if the sync options of GitOpsDeploymentSyncRun are immutable")
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(gitopsDeplSyncRun), gitopsDeplSyncRun)
+			Expect(err).ToNot(HaveOccurred())
+
+			gitopsDeplSyncRun.Spec.Prune = !gitopsDeplSyncRun.Spec.Prune
+			err = k8sClient.Update(ctx, gitopsDeplSyncRun)
+			Expect(err).ToNot(HaveOccurred())
+			userDevErr = applicationAction.applicationEventRunner_handleSyncRunModifiedInternal(ctx, dbQueries)
+			Expect(userDevErr.DevError().Error()).Should(Equal(ErrSyncOptionsAreImmutable))
+			Expect(userDevErr.UserError()).Should(Equal(ErrSyncOptionsAreImmutable))
 		})
 
 		It("should terminate the SyncOperation and create an Operation when the SyncRun CR is deleted", func() {
@@ -351,6 +419,166 @@ var _ = Describe("Application Event Runner SyncRuns", func() {
 			Expect(userDevErr.UserError()).Should(Equal(expectedErr))
 		})
 
+		It("should roll back an automated GitOpsDeployment to an entry of its deployment history, and disable automated sync until the SyncRun is deleted", func() {
+
+			By("create a GitOpsDeployment with Automated sync policy, and a deployment history")
+			gitopsDeplAutomated := managedgitopsv1alpha1.GitOpsDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-depl-rollback",
+					Namespace: gitopsDepl.Namespace,
+					UID:       uuid.NewUUID(),
+				},
+				Spec: managedgitopsv1alpha1.GitOpsDeploymentSpec{
+					Type:   managedgitopsv1alpha1.GitOpsDeploymentSpecType_Automated,
+					Source: gitopsDepl.Spec.Source,
+				},
+			}
+
+			err := k8sClient.Create(ctx, &gitopsDeplAutomated)
+			Expect(err).ToNot(HaveOccurred())
+
+			applicationAction.eventResourceName = gitopsDeplAutomated.Name
+			_, _, _, _, userDevErr := applicationAction.applicationEventRunner_handleDeploymentModified(ctx, dbQueries)
+			Expect(userDevErr).To(BeNil())
+
+			getApplicationSpecField := func() string {
+				deplToAppMapping := db.DeploymentToApplicationMapping{Deploymenttoapplicationmapping_uid_id: string(gitopsDeplAutomated.UID)}
+				Expect(dbQueries.GetDeploymentToApplicationMappingByDeplId(ctx, &deplToAppMapping)).To(Succeed())
+
+				application := db.Application{Application_id: deplToAppMapping.Application_id}
+				Expect(dbQueries.GetApplicationById(ctx, &application)).To(Succeed())
+
+				return application.Spec_field
+			}
+			Expect(getApplicationSpecField()).To(ContainSubstring("automated"))
+
+			deplToAppMapping := db.DeploymentToApplicationMapping{Deploymenttoapplicationmapping_uid_id: string(gitopsDeplAutomated.UID)}
+			Expect(dbQueries.GetDeploymentToApplicationMappingByDeplId(ctx, &deplToAppMapping)).To(Succeed())
+
+			sourceJSON, err := json.Marshal(gitopsDepl.Spec.Source)
+			Expect(err).ToNot(HaveOccurred())
+
+			rollbackHistory := db.DeploymentHistory{
+				Application_id: deplToAppMapping.Application_id,
+				Revision:       "0a1b2c3d",
+				Source:         string(sourceJSON),
+				DeployedAt:     time.Now().Add(-time.Hour),
+			}
+			Expect(dbQueries.CreateDeploymentHistory(ctx, &rollbackHistory)).To(Succeed())
+			Expect(dbQueries.CreateDeploymentHistory(ctx, &db.DeploymentHistory{
+				Application_id: deplToAppMapping.Application_id,
+				Revision:       "4e5f6a7b",
+				Source:         string(sourceJSON),
+				DeployedAt:     time.Now(),
+			})).To(Succeed())
+
+			By("create a SyncRun CR that rolls back the GitOpsDeployment to the ID of a deployment history entry")
+			syncRun := managedgitopsv1alpha1.GitOpsDeploymentSyncRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "rollback-syncrun",
+					Namespace: gitopsDeplAutomated.Namespace,
+					UID:       uuid.NewUUID(),
+				},
+				Spec: managedgitopsv1alpha1.GitOpsDeploymentSyncRunSpec{
+					GitopsDeploymentName: gitopsDeplAutomated.Name,
+					RollbackToHistoryID:  rollbackHistory.DeploymentHistory_id,
+				},
+			}
+
+			err = k8sClient.Create(ctx, &syncRun)
+			Expect(err).ToNot(HaveOccurred())
+
+			eventLoopInputChan := make(chan RequestMessage, 5)
+			applicationAction.eventLoopInputChan = eventLoopInputChan
+
+			expectQueuedEvent := func(eventType eventlooptypes.EventLoopEventType, reqResource eventlooptypes.GitOpsResourceType, name string) {
+				var queued RequestMessage
+				Eventually(eventLoopInputChan).Should(Receive(&queued))
+				Expect(queued.Message.Event.EventType).To(Equal(eventType))
+				Expect(queued.Message.Event.ReqResource).To(Equal(reqResource))
+				Expect(queued.Message.Event.Request.Name).To(Equal(name))
+			}
+
+			applicationAction.eventResourceName = syncRun.Name
+			userDevErr = applicationAction.applicationEventRunner_handleSyncRunModifiedInternal(ctx, dbQueries)
+			Expect(userDevErr).To(BeNil())
+
+			By("check if the rollback is held, and an event is queued for the GitOpsDeployment, while automated sync is enabled")
+			mapping := db.APICRToDatabaseMapping{
+				APIResourceType: db.APICRToDatabaseMapping_ResourceType_GitOpsDeploymentSyncRun,
+				APIResourceUID:  string(syncRun.UID),
+				DBRelationType:  db.APICRToDatabaseMapping_DBRelationType_SyncOperation,
+			}
+			err = dbQueries.GetDatabaseMappingForAPICR(ctx, &mapping)
+			Expect(db.IsResultNotFoundError(err)).To(BeTrue())
+
+			expectQueuedEvent(eventlooptypes.DeploymentModified, eventlooptypes.GitOpsDeploymentTypeName, gitopsDeplAutomated.Name)
+
+			By("process the GitOpsDeployment event, and check if an event is queued for the held SyncRun")
+			applicationAction.eventResourceName = gitopsDeplAutomated.Name
+			_, _, _, _, userDevErr = applicationAction.applicationEventRunner_handleDeploymentModified(ctx, dbQueries)
+			Expect(userDevErr).To(BeNil())
+
+			expectQueuedEvent(eventlooptypes.SyncRunModified, eventlooptypes.GitOpsDeploymentSyncRunTypeName, syncRun.Name)
+
+			applicationAction.eventResourceName = syncRun.Name
+			userDevErr = applicationAction.applicationEventRunner_handleSyncRunModifiedInternal(ctx, dbQueries)
+			Expect(userDevErr).To(BeNil())
+
+			By("check if the SyncOperation deploys the rollback revision")
+			err = dbQueries.GetDatabaseMappingForAPICR(ctx, &mapping)
+			Expect(err).ToNot(HaveOccurred())
+
+			syncOperation := db.SyncOperation{SyncOperation_id: mapping.DBRelationKey}
+			err = dbQueries.GetSyncOperationById(ctx, &syncOperation)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(syncOperation.Revision).Should(Equal("0a1b2c3d"))
+
+			By("check if automated sync is disabled, and the source is pinned to the rollback revision, while the rollback is in effect")
+			Expect(getApplicationSpecField()).ToNot(ContainSubstring("automated"))
+			Expect(getApplicationSpecField()).To(ContainSubstring("0a1b2c3d"))
+
+			By("delete the SyncRun CR, and check if automated sync is enabled again")
+			err = k8sClient.Delete(ctx, &syncRun)
+			Expect(err).ToNot(HaveOccurred())
+
+			userDevErr = applicationAction.applicationEventRunner_handleSyncRunModifiedInternal(ctx, dbQueries)
+			Expect(userDevErr).To(BeNil())
+
+			expectQueuedEvent(eventlooptypes.DeploymentModified, eventlooptypes.GitOpsDeploymentTypeName, gitopsDeplAutomated.Name)
+
+			applicationAction.eventResourceName = gitopsDeplAutomated.Name
+			_, _, _, _, userDevErr = applicationAction.applicationEventRunner_handleDeploymentModified(ctx, dbQueries)
+			Expect(userDevErr).To(BeNil())
+
+			Expect(getApplicationSpecField()).To(ContainSubstring("automated"))
+			Expect(getApplicationSpecField()).ToNot(ContainSubstring("0a1b2c3d"))
+		})
+
+		It("should return an error when rolling back to a revision that is not in the deployment history", func() {
+
+			By("create a SyncRun CR that rolls back to an unknown revision")
+			syncRun := managedgitopsv1alpha1.GitOpsDeploymentSyncRun{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "rollback-syncrun",
+					Namespace: gitopsDepl.Namespace,
+					UID:       uuid.NewUUID(),
+				},
+				Spec: managedgitopsv1alpha1.GitOpsDeploymentSyncRunSpec{
+					GitopsDeploymentName: gitopsDepl.Name,
+					RollbackToRevision:   "0a1b2c3d",
+				},
+			}
+
+			err := k8sClient.Create(ctx, &syncRun)
+			Expect(err).ToNot(HaveOccurred())
+
+			applicationAction.eventResourceName = syncRun.Name
+			userDevErr := applicationAction.applicationEventRunner_handleSyncRunModifiedInternal(ctx, dbQueries)
+			Expect(userDevErr).ToNot(BeNil())
+			Expect(userDevErr.UserError()).Should(Equal(fmt.Sprintf("unable to roll back GitOpsDeployment '%s': revision '0a1b2c3d' is not in the deployment history of the GitOpsDeployment", gitopsDepl.Name)))
+		})
+
 		It("should return true shutdown signal if neither CR nor DB entry exists", func() {
 			By("delete the SyncRun CR and the relevant DB details")
 			err := k8sClient.Delete(ctx, gitopsDeplSyncRun)
@@ -507,3 +735,137 @@ var _ = Describe("Application Event Runner SyncRuns", func() {
 		})
 	})
 })
+
+var _ = Describe("convertSyncOperationToSyncRunStatus", func() {
+
+	It("should report a SyncOperation that has not yet started as Pending", func() {
+		status := managedgitopsv1alpha1.GitOpsDeploymentSyncRunStatus{}
+		convertSyncOperationToSyncRunStatus(db.SyncOperation{}, &status, log.FromContext(context.Background()))
+
+		Expect(status.Phase).To(Equal(managedgitopsv1alpha1.SyncRunPhase_Pending))
+		Expect(status.StartedAt).To(BeNil())
+		Expect(status.FinishedAt).To(BeNil())
+		Expect(status.Resources).To(BeNil())
+	})
+
+	It("should convert the status of a completed SyncOperation, and preserve the existing conditions", func() {
+		startedAt := time.Date(2024, time.January, 1, 9, 30, 0, 500, time.UTC)
+
+		status := managedgitopsv1alpha1.GitOpsDeploymentSyncRunStatus{
+			Conditions: []managedgitopsv1alpha1.GitOpsDeploymentSyncRunCondition{
+				{Type: managedgitopsv1alpha1.GitOpsDeploymentSyncRunConditionErrorOccurred, Status: managedgitopsv1alpha1.GitOpsConditionStatusFalse},
+			},
+		}
+		convertSyncOperationToSyncRunStatus(db.SyncOperation{
+			Phase:           db.SyncOperation_Phase_Succeeded,
+			PhaseMessage:    "successfully synced (all tasks run)",
+			StartedAt:       startedAt,
+			FinishedAt:      startedAt.Add(time.Minute),
+			SyncedRevision:  "0a1b2c3d",
+			ResourceResults: `[{"version":"v1","kind":"ConfigMap","namespace":"my-namespace","name":"my-config","status":"Synced"}]`,
+		}, &status, log.FromContext(context.Background()))
+
+		Expect(status.Conditions).To(HaveLen(1))
+		Expect(status.Phase).To(Equal(managedgitopsv1alpha1.SyncRunPhase_Succeeded))
+		Expect(status.Message).To(Equal("successfully synced (all tasks run)"))
+		Expect(status.StartedAt.Time.Equal(startedAt.Truncate(time.Second))).To(BeTrue())
+		Expect(status.FinishedAt.Time.Equal(startedAt.Add(time.Minute).Truncate(time.Second))).To(BeTrue())
+		Expect(status.Revision).To(Equal("0a1b2c3d"))
+		Expect(status.Resources).To(Equal([]managedgitopsv1alpha1.SyncRunResourceResult{
+			{Version: "v1", Kind: "ConfigMap", Namespace: "my-namespace", Name: "my-config", Status: "Synced"},
+		}))
+	})
+
+	It("should report a running GitOpsDeploymentSyncRun as Terminated, once its SyncOperation is terminated", func() {
+		startedAt := time.Date(2024, time.January, 1, 9, 30, 0, 0, time.UTC)
+
+		status := managedgitopsv1alpha1.GitOpsDeploymentSyncRunStatus{}
+		convertSyncOperationToSyncRunStatus(db.SyncOperation{
+			Phase:     db.SyncOperation_Phase_Running,
+			StartedAt: startedAt,
+		}, &status, log.FromContext(context.Background()))
+
+		Expect(status.Phase).To(Equal(managedgitopsv1alpha1.SyncRunPhase_Running))
+		Expect(status.IsFinished()).To(BeFalse())
+
+		convertSyncOperationToSyncRunStatus(db.SyncOperation{
+			Phase:        db.SyncOperation_Phase_Terminated,
+			PhaseMessage: "Operation terminated",
+			StartedAt:    startedAt,
+			FinishedAt:   startedAt.Add(time.Minute),
+		}, &status, log.FromContext(context.Background()))
+
+		Expect(status.Phase).To(Equal(managedgitopsv1alpha1.SyncRunPhase_Terminated))
+		Expect(status.Message).To(Equal("Operation terminated"))
+		Expect(status.IsFinished()).To(BeTrue())
+	})
+})
+
+var _ = Describe("isRollbackAppliedToApplication", func() {
+
+	history := managedgitopsv1alpha1.DeploymentHistory{
+		Revision: "0a1b2c3d",
+		Source:   managedgitopsv1alpha1.ApplicationSource{RepoURL: "https://github.com/test/old-repo", Path: "environments/old"},
+	}
+
+	applicationWithSpec := func(specFieldInput argoCDSpecInput) db.Application {
+		specFieldInput.crName = "my-application"
+		specFieldInput.crNamespace = "argocd"
+		specField, err := createSpecField(specFieldInput)
+		Expect(err).ToNot(HaveOccurred())
+
+		return db.Application{Application_id: "test-application", Spec_field: specField}
+	}
+
+	It("should return true only once the Application has the source of the deployment history entry, and automated sync is disabled", func() {
+		specFieldInput := argoCDSpecInput{
+			sourceRepoURL:        "https://github.com/test/new-repo",
+			sourcePath:           "environments/new",
+			sourceTargetRevision: "main",
+			automated:            true,
+		}
+
+		rollbackApplied, err := isRollbackAppliedToApplication(applicationWithSpec(specFieldInput), history)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rollbackApplied).To(BeFalse())
+
+		By("pinning the source, while automated sync is still enabled")
+		rollBackSourcesOfSpecInput(&specFieldInput, history)
+		rollbackApplied, err = isRollbackAppliedToApplication(applicationWithSpec(specFieldInput), history)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rollbackApplied).To(BeFalse())
+
+		By("disabling automated sync")
+		specFieldInput.automated = false
+		rollbackApplied, err = isRollbackAppliedToApplication(applicationWithSpec(specFieldInput), history)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rollbackApplied).To(BeTrue())
+	})
+
+	It("should compare every source of an Application with multiple sources", func() {
+		multiSourceHistory := managedgitopsv1alpha1.DeploymentHistory{
+			Revision: "0a1b2c3d",
+			Sources: []managedgitopsv1alpha1.ApplicationSource{
+				{RepoURL: "https://github.com/test/test", Path: "environments/old"},
+				{RepoURL: "https://github.com/test/values", Ref: "values"},
+			},
+			Revisions: []string{"0a1b2c3d", "4e5f6a7b"},
+		}
+
+		specFieldInput := argoCDSpecInput{
+			sources: []fauxargocd.ApplicationSource{
+				{RepoURL: "https://github.com/test/test", Path: "environments/old", TargetRevision: "0a1b2c3d"},
+				{RepoURL: "https://github.com/test/values", TargetRevision: "main", Ref: "values"},
+			},
+		}
+
+		rollbackApplied, err := isRollbackAppliedToApplication(applicationWithSpec(specFieldInput), multiSourceHistory)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rollbackApplied).To(BeFalse())
+
+		rollBackSourcesOfSpecInput(&specFieldInput, multiSourceHistory)
+		rollbackApplied, err = isRollbackAppliedToApplication(applicationWithSpec(specFieldInput), multiSourceHistory)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rollbackApplied).To(BeTrue())
+	})
+})
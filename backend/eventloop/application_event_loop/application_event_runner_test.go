@@ -91,7 +91,7 @@ var _ = Describe("ApplicationEventLoop Test", func() {
 			}
 			ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
-			sharedResourceLoop := shared_resource_loop.NewSharedResourceLoop()
+			sharedResourceLoop := shared_resource_loop.NewSharedResourceLoop(nil)
 
 			// 1) send a deployment modified event, to ensure the deployment is added to the database, and processed
 			a := applicationEventLoopRunner_Action{
@@ -168,7 +168,7 @@ var _ = Describe("ApplicationEventLoop Test", func() {
 			}
 			ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
-			sharedResourceLoop := shared_resource_loop.NewSharedResourceLoop()
+			sharedResourceLoop := shared_resource_loop.NewSharedResourceLoop(nil)
 
 			// 1) send a deployment modified event, to ensure the deployment is added to the database, and processed
 			a := applicationEventLoopRunner_Action{
@@ -261,7 +261,7 @@ var _ = Describe("ApplicationEventLoop Test", func() {
 				eventResourceNamespace:      gitopsDepl.Namespace,
 				workspaceClient:             k8sClient,
 				log:                         log.FromContext(context.Background()),
-				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(),
+				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(nil),
 				workspaceID:                 workspaceID,
 				testOnlySkipCreateOperation: true,
 				k8sClientFactory: MockSRLK8sClientFactory{
@@ -501,7 +501,7 @@ var _ = Describe("ApplicationEventLoop Test", func() {
 				eventResourceNamespace:      gitopsDepl.Namespace,
 				workspaceClient:             k8sClient,
 				log:                         log.FromContext(context.Background()),
-				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(),
+				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(nil),
 				workspaceID:                 workspaceID,
 				testOnlySkipCreateOperation: true,
 				k8sClientFactory: MockSRLK8sClientFactory{
@@ -665,7 +665,7 @@ var _ = Describe("ApplicationEventLoop Test", func() {
 				eventResourceNamespace:      gitopsDepl.Namespace,
 				workspaceClient:             k8sClient,
 				log:                         log.FromContext(context.Background()),
-				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(),
+				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(nil),
 				workspaceID:                 workspaceID,
 				testOnlySkipCreateOperation: true,
 				k8sClientFactory:            mockK8sClientFactory,
@@ -821,7 +821,7 @@ var _ = Describe("ApplicationEventLoop Test", func() {
 		// 	eventResourceNamespace:      workspace.Namespace,
 		// 	workspaceClient:             k8sClient,
 		// 	log:                         log.FromContext(context.Background()),
-		// 	sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(),
+		// 	sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(nil),
 		// 	workspaceID:                 workspaceID,
 		// 	testOnlySkipCreateOperation: true,
 		// 	k8sClientFactory: MockSRLK8sClientFactory{
@@ -847,7 +847,7 @@ var _ = Describe("ApplicationEventLoop Test", func() {
 				eventResourceNamespace:      workspace.Namespace,
 				workspaceClient:             k8sClient,
 				log:                         log.FromContext(context.Background()),
-				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(),
+				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(nil),
 				workspaceID:                 workspaceID,
 				testOnlySkipCreateOperation: true,
 				k8sClientFactory: MockSRLK8sClientFactory{
@@ -894,7 +894,7 @@ var _ = Describe("ApplicationEventLoop Test", func() {
 				eventResourceNamespace:      gitopsDepl.Namespace,
 				workspaceClient:             k8sClient,
 				log:                         log.FromContext(context.Background()),
-				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(),
+				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(nil),
 				workspaceID:                 workspaceID,
 				testOnlySkipCreateOperation: true,
 				k8sClientFactory: MockSRLK8sClientFactory{
@@ -955,7 +955,7 @@ var _ = Describe("ApplicationEventLoop Test", func() {
 				eventResourceNamespace:      gitopsDepl.Namespace,
 				workspaceClient:             k8sClient,
 				log:                         log.FromContext(context.Background()),
-				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(),
+				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(nil),
 				workspaceID:                 workspaceID,
 				testOnlySkipCreateOperation: true,
 				k8sClientFactory: MockSRLK8sClientFactory{
@@ -1265,7 +1265,7 @@ var _ = Describe("ApplicationEventLoop Test", func() {
 			dbQueries, err = db.NewUnsafePostgresDBQueries(false, false)
 			Expect(err).ToNot(HaveOccurred())
 
-			sharedResourceEventLoop = shared_resource_loop.NewSharedResourceLoop()
+			sharedResourceEventLoop = shared_resource_loop.NewSharedResourceLoop(nil)
 
 			clusterUser, _, err = sharedResourceEventLoop.GetOrCreateClusterUserByNamespaceUID(ctx, k8sClient, *namespace, log.FromContext(ctx))
 			Expect(err).To(Succeed())
@@ -1311,7 +1311,7 @@ var _ = Describe("ApplicationEventLoop Test", func() {
 				}
 
 				By("starting the application event runner, and giving it our fake k8s client")
-				inputChannel := startNewApplicationEventLoopRunner(ctx, informWorkCompleteChan, sharedResourceEventLoop, gitopsDeploymentName, gitopsDeploymentNamespace, namespaceID, "", fakeFactory)
+				inputChannel := startNewApplicationEventLoopRunner(ctx, informWorkCompleteChan, sharedResourceEventLoop, nil, nil, gitopsDeploymentName, gitopsDeploymentNamespace, namespaceID, "", fakeFactory)
 
 				operationCreated := make(chan bool)
 
@@ -1426,7 +1426,7 @@ var _ = Describe("ApplicationEventLoop Test", func() {
 				}
 
 				By("starting the application event runner, and giving it our fake k8s client")
-				inputChannel := startNewApplicationEventLoopRunner(ctx, informWorkCompleteChan, sharedResourceEventLoop, gitopsDeploymentName, gitopsDeploymentNamespace, namespaceID, "", fakeFactory)
+				inputChannel := startNewApplicationEventLoopRunner(ctx, informWorkCompleteChan, sharedResourceEventLoop, nil, nil, gitopsDeploymentName, gitopsDeploymentNamespace, namespaceID, "", fakeFactory)
 
 				By("creating a go routine that will mark Operations as completed, to simulate cluster-agent")
 				go func() {
@@ -1649,7 +1649,7 @@ var _ = Describe("ApplicationEventLoop Test", func() {
 				}
 
 				By("starting the application event runner, and giving it our fake k8s client")
-				inputChannel := startNewApplicationEventLoopRunner(ctx, informWorkCompleteChan, sharedResourceEventLoop, gitopsDeploymentName, gitopsDeploymentNamespace, namespaceID, "", fakeFactory)
+				inputChannel := startNewApplicationEventLoopRunner(ctx, informWorkCompleteChan, sharedResourceEventLoop, nil, nil, gitopsDeploymentName, gitopsDeploymentNamespace, namespaceID, "", fakeFactory)
 
 				applicationIdChan := make(chan string, 10)
 
@@ -1804,7 +1804,7 @@ var _ = Describe("ApplicationEventLoop Test", func() {
 					fakeClient: k8sClient,
 				}
 
-				inputChannel := startNewApplicationEventLoopRunner(ctx, informWorkCompleteChan, sharedResourceEventLoop, gitopsDeploymentName, gitopsDeploymentNamespace, namespaceID, "", fakeFactory)
+				inputChannel := startNewApplicationEventLoopRunner(ctx, informWorkCompleteChan, sharedResourceEventLoop, nil, nil, gitopsDeploymentName, gitopsDeploymentNamespace, namespaceID, "", fakeFactory)
 
 				By("creating a go routine that will mark Operations as completed, to simulate cluster-agent")
 				go func() {
@@ -2219,7 +2219,7 @@ var _ = Describe("application_event_runner_deployments.go Tests", func() {
 				eventResourceNamespace:      gitopsDepl.Namespace,
 				workspaceClient:             k8sClient,
 				log:                         log.FromContext(context.Background()),
-				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(),
+				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(nil),
 				workspaceID:                 string(namespace.UID),
 				testOnlySkipCreateOperation: true,
 				k8sClientFactory:            mockK8sClientFactory,
@@ -2291,7 +2291,7 @@ var _ = Describe("application_event_runner_deployments.go Tests", func() {
 				eventResourceNamespace:      gitopsDepl.Namespace,
 				workspaceClient:             k8sClient,
 				log:                         log.FromContext(context.Background()),
-				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(),
+				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(nil),
 				workspaceID:                 string(namespace.UID),
 				testOnlySkipCreateOperation: true,
 				k8sClientFactory:            mockK8sClientFactory,
@@ -2370,7 +2370,7 @@ var _ = Describe("application_event_runner_deployments.go Tests", func() {
 				eventResourceNamespace:      gitopsDepl.Namespace,
 				workspaceClient:             k8sClient,
 				log:                         log.FromContext(context.Background()),
-				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(),
+				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(nil),
 				workspaceID:                 string(namespace.UID),
 				testOnlySkipCreateOperation: true,
 				k8sClientFactory:            mockK8sClientFactory,
@@ -2433,7 +2433,7 @@ var _ = Describe("application_event_runner_deployments.go Tests", func() {
 				eventResourceNamespace:      gitopsDepl.Namespace,
 				workspaceClient:             k8sClient,
 				log:                         log.FromContext(context.Background()),
-				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(),
+				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(nil),
 				workspaceID:                 string(namespace.UID),
 				testOnlySkipCreateOperation: true,
 				k8sClientFactory:            mockK8sClientFactory,
@@ -93,7 +93,7 @@ var _ = Describe("Test for Gitopsdeployment metrics counter", func() {
 				eventResourceNamespace:      gitopsDepl.Namespace,
 				workspaceClient:             k8sClient,
 				log:                         log.FromContext(context.Background()),
-				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(),
+				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(nil),
 				workspaceID:                 workspaceID,
 				testOnlySkipCreateOperation: true,
 				k8sClientFactory: MockSRLK8sClientFactory{
@@ -192,7 +192,7 @@ var _ = Describe("Test for Gitopsdeployment metrics counter", func() {
 				eventResourceNamespace:      gitopsDepl.Namespace,
 				workspaceClient:             k8sClient,
 				log:                         log.FromContext(context.Background()),
-				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(),
+				sharedResourceEventLoop:     shared_resource_loop.NewSharedResourceLoop(nil),
 				workspaceID:                 workspaceID,
 				testOnlySkipCreateOperation: true,
 				k8sClientFactory: MockSRLK8sClientFactory{
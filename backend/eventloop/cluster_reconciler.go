@@ -45,14 +45,14 @@ func NewClusterReconciler(client client.Client, discoveryClient discovery.Discov
 	}
 }
 
-//+kubebuilder:rbac:groups="",resources=persistentvolumeclaims;persistentvolumes;secrets;configmaps;pods;endpoints;services;serviceaccounts,verbs=get;list;delete
-//+kubebuilder:rbac:groups="apps",resources=replicasets;statefulsets;daemonsets;deployments,verbs=get;list;delete
-//+kubebuilder:rbac:groups="discovery.k8s.io",resources=endpointslices,verbs=get;list;delete
-//+kubebuilder:rbac:groups="networking.k8s.io",resources=ingresses;ingressclasses,verbs=get;list;delete
-//+kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources=rolebindings;roles,verbs=get;list;delete
-//+kubebuilder:rbac:groups="route.openshift.io",resources=routes,verbs=get;list;delete
-//+kubebuilder:rbac:groups="triggers.tekton.dev",resources=eventlisteners;triggertemplates,verbs=get;list;delete
-//+kubebuilder:rbac:groups="pipelinesascode.tekton.dev",resources=repositories,verbs=get;list;delete
+//+kubebuilder:rbac:groups="",resources=persistentvolumeclaims;persistentvolumes;secrets;configmaps;pods;endpoints;services;serviceaccounts,verbs=get;list;delete;patch
+//+kubebuilder:rbac:groups="apps",resources=replicasets;statefulsets;daemonsets;deployments,verbs=get;list;delete;patch
+//+kubebuilder:rbac:groups="discovery.k8s.io",resources=endpointslices,verbs=get;list;delete;patch
+//+kubebuilder:rbac:groups="networking.k8s.io",resources=ingresses;ingressclasses,verbs=get;list;delete;patch
+//+kubebuilder:rbac:groups="rbac.authorization.k8s.io",resources=rolebindings;roles,verbs=get;list;delete;patch
+//+kubebuilder:rbac:groups="route.openshift.io",resources=routes,verbs=get;list;delete;patch
+//+kubebuilder:rbac:groups="triggers.tekton.dev",resources=eventlisteners;triggertemplates,verbs=get;list;delete;patch
+//+kubebuilder:rbac:groups="pipelinesascode.tekton.dev",resources=repositories,verbs=get;list;delete;patch
 
 func (c *ClusterReconciler) Start() {
 	go func() {
@@ -95,6 +95,13 @@ func (c *ClusterReconciler) cleanOrphanedResources(ctx context.Context, logParam
 		return
 	}
 
+	// The resources of a suspended GitOpsDeployment are never deleted, even if they appear to be orphaned.
+	suspendedDeploymentUIDs, err := getUIDsOfSuspendedGitOpsDeployments(ctx, c.client)
+	if err != nil {
+		logParam.Error(err, "failed to list suspended GitOpsDeployments")
+		return
+	}
+
 	for i, obj := range apiObjects {
 
 		log := logParam.WithValues(
@@ -124,6 +131,12 @@ func (c *ClusterReconciler) cleanOrphanedResources(ctx context.Context, logParam
 			continue
 		}
 
+		// Skip resources that were intentionally orphaned, by deleting their GitOpsDeployment with the 'Orphan' deletion policy
+		if obj.GetAnnotations()[managedgitopsv1alpha1.OrphanedResourceAnnotation] == "true" {
+			log.V(logutil.LogLevel_Debug).Info("Skipping resource of a GitOpsDeployment that was deleted with the Orphan deletion policy")
+			continue
+		}
+
 		// Check if a GitOpsDeployment exists with the UID specified in the Application name.
 		gitopsDeplList := &managedgitopsv1alpha1.GitOpsDeploymentList{}
 
@@ -140,6 +153,11 @@ func (c *ClusterReconciler) cleanOrphanedResources(ctx context.Context, logParam
 			continue
 		}
 
+		if _, suspended := suspendedDeploymentUIDs[expectedUID]; suspended {
+			log.V(logutil.LogLevel_Debug).Info("Skipping resource of a suspended GitOpsDeployment")
+			continue
+		}
+
 		found := false
 		for _, gitopsDepl := range gitopsDeplList.Items {
 			if string(gitopsDepl.UID) == expectedUID {
@@ -164,6 +182,25 @@ func (c *ClusterReconciler) cleanOrphanedResources(ctx context.Context, logParam
 	}
 }
 
+// getUIDsOfSuspendedGitOpsDeployments returns the UIDs of all GitOpsDeployments on the cluster that have .spec.suspend set.
+// - map key: GitOpsDeployment UID, map value: unused
+func getUIDsOfSuspendedGitOpsDeployments(ctx context.Context, k8sClient client.Client) (map[string]any, error) {
+
+	gitopsDeplList := &managedgitopsv1alpha1.GitOpsDeploymentList{}
+	if err := k8sClient.List(ctx, gitopsDeplList); err != nil {
+		return nil, err
+	}
+
+	res := map[string]any{}
+	for _, gitopsDepl := range gitopsDeplList.Items {
+		if gitopsDepl.Spec.Suspend {
+			res[string(gitopsDepl.UID)] = nil
+		}
+	}
+
+	return res, nil
+}
+
 // getAllNamespacedAPIResources returns all namespace scoped resources from a Kubernetes cluster.
 func (c *ClusterReconciler) getAllNamespacedAPIResources(ctx context.Context, log logr.Logger, opts ...client.ListOption) ([]unstructured.Unstructured, error) {
 	apiResourceList, err := c.discoveryClient.ServerPreferredNamespacedResources()
@@ -184,6 +184,37 @@ var _ = Describe("ClusterReconciler tests", func() {
 			server.Close()
 		})
 
+		It("should return only the UIDs of suspended GitOpsDeployments, from all namespaces", func() {
+
+			suspendedGitOpsDepl := &managedgitopsv1alpha1.GitOpsDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "suspended",
+					Namespace: namespace.Name,
+					UID:       uuid.NewUUID(),
+				},
+				Spec: managedgitopsv1alpha1.GitOpsDeploymentSpec{
+					Suspend: true,
+				},
+			}
+			err := k8sClient.Create(ctx, suspendedGitOpsDepl)
+			Expect(err).ToNot(HaveOccurred())
+
+			gitopsDepl := &managedgitopsv1alpha1.GitOpsDeployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "not-suspended",
+					Namespace: "my-user",
+					UID:       uuid.NewUUID(),
+				},
+			}
+			err = k8sClient.Create(ctx, gitopsDepl)
+			Expect(err).ToNot(HaveOccurred())
+
+			suspendedUIDs, err := getUIDsOfSuspendedGitOpsDeployments(ctx, k8sClient)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(suspendedUIDs).To(HaveLen(1))
+			Expect(suspendedUIDs).To(HaveKey(string(suspendedGitOpsDepl.UID)))
+		})
+
 		It("should delete orphaned resources", func() {
 
 			Skip("skip due to API changes")
@@ -330,6 +361,30 @@ var _ = Describe("ClusterReconciler tests", func() {
 			Expect(err).ToNot(HaveOccurred())
 		})
 
+		It("should not delete a resource that was orphaned by the Orphan deletion policy of its GitOpsDeployment", func() {
+
+			namespacedObj := &corev1.Service{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-1",
+					Namespace: namespace.Name,
+					Labels: map[string]string{
+						"app.kubernetes.io/instance": "gitopsdepl-08745631-43fe-41c3-9bd8-a2cf347a04c2",
+					},
+					Annotations: map[string]string{
+						managedgitopsv1alpha1.OrphanedResourceAnnotation: "true",
+					},
+				},
+			}
+
+			err := k8sClient.Create(ctx, namespacedObj)
+			Expect(err).ToNot(HaveOccurred())
+
+			reconciler.cleanOrphanedResources(ctx, logger)
+
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(namespacedObj), namespacedObj)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
 		It("should not delete an orphaned PersisentVolumeClaim", func() {
 
 			Skip("skip due to API changes")
@@ -5,6 +5,7 @@ import (
 
 	logutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/log"
 	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/eventlooptypes"
+	"github.com/redhat-appstudio/managed-gitops/backend/events"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 )
 
@@ -26,10 +27,10 @@ type ControllerEventLoop struct {
 	EventLoopInputChannel chan eventlooptypes.EventLoopEvent
 }
 
-func NewControllerEventLoop() *ControllerEventLoop {
+func NewControllerEventLoop(eventRecorder *events.Recorder) *ControllerEventLoop {
 
 	channel := make(chan eventlooptypes.EventLoopEvent)
-	go controllerEventLoopRouter(channel, defaultWorkspaceEventLoopRouterFactory{})
+	go controllerEventLoopRouter(channel, defaultWorkspaceEventLoopRouterFactory{eventRecorder: eventRecorder})
 
 	res := &ControllerEventLoop{
 		EventLoopInputChannel: channel,
@@ -117,12 +118,14 @@ type workspaceEventLoopRouterFactory interface {
 }
 
 type defaultWorkspaceEventLoopRouterFactory struct {
+	// eventRecorder records K8s Events on the GitOps API resources, and is shared by all the workspace event loops
+	eventRecorder *events.Recorder
 }
 
 var _ workspaceEventLoopRouterFactory = defaultWorkspaceEventLoopRouterFactory{}
 
 func (d defaultWorkspaceEventLoopRouterFactory) startWorkspaceEventLoopRouter(namespaceName string, namespaceID string) WorkspaceEventLoopRouterStruct {
 
-	return newWorkspaceEventLoopRouter(namespaceName, namespaceID)
+	return newWorkspaceEventLoopRouter(namespaceName, namespaceID, d.eventRecorder)
 
 }
@@ -3,6 +3,8 @@ package eventloop
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -19,6 +21,7 @@ import (
 	logutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/log"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/operations"
 	sharedresourceloop "github.com/redhat-appstudio/managed-gitops/backend/eventloop/shared_resource_loop"
+	corev1 "k8s.io/api/core/v1"
 	apierr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -28,6 +31,7 @@ const (
 	defaultDatabaseReconcilerInterval = 30 * time.Minute // Interval in Minutes to reconcile Database.
 	sleepIntervalsOfBatches           = 1 * time.Second  // Interval in Millisecond between each batch.
 	waitTimeforRowDelete              = 1 * time.Hour    // Number of hours to wait before deleting DB row
+	syncRunGarbageCollectionInterval  = 5 * time.Minute  // Interval between each garbage collection of finished GitOpsDeploymentSyncRuns
 )
 
 // A 'dangling' DB entry (for lack of a better term) is a row in the database that points to a K8s resource that no longer exists
@@ -66,6 +70,10 @@ func (r *DatabaseReconciler) StartDatabaseReconciler() {
 	} else {
 		log.Info("Database reconciliation has been disabled")
 	}
+
+	// Finished GitOpsDeploymentSyncRuns are garbage collected independently of the self-healing interval, as users rely on
+	// the garbage collection to enforce the .spec.ttlSecondsAfterFinished of their GitOpsDeploymentSyncRuns.
+	r.startTimerForNextSyncRunGarbageCollection(ctx, syncRunGarbageCollectionInterval, log)
 }
 
 func (r *DatabaseReconciler) startTimerForNextCycle(ctx context.Context, databaseReconcilerInterval time.Duration, log logr.Logger) {
@@ -347,6 +355,12 @@ func cleanOrphanedEntriesfromTable_ACTDM(ctx context.Context, dbQueries db.Datab
 				if err := cleanOrphanedEntriesfromTable_ACTDM_GitOpsDeploymentSyncRun(ctx, client, dbQueries, apiCrToDbMappingFromDB, objectMeta, log); err != nil && res == nil {
 					res = err
 				}
+			} else if db.APICRToDatabaseMapping_ResourceType_GitOpsDeploymentDiff == apiCrToDbMappingFromDB.APIResourceType {
+
+				// Process if CR is of GitOpsDeploymentDiff type.
+				if err := cleanOrphanedEntriesfromTable_ACTDM_GitOpsDeploymentDiff(ctx, client, dbQueries, apiCrToDbMappingFromDB, objectMeta, log); err != nil && res == nil {
+					res = err
+				}
 			} else {
 				log.Error(nil, "SEVERE: unrecognized APIResourceType", "resourceType", apiCrToDbMappingFromDB.APIResourceType)
 				if res == nil {
@@ -500,6 +514,35 @@ func cleanOrphanedEntriesfromTable_ACTDM_GitOpsDeploymentSyncRun(ctx context.Con
 	return createOperation(ctx, applicationDb.Engine_instance_inst_id, syncOperationDb.SyncOperation_id, syncRunK8s.Namespace, db.OperationResourceType_SyncOperation, dbQueries, client, log)
 }
 
+func cleanOrphanedEntriesfromTable_ACTDM_GitOpsDeploymentDiff(ctx context.Context, client client.Client, dbQueries db.DatabaseQueries, apiCrToDbMappingFromDB db.APICRToDatabaseMapping, objectMeta metav1.ObjectMeta, log logr.Logger) error {
+	// Process if CR is of GitOpsDeploymentDiff type.
+	diffK8s := managedgitopsv1alpha1.GitOpsDeploymentDiff{ObjectMeta: objectMeta}
+
+	// Check if required CR is present in cluster
+	if isOrphaned, err := isRowOrphaned(ctx, client, apiCrToDbMappingFromDB, &diffK8s, log); err != nil {
+		return err
+	} else if !isOrphaned {
+		return nil
+	}
+
+	// If CR is not present in cluster clean ACTDM entry
+	if err := deleteDbEntry(ctx, apiCrToDbMappingFromDB.DBRelationKey, dbType_APICRToDatabaseMapping, apiCrToDbMappingFromDB, dbQueries, log); err != nil {
+		log.Error(err, "Error occurred in cleanOrphanedEntriesfromTable_ACTDM_GitOpsDeploymentDiff while deleting APICRToDatabaseMapping entry: "+apiCrToDbMappingFromDB.DBRelationKey+" from DB.")
+		return fmt.Errorf("error occurred in cleanOrphanedEntriesfromTable_ACTDM_GitOpsDeploymentDiff while deleting APICRToDatabaseMapping entry: %w", err)
+	}
+
+	// Clean DiffOperation table entry: unlike a SyncOperation, the cluster-agent has nothing to clean up for a
+	// DiffOperation, so no Operation is required.
+	if err := deleteDbEntry(ctx, apiCrToDbMappingFromDB.DBRelationKey, dbType_DiffOperation, diffK8s, dbQueries, log); err != nil {
+		log.Error(err, "Error occurred in cleanOrphanedEntriesfromTable_ACTDM_GitOpsDeploymentDiff while deleting DiffOperation entry: "+apiCrToDbMappingFromDB.DBRelationKey+" from DB.")
+		return fmt.Errorf("error occurred in cleanOrphanedEntriesfromTable_ACTDM_GitOpsDeploymentDiff while deleting DiffOperation entry: %w", err)
+	}
+
+	log.Info("Managed-gitops clean up job for DB deleted DiffOperation: " + apiCrToDbMappingFromDB.DBRelationKey + " entry from DB.")
+
+	return nil
+}
+
 // isRowOrphaned function checks if the given CR pointed by APICRToDBMapping is present in the cluster.
 func isRowOrphaned(ctx context.Context, k8sClient client.Client, apiCrToDbMapping db.APICRToDatabaseMapping, obj client.Object, logger logr.Logger) (bool, error) {
 
@@ -532,6 +575,7 @@ const (
 	dbType_DeploymentToApplicationMapping dbTableName = "DeploymentToApplicationMapping"
 	dbType_Application                    dbTableName = "Application"
 	dbType_SyncOperation                  dbTableName = "SyncOperation"
+	dbType_DiffOperation                  dbTableName = "DiffOperation"
 	dbType_APICRToDatabaseMapping         dbTableName = "APICRToDatabaseMapping"
 	dbType_ManagedEnvironment             dbTableName = "ManagedEnvironment"
 	dbType_Operation                      dbTableName = "Operation"
@@ -563,9 +607,20 @@ func deleteDbEntry(ctx context.Context, id string, dbRow dbTableName, t interfac
 	case dbType_ApplicationOwner:
 		rowsDeleted, err = dbQueries.DeleteApplicationOwner(ctx, id)
 	case dbType_Application:
+		// The DeploymentHistory and DiffOperation rows of the Application must be deleted first, due to the foreign key constraint
+		if _, err := dbQueries.DeleteDeploymentHistoryByApplicationId(ctx, id); err != nil {
+			log.Error(err, "error occurred while deleting DeploymentHistory rows of Application", "applicationID", id)
+			return err
+		}
+		if _, err := dbQueries.DeleteDiffOperationsByApplicationId(ctx, id); err != nil {
+			log.Error(err, "error occurred while deleting DiffOperation rows of Application", "applicationID", id)
+			return err
+		}
 		rowsDeleted, err = dbQueries.DeleteApplicationById(ctx, id)
 	case dbType_SyncOperation:
 		rowsDeleted, err = dbQueries.DeleteSyncOperationById(ctx, id)
+	case dbType_DiffOperation:
+		rowsDeleted, err = dbQueries.DeleteDiffOperationById(ctx, id)
 	case dbType_Operation:
 		rowsDeleted, err = dbQueries.DeleteOperationById(ctx, id)
 	case dbType_APICRToDatabaseMapping:
@@ -1642,3 +1697,149 @@ func createOperation(ctx context.Context, gitopsengineinstanceId, resourceId, na
 
 	return nil
 }
+
+///////////////
+// Garbage collection of finished GitOpsDeploymentSyncRuns.
+// This will delete GitOpsDeploymentSyncRun CRs once their .spec.ttlSecondsAfterFinished has elapsed, or once they exceed
+// the history limit of their Namespace. The database entries they relate to (SyncOperation and ACTDM) are then cleaned up
+// by the application event loop, when it processes the deletion.
+///////////////
+
+func (r *DatabaseReconciler) startTimerForNextSyncRunGarbageCollection(ctx context.Context, interval time.Duration, log logr.Logger) {
+	go func() {
+		// Timer to trigger the garbage collection
+		timer := time.NewTimer(interval)
+		<-timer.C
+
+		_, _ = sharedutil.CatchPanic(func() error {
+			if err := garbageCollectFinishedSyncRuns(ctx, r.Client, time.Now(), log); err != nil {
+				log.Error(err, "error from startTimerForNextSyncRunGarbageCollection")
+			}
+			return nil
+		})
+
+		// Kick off the timer again, once the old task runs.
+		r.startTimerForNextSyncRunGarbageCollection(ctx, interval, log)
+	}()
+}
+
+// garbageCollectFinishedSyncRuns deletes the finished GitOpsDeploymentSyncRuns of each GitOpsDeployment that have expired:
+// either because their .spec.ttlSecondsAfterFinished has elapsed, or because they exceed the history limit that is set
+// on their Namespace via the SyncRunHistoryLimitAnnotation.
+func garbageCollectFinishedSyncRuns(ctx context.Context, k8sClient client.Client, now time.Time, l logr.Logger) error {
+
+	log := l.WithValues(sharedutil.Log_JobKey, sharedutil.Log_JobKeyValue).
+		WithValues(sharedutil.Log_JobTypeKey, "SyncRun_GC")
+
+	var syncRunList managedgitopsv1alpha1.GitOpsDeploymentSyncRunList
+	if err := k8sClient.List(ctx, &syncRunList); err != nil {
+		log.Error(err, "unable to list GitOpsDeploymentSyncRuns")
+		return fmt.Errorf("unable to list GitOpsDeploymentSyncRuns: %w", err)
+	}
+
+	// Group the finished GitOpsDeploymentSyncRuns by Namespace, and then by GitOpsDeployment
+	finishedSyncRuns := map[string]map[string][]managedgitopsv1alpha1.GitOpsDeploymentSyncRun{}
+	for _, syncRun := range syncRunList.Items {
+
+		if syncRun.DeletionTimestamp != nil || !syncRun.Status.IsFinished() {
+			continue
+		}
+
+		if _, exists := finishedSyncRuns[syncRun.Namespace]; !exists {
+			finishedSyncRuns[syncRun.Namespace] = map[string][]managedgitopsv1alpha1.GitOpsDeploymentSyncRun{}
+		}
+
+		syncRunsOfDeployment := finishedSyncRuns[syncRun.Namespace]
+		syncRunsOfDeployment[syncRun.Spec.GitopsDeploymentName] = append(syncRunsOfDeployment[syncRun.Spec.GitopsDeploymentName], syncRun)
+	}
+
+	var res error
+
+	for namespace, syncRunsOfDeployment := range finishedSyncRuns {
+
+		historyLimit := getSyncRunHistoryLimitOfNamespace(ctx, k8sClient, namespace, log)
+
+		for _, syncRuns := range syncRunsOfDeployment {
+
+			for _, syncRun := range getExpiredSyncRuns(syncRuns, historyLimit, now) {
+
+				if err := deleteFinishedSyncRun(ctx, k8sClient, syncRun, log); err != nil && res == nil {
+					res = err
+				}
+			}
+		}
+	}
+
+	return res
+}
+
+// getSyncRunHistoryLimitOfNamespace returns the value of the SyncRunHistoryLimitAnnotation of the Namespace,
+// or -1 if the Namespace does not have a valid history limit.
+func getSyncRunHistoryLimitOfNamespace(ctx context.Context, k8sClient client.Client, namespaceName string, log logr.Logger) int {
+
+	namespace := corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespaceName}}
+	if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(&namespace), &namespace); err != nil {
+		log.Error(err, "unable to retrieve Namespace of GitOpsDeploymentSyncRuns", "namespace", namespaceName)
+		return -1
+	}
+
+	value, exists := namespace.Annotations[managedgitopsv1alpha1.SyncRunHistoryLimitAnnotation]
+	if !exists {
+		return -1
+	}
+
+	historyLimit, err := strconv.Atoi(value)
+	if err != nil || historyLimit < 0 {
+		log.Info("ignoring invalid history limit of Namespace", "namespace", namespaceName, "value", value)
+		return -1
+	}
+
+	return historyLimit
+}
+
+// getExpiredSyncRuns returns the finished GitOpsDeploymentSyncRuns of a single GitOpsDeployment that should be deleted:
+// - those whose .spec.ttlSecondsAfterFinished has elapsed, and
+// - if historyLimit is not negative, those that are older than the 'historyLimit' most recently finished GitOpsDeploymentSyncRuns.
+//
+// GitOpsDeploymentSyncRuns that roll back a GitOpsDeployment are never deleted due to the history limit, as deleting
+// them resumes automated sync of the GitOpsDeployment: they are only deleted once their TTL has elapsed.
+func getExpiredSyncRuns(syncRuns []managedgitopsv1alpha1.GitOpsDeploymentSyncRun, historyLimit int, now time.Time) []managedgitopsv1alpha1.GitOpsDeploymentSyncRun {
+
+	// Sort by finish time, most recent first
+	sort.SliceStable(syncRuns, func(i, j int) bool {
+		return syncRuns[j].Status.FinishedAt.Before(syncRuns[i].Status.FinishedAt)
+	})
+
+	var res []managedgitopsv1alpha1.GitOpsDeploymentSyncRun
+
+	for i, syncRun := range syncRuns {
+
+		ttl := syncRun.Spec.TTLSecondsAfterFinished
+
+		if ttl != nil && !now.Before(syncRun.Status.FinishedAt.Add(time.Duration(*ttl)*time.Second)) {
+			res = append(res, syncRun)
+
+		} else if historyLimit >= 0 && i >= historyLimit && !syncRun.Spec.IsRollback() {
+			res = append(res, syncRun)
+		}
+	}
+
+	return res
+}
+
+// deleteFinishedSyncRun deletes a GitOpsDeploymentSyncRun CR. The database entries of the CR are not deleted here: the
+// deletion is processed by the application event loop like any other deletion of a GitOpsDeploymentSyncRun, which cleans
+// up the SyncOperation and APICRToDatabaseMapping, and (for a rollback) resumes automated sync of the GitOpsDeployment.
+func deleteFinishedSyncRun(ctx context.Context, k8sClient client.Client, syncRun managedgitopsv1alpha1.GitOpsDeploymentSyncRun, l logr.Logger) error {
+
+	log := l.WithValues(logutil.Log_K8s_Request_Name, syncRun.Name, logutil.Log_K8s_Request_Namespace, syncRun.Namespace)
+
+	if err := k8sClient.Delete(ctx, &syncRun); err != nil && !apierr.IsNotFound(err) {
+		log.Error(err, "unable to delete finished GitOpsDeploymentSyncRun")
+		return fmt.Errorf("unable to delete finished GitOpsDeploymentSyncRun: %w", err)
+	}
+
+	log.Info("Deleted finished GitOpsDeploymentSyncRun", "finishedAt", syncRun.Status.FinishedAt)
+
+	return nil
+}
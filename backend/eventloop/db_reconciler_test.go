@@ -22,6 +22,7 @@ import (
 	sharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util"
 	sharedoperations "github.com/redhat-appstudio/managed-gitops/backend-shared/util/operations"
 	corev1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	logger "sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -2472,3 +2473,175 @@ func (f MockSRLK8sClientFactory) GetK8sClientForGitOpsEngineInstance(ctx context
 func (f MockSRLK8sClientFactory) GetK8sClientForServiceWorkspace() (client.Client, error) {
 	return f.fakeClient, nil
 }
+
+var _ = Describe("GitOpsDeploymentSyncRun garbage collection tests", func() {
+
+	newFinishedSyncRun := func(name string, finishedAt time.Time) managedgitopsv1alpha1.GitOpsDeploymentSyncRun {
+		finishedAtTime := metav1.NewTime(finishedAt.Truncate(time.Second))
+		return managedgitopsv1alpha1.GitOpsDeploymentSyncRun{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "my-user",
+				UID:       uuid.NewUUID(),
+			},
+			Spec: managedgitopsv1alpha1.GitOpsDeploymentSyncRunSpec{
+				GitopsDeploymentName: "test-app",
+			},
+			Status: managedgitopsv1alpha1.GitOpsDeploymentSyncRunStatus{
+				Phase:      managedgitopsv1alpha1.SyncRunPhase_Succeeded,
+				FinishedAt: &finishedAtTime,
+			},
+		}
+	}
+
+	namesOf := func(syncRuns []managedgitopsv1alpha1.GitOpsDeploymentSyncRun) []string {
+		res := []string{}
+		for _, syncRun := range syncRuns {
+			res = append(res, syncRun.Name)
+		}
+		return res
+	}
+
+	Context("Testing getExpiredSyncRuns function.", func() {
+
+		now := time.Now()
+
+		It("should not return any GitOpsDeploymentSyncRuns if there is no TTL or history limit", func() {
+			syncRuns := []managedgitopsv1alpha1.GitOpsDeploymentSyncRun{
+				newFinishedSyncRun("old", now.Add(-48*time.Hour)),
+				newFinishedSyncRun("new", now.Add(-1*time.Minute)),
+			}
+
+			Expect(getExpiredSyncRuns(syncRuns, -1, now)).To(BeEmpty())
+		})
+
+		It("should return the GitOpsDeploymentSyncRuns whose TTL has elapsed", func() {
+			expired := newFinishedSyncRun("expired", now.Add(-2*time.Hour))
+			ttl := int32(3600)
+			expired.Spec.TTLSecondsAfterFinished = &ttl
+
+			notExpired := newFinishedSyncRun("not-expired", now.Add(-10*time.Minute))
+			notExpired.Spec.TTLSecondsAfterFinished = &ttl
+
+			res := getExpiredSyncRuns([]managedgitopsv1alpha1.GitOpsDeploymentSyncRun{expired, notExpired}, -1, now)
+			Expect(namesOf(res)).To(Equal([]string{"expired"}))
+		})
+
+		It("should treat a TTL of zero as expiring immediately after the sync finished", func() {
+			syncRun := newFinishedSyncRun("zero-ttl", now)
+			ttl := int32(0)
+			syncRun.Spec.TTLSecondsAfterFinished = &ttl
+
+			res := getExpiredSyncRuns([]managedgitopsv1alpha1.GitOpsDeploymentSyncRun{syncRun}, -1, now)
+			Expect(namesOf(res)).To(Equal([]string{"zero-ttl"}))
+		})
+
+		It("should return all but the most recently finished GitOpsDeploymentSyncRuns when a history limit is set", func() {
+			syncRuns := []managedgitopsv1alpha1.GitOpsDeploymentSyncRun{
+				newFinishedSyncRun("third", now.Add(-3*time.Hour)),
+				newFinishedSyncRun("first", now.Add(-1*time.Hour)),
+				newFinishedSyncRun("fourth", now.Add(-4*time.Hour)),
+				newFinishedSyncRun("second", now.Add(-2*time.Hour)),
+			}
+
+			res := getExpiredSyncRuns(syncRuns, 2, now)
+			Expect(namesOf(res)).To(ConsistOf("third", "fourth"))
+
+			res = getExpiredSyncRuns(syncRuns, 0, now)
+			Expect(namesOf(res)).To(ConsistOf("first", "second", "third", "fourth"))
+		})
+
+		It("should not return rollback GitOpsDeploymentSyncRuns due to the history limit, but should due to their TTL", func() {
+			rollback := newFinishedSyncRun("rollback", now.Add(-2*time.Hour))
+			rollback.Spec.RollbackToRevision = "0a1b2c3d"
+
+			syncRuns := []managedgitopsv1alpha1.GitOpsDeploymentSyncRun{
+				newFinishedSyncRun("latest", now.Add(-1*time.Hour)),
+				rollback,
+			}
+
+			Expect(getExpiredSyncRuns(syncRuns, 1, now)).To(BeEmpty())
+
+			ttl := int32(60)
+			syncRuns[1].Spec.TTLSecondsAfterFinished = &ttl
+
+			res := getExpiredSyncRuns(syncRuns, 1, now)
+			Expect(namesOf(res)).To(Equal([]string{"rollback"}))
+		})
+	})
+
+	Context("Testing garbageCollectFinishedSyncRuns function.", func() {
+
+		var log logr.Logger
+		var ctx context.Context
+		var k8sClient client.WithWatch
+		var apiNamespace *corev1.Namespace
+
+		BeforeEach(func() {
+			scheme,
+				argocdNamespace,
+				kubesystemNamespace,
+				namespace,
+				err := tests.GenericTestSetup()
+			Expect(err).ToNot(HaveOccurred())
+
+			apiNamespace = namespace
+
+			k8sClient = fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(apiNamespace, argocdNamespace, kubesystemNamespace).
+				Build()
+
+			ctx = context.Background()
+			log = logger.FromContext(ctx)
+		})
+
+		It("should delete an expired GitOpsDeploymentSyncRun, but not one that has not finished", func() {
+			now := time.Now()
+
+			expiredSyncRun := newFinishedSyncRun("expired", now.Add(-2*time.Hour))
+			ttl := int32(3600)
+			expiredSyncRun.Spec.TTLSecondsAfterFinished = &ttl
+			Expect(k8sClient.Create(ctx, &expiredSyncRun)).To(Succeed())
+
+			activeSyncRun := newFinishedSyncRun("active", now)
+			activeSyncRun.Status.Phase = managedgitopsv1alpha1.SyncRunPhase_Running
+			activeSyncRun.Status.FinishedAt = nil
+			activeSyncRun.Spec.TTLSecondsAfterFinished = &ttl
+			Expect(k8sClient.Create(ctx, &activeSyncRun)).To(Succeed())
+
+			Expect(garbageCollectFinishedSyncRuns(ctx, k8sClient, now, log)).To(Succeed())
+
+			By("verifying the expired GitOpsDeploymentSyncRun is deleted")
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(&expiredSyncRun), &expiredSyncRun)
+			Expect(apierr.IsNotFound(err)).To(BeTrue())
+
+			By("verifying the GitOpsDeploymentSyncRun that has not finished is not deleted")
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(&activeSyncRun), &activeSyncRun)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("should keep only the most recently finished GitOpsDeploymentSyncRuns when the Namespace has a history limit", func() {
+			now := time.Now()
+
+			By("setting a history limit of 1 on the Namespace")
+			apiNamespace.Annotations = map[string]string{managedgitopsv1alpha1.SyncRunHistoryLimitAnnotation: "1"}
+			err := k8sClient.Update(ctx, apiNamespace)
+			Expect(err).ToNot(HaveOccurred())
+
+			newSyncRun := newFinishedSyncRun("new", now.Add(-1*time.Hour))
+			Expect(k8sClient.Create(ctx, &newSyncRun)).To(Succeed())
+
+			oldSyncRun := newFinishedSyncRun("old", now.Add(-2*time.Hour))
+			Expect(k8sClient.Create(ctx, &oldSyncRun)).To(Succeed())
+
+			Expect(garbageCollectFinishedSyncRuns(ctx, k8sClient, now, log)).To(Succeed())
+
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(&oldSyncRun), &oldSyncRun)
+			Expect(apierr.IsNotFound(err)).To(BeTrue())
+
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(&newSyncRun), &newSyncRun)
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+})
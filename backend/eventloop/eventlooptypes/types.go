@@ -20,6 +20,7 @@ const (
 	RepositoryCredentialModified EventLoopEventType = "RepositoryCredentialModified"
 	ManagedEnvironmentModified   EventLoopEventType = "ManagedEnvironmentModified"
 	SyncRunModified              EventLoopEventType = "SyncRunModified"
+	DiffModified                 EventLoopEventType = "DiffModified"
 	UpdateDeploymentStatusTick   EventLoopEventType = "UpdateDeploymentStatusTick"
 )
 
@@ -122,6 +123,7 @@ type GitOpsResourceType string
 const (
 	GitOpsDeploymentTypeName                     GitOpsResourceType = "GitOpsDeployment"
 	GitOpsDeploymentSyncRunTypeName              GitOpsResourceType = "GitOpsDeploymentSyncRun"
+	GitOpsDeploymentDiffTypeName                 GitOpsResourceType = "GitOpsDeploymentDiff"
 	GitOpsDeploymentRepositoryCredentialTypeName GitOpsResourceType = "GitOpsDeploymentRepositoryCredential"
 	GitOpsDeploymentManagedEnvironmentTypeName   GitOpsResourceType = "GitOpsDeploymentManagedEnvironmentTypeName"
 )
@@ -0,0 +1,298 @@
+package eventloop
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
+	db "github.com/redhat-appstudio/managed-gitops/backend-shared/db"
+	sharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util"
+	logutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/log"
+	sharedresourceloop "github.com/redhat-appstudio/managed-gitops/backend/eventloop/shared_resource_loop"
+	"github.com/redhat-appstudio/managed-gitops/backend/events"
+	"github.com/redhat-appstudio/managed-gitops/backend/metrics"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/flowcontrol"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	managedEnvProberInterval      = 5 * time.Minute // Interval between each run of the managed environment prober.
+	managedEnvProberRowBatchSize  = 100             // Number of rows needs to be fetched in each batch.
+	managedEnvProberBatchInterval = 1 * time.Second // Interval between each batch.
+	managedEnvProbesPerHostPerSec = 1               // Rate at which probes may be sent to a single cluster host.
+	managedEnvProbesPerHostBurst  = 5               // Number of probes that may be sent to a single cluster host, before being rate limited.
+	managedEnvMaxConcurrentProbes = 10              // Maximum number of managed environments that are probed at the same time.
+)
+
+// ManagedEnvironmentProber periodically probes the cluster of each GitOpsDeploymentManagedEnvironment (which has
+// successfully initialized a connection), and updates the 'Reachable' condition and metrics of the managed environment
+// with the result.
+type ManagedEnvironmentProber struct {
+	client.Client
+	DB               db.DatabaseQueries
+	K8sClientFactory sharedresourceloop.SRLK8sClientFactory
+
+	// EventRecorder records K8s Events on the managed environments whose reachability changes
+	EventRecorder *events.Recorder
+
+	mutex sync.Mutex
+
+	// hostRateLimiters rate limits the probes that are sent to each cluster host (API URL), as multiple managed environments
+	// may target the same cluster.
+	hostRateLimiters map[string]flowcontrol.RateLimiter
+
+	// probedManagedEnvs is the set of managed environments that were probed in the previous run, in order to remove the
+	// metrics of managed environments that no longer exist.
+	probedManagedEnvs map[types.NamespacedName]bool
+}
+
+// StartManagedEnvironmentProber starts a goroutine which periodically probes the clusters of managed environments.
+func (p *ManagedEnvironmentProber) StartManagedEnvironmentProber() {
+	p.startTimerForNextCycle()
+}
+
+func (p *ManagedEnvironmentProber) startTimerForNextCycle() {
+	go func() {
+		// Timer to trigger the prober
+		timer := time.NewTimer(managedEnvProberInterval)
+		<-timer.C
+
+		ctx := context.Background()
+		log := log.FromContext(ctx).
+			WithName(logutil.LogLogger_managed_gitops).
+			WithValues(logutil.Log_Component, logutil.Log_Component_Backend_ManagedEnvProber)
+
+		if _, err := sharedutil.CatchPanic(func() error {
+
+			p.probeManagedEnvironments(ctx, log)
+
+			return nil
+		}); err != nil {
+			log.Error(err, "error on managed environment probe")
+		}
+
+		// Kick off the timer again, once the old task runs.
+		// This ensures that at least 'managedEnvProberInterval' time elapses from the end of one run to the beginning of another.
+		p.startTimerForNextCycle()
+	}()
+}
+
+// probeManagedEnvironments probes the cluster of each managed environment, based on the APICRToDatabaseMapping entries
+// of the managed environments.
+func (p *ManagedEnvironmentProber) probeManagedEnvironments(ctx context.Context, logParam logr.Logger) {
+
+	log := logParam.WithValues(sharedutil.Log_JobKey, "probeManagedEnvironments")
+
+	probedManagedEnvs := map[types.NamespacedName]bool{}
+
+	offSet := 0
+
+	// Continuously iterate and fetch batches until all entries of ACTDM table are processed.
+	for {
+		if offSet != 0 {
+			time.Sleep(managedEnvProberBatchInterval)
+		}
+
+		var listOfApiCrToDbMapping []db.APICRToDatabaseMapping
+
+		if err := p.DB.GetAPICRToDatabaseMappingBatch(ctx, &listOfApiCrToDbMapping, managedEnvProberRowBatchSize, offSet); err != nil {
+			log.Error(err, fmt.Sprintf("Error occurred in managed environment prober while fetching batch from Offset: %d to %d: ",
+				offSet, offSet+managedEnvProberRowBatchSize))
+			// Don't remove the metrics of managed environments that we were unable to process
+			return
+		}
+
+		// Break the loop if no entries are left in table to be processed.
+		if len(listOfApiCrToDbMapping) == 0 {
+			break
+		}
+
+		// Probe the managed environments of the batch concurrently (up to managedEnvMaxConcurrentProbes at a time), so that
+		// an unreachable cluster (which only fails once the probe times out) doesn't delay the probes of other clusters.
+		var wg sync.WaitGroup
+		var probedManagedEnvsMutex sync.Mutex
+		probeSlots := make(chan struct{}, managedEnvMaxConcurrentProbes)
+
+		for i := range listOfApiCrToDbMapping {
+			apiCrToDbMapping := listOfApiCrToDbMapping[i] // To avoid "Implicit memory aliasing in for loop." error.
+
+			if apiCrToDbMapping.APIResourceType != db.APICRToDatabaseMapping_ResourceType_GitOpsDeploymentManagedEnvironment {
+				continue
+			}
+
+			probeSlots <- struct{}{}
+			wg.Add(1)
+
+			go func() {
+				defer func() {
+					<-probeSlots
+					wg.Done()
+				}()
+
+				if _, err := sharedutil.CatchPanic(func() error {
+
+					if p.probeManagedEnvironment(ctx, apiCrToDbMapping, log) {
+						probedManagedEnvsMutex.Lock()
+						defer probedManagedEnvsMutex.Unlock()
+						probedManagedEnvs[types.NamespacedName{Namespace: apiCrToDbMapping.APIResourceNamespace, Name: apiCrToDbMapping.APIResourceName}] = true
+					}
+
+					return nil
+				}); err != nil {
+					log.Error(err, "error on probe of managed environment", "managedEnvName", apiCrToDbMapping.APIResourceName)
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		// Skip processed entries in next iteration
+		offSet += managedEnvProberRowBatchSize
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	// Remove the metrics of managed environments that are no longer probed
+	for managedEnv := range p.probedManagedEnvs {
+		if !probedManagedEnvs[managedEnv] {
+			metrics.RemoveManagedEnvironmentProbeResult(managedEnv.Name, managedEnv.Namespace)
+		}
+	}
+	p.probedManagedEnvs = probedManagedEnvs
+}
+
+// probeManagedEnvironment probes the cluster of a single managed environment, and updates the managed environment with the result.
+// Returns true if the managed environment is (still) probed, or false otherwise (for example, if it no longer exists).
+func (p *ManagedEnvironmentProber) probeManagedEnvironment(ctx context.Context, apiCrToDbMapping db.APICRToDatabaseMapping, logParam logr.Logger) bool {
+
+	managedEnvCR := managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      apiCrToDbMapping.APIResourceName,
+			Namespace: apiCrToDbMapping.APIResourceNamespace,
+		},
+	}
+
+	log := logParam.WithValues("managedEnvName", managedEnvCR.Name, "managedEnvNamespace", managedEnvCR.Namespace)
+
+	if err := p.Client.Get(ctx, client.ObjectKeyFromObject(&managedEnvCR), &managedEnvCR); err != nil {
+		log.V(logutil.LogLevel_Debug).Info("unable to retrieve GitOpsDeploymentManagedEnvironment, skipping probe", "error", err.Error())
+		return false
+	}
+
+	if string(managedEnvCR.UID) != apiCrToDbMapping.APIResourceUID {
+		// The APICRToDatabaseMapping refers to an older resource of the same name
+		return false
+	}
+
+	// Only managed environments which have successfully initialized a connection are probed: the condition of other
+	// managed environments already reports why the connection could not be initialized.
+	if !meta.IsStatusConditionTrue(managedEnvCR.Status.Conditions, managedgitopsv1alpha1.ManagedEnvironmentStatusConnectionInitializationSucceeded) {
+		return false
+	}
+
+	managedEnv := db.ManagedEnvironment{Managedenvironment_id: apiCrToDbMapping.DBRelationKey}
+	if err := p.DB.GetManagedEnvironmentById(ctx, &managedEnv); err != nil {
+		log.V(logutil.LogLevel_Debug).Info("unable to retrieve ManagedEnvironment, skipping probe", "error", err.Error())
+		return false
+	}
+
+	clusterCreds := db.ClusterCredentials{Clustercredentials_cred_id: managedEnv.Clustercredentials_id}
+	if err := p.DB.GetClusterCredentialsById(ctx, &clusterCreds); err != nil {
+		log.V(logutil.LogLevel_Debug).Info("unable to retrieve ClusterCredentials, skipping probe", "error", err.Error())
+		return false
+	}
+
+	// Probes are delayed, rather than skipped, when the cluster host is rate limited: otherwise, when many managed
+	// environments target the same host, the same managed environments would be skipped on every run.
+	if err := p.waitForProbeOfHost(ctx, clusterCreds.Host); err != nil {
+		log.V(logutil.LogLevel_Debug).Info("unable to wait for the rate limiter of the cluster host, skipping probe", "host", clusterCreds.Host, "error", err.Error())
+		// The managed environment is still probed, it has just been skipped for this run
+		return true
+	}
+
+	probeResult := sharedresourceloop.ProbeManagedEnvironmentCluster(ctx, clusterCreds, p.K8sClientFactory)
+	probeTime := metav1.Now()
+
+	metrics.SetManagedEnvironmentProbeResult(managedEnvCR.Name, managedEnvCR.Namespace, probeResult.Status == metav1.ConditionTrue, probeTime.Time)
+
+	if err := updateManagedEnvironmentReachableCondition(ctx, p.Client, managedEnvCR, probeResult, probeTime, p.EventRecorder, log); err != nil {
+		log.Error(err, "unable to update the Reachable condition of GitOpsDeploymentManagedEnvironment")
+	}
+
+	return true
+}
+
+// waitForProbeOfHost blocks until a probe may be sent to the given cluster host, based on the rate limiter of the host.
+// Returns an error if the context is done before then.
+func (p *ManagedEnvironmentProber) waitForProbeOfHost(ctx context.Context, host string) error {
+	return p.getRateLimiterOfHost(host).Wait(ctx)
+}
+
+func (p *ManagedEnvironmentProber) getRateLimiterOfHost(host string) flowcontrol.RateLimiter {
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.hostRateLimiters == nil {
+		p.hostRateLimiters = map[string]flowcontrol.RateLimiter{}
+	}
+
+	key := strings.ToLower(host)
+
+	rateLimiter, exists := p.hostRateLimiters[key]
+	if !exists {
+		rateLimiter = flowcontrol.NewTokenBucketRateLimiter(managedEnvProbesPerHostPerSec, managedEnvProbesPerHostBurst)
+		p.hostRateLimiters[key] = rateLimiter
+	}
+
+	return rateLimiter
+}
+
+// updateManagedEnvironmentReachableCondition updates the 'Reachable' condition, and last probe time, of the managed environment
+// to match the result of the probe.
+// - The managed environment is only updated if the result of the probe differs from the existing condition: otherwise,
+// every managed environment would be updated on every run of the prober.
+func updateManagedEnvironmentReachableCondition(ctx context.Context, k8sClient client.Client,
+	managedEnvCR managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironment, probeResult sharedresourceloop.ManagedEnvironmentProbeResult,
+	probeTime metav1.Time, eventRecorder *events.Recorder, log logr.Logger) error {
+
+	const conditionType = managedgitopsv1alpha1.ManagedEnvironmentStatusReachable
+
+	existingCondition := meta.FindStatusCondition(managedEnvCR.Status.Conditions, conditionType)
+	if existingCondition != nil && existingCondition.Status == probeResult.Status &&
+		existingCondition.Reason == string(probeResult.Reason) && existingCondition.Message == probeResult.Message {
+		return nil
+	}
+
+	meta.SetStatusCondition(&managedEnvCR.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             probeResult.Status,
+		Reason:             string(probeResult.Reason),
+		Message:            probeResult.Message,
+		LastTransitionTime: probeTime,
+	})
+	managedEnvCR.Status.LastProbeTime = &probeTime
+
+	if err := k8sClient.Status().Update(ctx, &managedEnvCR); err != nil {
+		return err
+	}
+
+	log.Info("Reachable condition of GitOpsDeploymentManagedEnvironment changed", "status", probeResult.Status, "reason", probeResult.Reason)
+
+	if probeResult.Status == metav1.ConditionTrue {
+		eventRecorder.RecordNormal(&managedEnvCR, conditionType, probeResult.Message)
+	} else {
+		eventRecorder.RecordWarning(&managedEnvCR, string(probeResult.Reason), "The cluster of the managed environment is not reachable: "+probeResult.Message)
+	}
+
+	return nil
+}
@@ -0,0 +1,244 @@
+package eventloop
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
+	db "github.com/redhat-appstudio/managed-gitops/backend-shared/db"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/tests"
+	sharedresourceloop "github.com/redhat-appstudio/managed-gitops/backend/eventloop/shared_resource_loop"
+	"github.com/redhat-appstudio/managed-gitops/backend/metrics"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	logger "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("ManagedEnvironment Prober Tests", func() {
+
+	var log logr.Logger
+	var ctx context.Context
+	var k8sClient client.WithWatch
+	var apiNamespace *corev1.Namespace
+
+	// selfSubjectReviewErr is the error returned by the (fake) managed environment cluster, in response to a SelfSubjectReview
+	var selfSubjectReviewErr error
+
+	BeforeEach(func() {
+		scheme, argocdNamespace, kubesystemNamespace, namespace, err := tests.GenericTestSetup()
+		Expect(err).ToNot(HaveOccurred())
+		apiNamespace = namespace
+
+		selfSubjectReviewErr = nil
+
+		k8sClient = fake.NewClientBuilder().
+			WithScheme(scheme).
+			WithObjects(apiNamespace, argocdNamespace, kubesystemNamespace).
+			WithStatusSubresource(&managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironment{}).
+			WithInterceptorFuncs(interceptor.Funcs{
+				Create: func(ctx context.Context, client client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					if _, ok := obj.(*authenticationv1.SelfSubjectReview); ok {
+						return selfSubjectReviewErr
+					}
+					return client.Create(ctx, obj, opts...)
+				},
+			}).Build()
+
+		ctx = context.Background()
+		log = logger.FromContext(ctx)
+
+		metrics.ClearManagedEnvironmentMetrics()
+	})
+
+	Context("Testing the probeManagedEnvironments function", func() {
+
+		var dbq db.AllDatabaseQueries
+		var managedEnvCR *managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironment
+		var prober *ManagedEnvironmentProber
+
+		BeforeEach(func() {
+			err := db.SetupForTestingDBGinkgo()
+			Expect(err).ToNot(HaveOccurred())
+
+			dbq, err = db.NewUnsafePostgresDBQueries(false, true)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, managedEnvironment, _, _, _, err := db.CreateSampleData(dbq)
+			Expect(err).ToNot(HaveOccurred())
+
+			managedEnvCR = &managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-managed-env",
+					Namespace: apiNamespace.Name,
+					UID:       uuid.NewUUID(),
+				},
+				Spec: managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironmentSpec{
+					APIURL:                   "https://api.my-cluster.com:6443",
+					ClusterCredentialsSecret: "my-secret",
+				},
+			}
+			Expect(k8sClient.Create(ctx, managedEnvCR)).To(Succeed())
+
+			managedEnvCR.Status.Conditions = []metav1.Condition{{
+				Type:               managedgitopsv1alpha1.ManagedEnvironmentStatusConnectionInitializationSucceeded,
+				Status:             metav1.ConditionTrue,
+				Reason:             string(managedgitopsv1alpha1.ConditionReasonSucceeded),
+				LastTransitionTime: metav1.Now(),
+			}}
+			Expect(k8sClient.Status().Update(ctx, managedEnvCR)).To(Succeed())
+
+			apiCRToDBMapping := db.APICRToDatabaseMapping{
+				APIResourceType:      db.APICRToDatabaseMapping_ResourceType_GitOpsDeploymentManagedEnvironment,
+				APIResourceUID:       string(managedEnvCR.UID),
+				APIResourceName:      managedEnvCR.Name,
+				APIResourceNamespace: managedEnvCR.Namespace,
+				NamespaceUID:         string(apiNamespace.UID),
+				DBRelationType:       db.APICRToDatabaseMapping_DBRelationType_ManagedEnvironment,
+				DBRelationKey:        managedEnvironment.Managedenvironment_id,
+			}
+			Expect(dbq.CreateAPICRToDatabaseMapping(ctx, &apiCRToDBMapping)).To(Succeed())
+
+			prober = &ManagedEnvironmentProber{
+				Client:           k8sClient,
+				DB:               dbq,
+				K8sClientFactory: MockSRLK8sClientFactory{fakeClient: k8sClient},
+			}
+		})
+
+		AfterEach(func() {
+			dbq.CloseDatabase()
+		})
+
+		It("should set the Reachable condition and metrics of a managed environment, based on the result of the probe", func() {
+
+			By("probing a managed environment whose cluster is reachable")
+			prober.probeManagedEnvironments(ctx, log)
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(managedEnvCR), managedEnvCR)).To(Succeed())
+			condition := meta.FindStatusCondition(managedEnvCR.Status.Conditions, managedgitopsv1alpha1.ManagedEnvironmentStatusReachable)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(managedEnvCR.Status.LastProbeTime).ToNot(BeNil())
+			Expect(testutil.ToFloat64(metrics.ManagedEnvironmentReachable.WithLabelValues(managedEnvCR.Name, managedEnvCR.Namespace))).To(Equal(1.0))
+
+			By("probing the managed environment once its token has been revoked")
+			selfSubjectReviewErr = k8serrors.NewUnauthorized("token revoked")
+
+			prober.probeManagedEnvironments(ctx, log)
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(managedEnvCR), managedEnvCR)).To(Succeed())
+			condition = meta.FindStatusCondition(managedEnvCR.Status.Conditions, managedgitopsv1alpha1.ManagedEnvironmentStatusReachable)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(string(managedgitopsv1alpha1.ConditionReasonInvalidCredentials)))
+			Expect(testutil.ToFloat64(metrics.ManagedEnvironmentReachable.WithLabelValues(managedEnvCR.Name, managedEnvCR.Namespace))).To(Equal(0.0))
+
+			By("removing the metrics of the managed environment once it has been deleted")
+			Expect(k8sClient.Delete(ctx, managedEnvCR)).To(Succeed())
+			prober.probeManagedEnvironments(ctx, log)
+			Expect(testutil.CollectAndCount(metrics.ManagedEnvironmentReachable)).To(Equal(0))
+		})
+
+		It("should not probe a managed environment that has not successfully initialized a connection", func() {
+
+			managedEnvCR.Status.Conditions[0].Status = metav1.ConditionFalse
+			Expect(k8sClient.Status().Update(ctx, managedEnvCR)).To(Succeed())
+
+			prober.probeManagedEnvironments(ctx, log)
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(managedEnvCR), managedEnvCR)).To(Succeed())
+			Expect(meta.FindStatusCondition(managedEnvCR.Status.Conditions, managedgitopsv1alpha1.ManagedEnvironmentStatusReachable)).To(BeNil())
+			Expect(managedEnvCR.Status.LastProbeTime).To(BeNil())
+			Expect(testutil.CollectAndCount(metrics.ManagedEnvironmentReachable)).To(Equal(0))
+		})
+	})
+
+	Context("Testing the waitForProbeOfHost function", func() {
+
+		It("should rate limit the probes of each cluster host, by delaying them", func() {
+			prober := &ManagedEnvironmentProber{}
+			ctx := context.Background()
+
+			for i := 0; i < managedEnvProbesPerHostBurst; i++ {
+				Expect(prober.waitForProbeOfHost(ctx, "https://api.my-cluster.com:6443")).To(Succeed())
+			}
+
+			By("verifying the probes of the host are rate limited once the burst is exceeded, regardless of case")
+			shortCtx, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+			defer cancel()
+			Expect(prober.waitForProbeOfHost(shortCtx, "https://API.my-cluster.com:6443")).ToNot(Succeed())
+
+			By("verifying the probe of the host is delayed, rather than skipped")
+			start := time.Now()
+			Expect(prober.waitForProbeOfHost(ctx, "https://api.my-cluster.com:6443")).To(Succeed())
+			Expect(time.Since(start)).To(BeNumerically(">", 100*time.Millisecond))
+
+			By("verifying the probes of other hosts are not rate limited")
+			start = time.Now()
+			Expect(prober.waitForProbeOfHost(ctx, "https://api.other-cluster.com:6443")).To(Succeed())
+			Expect(time.Since(start)).To(BeNumerically("<", 100*time.Millisecond))
+		})
+	})
+
+	Context("Testing the updateManagedEnvironmentReachableCondition function", func() {
+
+		It("should only update the managed environment if the Reachable condition changed", func() {
+
+			managedEnvCR := &managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-managed-env",
+					Namespace: apiNamespace.Name,
+				},
+			}
+			Expect(k8sClient.Create(ctx, managedEnvCR)).To(Succeed())
+
+			probeResult := sharedresourceloop.ManagedEnvironmentProbeResult{
+				Status:  metav1.ConditionTrue,
+				Reason:  managedgitopsv1alpha1.ConditionReasonSucceeded,
+				Message: "The cluster of the managed environment is reachable",
+			}
+
+			firstProbeTime := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+			Expect(updateManagedEnvironmentReachableCondition(ctx, k8sClient, *managedEnvCR, probeResult, firstProbeTime, nil, log)).To(Succeed())
+
+			By("probing again, with the same result")
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(managedEnvCR), managedEnvCR)).To(Succeed())
+			resourceVersion := managedEnvCR.ResourceVersion
+			secondProbeTime := metav1.NewTime(time.Now().Truncate(time.Second))
+			Expect(updateManagedEnvironmentReachableCondition(ctx, k8sClient, *managedEnvCR, probeResult, secondProbeTime, nil, log)).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(managedEnvCR), managedEnvCR)).To(Succeed())
+			Expect(managedEnvCR.ResourceVersion).To(Equal(resourceVersion))
+			condition := meta.FindStatusCondition(managedEnvCR.Status.Conditions, managedgitopsv1alpha1.ManagedEnvironmentStatusReachable)
+			Expect(condition).ToNot(BeNil())
+			Expect(condition.LastTransitionTime.Equal(&firstProbeTime)).To(BeTrue())
+			Expect(managedEnvCR.Status.LastProbeTime.Equal(&firstProbeTime)).To(BeTrue())
+
+			By("probing again, once the cluster is no longer reachable")
+			probeResult = sharedresourceloop.ManagedEnvironmentProbeResult{
+				Status:  metav1.ConditionFalse,
+				Reason:  managedgitopsv1alpha1.ConditionReasonClusterUnreachable,
+				Message: "connection refused",
+			}
+			Expect(updateManagedEnvironmentReachableCondition(ctx, k8sClient, *managedEnvCR, probeResult, secondProbeTime, nil, log)).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(managedEnvCR), managedEnvCR)).To(Succeed())
+			condition = meta.FindStatusCondition(managedEnvCR.Status.Conditions, managedgitopsv1alpha1.ManagedEnvironmentStatusReachable)
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(string(managedgitopsv1alpha1.ConditionReasonClusterUnreachable)))
+			Expect(condition.LastTransitionTime.Equal(&secondProbeTime)).To(BeTrue())
+			Expect(managedEnvCR.Status.LastProbeTime.Equal(&secondProbeTime)).To(BeTrue())
+		})
+	})
+})
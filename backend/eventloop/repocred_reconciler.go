@@ -16,6 +16,7 @@ import (
 	sharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util"
 	logutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/log"
 	sharedresourceloop "github.com/redhat-appstudio/managed-gitops/backend/eventloop/shared_resource_loop"
+	"github.com/redhat-appstudio/managed-gitops/backend/events"
 )
 
 const (
@@ -28,6 +29,9 @@ const (
 type RepoCredReconciler struct {
 	client.Client
 	DB db.DatabaseQueries
+
+	// EventRecorder records K8s Events on the GitOpsDeploymentRepositoryCredentials whose status changes
+	EventRecorder *events.Recorder
 }
 
 // This function iterates through each entry of RepositoryCredential table in DB and updates the status of the CR.
@@ -49,7 +53,7 @@ func (r *RepoCredReconciler) startTimerForNextCycle() {
 		if _, err := sharedutil.CatchPanic(func() error {
 
 			// Reconcile RepositoryCredentials here
-			reconcileRepositoryCredentials(ctx, r.DB, r.Client, log)
+			reconcileRepositoryCredentials(ctx, r.DB, r.Client, r.EventRecorder, log)
 
 			return nil
 		}); err != nil {
@@ -67,7 +71,7 @@ func (r *RepoCredReconciler) startTimerForNextCycle() {
 // Reconcile logic for API CR To Database Mapping table and utility functions.
 // This will reconcile repository credential entries from ACTDM table and RepoistoryCredential table
 // /////////////
-func reconcileRepositoryCredentials(ctx context.Context, dbQueries db.DatabaseQueries, client client.Client, logParam logr.Logger) {
+func reconcileRepositoryCredentials(ctx context.Context, dbQueries db.DatabaseQueries, client client.Client, eventRecorder *events.Recorder, logParam logr.Logger) {
 
 	offSet := 0
 	log := logParam.WithValues(sharedutil.Log_JobKey, "reconcileRepositoryCredentials")
@@ -105,7 +109,7 @@ func reconcileRepositoryCredentials(ctx context.Context, dbQueries db.DatabaseQu
 			if db.APICRToDatabaseMapping_ResourceType_GitOpsDeploymentRepositoryCredential == apiCrToDbMappingFromDB.APIResourceType {
 
 				// Process if CR is of GitOpsDeploymentRepositoryCredential type.
-				reconcileRepositoryCredentialStatus(ctx, apiCrToDbMappingFromDB, objectMeta, sharedresourceloop.DefaultValidateRepositoryCredentials, client, dbQueries, log)
+				reconcileRepositoryCredentialStatus(ctx, apiCrToDbMappingFromDB, objectMeta, sharedresourceloop.DefaultValidateRepositoryCredentials, client, dbQueries, eventRecorder, log)
 
 				log.V(logutil.LogLevel_Debug).Info("RepositoryCredential ACTDM Reconcile processed APICRToDatabaseMapping entry: " + apiCrToDbMappingFromDB.APIResourceUID)
 
@@ -118,7 +122,7 @@ func reconcileRepositoryCredentials(ctx context.Context, dbQueries db.DatabaseQu
 	}
 }
 
-func reconcileRepositoryCredentialStatus(ctx context.Context, apiCrToDbMappingFromDB db.APICRToDatabaseMapping, objectMeta metav1.ObjectMeta, validateRepo sharedresourceloop.ValidateRepoURLAndCredentialsFunction, apiNamespaceClient client.Client, dbQueries db.DatabaseQueries, l logr.Logger) {
+func reconcileRepositoryCredentialStatus(ctx context.Context, apiCrToDbMappingFromDB db.APICRToDatabaseMapping, objectMeta metav1.ObjectMeta, validateRepo sharedresourceloop.ValidateRepoURLAndCredentialsFunction, apiNamespaceClient client.Client, dbQueries db.DatabaseQueries, eventRecorder *events.Recorder, l logr.Logger) {
 
 	gitopsDeploymentRepositoryCredentialCR := managedgitopsv1alpha1.GitOpsDeploymentRepositoryCredential{ObjectMeta: objectMeta}
 
@@ -132,7 +136,7 @@ func reconcileRepositoryCredentialStatus(ctx context.Context, apiCrToDbMappingFr
 
 	// Sanity test for gitopsDeploymentRepositoryCredentialCR.Spec.Secret to be non-empty value
 	if gitopsDeploymentRepositoryCredentialCR.Spec.Secret == "" {
-		if _, err := sharedresourceloop.UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, &gitopsDeploymentRepositoryCredentialCR, nil, validateRepo, apiNamespaceClient, log); err != nil {
+		if _, err := sharedresourceloop.UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, &gitopsDeploymentRepositoryCredentialCR, nil, validateRepo, apiNamespaceClient, eventRecorder, log); err != nil {
 			log.Error(err, "error updating status of GitopsDeploymentRepositoryCredential")
 		}
 		return
@@ -148,14 +152,14 @@ func reconcileRepositoryCredentialStatus(ctx context.Context, apiCrToDbMappingFr
 	// Fetch the secret from the cluster
 	if err := apiNamespaceClient.Get(ctx, client.ObjectKey{Name: secret.Name, Namespace: secret.Namespace}, secret); err != nil {
 		log.Error(err, "Secret not found when reconcling repository credential status", "secretName", secret.Name)
-		if _, err := sharedresourceloop.UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, &gitopsDeploymentRepositoryCredentialCR, nil, validateRepo, apiNamespaceClient, log); err != nil {
+		if _, err := sharedresourceloop.UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, &gitopsDeploymentRepositoryCredentialCR, nil, validateRepo, apiNamespaceClient, eventRecorder, log); err != nil {
 			log.Error(err, "error updating status of GitopsDeploymentRepositoryCredential")
 		}
 		return
 	}
 
 	// Update the status of GitopsDeploymentRepositoryCredential
-	if _, err := sharedresourceloop.UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, &gitopsDeploymentRepositoryCredentialCR, secret, validateRepo, apiNamespaceClient, log); err != nil {
+	if _, err := sharedresourceloop.UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, &gitopsDeploymentRepositoryCredentialCR, secret, validateRepo, apiNamespaceClient, eventRecorder, log); err != nil {
 		log.Error(err, "error updating status of GitopsDeploymentRepositoryCredential")
 	}
 
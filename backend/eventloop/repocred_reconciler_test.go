@@ -107,7 +107,7 @@ var _ = Describe("RepoCred Reconcile Function Tests", func() {
 				Name:      apiCRToDatabaseMappingDb.APIResourceName,
 				Namespace: apiCRToDatabaseMappingDb.APIResourceNamespace,
 			}
-			reconcileRepositoryCredentialStatus(ctx, db.APICRToDatabaseMapping{}, objectMeta, mock_skipValidateRepositoryCredentials, k8sClient, dbq, log)
+			reconcileRepositoryCredentialStatus(ctx, db.APICRToDatabaseMapping{}, objectMeta, mock_skipValidateRepositoryCredentials, k8sClient, dbq, nil, log)
 		})
 
 		It("should set an error status for RepositoryCredentials if the secret field is not set in the CR", func() {
@@ -125,7 +125,7 @@ var _ = Describe("RepoCred Reconcile Function Tests", func() {
 			Expect(err).ToNot(HaveOccurred())
 
 			By("calling the Reconcile function.")
-			reconcileRepositoryCredentials(ctx, dbq, k8sClient, log)
+			reconcileRepositoryCredentials(ctx, dbq, k8sClient, nil, log)
 
 			By("verifying that status is updated for GitopsDeploymentRepositoryCredentialCR.")
 			objectMeta := metav1.ObjectMeta{
@@ -162,7 +162,7 @@ var _ = Describe("RepoCred Reconcile Function Tests", func() {
 			Expect(err).ToNot(HaveOccurred())
 
 			By("calling the Reconcile function.")
-			reconcileRepositoryCredentials(ctx, dbq, k8sClient, log)
+			reconcileRepositoryCredentials(ctx, dbq, k8sClient, nil, log)
 
 			By("verifing that status is updated for GitopsDeploymentRepositoryCredentialCR.")
 			objectMeta := metav1.ObjectMeta{
@@ -212,7 +212,7 @@ var _ = Describe("RepoCred Reconcile Function Tests", func() {
 			Expect(err).ToNot(HaveOccurred())
 
 			By("calling the Reconcile function.")
-			reconcileRepositoryCredentials(ctx, dbq, k8sClient, log)
+			reconcileRepositoryCredentials(ctx, dbq, k8sClient, nil, log)
 
 			By("verifing that status is updated for GitopsDeploymentRepositoryCredentialCR.")
 			objectMeta := metav1.ObjectMeta{
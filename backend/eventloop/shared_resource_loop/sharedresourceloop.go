@@ -13,6 +13,7 @@ import (
 	sharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/gitopserrors"
 	logutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/log"
+	"github.com/redhat-appstudio/managed-gitops/backend/events"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -45,6 +46,10 @@ import (
 type SharedResourceEventLoop struct {
 	inputChannel chan sharedResourceLoopMessage
 
+	// eventRecorder records K8s Events on the managed environments and repository credentials that are reconciled by the loop.
+	// May be nil in unit tests.
+	eventRecorder *events.Recorder
+
 	// For use by unit tests only: If this function is non-nil, it will be used instead of the default repository credentials validation function.
 	validateRepoURLAndCredentialsFunction ValidateRepoURLAndCredentialsFunction
 }
@@ -263,10 +268,11 @@ func (srEventLoop *SharedResourceEventLoop) ReconcileRepositoryCredential(ctx co
 
 // NewSharedResourceLoop creates a new SharedResourceLoop, and starts the goroutine responsible for processing channel messages.
 // See documentation at top of this file for details.
-func NewSharedResourceLoop() *SharedResourceEventLoop {
+func NewSharedResourceLoop(eventRecorder *events.Recorder) *SharedResourceEventLoop {
 
 	sharedResourceEventLoop := &SharedResourceEventLoop{
-		inputChannel: make(chan sharedResourceLoopMessage),
+		inputChannel:  make(chan sharedResourceLoopMessage),
+		eventRecorder: eventRecorder,
 	}
 
 	go sharedResourceEventLoop.internalSharedResourceEventLoop(sharedResourceEventLoop.inputChannel)
@@ -448,7 +454,7 @@ func (srel *SharedResourceEventLoop) processSharedResourceMessage(ctx context.Co
 
 		res, isUserError, err := internalProcessMessage_ReconcileSharedManagedEnv(ctx, msg.workspaceClient, payload.managedEnvironmentCRName,
 			payload.managedEnvironmentCRNamespace, payload.isWorkspaceTarget, msg.workspaceNamespace,
-			payload.k8sClientFactory, dbQueries, log)
+			payload.k8sClientFactory, dbQueries, srel.eventRecorder, log)
 
 		response := sharedResourceLoopMessage_getOrCreateSharedResourcesResponse{
 			err:               err,
@@ -513,7 +519,7 @@ func (srel *SharedResourceEventLoop) processSharedResourceMessage(ctx context.Co
 			}
 
 			repositoryCredential, err = internalProcessMessage_ReconcileRepositoryCredential(ctx,
-				payload.repositoryCredentialCRName, msg.workspaceNamespace, repoCredValidationFunction, msg.workspaceClient, dbQueries, true, log)
+				payload.repositoryCredentialCRName, msg.workspaceNamespace, repoCredValidationFunction, msg.workspaceClient, dbQueries, true, srel.eventRecorder, log)
 
 		} else {
 			err = fmt.Errorf("SEVERE - unexpected cast in internalSharedResourceEventLoop")
@@ -611,13 +617,25 @@ func reconcileAppProjectRepositories(ctx context.Context, namespace corev1.Names
 	}
 
 	for _, gitopsDepl := range gitopsDeployments.Items {
-		gitURLOfGitOpsDepl := NormalizeGitURL(gitopsDepl.Spec.Source.RepoURL)
 
-		expectedEntry := db.AppProjectRepository{
-			Clusteruser_id: clusterUser.Clusteruser_id,
-			RepoURL:        gitURLOfGitOpsDepl,
+		// A GitOpsDeployment with multiple sources requires access to the repository of each source
+		repoURLs := []string{gitopsDepl.Spec.Source.RepoURL}
+		if len(gitopsDepl.Spec.Sources) > 0 {
+			repoURLs = []string{}
+			for _, source := range gitopsDepl.Spec.Sources {
+				repoURLs = append(repoURLs, source.RepoURL)
+			}
+		}
+
+		for _, repoURL := range repoURLs {
+			gitURLOfGitOpsDepl := NormalizeGitURL(repoURL)
+
+			expectedEntry := db.AppProjectRepository{
+				Clusteruser_id: clusterUser.Clusteruser_id,
+				RepoURL:        gitURLOfGitOpsDepl,
+			}
+			expectedDBEntries[gitURLOfGitOpsDepl] = expectedEntry
 		}
-		expectedDBEntries[gitURLOfGitOpsDepl] = expectedEntry
 	}
 
 	resDatabaseUpdated := false // Whether or not the database was updated by this call
@@ -2,8 +2,11 @@ package shared_resource_loop
 
 import (
 	"context"
+	"crypto/x509"
 	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"sort"
 	"strings"
 
@@ -16,6 +19,7 @@ import (
 	logutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/log"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/operations"
 	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/eventlooptypes"
+	"github.com/redhat-appstudio/managed-gitops/backend/events"
 	corev1 "k8s.io/api/core/v1"
 	apierr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -30,6 +34,8 @@ import (
 
 const (
 	KubeconfigKey                 = "kubeconfig"
+	ServerKey                     = "server" // Used by Secrets of type ManagedEnvironmentTokenSecretType
+	TokenKey                      = "token"  // Used by Secrets of type ManagedEnvironmentTokenSecretType
 	UnableToCreateRestConfigError = "unable to create k8s client from restConfig from managed environment secret"
 )
 
@@ -51,6 +57,7 @@ func internalProcessMessage_ReconcileSharedManagedEnv(ctx context.Context, works
 	workspaceNamespace corev1.Namespace,
 	k8sClientFactory SRLK8sClientFactory,
 	dbQueries db.DatabaseQueries,
+	eventRecorder *events.Recorder,
 	log logr.Logger) (SharedResourceManagedEnvContainer, bool, error) {
 
 	container, condition, isUserError, err := internalProcessMessage_internalReconcileSharedManagedEnv(ctx, workspaceClient, managedEnvironmentCRName,
@@ -59,7 +66,7 @@ func internalProcessMessage_ReconcileSharedManagedEnv(ctx context.Context, works
 	if condition.reason != "" && condition.managedEnvCR.Name != "" {
 
 		// If a metav1.Condition{} needs to be set, set it here.
-		updateManagedEnvironmentConnectionStatus(ctx, condition.managedEnvCR, workspaceClient, condition, log)
+		updateManagedEnvironmentConnectionStatus(ctx, condition.managedEnvCR, workspaceClient, condition, eventRecorder, log)
 
 	}
 
@@ -211,11 +218,20 @@ func internalProcessMessage_internalReconcileSharedManagedEnv(ctx context.Contex
 			}, userError_true, errors.New(msg)
 	}
 
+	caBundle, err := getCABundleOfManagedEnvironment(ctx, workspaceClient, managedEnvironmentCR, secretCR)
+	if err != nil {
+		return newSharedResourceManagedEnvContainer(),
+			convertErrToEnvInitCondition(managedgitopsv1alpha1.ConditionReasonInvalidCABundle, err, managedEnvironmentCR),
+			userError_true, err
+	}
+
 	// We found the managed env, now verify that the ManagedEnv's .spec values match the corresponding fields in the ClusterCredentials row
 	if clusterCreds.Host != managedEnvironmentCR.Spec.APIURL ||
 		clusterCreds.AllowInsecureSkipTLSVerify != managedEnvironmentCR.Spec.AllowInsecureSkipTLSVerify ||
 		clusterCreds.ClusterResources != managedEnvironmentCR.Spec.ClusterResources ||
-		clusterCreds.Namespaces != managedEnvNamespaceSliceList {
+		clusterCreds.Namespaces != managedEnvNamespaceSliceList ||
+		clusterCreds.Ca_bundle != caBundle ||
+		clusterCreds.Proxy_url != managedEnvironmentCR.Spec.ProxyURL {
 		// C) If at least one of the fields in the managed env CR has changed, then replace the cluster credentials of the managed environment
 		return replaceExistingManagedEnv(ctx, gitopsEngineClient, workspaceClient, *clusterUser, isNewUser, managedEnvironmentCR, secretCR, *managedEnv,
 			workspaceNamespace, k8sClientFactory, dbQueries, log)
@@ -233,7 +249,23 @@ func internalProcessMessage_internalReconcileSharedManagedEnv(ctx context.Contex
 
 	// The API url hasn't changed, the existing service account still works, so no more work needed.
 
-	// E) We already have an existing managed env from the database, so get or create the remaining items for it
+	// E) If the token of the service account that we created for the managed environment is due to be rotated, rotate it.
+	// If the rotation fails, the existing cluster credentials are still valid (and used), and the rotation is retried on the next reconcile.
+	// Otherwise, revoke the previous token once Argo CD no longer uses it: this is retried on each reconcile until it succeeds.
+	rotationInterval := sharedutil.ServiceAccountTokenRotationInterval(log)
+	if isServiceAccountTokenRotationDue(managedEnvironmentCR, *clusterCreds, rotationInterval) {
+		if err := rotateServiceAccountToken(ctx, workspaceClient, *clusterUser, managedEnvironmentCR, secretCR, managedEnv, *clusterCreds,
+			k8sClientFactory, dbQueries, log); err != nil {
+			log.Error(err, "unable to rotate the service account token of the managed environment")
+		}
+	} else if isServiceAccountTokenRotated(managedEnvironmentCR, *clusterCreds, rotationInterval) {
+		if err := revokeRotatedServiceAccountTokens(ctx, *clusterUser, managedEnvironmentCR, *managedEnv, *clusterCreds,
+			k8sClientFactory, dbQueries, log); err != nil {
+			log.Error(err, "unable to revoke the previous service account token of the managed environment")
+		}
+	}
+
+	// F) We already have an existing managed env from the database, so get or create the remaining items for it
 
 	engineInstance, isNewEngineInstance, clusterAccess, isNewClusterAccess, engineCluster, uerr := wrapManagedEnv(ctx,
 		*managedEnv, workspaceNamespace, *clusterUser, gitopsEngineClient, dbQueries, log)
@@ -846,48 +878,27 @@ func createNewClusterCredentials(ctx context.Context, managedEnvironment managed
 	secret corev1.Secret, k8sClientFactory SRLK8sClientFactory, dbQueries db.DatabaseQueries, log logr.Logger,
 	workspaceClient client.Client) (db.ClusterCredentials, connectionInitializedCondition, bool, error) {
 
-	if secret.Type != sharedutil.ManagedEnvironmentSecretType {
-		err := fmt.Errorf("invalid secret type: %s", secret.Type)
-		return db.ClusterCredentials{},
-			convertErrToEnvInitCondition(managedgitopsv1alpha1.ConditionReasonInvalidSecretType, err, managedEnvironment),
-			userError_true, err
-	}
-
-	kubeconfig, exists := secret.Data[KubeconfigKey]
-	if !exists {
-		err := fmt.Errorf("missing %s field in Secret", KubeconfigKey)
+	var restConfig *rest.Config
+	var condition connectionInitializedCondition
+	var err error
 
-		return db.ClusterCredentials{},
-			convertErrToEnvInitCondition(managedgitopsv1alpha1.ConditionReasonMissingKubeConfigField, err, managedEnvironment),
-			userError_true, err
+	switch secret.Type {
+	case sharedutil.ManagedEnvironmentSecretType:
+		restConfig, condition, err = getRestConfigFromKubeconfigSecret(managedEnvironment, secret)
+	case sharedutil.ManagedEnvironmentTokenSecretType:
+		restConfig, condition, err = getRestConfigFromTokenSecret(managedEnvironment, secret)
+	default:
+		err = fmt.Errorf("invalid secret type: %s", secret.Type)
+		condition = convertErrToEnvInitCondition(managedgitopsv1alpha1.ConditionReasonInvalidSecretType, err, managedEnvironment)
 	}
-
-	// Load the kubeconfig from the field
-	config, err := clientcmd.Load(kubeconfig)
 	if err != nil {
-		err := fmt.Errorf("unable to parse kubeconfig data: %w", err)
-
-		return db.ClusterCredentials{},
-			convertErrToEnvInitCondition(managedgitopsv1alpha1.ConditionReasonUnableToParseKubeconfigData, err, managedEnvironment),
-			userError_true, err
-
-	}
-
-	matchingContextName, matchingContext, err := locateContextThatMatchesAPIURL(config, managedEnvironment.Spec.APIURL)
-	if err != nil {
-		return db.ClusterCredentials{},
-			convertErrToEnvInitCondition(managedgitopsv1alpha1.ConditionReasonUnableToLocateContext, err, managedEnvironment),
-			userError_true, err
+		return db.ClusterCredentials{}, condition, userError_true, err
 	}
 
-	clientConfig := clientcmd.NewNonInteractiveClientConfig(*config, matchingContextName, &clientcmd.ConfigOverrides{}, nil)
-
-	restConfig, err := clientConfig.ClientConfig()
+	caBundle, err := getCABundleOfManagedEnvironment(ctx, workspaceClient, managedEnvironment, secret)
 	if err != nil {
-		err := fmt.Errorf("unable to retrieve restConfig from managed environment secret: %w", err)
-
 		return db.ClusterCredentials{},
-			convertErrToEnvInitCondition(managedgitopsv1alpha1.ConditionReasonUnableToRetrieveRestConfig, err, managedEnvironment),
+			convertErrToEnvInitCondition(managedgitopsv1alpha1.ConditionReasonInvalidCABundle, err, managedEnvironment),
 			userError_true, err
 	}
 
@@ -896,6 +907,21 @@ func createNewClusterCredentials(ctx context.Context, managedEnvironment managed
 		restConfig.Insecure = true
 		restConfig.TLSClientConfig.CAFile = ""
 		restConfig.TLSClientConfig.CAData = nil
+	} else if caBundle != "" {
+		// Otherwise, verify the certificate using the CA bundle, if one was provided
+		restConfig.TLSClientConfig.CAFile = ""
+		restConfig.TLSClientConfig.CAData = []byte(caBundle)
+	}
+
+	// Connect to the cluster via the proxy of the managed environment, if one was specified
+	proxyURL, err := parseProxyURL(managedEnvironment.Spec.ProxyURL)
+	if err != nil {
+		return db.ClusterCredentials{},
+			convertErrToEnvInitCondition(managedgitopsv1alpha1.ConditionReasonInvalidProxyURL, err, managedEnvironment),
+			userError_true, err
+	}
+	if proxyURL != nil {
+		restConfig.Proxy = http.ProxyURL(proxyURL)
 	}
 
 	k8sClient, err := k8sClientFactory.BuildK8sClient(restConfig)
@@ -907,7 +933,7 @@ func createNewClusterCredentials(ctx context.Context, managedEnvironment managed
 			userError_false, err
 	}
 
-	var saBearerToken string
+	var saBearerToken, clientCertificateData, clientKeyData string
 	log.Info("createNewServiceAccount is ", "CreateNewServiceAccount", managedEnvironment.Spec.CreateNewServiceAccount)
 	if managedEnvironment.Spec.CreateNewServiceAccount {
 		// This is the original behaviour, where we create a new service account
@@ -921,12 +947,11 @@ func createNewClusterCredentials(ctx context.Context, managedEnvironment managed
 
 		}
 	} else {
-		// If an existing service account is used instead, we just simply take the provided secret as the token for the cluster credentials
+		// If an existing service account is used instead, we just simply take the credentials provided in the secret (either a token,
+		// or a client certificate/key) as the credentials for the cluster credentials
 
-		val, exists := config.AuthInfos[matchingContext.AuthInfo]
-
-		if !exists {
-			msg := "unable to extract remote cluster configuration from kubeconfig, missing auth info for " + matchingContextName
+		if restConfig.BearerToken == "" && (len(restConfig.CertData) == 0 || len(restConfig.KeyData) == 0) {
+			msg := fmt.Sprintf("the credentials in Secret '%s' must contain a service account token, or a client certificate and key", secret.Name)
 			return db.ClusterCredentials{}, connectionInitializedCondition{
 				managedEnvCR: managedEnvironment,
 				status:       metav1.ConditionFalse,
@@ -935,17 +960,11 @@ func createNewClusterCredentials(ctx context.Context, managedEnvironment managed
 			}, userError_true, fmt.Errorf("%s", msg)
 		}
 
-		if val.Token == "" {
-			msg := fmt.Sprintf("kubeconfig must have a service account token for the user in context \"%s\". client-certificate is not supported at this time.", matchingContextName)
-			return db.ClusterCredentials{}, connectionInitializedCondition{
-				managedEnvCR: managedEnvironment,
-				status:       metav1.ConditionFalse,
-				reason:       managedgitopsv1alpha1.ConditionReasonUnableToParseKubeconfigData,
-				message:      msg,
-			}, userError_true, fmt.Errorf("%s", msg)
+		saBearerToken = restConfig.BearerToken
+		if saBearerToken == "" {
+			clientCertificateData = string(restConfig.CertData)
+			clientKeyData = string(restConfig.KeyData)
 		}
-
-		saBearerToken = val.Token
 	}
 
 	// Convert the .spec.namespaces field to a comma-separated list of namespaces
@@ -977,6 +996,10 @@ func createNewClusterCredentials(ctx context.Context, managedEnvironment managed
 		AllowInsecureSkipTLSVerify:  insecureVerifyTLS,
 		Namespaces:                  namespacesField,
 		ClusterResources:            managedEnvironment.Spec.ClusterResources,
+		Ca_bundle:                   caBundle,
+		Client_certificate_data:     clientCertificateData,
+		Client_key_data:             clientKeyData,
+		Proxy_url:                   managedEnvironment.Spec.ProxyURL,
 	}
 	// If an existing service account is used instead, we should verify the cluster credentials based on the provided token
 	if !managedEnvironment.Spec.CreateNewServiceAccount {
@@ -986,11 +1009,12 @@ func createNewClusterCredentials(ctx context.Context, managedEnvironment managed
 
 			log.Error(err, "Unable to verify ClusterCredentials using provided token", clusterCredentials.GetAsLogKeyValues()...)
 
-			message := "Unable to validate the credentials provided in the ManagedEnvironment Secret. Verify the API URL, and service account token are correct."
+			message := "Unable to validate the credentials provided in the ManagedEnvironment Secret. Verify the API URL, and service account token (or client certificate) are correct."
 			if apierr.IsForbidden(err) {
 				message = "Provided service account does not have permission to access resources in the cluster. Verify that the service account has the correct Role and RoleBinding."
 			} else if isCertificateSignedByUnknownAuthority(err) {
-				message = "Certificate signed by unknown authority. Note that the '.spec.allowInsecureSkipTLSVerify' field can be used to ignore this error."
+				message = "Certificate signed by unknown authority. Note that the CA bundle of the cluster can be provided via the '.spec.caBundleConfigMap' field " +
+					"(or the '" + managedgitopsv1alpha1.ManagedEnvironmentCABundleKey + "' key of the Secret), or the '.spec.allowInsecureSkipTLSVerify' field can be used to ignore this error."
 			}
 			return db.ClusterCredentials{}, connectionInitializedCondition{
 				managedEnvCR: managedEnvironment,
@@ -1019,6 +1043,160 @@ func createNewClusterCredentials(ctx context.Context, managedEnvironment managed
 
 }
 
+// getRestConfigFromKubeconfigSecret returns a REST config for the cluster of the managed environment, based on the context
+// of the kubeconfig (in the given Secret) that matches the API URL of the managed environment.
+func getRestConfigFromKubeconfigSecret(managedEnvironment managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironment,
+	secret corev1.Secret) (*rest.Config, connectionInitializedCondition, error) {
+
+	kubeconfig, exists := secret.Data[KubeconfigKey]
+	if !exists {
+		err := fmt.Errorf("missing %s field in Secret", KubeconfigKey)
+
+		return nil, convertErrToEnvInitCondition(managedgitopsv1alpha1.ConditionReasonMissingKubeConfigField, err, managedEnvironment), err
+	}
+
+	// Load the kubeconfig from the field
+	config, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		err := fmt.Errorf("unable to parse kubeconfig data: %w", err)
+
+		return nil, convertErrToEnvInitCondition(managedgitopsv1alpha1.ConditionReasonUnableToParseKubeconfigData, err, managedEnvironment), err
+	}
+
+	matchingContextName, matchingContext, err := locateContextThatMatchesAPIURL(config, managedEnvironment.Spec.APIURL)
+	if err != nil {
+		return nil, convertErrToEnvInitCondition(managedgitopsv1alpha1.ConditionReasonUnableToLocateContext, err, managedEnvironment), err
+	}
+
+	authInfo, exists := config.AuthInfos[matchingContext.AuthInfo]
+	if !exists || authInfo == nil {
+		err := fmt.Errorf("unable to extract remote cluster configuration from kubeconfig, missing auth info for %s", matchingContextName)
+
+		return nil, convertErrToEnvInitCondition(managedgitopsv1alpha1.ConditionReasonUnableToParseKubeconfigData, err, managedEnvironment), err
+	}
+
+	// Verify the auth info is supported before building the REST config: exec plugins and auth providers would otherwise
+	// be run by the GitOps Service, and files would be read from the file system of the GitOps Service.
+	if err := verifyAuthInfoIsSupported(*authInfo); err != nil {
+		err := fmt.Errorf("unsupported user in context \"%s\" of kubeconfig: %w", matchingContextName, err)
+
+		return nil, convertErrToEnvInitCondition(managedgitopsv1alpha1.ConditionReasonUnsupportedAuthType, err, managedEnvironment), err
+	}
+
+	clientConfig := clientcmd.NewNonInteractiveClientConfig(*config, matchingContextName, &clientcmd.ConfigOverrides{}, nil)
+
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		err := fmt.Errorf("unable to retrieve restConfig from managed environment secret: %w", err)
+
+		return nil, convertErrToEnvInitCondition(managedgitopsv1alpha1.ConditionReasonUnableToRetrieveRestConfig, err, managedEnvironment), err
+	}
+
+	return restConfig, connectionInitializedCondition{}, nil
+}
+
+// verifyAuthInfoIsSupported returns an error if the kubeconfig user uses an authentication mechanism which is not supported.
+// Supported mechanisms are a bearer token, or client certificate/key data, specified inline within the kubeconfig.
+func verifyAuthInfoIsSupported(authInfo clientcmdapi.AuthInfo) error {
+
+	if authInfo.Exec != nil {
+		return fmt.Errorf("exec plugins are not supported: use a service account token, or client certificate data, instead")
+	}
+	if authInfo.AuthProvider != nil {
+		return fmt.Errorf("auth providers are not supported: use a service account token, or client certificate data, instead")
+	}
+	if authInfo.TokenFile != "" || authInfo.ClientCertificate != "" || authInfo.ClientKey != "" {
+		return fmt.Errorf("credentials stored in files are not supported: use the 'token', 'client-certificate-data' and 'client-key-data' fields instead")
+	}
+	if authInfo.Username != "" || authInfo.Password != "" {
+		return fmt.Errorf("basic authentication is not supported: use a service account token, or client certificate data, instead")
+	}
+	if len(authInfo.ClientCertificateData) > 0 != (len(authInfo.ClientKeyData) > 0) {
+		return fmt.Errorf("both 'client-certificate-data' and 'client-key-data' must be specified")
+	}
+
+	return nil
+}
+
+// getRestConfigFromTokenSecret returns a REST config for the cluster of the managed environment, based on the 'server' and
+// 'token' fields of the given Secret (of type ManagedEnvironmentTokenSecretType).
+func getRestConfigFromTokenSecret(managedEnvironment managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironment,
+	secret corev1.Secret) (*rest.Config, connectionInitializedCondition, error) {
+
+	server, exists := secret.Data[ServerKey]
+	if !exists {
+		err := fmt.Errorf("missing %s field in Secret", ServerKey)
+
+		return nil, convertErrToEnvInitCondition(managedgitopsv1alpha1.ConditionReasonMissingKubeConfigField, err, managedEnvironment), err
+	}
+
+	if !strings.EqualFold(strings.TrimSpace(string(server)), managedEnvironment.Spec.APIURL) {
+		err := fmt.Errorf("the %s field of Secret '%s' does not match the API URL of the managed environment", ServerKey, secret.Name)
+
+		return nil, convertErrToEnvInitCondition(managedgitopsv1alpha1.ConditionReasonUnableToLocateContext, err, managedEnvironment), err
+	}
+
+	token := strings.TrimSpace(string(secret.Data[TokenKey]))
+	if token == "" {
+		err := fmt.Errorf("missing %s field in Secret", TokenKey)
+
+		return nil, convertErrToEnvInitCondition(managedgitopsv1alpha1.ConditionReasonMissingKubeConfigField, err, managedEnvironment), err
+	}
+
+	// The CA bundle of the Secret, if any, is applied by the caller
+	restConfig := &rest.Config{
+		Host:        managedEnvironment.Spec.APIURL,
+		BearerToken: token,
+	}
+
+	return restConfig, connectionInitializedCondition{}, nil
+}
+
+// getCABundleOfManagedEnvironment returns the PEM-encoded CA bundle of the managed environment, or "" if none was provided.
+// The CA bundle is read from the ConfigMap referenced by .spec.caBundleConfigMap, if specified, otherwise from the
+// credentials Secret. Returns an error if the ConfigMap could not be retrieved, or the CA bundle is not valid.
+func getCABundleOfManagedEnvironment(ctx context.Context, workspaceClient client.Client,
+	managedEnvironment managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironment, secret corev1.Secret) (string, error) {
+
+	var caBundle string
+
+	if managedEnvironment.Spec.CABundleConfigMap != "" {
+
+		configMap := corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      managedEnvironment.Spec.CABundleConfigMap,
+				Namespace: managedEnvironment.Namespace,
+			},
+		}
+		if err := workspaceClient.Get(ctx, client.ObjectKeyFromObject(&configMap), &configMap); err != nil {
+			return "", fmt.Errorf("unable to retrieve CA bundle ConfigMap '%s': %w", configMap.Name, err)
+		}
+
+		value, exists := configMap.Data[managedgitopsv1alpha1.ManagedEnvironmentCABundleKey]
+		if !exists {
+			return "", fmt.Errorf("missing %s field in CA bundle ConfigMap '%s'", managedgitopsv1alpha1.ManagedEnvironmentCABundleKey, configMap.Name)
+		}
+		caBundle = value
+
+	} else if value, exists := secret.Data[managedgitopsv1alpha1.ManagedEnvironmentCABundleKey]; exists {
+		caBundle = string(value)
+	}
+
+	if caBundle == "" {
+		return "", nil
+	}
+
+	if len(caBundle) > db.ClusterCredentialsCaBundleLength {
+		return "", fmt.Errorf("the CA bundle exceeds the maximum size of %d bytes", db.ClusterCredentialsCaBundleLength)
+	}
+
+	if !x509.NewCertPool().AppendCertsFromPEM([]byte(caBundle)) {
+		return "", fmt.Errorf("the CA bundle does not contain any valid PEM-encoded certificates")
+	}
+
+	return caBundle, nil
+}
+
 // locateContextThatMatchesAPIURL examines a kubeconfig (Config struct), and looks for the context that
 // matches the cluster with the given API URL.
 // See 'sharedresourceloop_managedend_test.go' for an example of a kubeconfig.
@@ -1076,7 +1254,7 @@ func sanityTestCredentials(clusterCreds db.ClusterCredentials) (*rest.Config, bo
 	if clusterCreds.Host == "" {
 		return nil, false, fmt.Errorf("cluster credentials is missing host")
 	}
-	if clusterCreds.Serviceaccount_bearer_token == "" {
+	if clusterCreds.Serviceaccount_bearer_token == "" && (clusterCreds.Client_certificate_data == "" || clusterCreds.Client_key_data == "") {
 		return nil, false, fmt.Errorf("cluster credentials is missing service account bearer token")
 	}
 
@@ -1085,13 +1263,59 @@ func sanityTestCredentials(clusterCreds db.ClusterCredentials) (*rest.Config, bo
 		BearerToken: clusterCreds.Serviceaccount_bearer_token,
 	}
 
+	if clusterCreds.Serviceaccount_bearer_token == "" {
+		configParam.TLSClientConfig.CertData = []byte(clusterCreds.Client_certificate_data)
+		configParam.TLSClientConfig.KeyData = []byte(clusterCreds.Client_key_data)
+	}
+
 	configParam.Insecure = clusterCreds.AllowInsecureSkipTLSVerify
 
+	if !clusterCreds.AllowInsecureSkipTLSVerify && clusterCreds.Ca_bundle != "" {
+		configParam.TLSClientConfig.CAData = []byte(clusterCreds.Ca_bundle)
+	}
+
+	proxyURL, err := parseProxyURL(clusterCreds.Proxy_url)
+	if err != nil {
+		return nil, false, err
+	}
+	if proxyURL != nil {
+		configParam.Proxy = http.ProxyURL(proxyURL)
+	}
+
 	configParam.ServerName = ""
 
 	return configParam, true, nil
 }
 
+// parseProxyURL returns the parsed URL of the HTTP(S) proxy used to connect to the cluster of a managed environment, or nil
+// if no proxy is used. Returns an error if the proxy URL is not valid.
+func parseProxyURL(proxyURL string) (*url.URL, error) {
+
+	if proxyURL == "" {
+		return nil, nil
+	}
+
+	if len(proxyURL) > db.ClusterCredentialsProxyURLLength {
+		return nil, fmt.Errorf("the proxy URL exceeds the maximum length of %d characters", db.ClusterCredentialsProxyURLLength)
+	}
+
+	// The parse error is not wrapped, as it contains the URL, which may contain the credentials of the proxy
+	parsedURL, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("the proxy URL is not a valid URL")
+	}
+
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" && parsedURL.Scheme != "socks5" {
+		return nil, fmt.Errorf("the proxy URL must use the 'http', 'https' or 'socks5' scheme")
+	}
+
+	if parsedURL.Host == "" {
+		return nil, fmt.Errorf("the proxy URL is missing a host")
+	}
+
+	return parsedURL, nil
+}
+
 // connectionInitializedCondition is returned by functions in this file, to indicate that a Condition should be set in the
 // .status.conditions field of the ManagedEnvironment CR, of type ManagedEnvironmentStatusConnectionInitializationSucceeded.
 type connectionInitializedCondition struct {
@@ -1176,7 +1400,7 @@ func createUnknownErrorEnvInitCondition() connectionInitializedCondition {
 // to preserve the LastTransitionTime (see https://pkg.go.dev/k8s.io/apimachinery/pkg/apis/meta/v1#Condition.LastTransitionTime )
 func updateManagedEnvironmentConnectionStatus(ctx context.Context,
 	managedEnvironment managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironment,
-	client client.Client, connInitCondition connectionInitializedCondition, log logr.Logger) {
+	client client.Client, connInitCondition connectionInitializedCondition, eventRecorder *events.Recorder, log logr.Logger) {
 
 	const conditionType = managedgitopsv1alpha1.ManagedEnvironmentStatusConnectionInitializationSucceeded
 	var condition *metav1.Condition = nil
@@ -1199,6 +1423,13 @@ func updateManagedEnvironmentConnectionStatus(ctx context.Context,
 		condition.Status = connInitCondition.status
 		if err := client.Status().Update(ctx, &managedEnvironment); err != nil {
 			log.Error(err, "updating managed environment status condition")
+			return
+		}
+
+		if connInitCondition.status == metav1.ConditionTrue {
+			eventRecorder.RecordNormal(&managedEnvironment, conditionType, "Connection to the managed environment was successfully initialized")
+		} else {
+			eventRecorder.RecordWarning(&managedEnvironment, string(connInitCondition.reason), "Unable to initialize connection to the managed environment: "+connInitCondition.message)
 		}
 	}
 }
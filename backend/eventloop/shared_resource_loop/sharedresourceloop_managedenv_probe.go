@@ -0,0 +1,121 @@
+package shared_resource_loop
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/db"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// managedEnvProbeTimeout is the maximum amount of time to wait for a response from the cluster of a managed environment,
+// for each request of a probe.
+const managedEnvProbeTimeout = 15 * time.Second
+
+// ManagedEnvironmentProbeResult is the result of probing the cluster of a managed environment, and corresponds to the
+// 'Reachable' condition of the GitOpsDeploymentManagedEnvironment.
+type ManagedEnvironmentProbeResult struct {
+	Status  metav1.ConditionStatus
+	Reason  managedgitopsv1alpha1.ManagedEnvironmentConditionReason
+	Message string
+}
+
+// ProbeManagedEnvironmentCluster verifies that the cluster of a managed environment is still reachable using the given
+// cluster credentials:
+// - the API of the cluster is reachable
+// - the credentials are still valid (via a SelfSubjectReview, or a SelfSubjectAccessReview on older clusters)
+// - the namespaces of the managed environment (if any) still exist
+func ProbeManagedEnvironmentCluster(ctx context.Context, clusterCreds db.ClusterCredentials, k8sClientFactory SRLK8sClientFactory) ManagedEnvironmentProbeResult {
+
+	restConfig, _, err := sanityTestCredentials(clusterCreds)
+	if err != nil {
+		return newProbeFailure(managedgitopsv1alpha1.ConditionReasonInvalidCredentials, err)
+	}
+	restConfig.Timeout = managedEnvProbeTimeout
+
+	k8sClient, err := k8sClientFactory.BuildK8sClient(restConfig)
+	if err != nil {
+		return newProbeFailure(managedgitopsv1alpha1.ConditionReasonClusterUnreachable,
+			fmt.Errorf("unable to create client for '%s': %w", clusterCreds.Host, err))
+	}
+
+	if err := verifyClusterCredentialsAreValid(ctx, k8sClient); err != nil {
+		return convertProbeErrToResult(fmt.Errorf("unable to verify the credentials of the managed environment: %w", err))
+	}
+
+	if clusterCreds.Namespaces != "" {
+		for _, namespaceName := range strings.Split(clusterCreds.Namespaces, ",") {
+
+			namespace := corev1.Namespace{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: namespaceName,
+				},
+			}
+			if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(&namespace), &namespace); err != nil {
+				if apierr.IsNotFound(err) {
+					return newProbeFailure(managedgitopsv1alpha1.ConditionReasonNamespaceNotFound,
+						fmt.Errorf("namespace '%s' of the managed environment does not exist", namespaceName))
+				}
+				return convertProbeErrToResult(fmt.Errorf("unable to retrieve namespace '%s' of the managed environment: %w", namespaceName, err))
+			}
+		}
+	}
+
+	return ManagedEnvironmentProbeResult{
+		Status:  metav1.ConditionTrue,
+		Reason:  managedgitopsv1alpha1.ConditionReasonSucceeded,
+		Message: "The cluster of the managed environment is reachable",
+	}
+}
+
+// verifyClusterCredentialsAreValid returns nil if the credentials of the client are accepted by the cluster.
+func verifyClusterCredentialsAreValid(ctx context.Context, k8sClient client.Client) error {
+
+	selfSubjectReview := authenticationv1.SelfSubjectReview{}
+	err := k8sClient.Create(ctx, &selfSubjectReview)
+	if err == nil || (!apierr.IsNotFound(err) && !meta.IsNoMatchError(err)) {
+		return err
+	}
+
+	// SelfSubjectReview is only available on Kubernetes 1.28+: on older clusters, fall back to a SelfSubjectAccessReview,
+	// which is available to any authenticated user.
+	selfSubjectAccessReview := authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Verb:     "get",
+				Resource: "namespaces",
+			},
+		},
+	}
+	return k8sClient.Create(ctx, &selfSubjectAccessReview)
+}
+
+// convertProbeErrToResult converts an error, returned by a request to the cluster, to a failed probe result.
+func convertProbeErrToResult(err error) ManagedEnvironmentProbeResult {
+
+	reason := managedgitopsv1alpha1.ConditionReasonClusterUnreachable
+	if apierr.IsUnauthorized(err) {
+		reason = managedgitopsv1alpha1.ConditionReasonInvalidCredentials
+	} else if apierr.IsForbidden(err) {
+		reason = managedgitopsv1alpha1.ConditionReasonInsufficientPermissions
+	}
+
+	return newProbeFailure(reason, err)
+}
+
+func newProbeFailure(reason managedgitopsv1alpha1.ManagedEnvironmentConditionReason, err error) ManagedEnvironmentProbeResult {
+	return ManagedEnvironmentProbeResult{
+		Status:  metav1.ConditionFalse,
+		Reason:  reason,
+		Message: err.Error(),
+	}
+}
@@ -0,0 +1,113 @@
+package shared_resource_loop
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/db"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/tests"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+var _ = Describe("Test ProbeManagedEnvironmentCluster function", func() {
+
+	var ctx context.Context
+	var clusterCreds db.ClusterCredentials
+
+	// createFactory returns a client factory, whose client responds to SelfSubjectReviews/SelfSubjectAccessReviews with the given errors
+	createFactory := func(selfSubjectReviewErr error, selfSubjectAccessReviewErr error, objs ...client.Object) MockSRLK8sClientFactory {
+
+		scheme, _, _, _, err := tests.GenericTestSetup()
+		Expect(err).ToNot(HaveOccurred())
+
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, client client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				switch obj.(type) {
+				case *authenticationv1.SelfSubjectReview:
+					return selfSubjectReviewErr
+				case *authorizationv1.SelfSubjectAccessReview:
+					return selfSubjectAccessReviewErr
+				}
+				return client.Create(ctx, obj, opts...)
+			},
+		}).Build()
+
+		return MockSRLK8sClientFactory{fakeClient: k8sClient}
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		clusterCreds = db.ClusterCredentials{
+			Host:                        "https://api.my-cluster.com:6443",
+			Serviceaccount_bearer_token: "my-token",
+		}
+	})
+
+	It("should report the cluster as reachable if the credentials are valid", func() {
+		res := ProbeManagedEnvironmentCluster(ctx, clusterCreds, createFactory(nil, nil))
+		Expect(res.Status).To(Equal(metav1.ConditionTrue))
+		Expect(res.Reason).To(Equal(managedgitopsv1alpha1.ConditionReasonSucceeded))
+	})
+
+	It("should report invalid credentials if the token is no longer valid", func() {
+		res := ProbeManagedEnvironmentCluster(ctx, clusterCreds, createFactory(k8serrors.NewUnauthorized("token revoked"), nil))
+		Expect(res.Status).To(Equal(metav1.ConditionFalse))
+		Expect(res.Reason).To(Equal(managedgitopsv1alpha1.ConditionReasonInvalidCredentials))
+		Expect(res.Message).To(ContainSubstring("token revoked"))
+	})
+
+	It("should fall back to a SelfSubjectAccessReview, if SelfSubjectReview is not available on the cluster", func() {
+		notFoundErr := k8serrors.NewNotFound(schema.GroupResource{Group: "authentication.k8s.io", Resource: "selfsubjectreviews"}, "")
+
+		By("verifying the cluster is reachable if the SelfSubjectAccessReview succeeds")
+		res := ProbeManagedEnvironmentCluster(ctx, clusterCreds, createFactory(notFoundErr, nil))
+		Expect(res.Status).To(Equal(metav1.ConditionTrue))
+
+		By("verifying the credentials are invalid if the SelfSubjectAccessReview fails")
+		res = ProbeManagedEnvironmentCluster(ctx, clusterCreds, createFactory(notFoundErr, k8serrors.NewUnauthorized("token revoked")))
+		Expect(res.Status).To(Equal(metav1.ConditionFalse))
+		Expect(res.Reason).To(Equal(managedgitopsv1alpha1.ConditionReasonInvalidCredentials))
+	})
+
+	It("should report the cluster as unreachable if the API of the cluster is not reachable", func() {
+		res := ProbeManagedEnvironmentCluster(ctx, clusterCreds, createFactory(k8serrors.NewServiceUnavailable("connection refused"), nil))
+		Expect(res.Status).To(Equal(metav1.ConditionFalse))
+		Expect(res.Reason).To(Equal(managedgitopsv1alpha1.ConditionReasonClusterUnreachable))
+	})
+
+	It("should report invalid credentials if the cluster credentials don't contain a token or client certificate", func() {
+		clusterCreds.Serviceaccount_bearer_token = ""
+
+		res := ProbeManagedEnvironmentCluster(ctx, clusterCreds, createFactory(nil, nil))
+		Expect(res.Status).To(Equal(metav1.ConditionFalse))
+		Expect(res.Reason).To(Equal(managedgitopsv1alpha1.ConditionReasonInvalidCredentials))
+	})
+
+	It("should verify that the namespaces of the managed environment exist", func() {
+		clusterCreds.Namespaces = "namespace-a,namespace-b"
+
+		namespaceA := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "namespace-a"}}
+		namespaceB := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "namespace-b"}}
+
+		By("verifying the cluster is reachable if all the namespaces exist")
+		res := ProbeManagedEnvironmentCluster(ctx, clusterCreds, createFactory(nil, nil, namespaceA, namespaceB))
+		Expect(res.Status).To(Equal(metav1.ConditionTrue))
+
+		By("verifying the cluster is not reachable if a namespace doesn't exist")
+		res = ProbeManagedEnvironmentCluster(ctx, clusterCreds, createFactory(nil, nil, namespaceA))
+		Expect(res.Status).To(Equal(metav1.ConditionFalse))
+		Expect(res.Reason).To(Equal(managedgitopsv1alpha1.ConditionReasonNamespaceNotFound))
+		Expect(res.Message).To(ContainSubstring("namespace-b"))
+	})
+})
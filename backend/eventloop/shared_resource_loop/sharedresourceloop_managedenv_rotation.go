@@ -0,0 +1,321 @@
+package shared_resource_loop
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/db"
+	sharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util"
+	logutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/log"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/operations"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// isServiceAccountTokenRotated returns true if the cluster credentials contain the token of a ServiceAccount that was
+// created by the GitOps Service (via .spec.createNewServiceAccount), and service account tokens are rotated.
+func isServiceAccountTokenRotated(managedEnvironmentCR managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironment,
+	clusterCreds db.ClusterCredentials, rotationInterval time.Duration) bool {
+
+	return rotationInterval > 0 &&
+		managedEnvironmentCR.Spec.CreateNewServiceAccount &&
+		clusterCreds.Serviceaccount_bearer_token != ""
+}
+
+// isServiceAccountTokenRotationDue returns true if the cluster credentials contain the token of a ServiceAccount that was
+// created by the GitOps Service (via .spec.createNewServiceAccount), and that token is older than the rotation interval.
+func isServiceAccountTokenRotationDue(managedEnvironmentCR managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironment,
+	clusterCreds db.ClusterCredentials, rotationInterval time.Duration) bool {
+
+	return isServiceAccountTokenRotated(managedEnvironmentCR, clusterCreds, rotationInterval) &&
+		time.Since(clusterCreds.Created_on) >= rotationInterval
+}
+
+// rotateServiceAccountToken rotates the token of the ServiceAccount that was created by the GitOps Service for a managed environment:
+//  1. A new token Secret is created for the ServiceAccount, and the token is stored in new ClusterCredentials.
+//  2. The connection to the cluster is verified using the new token.
+//  3. The ManagedEnvironment is updated to reference the new ClusterCredentials, and the old ClusterCredentials are deleted.
+//  4. An Operation is created for each Argo CD instance that uses the managed environment, so that the cluster-agent refreshes
+//     the Argo CD cluster secret with the new token. The Operations are not waited for, as the shared resource loop
+//     processes a single event at a time.
+//
+// The old token Secret(s) of the ServiceAccount are deleted by revokeRotatedServiceAccountTokens on a later reconcile,
+// once the cluster secrets have been refreshed.
+//
+// If the rotation fails before step 3, the old ClusterCredentials remain in use. Otherwise, 'managedEnv' is updated to
+// reference the new ClusterCredentials (even if an error is returned).
+func rotateServiceAccountToken(ctx context.Context, workspaceClient client.Client, clusterUser db.ClusterUser,
+	managedEnvironmentCR managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironment, secret corev1.Secret,
+	managedEnv *db.ManagedEnvironment, oldClusterCreds db.ClusterCredentials,
+	k8sClientFactory SRLK8sClientFactory, dbQueries db.DatabaseQueries, log logr.Logger) error {
+
+	log = log.WithValues("managedEnvID", managedEnv.Managedenvironment_id, "oldClusterCredentialsID", oldClusterCreds.Clustercredentials_cred_id)
+
+	log.Info("Rotating the service account token of the managed environment")
+
+	// 1) Create a new token for the ServiceAccount, and store it in new cluster credentials
+	newClusterCreds, _, _, err := createNewClusterCredentials(ctx, managedEnvironmentCR, secret, k8sClientFactory, dbQueries, log, workspaceClient)
+	if err != nil {
+		return fmt.Errorf("unable to create new cluster credentials: %w", err)
+	}
+
+	// revertRotation deletes the new cluster credentials, and revokes the new token, using the old token (which is still valid)
+	revertRotation := func() {
+		if _, err := dbQueries.DeleteClusterCredentialsById(ctx, newClusterCreds.Clustercredentials_cred_id); err != nil {
+			log.Error(err, "Unable to delete new ClusterCredentials, after failed service account token rotation",
+				"newClusterCredentialsID", newClusterCreds.Clustercredentials_cred_id)
+		}
+		if err := deleteStaleServiceAccountTokens(ctx, managedEnvironmentCR, oldClusterCreds, k8sClientFactory, log); err != nil {
+			log.Error(err, "Unable to revoke new service account token, after failed service account token rotation")
+		}
+	}
+
+	// 2) Verify the connection to the cluster using the new token, before the managed environment is updated to use it
+	if validClusterCreds, err := verifyClusterCredentialsWithNamespaceList(ctx, newClusterCreds, managedEnvironmentCR, k8sClientFactory); !validClusterCreds || err != nil {
+		revertRotation()
+		return fmt.Errorf("unable to connect to the cluster using the new service account token: %v", err)
+	}
+
+	// 3) Update the managed environment to reference the new cluster credentials, then delete the old cluster credentials
+	managedEnv.Clustercredentials_id = newClusterCreds.Clustercredentials_cred_id
+	if err := dbQueries.UpdateManagedEnvironment(ctx, managedEnv); err != nil {
+		log.Error(err, "Unable to update ManagedEnvironment with rotated cluster credentials ID", managedEnv.GetAsLogKeyValues()...)
+
+		managedEnv.Clustercredentials_id = oldClusterCreds.Clustercredentials_cred_id
+		revertRotation()
+		return fmt.Errorf("unable to update managed environment with rotated credentials: %w", err)
+	}
+	log.Info("Updated ManagedEnvironment with rotated cluster credentials ID", managedEnv.GetAsLogKeyValues()...)
+
+	if _, err := dbQueries.DeleteClusterCredentialsById(ctx, oldClusterCreds.Clustercredentials_cred_id); err != nil {
+		log.Error(err, "Unable to delete old ClusterCredentials row which is no longer used by ManagedEnv")
+		return fmt.Errorf("unable to delete old cluster credentials '%s': %w", oldClusterCreds.Clustercredentials_cred_id, err)
+	}
+	log.Info("Deleted old ClusterCredentials row which is no longer used by ManagedEnv")
+
+	// 4) Instruct the cluster-agent to refresh the Argo CD cluster secret of the managed environment: the old token is
+	// still used by Argo CD until then, so it is only revoked once the Operations have completed.
+	gitopsEngineInstanceIDs, err := getGitOpsEngineInstanceIDsOfManagedEnv(ctx, *managedEnv, dbQueries)
+	if err != nil {
+		return err
+	}
+	if err := createArgoCDClusterSecretRefreshOperations(ctx, clusterUser, *managedEnv, gitopsEngineInstanceIDs, k8sClientFactory, dbQueries, log); err != nil {
+		return fmt.Errorf("unable to refresh Argo CD cluster secret with the rotated service account token: %w", err)
+	}
+
+	log.Info("Rotated the service account token of the managed environment", "newClusterCredentialsID", newClusterCreds.Clustercredentials_cred_id)
+
+	return nil
+}
+
+// revokeRotatedServiceAccountTokens deletes the previous token Secret(s) of the ServiceAccount of a managed environment,
+// once the Argo CD cluster secret of the managed environment has been refreshed with the current token in every Argo CD
+// instance. This is called on each reconcile of a managed environment whose token is rotated, so that a revocation that
+// could not be completed (for example, because an Operation was still in progress, or failed) is retried.
+//   - If there are no previous token Secrets, there is nothing to do.
+//   - An Operation to refresh the Argo CD cluster secret is (re)created for each Argo CD instance which has no Operation
+//     since the current cluster credentials were created, or whose most recent Operation failed.
+//   - Once the most recent Operation of every Argo CD instance has completed, the previous tokens are deleted, along with
+//     the Operations.
+func revokeRotatedServiceAccountTokens(ctx context.Context, clusterUser db.ClusterUser,
+	managedEnvironmentCR managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironment, managedEnv db.ManagedEnvironment,
+	clusterCreds db.ClusterCredentials, k8sClientFactory SRLK8sClientFactory, dbQueries db.DatabaseQueries, log logr.Logger) error {
+
+	managedEnvClient, err := getK8sClientOfClusterCredentials(clusterCreds, k8sClientFactory)
+	if err != nil {
+		return err
+	}
+
+	staleTokenSecrets, err := sharedutil.GetStaleServiceAccountTokenSecrets(ctx, managedEnvClient, string(managedEnvironmentCR.UID),
+		clusterCreds.Serviceaccount_ns, clusterCreds.Serviceaccount_bearer_token)
+	if err != nil {
+		return err
+	}
+	if len(staleTokenSecrets) == 0 {
+		return nil
+	}
+
+	gitopsEngineInstanceIDs, err := getGitOpsEngineInstanceIDsOfManagedEnv(ctx, managedEnv, dbQueries)
+	if err != nil {
+		return err
+	}
+
+	var refreshOperations []db.Operation
+	if err := dbQueries.ListOperationsByResourceIdAndTypeAndOwnerId(ctx, managedEnv.Managedenvironment_id,
+		db.OperationResourceType_ManagedEnvironment, &refreshOperations, clusterUser.Clusteruser_id); err != nil {
+		return fmt.Errorf("unable to list operations of managed environment '%s': %w", managedEnv.Managedenvironment_id, err)
+	}
+
+	// latestOperations is the most recent Operation of each Argo CD instance, since the cluster credentials were created
+	latestOperations := map[string]db.Operation{}
+	for _, operation := range refreshOperations {
+		if operation.Created_on.Before(clusterCreds.Created_on) {
+			continue
+		}
+		if latest, exists := latestOperations[operation.Instance_id]; !exists || operation.Created_on.After(latest.Created_on) {
+			latestOperations[operation.Instance_id] = operation
+		}
+	}
+
+	refreshed := true
+	instancesToRefresh := []string{}
+	for _, gitopsEngineInstanceID := range gitopsEngineInstanceIDs {
+		latest, exists := latestOperations[gitopsEngineInstanceID]
+		if exists && latest.State == db.OperationState_Completed {
+			continue
+		}
+		refreshed = false
+
+		if !exists || latest.State == db.OperationState_Failed {
+			instancesToRefresh = append(instancesToRefresh, gitopsEngineInstanceID)
+		}
+	}
+
+	if len(instancesToRefresh) > 0 {
+		if err := createArgoCDClusterSecretRefreshOperations(ctx, clusterUser, managedEnv, instancesToRefresh, k8sClientFactory, dbQueries, log); err != nil {
+			return fmt.Errorf("unable to refresh Argo CD cluster secret with the rotated service account token: %w", err)
+		}
+	}
+
+	if !refreshed {
+		log.V(logutil.LogLevel_Debug).Info("Waiting for the Argo CD cluster secrets of the managed environment to be refreshed, before revoking the previous service account token")
+		return nil
+	}
+
+	if err := sharedutil.DeleteStaleServiceAccountTokenSecrets(ctx, managedEnvClient, string(managedEnvironmentCR.UID),
+		clusterCreds.Serviceaccount_ns, clusterCreds.Serviceaccount_bearer_token, log); err != nil {
+		return fmt.Errorf("unable to revoke the previous service account token: %w", err)
+	}
+	log.Info("Revoked the previous service account token of the managed environment")
+
+	// The Operations are only cleaned up once the tokens have been revoked, as they record that the cluster secrets were refreshed
+	for _, operation := range refreshOperations {
+		if operation.State != db.OperationState_Completed && operation.State != db.OperationState_Failed {
+			continue
+		}
+		if err := cleanupArgoCDClusterSecretRefreshOperation(ctx, operation, k8sClientFactory, dbQueries, log); err != nil {
+			log.Error(err, "Unable to cleanup Operation to refresh Argo CD cluster secret", "operationID", operation.Operation_id)
+		}
+	}
+
+	return nil
+}
+
+// getGitOpsEngineInstanceIDsOfManagedEnv returns the IDs of the Argo CD instances that have access to the managed environment.
+func getGitOpsEngineInstanceIDsOfManagedEnv(ctx context.Context, managedEnv db.ManagedEnvironment, dbQueries db.DatabaseQueries) ([]string, error) {
+
+	clusterAccesses := []db.ClusterAccess{}
+	if err := dbQueries.ListClusterAccessesByManagedEnvironmentID(ctx, managedEnv.Managedenvironment_id, &clusterAccesses); err != nil {
+		return nil, fmt.Errorf("unable to list cluster accesses by managed id '%s': %w", managedEnv.Managedenvironment_id, err)
+	}
+
+	// gitopsEngineInstanceIDs is a hash set of all the gitops engine instances that have access to the managed environment
+	gitopsEngineInstanceIDs := map[string]bool{}
+	res := []string{}
+	for _, clusterAccess := range clusterAccesses {
+		if !gitopsEngineInstanceIDs[clusterAccess.Clusteraccess_gitops_engine_instance_id] {
+			gitopsEngineInstanceIDs[clusterAccess.Clusteraccess_gitops_engine_instance_id] = true
+			res = append(res, clusterAccess.Clusteraccess_gitops_engine_instance_id)
+		}
+	}
+
+	return res, nil
+}
+
+// createArgoCDClusterSecretRefreshOperations creates an Operation for each of the given Argo CD instances, which instructs the
+// cluster-agent to refresh the Argo CD cluster secret of the managed environment. The Operations are not waited for.
+func createArgoCDClusterSecretRefreshOperations(ctx context.Context, clusterUser db.ClusterUser, managedEnv db.ManagedEnvironment,
+	gitopsEngineInstanceIDs []string, k8sClientFactory SRLK8sClientFactory, dbQueries db.DatabaseQueries, log logr.Logger) error {
+
+	for _, gitopsEngineInstanceID := range gitopsEngineInstanceIDs {
+
+		gitopsEngineInstance := &db.GitopsEngineInstance{
+			Gitopsengineinstance_id: gitopsEngineInstanceID,
+		}
+		if err := dbQueries.GetGitopsEngineInstanceById(ctx, gitopsEngineInstance); err != nil {
+			return fmt.Errorf("unable to retrieve gitopsengineinstance '%s': %w", gitopsEngineInstanceID, err)
+		}
+
+		gitopsEngineClient, err := k8sClientFactory.GetK8sClientForGitOpsEngineInstance(ctx, gitopsEngineInstance)
+		if err != nil {
+			return fmt.Errorf("unable to retrieve k8s client for engine instance '%s': %w", gitopsEngineInstanceID, err)
+		}
+
+		operation := db.Operation{
+			Instance_id:             gitopsEngineInstanceID,
+			Operation_owner_user_id: clusterUser.Clusteruser_id,
+			Resource_type:           db.OperationResourceType_ManagedEnvironment,
+			Resource_id:             managedEnv.Managedenvironment_id,
+		}
+
+		log.Info("Creating Operation to refresh Argo CD cluster secret, referencing managed environment", "gitopsEngineInstanceID", gitopsEngineInstanceID)
+
+		if _, _, err := operations.CreateOperation(ctx, false, operation, clusterUser.Clusteruser_id,
+			gitopsEngineInstance.Namespace_name, dbQueries, gitopsEngineClient, log); err != nil {
+			return fmt.Errorf("unable to create operation for managed environment with rotated credentials: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// cleanupArgoCDClusterSecretRefreshOperation deletes an Operation that was created to refresh an Argo CD cluster secret,
+// and its Operation CR.
+func cleanupArgoCDClusterSecretRefreshOperation(ctx context.Context, operation db.Operation, k8sClientFactory SRLK8sClientFactory,
+	dbQueries db.DatabaseQueries, log logr.Logger) error {
+
+	gitopsEngineInstance := &db.GitopsEngineInstance{
+		Gitopsengineinstance_id: operation.Instance_id,
+	}
+	if err := dbQueries.GetGitopsEngineInstanceById(ctx, gitopsEngineInstance); err != nil {
+		return fmt.Errorf("unable to retrieve gitopsengineinstance '%s': %w", operation.Instance_id, err)
+	}
+
+	gitopsEngineClient, err := k8sClientFactory.GetK8sClientForGitOpsEngineInstance(ctx, gitopsEngineInstance)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve k8s client for engine instance '%s': %w", operation.Instance_id, err)
+	}
+
+	k8sOperation := managedgitopsv1alpha1.Operation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      operations.GenerateOperationCRName(operation),
+			Namespace: gitopsEngineInstance.Namespace_name,
+		},
+	}
+
+	return operations.CleanupOperation(ctx, operation, k8sOperation, dbQueries, gitopsEngineClient, true, log)
+}
+
+// deleteStaleServiceAccountTokens connects to the cluster of the managed environment using the given cluster credentials, and
+// deletes the token Secrets of the ServiceAccount of the managed environment, other than the token of those cluster credentials.
+func deleteStaleServiceAccountTokens(ctx context.Context, managedEnvironmentCR managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironment,
+	clusterCreds db.ClusterCredentials, k8sClientFactory SRLK8sClientFactory, log logr.Logger) error {
+
+	k8sClient, err := getK8sClientOfClusterCredentials(clusterCreds, k8sClientFactory)
+	if err != nil {
+		return err
+	}
+
+	return sharedutil.DeleteStaleServiceAccountTokenSecrets(ctx, k8sClient, string(managedEnvironmentCR.UID),
+		clusterCreds.Serviceaccount_ns, clusterCreds.Serviceaccount_bearer_token, log)
+}
+
+// getK8sClientOfClusterCredentials returns a client of the cluster of the given cluster credentials.
+func getK8sClientOfClusterCredentials(clusterCreds db.ClusterCredentials, k8sClientFactory SRLK8sClientFactory) (client.Client, error) {
+
+	restConfig, _, err := sanityTestCredentials(clusterCreds)
+	if err != nil {
+		return nil, err
+	}
+
+	k8sClient, err := k8sClientFactory.BuildK8sClient(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create client for '%s': %w", clusterCreds.Host, err)
+	}
+
+	return k8sClient, nil
+}
@@ -0,0 +1,290 @@
+package shared_resource_loop
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
+	db "github.com/redhat-appstudio/managed-gitops/backend-shared/db"
+	sharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/tests"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var _ = Describe("SharedResourceEventLoop ManagedEnvironment service account token rotation Test", func() {
+
+	Context("Test rotateServiceAccountToken function", func() {
+
+		var ctx context.Context
+		var log logr.Logger
+		var k8sClient client.WithWatch
+		var dbQueries db.AllDatabaseQueries
+
+		var managedEnvCR managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironment
+		var managedEnvSecret corev1.Secret
+		var managedEnvRow db.ManagedEnvironment
+		var oldClusterCreds db.ClusterCredentials
+		var oldTokenSecret *corev1.Secret
+
+		// listNamespacesErr is the error returned by the (fake) managed environment cluster, in response to a request to list namespaces
+		var listNamespacesErr error
+
+		BeforeEach(func() {
+			err := db.SetupForTestingDBGinkgo()
+			Expect(err).ToNot(HaveOccurred())
+
+			ctx = context.Background()
+			log = logf.FromContext(ctx)
+
+			scheme, argocdNamespace, kubesystemNamespace, namespace, err := tests.GenericTestSetup()
+			Expect(err).ToNot(HaveOccurred())
+
+			listNamespacesErr = nil
+
+			managedEnvCR = managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "my-managed-env",
+					Namespace: namespace.Name,
+					UID:       uuid.NewUUID(),
+				},
+				Spec: managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironmentSpec{
+					APIURL:                   "https://api.fake-unit-test-data.origin-ci-int-gce.dev.rhcloud.com:6443",
+					ClusterCredentialsSecret: "my-managed-env-secret",
+					CreateNewServiceAccount:  true,
+				},
+			}
+
+			managedEnvSecret = corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      managedEnvCR.Spec.ClusterCredentialsSecret,
+					Namespace: namespace.Name,
+				},
+				Type: sharedutil.ManagedEnvironmentSecretType,
+				Data: map[string][]byte{
+					KubeconfigKey: ([]byte)(generateFakeKubeConfig()),
+				},
+			}
+
+			oldTokenSecret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "old-token-secret",
+					Namespace: kubesystemNamespace.Name,
+					Annotations: map[string]string{
+						corev1.ServiceAccountNameKey: sharedutil.GenerateServiceAccountName(string(managedEnvCR.UID)),
+					},
+				},
+				Type: corev1.SecretTypeServiceAccountToken,
+				Data: map[string][]byte{
+					"token": []byte("old-token"),
+				},
+			}
+
+			k8sClient = fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(namespace, argocdNamespace, kubesystemNamespace, &managedEnvCR, &managedEnvSecret, oldTokenSecret).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Create: func(ctx context.Context, client client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+						// Simulate K8s populating the token of a new service account token Secret
+						if secret, ok := obj.(*corev1.Secret); ok && secret.Type == corev1.SecretTypeServiceAccountToken {
+							secret.Data = map[string][]byte{"token": []byte("new-token-" + string(uuid.NewUUID()))}
+						}
+						return client.Create(ctx, obj, opts...)
+					},
+					List: func(ctx context.Context, client client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+						if _, ok := list.(*corev1.NamespaceList); ok && listNamespacesErr != nil {
+							return listNamespacesErr
+						}
+						return client.List(ctx, list, opts...)
+					},
+				}).Build()
+
+			dbQueries, err = db.NewUnsafePostgresDBQueries(false, true)
+			Expect(err).ToNot(HaveOccurred())
+
+			oldClusterCreds = db.ClusterCredentials{
+				Host:                        managedEnvCR.Spec.APIURL,
+				Serviceaccount_bearer_token: "old-token",
+				Serviceaccount_ns:           kubesystemNamespace.Name,
+				AllowInsecureSkipTLSVerify:  true,
+			}
+			err = dbQueries.CreateClusterCredentials(ctx, &oldClusterCreds)
+			Expect(err).ToNot(HaveOccurred())
+
+			managedEnvRow = db.ManagedEnvironment{
+				Name:                  managedEnvCR.Name,
+				Clustercredentials_id: oldClusterCreds.Clustercredentials_cred_id,
+			}
+			err = dbQueries.CreateManagedEnvironment(ctx, &managedEnvRow)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			dbQueries.CloseDatabase()
+		})
+
+		It("should replace the cluster credentials with a new token, and revoke the old token on the next reconcile, if the connection check succeeds", func() {
+
+			mockFactory := MockSRLK8sClientFactory{fakeClient: k8sClient}
+
+			err := rotateServiceAccountToken(ctx, k8sClient, db.ClusterUser{Clusteruser_id: "test-user"}, managedEnvCR, managedEnvSecret,
+				&managedEnvRow, oldClusterCreds, mockFactory, dbQueries, log)
+			Expect(err).ToNot(HaveOccurred())
+
+			By("verifying the managed environment references new cluster credentials, containing a new token")
+			Expect(managedEnvRow.Clustercredentials_id).ToNot(Equal(oldClusterCreds.Clustercredentials_cred_id))
+
+			updatedManagedEnvRow := db.ManagedEnvironment{Managedenvironment_id: managedEnvRow.Managedenvironment_id}
+			err = dbQueries.GetManagedEnvironmentById(ctx, &updatedManagedEnvRow)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updatedManagedEnvRow.Clustercredentials_id).To(Equal(managedEnvRow.Clustercredentials_id))
+
+			newClusterCreds := db.ClusterCredentials{Clustercredentials_cred_id: managedEnvRow.Clustercredentials_id}
+			err = dbQueries.GetClusterCredentialsById(ctx, &newClusterCreds)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(newClusterCreds.Serviceaccount_bearer_token).To(HavePrefix("new-token-"))
+
+			By("verifying the old cluster credentials were deleted")
+			err = dbQueries.GetClusterCredentialsById(ctx, &db.ClusterCredentials{Clustercredentials_cred_id: oldClusterCreds.Clustercredentials_cred_id})
+			Expect(db.IsResultNotFoundError(err)).To(BeTrue())
+
+			By("verifying the old token secret is only deleted by the next reconcile")
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(oldTokenSecret), oldTokenSecret)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = revokeRotatedServiceAccountTokens(ctx, db.ClusterUser{Clusteruser_id: "test-user"}, managedEnvCR, managedEnvRow,
+				newClusterCreds, mockFactory, dbQueries, log)
+			Expect(err).ToNot(HaveOccurred())
+
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(oldTokenSecret), oldTokenSecret)
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+		})
+
+		It("should only revoke the old token once the Argo CD cluster secret has been refreshed, retrying failed refreshes", func() {
+
+			mockFactory := MockSRLK8sClientFactory{fakeClient: k8sClient}
+
+			_, _, _, engineInstance, sampleClusterAccess, err := db.CreateSampleData(dbQueries)
+			Expect(err).ToNot(HaveOccurred())
+
+			clusterUser := db.ClusterUser{Clusteruser_id: sampleClusterAccess.Clusteraccess_user_id}
+
+			err = dbQueries.CreateClusterAccess(ctx, &db.ClusterAccess{
+				Clusteraccess_user_id:                   clusterUser.Clusteruser_id,
+				Clusteraccess_managed_environment_id:    managedEnvRow.Managedenvironment_id,
+				Clusteraccess_gitops_engine_instance_id: engineInstance.Gitopsengineinstance_id,
+			})
+			Expect(err).ToNot(HaveOccurred())
+
+			err = rotateServiceAccountToken(ctx, k8sClient, clusterUser, managedEnvCR, managedEnvSecret,
+				&managedEnvRow, oldClusterCreds, mockFactory, dbQueries, log)
+			Expect(err).ToNot(HaveOccurred())
+
+			newClusterCreds := db.ClusterCredentials{Clustercredentials_cred_id: managedEnvRow.Clustercredentials_id}
+			err = dbQueries.GetClusterCredentialsById(ctx, &newClusterCreds)
+			Expect(err).ToNot(HaveOccurred())
+
+			By("verifying an Operation was created to refresh the Argo CD cluster secret, without waiting for it")
+			refreshOperations := getAllOperationsForResourceID(ctx, managedEnvRow.Managedenvironment_id, dbQueries)
+			Expect(refreshOperations).To(HaveLen(1))
+			Expect(refreshOperations[0].State).To(Equal(db.OperationState_Waiting))
+
+			revoke := func() {
+				err := revokeRotatedServiceAccountTokens(ctx, clusterUser, managedEnvCR, managedEnvRow, newClusterCreds, mockFactory, dbQueries, log)
+				Expect(err).ToNot(HaveOccurred())
+			}
+
+			By("verifying the old token is not revoked while the Operation is in progress")
+			revoke()
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(oldTokenSecret), oldTokenSecret)).To(Succeed())
+			Expect(getAllOperationsForResourceID(ctx, managedEnvRow.Managedenvironment_id, dbQueries)).To(HaveLen(1))
+
+			By("verifying a new Operation is created if the Operation failed, and the old token is not revoked")
+			refreshOperations[0].State = db.OperationState_Failed
+			Expect(dbQueries.UpdateOperation(ctx, &refreshOperations[0])).To(Succeed())
+
+			revoke()
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(oldTokenSecret), oldTokenSecret)).To(Succeed())
+
+			refreshOperations = getAllOperationsForResourceID(ctx, managedEnvRow.Managedenvironment_id, dbQueries)
+			Expect(refreshOperations).To(HaveLen(2))
+
+			By("verifying the old token is revoked, and the Operations are cleaned up, once the new Operation has completed")
+			for idx := range refreshOperations {
+				if refreshOperations[idx].State == db.OperationState_Waiting {
+					refreshOperations[idx].State = db.OperationState_Completed
+					Expect(dbQueries.UpdateOperation(ctx, &refreshOperations[idx])).To(Succeed())
+				}
+			}
+
+			revoke()
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(oldTokenSecret), oldTokenSecret)
+			Expect(k8serrors.IsNotFound(err)).To(BeTrue())
+			Expect(getAllOperationsForResourceID(ctx, managedEnvRow.Managedenvironment_id, dbQueries)).To(BeEmpty())
+		})
+
+		It("should continue to use the old cluster credentials and token, if the connection check using the new token fails", func() {
+
+			listNamespacesErr = k8serrors.NewUnauthorized("new token is not valid")
+
+			err := rotateServiceAccountToken(ctx, k8sClient, db.ClusterUser{Clusteruser_id: "test-user"}, managedEnvCR, managedEnvSecret,
+				&managedEnvRow, oldClusterCreds, MockSRLK8sClientFactory{fakeClient: k8sClient}, dbQueries, log)
+			Expect(err).To(HaveOccurred())
+
+			By("verifying the managed environment still references the old cluster credentials")
+			Expect(managedEnvRow.Clustercredentials_id).To(Equal(oldClusterCreds.Clustercredentials_cred_id))
+
+			updatedManagedEnvRow := db.ManagedEnvironment{Managedenvironment_id: managedEnvRow.Managedenvironment_id}
+			err = dbQueries.GetManagedEnvironmentById(ctx, &updatedManagedEnvRow)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updatedManagedEnvRow.Clustercredentials_id).To(Equal(oldClusterCreds.Clustercredentials_cred_id))
+
+			By("verifying the old token secret was not deleted, and the new token secret was deleted")
+			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(oldTokenSecret), oldTokenSecret)
+			Expect(err).ToNot(HaveOccurred())
+
+			var secretList corev1.SecretList
+			err = k8sClient.List(ctx, &secretList, client.InNamespace(oldTokenSecret.Namespace))
+			Expect(err).ToNot(HaveOccurred())
+			for _, secret := range secretList.Items {
+				if secret.Type == corev1.SecretTypeServiceAccountToken {
+					Expect(secret.Name).To(Equal(oldTokenSecret.Name))
+				}
+			}
+		})
+	})
+
+	Context("Test isServiceAccountTokenRotationDue function", func() {
+
+		DescribeTable("should only return true if the token of a service account created by the GitOps Service is older than the rotation interval",
+			func(createNewServiceAccount bool, token string, tokenAge time.Duration, rotationInterval time.Duration, expected bool) {
+
+				managedEnvCR := managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironment{
+					Spec: managedgitopsv1alpha1.GitOpsDeploymentManagedEnvironmentSpec{
+						CreateNewServiceAccount: createNewServiceAccount,
+					},
+				}
+				clusterCreds := db.ClusterCredentials{
+					Serviceaccount_bearer_token: token,
+					Created_on:                  time.Now().Add(-tokenAge),
+				}
+
+				Expect(isServiceAccountTokenRotationDue(managedEnvCR, clusterCreds, rotationInterval)).To(Equal(expected))
+			},
+			Entry("token is older than the rotation interval", true, "token", 2*time.Hour, time.Hour, true),
+			Entry("token is newer than the rotation interval", true, "token", 30*time.Minute, time.Hour, false),
+			Entry("rotation is disabled", true, "token", 2*time.Hour, time.Duration(0), false),
+			Entry("service account was not created by the GitOps Service", false, "token", 2*time.Hour, time.Hour, false),
+			Entry("cluster credentials don't contain a token", true, "", 2*time.Hour, time.Hour, false),
+		)
+	})
+})
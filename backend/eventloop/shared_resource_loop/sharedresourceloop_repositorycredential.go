@@ -20,8 +20,10 @@ import (
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/db"
 	sharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/operations"
+	"github.com/redhat-appstudio/managed-gitops/backend/events"
 	corev1 "k8s.io/api/core/v1"
 	apierr "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -40,7 +42,7 @@ func internalProcessMessage_ReconcileRepositoryCredential(ctx context.Context,
 	repositoryCredentialCRNamespace corev1.Namespace,
 	validateRepoURLFunction ValidateRepoURLAndCredentialsFunction,
 	apiNamespaceClient client.Client,
-	dbQueries db.DatabaseQueries, shouldWait bool, l logr.Logger) (*db.RepositoryCredentials, error) {
+	dbQueries db.DatabaseQueries, shouldWait bool, eventRecorder *events.Recorder, l logr.Logger) (*db.RepositoryCredentials, error) {
 
 	resourceNS := repositoryCredentialCRNamespace.Name
 
@@ -177,7 +179,7 @@ func internalProcessMessage_ReconcileRepositoryCredential(ctx context.Context,
 
 	// Sanity test for gitopsDeploymentRepositoryCredentialCR.Spec.Secret to be non-empty value
 	if gitopsDeploymentRepositoryCredentialCR.Spec.Secret == "" {
-		if _, err := UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, gitopsDeploymentRepositoryCredentialCR, nil, validateRepoURLFunction, apiNamespaceClient, l); err != nil {
+		if _, err := UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, gitopsDeploymentRepositoryCredentialCR, nil, validateRepoURLFunction, apiNamespaceClient, eventRecorder, l); err != nil {
 			l.Error(err, fmt.Sprintf("error updating status of GitopsDeploymentRepositoryCredential %v", gitopsDeploymentRepositoryCredentialCR))
 		}
 		return nil, fmt.Errorf("secret cannot be empty")
@@ -205,7 +207,7 @@ func internalProcessMessage_ReconcileRepositoryCredential(ctx context.Context,
 			// Something went wrong, retry
 			errMessage = fmt.Errorf("error retrieving secret: %v", err)
 		}
-		if _, err := UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, gitopsDeploymentRepositoryCredentialCR, secret, validateRepoURLFunction, apiNamespaceClient, l); err != nil {
+		if _, err := UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, gitopsDeploymentRepositoryCredentialCR, secret, validateRepoURLFunction, apiNamespaceClient, eventRecorder, l); err != nil {
 			l.Error(err, fmt.Sprintf("error updating status of GitopsDeploymentRepositoryCredential %v", gitopsDeploymentRepositoryCredentialCR))
 		}
 
@@ -219,7 +221,7 @@ func internalProcessMessage_ReconcileRepositoryCredential(ctx context.Context,
 	}
 
 	// Before updating the records in DB, we need to set the Conditions of the CR
-	if isValidRepositoryCredentials, err := UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, gitopsDeploymentRepositoryCredentialCR, secret, validateRepoURLFunction, apiNamespaceClient, l); err != nil {
+	if isValidRepositoryCredentials, err := UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, gitopsDeploymentRepositoryCredentialCR, secret, validateRepoURLFunction, apiNamespaceClient, eventRecorder, l); err != nil {
 
 		retMsg := fmt.Sprintf("error updating status of GitopsDeploymentRepositoryCredential %v", gitopsDeploymentRepositoryCredentialCR)
 		l.Error(err, retMsg)
@@ -448,7 +450,7 @@ func createRepoCredOperation(ctx context.Context, dbRepoCred db.RepositoryCreden
 // to preserve the LastTransitionTime (see https://pkg.go.dev/k8s.io/apimachinery/pkg/apis/meta/v1#Condition.LastTransitionTime )
 //
 // returns true if the RepositoryCredentials status is valid, false otherwise (for example, false if CR references a Secret that doesn't exist)
-func UpdateGitopsDeploymentRepositoryCredentialStatus(ctx context.Context, repositoryCredential *managedgitopsv1alpha1.GitOpsDeploymentRepositoryCredential, secret *corev1.Secret, validateRepoURL ValidateRepoURLAndCredentialsFunction, k8sClient client.Client, log logr.Logger) (bool, error) {
+func UpdateGitopsDeploymentRepositoryCredentialStatus(ctx context.Context, repositoryCredential *managedgitopsv1alpha1.GitOpsDeploymentRepositoryCredential, secret *corev1.Secret, validateRepoURL ValidateRepoURLAndCredentialsFunction, k8sClient client.Client, eventRecorder *events.Recorder, log logr.Logger) (bool, error) {
 
 	// if the condition was sent along with the function call, we don't need to perform additional checks
 	newConditions, areCredsValid := generateRepositoryCredentialsConditions(ctx, *repositoryCredential, secret, validateRepoURL)
@@ -477,17 +479,41 @@ func UpdateGitopsDeploymentRepositoryCredentialStatus(ctx context.Context, repos
 			return false, vErr
 		}
 
+		oldErrorOccurredCondition := meta.FindStatusCondition(repositoryCredential.Status.Conditions,
+			managedgitopsv1alpha1.GitOpsDeploymentRepositoryCredentialConditionErrorOccurred)
+
 		repositoryCredential.Status.SetConditions(newConditions)
 		// Update the GitOpsDeploymentRepositoryCredential CR
 		if err := k8sClient.Status().Update(ctx, repositoryCredential); err != nil {
 			log.Error(err, "updating repository credential CR's status condition", "ns", repositoryCredential.Namespace, "name", repositoryCredential.Name)
 			return false, fmt.Errorf("updating repository credential CR's status condition: %w", err)
 		}
+
+		recordRepositoryCredentialEvent(repositoryCredential, oldErrorOccurredCondition, eventRecorder)
 	}
 
 	return areCredsValid, nil
 }
 
+// recordRepositoryCredentialEvent records a K8s Event on the GitOpsDeploymentRepositoryCredential, if its ErrorOccurred
+// condition has changed from the given previous condition.
+func recordRepositoryCredentialEvent(repositoryCredential *managedgitopsv1alpha1.GitOpsDeploymentRepositoryCredential, oldErrorOccurredCondition *metav1.Condition, eventRecorder *events.Recorder) {
+
+	newErrorOccurredCondition := meta.FindStatusCondition(repositoryCredential.Status.Conditions,
+		managedgitopsv1alpha1.GitOpsDeploymentRepositoryCredentialConditionErrorOccurred)
+
+	if newErrorOccurredCondition == nil || (oldErrorOccurredCondition != nil && oldErrorOccurredCondition.Status == newErrorOccurredCondition.Status &&
+		oldErrorOccurredCondition.Reason == newErrorOccurredCondition.Reason && oldErrorOccurredCondition.Message == newErrorOccurredCondition.Message) {
+		return
+	}
+
+	if newErrorOccurredCondition.Status == metav1.ConditionTrue {
+		eventRecorder.RecordWarning(repositoryCredential, newErrorOccurredCondition.Reason, newErrorOccurredCondition.Message)
+	} else {
+		eventRecorder.RecordNormal(repositoryCredential, newErrorOccurredCondition.Reason, newErrorOccurredCondition.Message)
+	}
+}
+
 // generateValidRepositoryCredentialsConditions generates set of conditions for the repository credentials, plus true/false on whether the credential data was valid
 func generateRepositoryCredentialsConditions(ctx context.Context, repositoryCredential managedgitopsv1alpha1.GitOpsDeploymentRepositoryCredential, secret *corev1.Secret, isValidRepoURLAndCredentials func(rawRepoURL string, secret corev1.Secret) error) ([]metav1.Condition, bool) {
 
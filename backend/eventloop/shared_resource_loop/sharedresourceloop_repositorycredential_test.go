@@ -182,7 +182,7 @@ var _ = Describe("SharedResourceEventLoop Repository Credential Tests", func() {
 				Type: sharedutil.RepositoryCredentialSecretType,
 			}
 
-			isValid, err := UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, gitopsDeploymentRepositoryCredentialCR, secret, mock_returnInvalidRepositoryCredentials, k8sClient, log.FromContext(ctx))
+			isValid, err := UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, gitopsDeploymentRepositoryCredentialCR, secret, mock_returnInvalidRepositoryCredentials, k8sClient, nil, log.FromContext(ctx))
 
 			Expect(err).ToNot(HaveOccurred())
 			Expect(isValid).To(BeFalse())
@@ -230,7 +230,7 @@ var _ = Describe("SharedResourceEventLoop Repository Credential Tests", func() {
 				Type: sharedutil.RepositoryCredentialSecretType,
 			}
 
-			isValid, err := UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, gitopsDeploymentRepositoryCredentialCR, secret, mock_returnInvalidRepositoryCredentials, k8sClient, log.FromContext(ctx))
+			isValid, err := UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, gitopsDeploymentRepositoryCredentialCR, secret, mock_returnInvalidRepositoryCredentials, k8sClient, nil, log.FromContext(ctx))
 
 			Expect(isValid).To(BeFalse())
 			Expect(err).ToNot(HaveOccurred())
@@ -261,7 +261,7 @@ var _ = Describe("SharedResourceEventLoop Repository Credential Tests", func() {
 				},
 			}
 
-			isValid, err := UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, gitopsDeploymentRepositoryCredentialCR, nil, mock_returnValidRepositoryCredentials, k8sClient, log.FromContext(ctx))
+			isValid, err := UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, gitopsDeploymentRepositoryCredentialCR, nil, mock_returnValidRepositoryCredentials, k8sClient, nil, log.FromContext(ctx))
 			Expect(err).ToNot(HaveOccurred())
 			Expect(isValid).To(BeFalse())
 
@@ -292,7 +292,7 @@ var _ = Describe("SharedResourceEventLoop Repository Credential Tests", func() {
 				},
 			}
 
-			isValid, err := UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, gitopsDeploymentRepositoryCredentialCR, nil, mock_returnValidRepositoryCredentials, k8sClient, log.FromContext(ctx))
+			isValid, err := UpdateGitopsDeploymentRepositoryCredentialStatus(ctx, gitopsDeploymentRepositoryCredentialCR, nil, mock_returnValidRepositoryCredentials, k8sClient, nil, log.FromContext(ctx))
 
 			Expect(err).ToNot(HaveOccurred())
 			Expect(isValid).To(BeFalse())
@@ -157,7 +157,7 @@ var _ = Describe("SharedResourceEventLoop Test", func() {
 
 		It("Should create or fetch a user by Namespace id.", func() {
 
-			sharedResourceEventLoop := NewSharedResourceLoop()
+			sharedResourceEventLoop := NewSharedResourceLoop(nil)
 
 			// At first assuming there are no existing users, hence creating new.
 			usrOld,
@@ -189,7 +189,7 @@ var _ = Describe("SharedResourceEventLoop Test", func() {
 
 		It("Should create or fetch resources.", func() {
 
-			sharedResourceEventLoop := NewSharedResourceLoop()
+			sharedResourceEventLoop := NewSharedResourceLoop(nil)
 
 			// At first assuming there are no existing resources, hence creating new.
 			sharedResourceOld, isUserErr, err := sharedResourceEventLoop.ReconcileSharedManagedEnv(ctx, k8sClient, *namespace, "", "",
@@ -246,7 +246,7 @@ var _ = Describe("SharedResourceEventLoop Test", func() {
 		})
 
 		It("Should fetch a engine instance by ID.", func() {
-			sharedResourceEventLoop := NewSharedResourceLoop()
+			sharedResourceEventLoop := NewSharedResourceLoop(nil)
 
 			// Negative test, engineInstance is not present, it should return error
 			engineInstanceOld, err := sharedResourceEventLoop.GetGitopsEngineInstanceById(ctx, "", k8sClient, *namespace, l)
@@ -303,7 +303,7 @@ var _ = Describe("SharedResourceEventLoop Test", func() {
 
 		It("Should fetch a GitOpsDeploymentRepositoryCredential.", func() {
 
-			sharedResourceEventLoop := NewSharedResourceLoop()
+			sharedResourceEventLoop := NewSharedResourceLoop(nil)
 
 			// Create new engine instance which will be used by "GetGitopsEngineInstanceById" fucntion
 			dbq, err := db.NewUnsafePostgresDBQueries(false, true)
@@ -397,7 +397,7 @@ var _ = Describe("SharedResourceEventLoop Test", func() {
 			repositoryCredentialCRNamespace.Name = gitopsEngineInstance.Namespace_name
 			repositoryCredentialCRNamespace.UID = types.UID(gitopsEngineInstance.Namespace_uid)
 
-			dbRepoCred, err := internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, mock_returnValidRepositoryCredentials, k8sClient, dbq, false, l)
+			dbRepoCred, err := internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, mock_returnValidRepositoryCredentials, k8sClient, dbq, false, nil, l)
 
 			// Negative test (there is no Secret)
 			Expect(err).To(HaveOccurred())
@@ -420,7 +420,7 @@ var _ = Describe("SharedResourceEventLoop Test", func() {
 
 			// Create again the CR
 			// Expected: Since there's no DB entry for the CR, it will create an operation
-			dbRepoCred, err = internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, mock_returnValidRepositoryCredentials, k8sClient, dbq, false, l)
+			dbRepoCred, err = internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, mock_returnValidRepositoryCredentials, k8sClient, dbq, false, nil, l)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(dbRepoCred).NotTo(BeNil())
 
@@ -498,7 +498,7 @@ var _ = Describe("SharedResourceEventLoop Test", func() {
 
 			// Re-running should not error
 			fmt.Println("Re-running the internalProcessMessage_ReconcileRepositoryCredential()")
-			dbRepoCred, err = internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, mock_returnValidRepositoryCredentials, k8sClient, dbq, false, l)
+			dbRepoCred, err = internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, mock_returnValidRepositoryCredentials, k8sClient, dbq, false, nil, l)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(dbRepoCred).NotTo(BeNil())
 
@@ -522,7 +522,7 @@ var _ = Describe("SharedResourceEventLoop Test", func() {
 			err = dbq.UpdateRepositoryCredentials(ctx, dbRepoCred)
 			Expect(err).ToNot(HaveOccurred())
 
-			dbRepoCred, err = internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, mock_returnValidRepositoryCredentials, k8sClient, dbq, false, l)
+			dbRepoCred, err = internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, mock_returnValidRepositoryCredentials, k8sClient, dbq, false, nil, l)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(dbRepoCred).ToNot(BeNil())
 
@@ -564,7 +564,7 @@ var _ = Describe("SharedResourceEventLoop Test", func() {
 			Expect(err).To(HaveOccurred()) // err unexpected number of rows affected:
 			// Expect(err).ToNot(HaveOccurred())
 
-			dbRepoCred, err = internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, mock_returnValidRepositoryCredentials, k8sClient, dbq, false, l)
+			dbRepoCred, err = internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, mock_returnValidRepositoryCredentials, k8sClient, dbq, false, nil, l)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(dbRepoCred).ToNot(BeNil())
 
@@ -591,7 +591,7 @@ var _ = Describe("SharedResourceEventLoop Test", func() {
 			// Expected: Since there is no GitOpsDeploymentRepositoryCredential CR, it will delete the DB entry
 			err = k8sClient.Delete(ctx, cr)
 			Expect(err).ToNot(HaveOccurred())
-			dbRepoCred, err = internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, mock_returnValidRepositoryCredentials, k8sClient, dbq, false, l)
+			dbRepoCred, err = internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, mock_returnValidRepositoryCredentials, k8sClient, dbq, false, nil, l)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(dbRepoCred).To(BeNil())
 
@@ -614,7 +614,7 @@ var _ = Describe("SharedResourceEventLoop Test", func() {
 
 			// Negative test: Try again to reconcile the RepositoryCredential
 			// Expected: It should not error (both db row and CR should be deleted). Nothing we can do.
-			dbRepoCred, err = internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, mock_returnValidRepositoryCredentials, k8sClient, dbq, false, l)
+			dbRepoCred, err = internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, mock_returnValidRepositoryCredentials, k8sClient, dbq, false, nil, l)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(dbRepoCred).To(BeNil())
 
@@ -634,7 +634,7 @@ var _ = Describe("SharedResourceEventLoop Test", func() {
 			err = dbq.CreateClusterUser(ctx, clusterUserDb)
 			Expect(err).ToNot(HaveOccurred())
 
-			sharedResourceEventLoop := NewSharedResourceLoop()
+			sharedResourceEventLoop := NewSharedResourceLoop(nil)
 
 			user,
 				isNewUser,
@@ -755,7 +755,7 @@ var _ = Describe("SharedResourceEventLoop Test", func() {
 			err = k8sClient.Create(ctx, secret)
 			Expect(err).ToNot(HaveOccurred())
 
-			dbRepoCred, err := internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, mock_returnValidRepositoryCredentials, k8sClient, dbq, false, l)
+			dbRepoCred, err := internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, mock_returnValidRepositoryCredentials, k8sClient, dbq, false, nil, l)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(dbRepoCred).NotTo(BeNil())
 
@@ -779,7 +779,7 @@ var _ = Describe("SharedResourceEventLoop Test", func() {
 			err := db.SetupForTestingDBGinkgo()
 			Expect(err).ToNot(HaveOccurred())
 
-			sharedResourceEventLoop := NewSharedResourceLoop()
+			sharedResourceEventLoop := NewSharedResourceLoop(nil)
 
 			By("Create new engine instance which will be used by `GetGitopsEngineInstanceById` function")
 			dbq, err := db.NewUnsafePostgresDBQueries(false, true)
@@ -878,7 +878,7 @@ var _ = Describe("SharedResourceEventLoop Test", func() {
 			err = k8sClient.Create(ctx, secret)
 			Expect(err).ToNot(HaveOccurred())
 
-			dbRepoCred, err := internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, mock_returnValidRepositoryCredentials, k8sClient, dbq, false, l)
+			dbRepoCred, err := internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, mock_returnValidRepositoryCredentials, k8sClient, dbq, false, nil, l)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(dbRepoCred).NotTo(BeNil())
 
@@ -906,7 +906,7 @@ var _ = Describe("SharedResourceEventLoop Test", func() {
 			Expect(err).ToNot(HaveOccurred())
 			Expect(appProjectRepositoryDB).NotTo(BeNil())
 
-			dbRepoCred, err = internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, mock_returnValidRepositoryCredentials, k8sClient, dbq, false, l)
+			dbRepoCred, err = internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, mock_returnValidRepositoryCredentials, k8sClient, dbq, false, nil, l)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(dbRepoCred).NotTo(BeNil())
 			Expect(dbRepoCred.PrivateURL).To(Equal("http://github.com/jgwest/my-repo"))
@@ -1001,7 +1001,7 @@ var _ = Describe("SharedResourceEventLoop Test", func() {
 			repositoryCredentialCRNamespace.Name = gitopsEngineInstance.Namespace_name
 			repositoryCredentialCRNamespace.UID = types.UID(gitopsEngineInstance.Namespace_uid)
 
-			dbRepoCred, err := internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, mock_returnValidRepositoryCredentials, k8sClient, dbq, false, l)
+			dbRepoCred, err := internalProcessMessage_ReconcileRepositoryCredential(ctx, cr.Name, repositoryCredentialCRNamespace, mock_returnValidRepositoryCredentials, k8sClient, dbq, false, nil, l)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(dbRepoCred).ToNot(BeNil())
 
@@ -1023,7 +1023,7 @@ var _ = Describe("SharedResourceEventLoop Test", func() {
 
 		It("Should test ReconcileRepositoryCredential when RepositoryCredential CR is nil", func() {
 
-			sharedResourceEventLoop := NewSharedResourceLoop()
+			sharedResourceEventLoop := NewSharedResourceLoop(nil)
 
 			repoCreds, err := sharedResourceEventLoop.ReconcileRepositoryCredential(ctx, k8sClient, *namespace, "test-name", MockSRLK8sClientFactory{fakeClient: k8sClient}, log.FromContext(context.Background()))
 			Expect(err).ToNot(HaveOccurred())
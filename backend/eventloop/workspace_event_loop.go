@@ -14,6 +14,7 @@ import (
 	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/application_event_loop"
 	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/eventlooptypes"
 	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/shared_resource_loop"
+	"github.com/redhat-appstudio/managed-gitops/backend/events"
 	corev1 "k8s.io/api/core/v1"
 	apierr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -38,24 +39,24 @@ import (
 
 // Start a workspace event loop router go routine, which is responsible for handling API namespace events and
 // then passing them to the controller loop.
-func newWorkspaceEventLoopRouter(namespaceName string, namespaceID string) WorkspaceEventLoopRouterStruct {
+func newWorkspaceEventLoopRouter(namespaceName string, namespaceID string, eventRecorder *events.Recorder) WorkspaceEventLoopRouterStruct {
 
 	res := WorkspaceEventLoopRouterStruct{
 		channel: make(chan workspaceEventLoopMessage),
 	}
 
-	internalStartWorkspaceEventLoopRouter(res.channel, namespaceName, namespaceID, defaultApplicationEventLoopFactory{})
+	internalStartWorkspaceEventLoopRouter(res.channel, namespaceName, namespaceID, eventRecorder, defaultApplicationEventLoopFactory{})
 
 	return res
 }
 
-func newWorkspaceEventLoopRouterWithFactory(namespaceName string, namespaceID string, applEventLoopFactory applicationEventQueueLoopFactory) WorkspaceEventLoopRouterStruct {
+func newWorkspaceEventLoopRouterWithFactory(namespaceName string, namespaceID string, eventRecorder *events.Recorder, applEventLoopFactory applicationEventQueueLoopFactory) WorkspaceEventLoopRouterStruct {
 
 	res := WorkspaceEventLoopRouterStruct{
 		channel: make(chan workspaceEventLoopMessage),
 	}
 
-	internalStartWorkspaceEventLoopRouter(res.channel, namespaceName, namespaceID, applEventLoopFactory)
+	internalStartWorkspaceEventLoopRouter(res.channel, namespaceName, namespaceID, eventRecorder, applEventLoopFactory)
 
 	return res
 }
@@ -89,7 +90,7 @@ type workspaceEventLoopMessage struct {
 // internalStartWorkspaceEventLoopRouter has the primary goal of catching panics from the workspaceEventLoopRouter, and
 // recovering from them.
 func internalStartWorkspaceEventLoopRouter(input chan workspaceEventLoopMessage, namespaceName string, namespaceID string,
-	applEventLoopFactory applicationEventQueueLoopFactory) {
+	eventRecorder *events.Recorder, applEventLoopFactory applicationEventQueueLoopFactory) {
 
 	go func() {
 
@@ -102,7 +103,7 @@ func internalStartWorkspaceEventLoopRouter(input chan workspaceEventLoopMessage,
 
 		for {
 			isPanic, _ := sharedutil.CatchPanic(func() error {
-				workspaceEventLoopRouter(input, namespaceName, namespaceID, applEventLoopFactory)
+				workspaceEventLoopRouter(input, namespaceName, namespaceID, eventRecorder, applEventLoopFactory)
 				return nil
 			})
 
@@ -178,6 +179,13 @@ type workspaceEventLoopInternalState struct {
 	// sharedResourceEventLoop is a reference to the shared resource loop
 	sharedResourceEventLoop *shared_resource_loop.SharedResourceEventLoop
 
+	// notificationSender sends the notifications of the GitOpsDeployments of the namespace, on behalf of their
+	// application event loops
+	notificationSender *application_event_loop.NotificationSender
+
+	// eventRecorder records K8s Events on the GitOps API resources of the namespace
+	eventRecorder *events.Recorder
+
 	// namespaceID is the UID of the namespace that the workspace event loop is handling
 	namespaceID string
 
@@ -194,7 +202,7 @@ type workspaceEventLoopInternalState struct {
 // workspaceEventLoopRouter receives all events for the namespace, and passes them to specific goroutine responsible
 // for handling events for individual applications.
 func workspaceEventLoopRouter(input chan workspaceEventLoopMessage, namespaceName string, namespaceID string,
-	applEventLoopFactory applicationEventQueueLoopFactory) {
+	eventRecorder *events.Recorder, applEventLoopFactory applicationEventQueueLoopFactory) {
 
 	ctx := context.Background()
 
@@ -205,10 +213,12 @@ func workspaceEventLoopRouter(input chan workspaceEventLoopMessage, namespaceNam
 	log.Info("workspaceEventLoopRouter started")
 	defer log.Info("workspaceEventLoopRouter ended.")
 
-	sharedResourceEventLoop := shared_resource_loop.NewSharedResourceLoop()
+	sharedResourceEventLoop := shared_resource_loop.NewSharedResourceLoop(eventRecorder)
 
 	state := workspaceEventLoopInternalState{
 		sharedResourceEventLoop: sharedResourceEventLoop,
+		notificationSender:      application_event_loop.NewNotificationSender(namespaceName, namespaceID, log),
+		eventRecorder:           eventRecorder,
 		orphanedResources:       map[string]map[string]eventlooptypes.EventLoopEvent{},
 		applicationMap:          map[string]workspaceEventLoop_applicationEventLoopEntry{},
 		applEventLoopFactory:    applEventLoopFactory,
@@ -304,12 +314,13 @@ func handleWorkspaceEventLoopMessage(ctx context.Context, event eventlooptypes.E
 	if event.Event.ReqResource == eventlooptypes.GitOpsDeploymentTypeName {
 		associatedGitOpsDeploymentName = event.Event.Request.Name
 
-	} else if event.Event.ReqResource == eventlooptypes.GitOpsDeploymentSyncRunTypeName {
+	} else if event.Event.ReqResource == eventlooptypes.GitOpsDeploymentSyncRunTypeName ||
+		event.Event.ReqResource == eventlooptypes.GitOpsDeploymentDiffTypeName {
 
-		associatedGitOpsDeploymentName = checkIfOrphanedGitOpsDeploymentSyncRun(ctx, event, state.orphanedResources, log)
+		associatedGitOpsDeploymentName = checkIfOrphanedResource(ctx, event, state.orphanedResources, log)
 
 		if associatedGitOpsDeploymentName == "" {
-			// The SyncRun no longer exists, or an unrecoverable error occurred, so just continue
+			// The resource is orphaned, or an unrecoverable error occurred, so just continue
 			return
 		}
 	}
@@ -327,7 +338,7 @@ func handleWorkspaceEventLoopMessage(ctx context.Context, event eventlooptypes.E
 
 		var err error
 		applicationEntryVal, err = startApplicationEventQueueLoop(ctx, event.Event.Client, associatedGitOpsDeploymentName, event,
-			state.sharedResourceEventLoop, state.applEventLoopFactory, log)
+			state.sharedResourceEventLoop, state.notificationSender, state.eventRecorder, state.applEventLoopFactory, log)
 		if err != nil {
 			// We already logged the error in startApplicationEventLoop, no need to log here
 			return
@@ -434,8 +445,8 @@ func handleStatusTickerMessage(state workspaceEventLoopInternalState) {
 }
 
 func startApplicationEventQueueLoop(ctx context.Context, k8sClient client.Client, associatedGitOpsDeploymentName string, event eventlooptypes.EventLoopMessage,
-	sharedResourceEventLoop *shared_resource_loop.SharedResourceEventLoop,
-	applEventLoopFactory applicationEventQueueLoopFactory, log logr.Logger) (workspaceEventLoop_applicationEventLoopEntry, error) {
+	sharedResourceEventLoop *shared_resource_loop.SharedResourceEventLoop, notificationSender *application_event_loop.NotificationSender,
+	eventRecorder *events.Recorder, applEventLoopFactory applicationEventQueueLoopFactory, log logr.Logger) (workspaceEventLoop_applicationEventLoopEntry, error) {
 
 	// Start the application event queue go-routine
 
@@ -444,6 +455,8 @@ func startApplicationEventQueueLoop(ctx context.Context, k8sClient client.Client
 		GitopsDeploymentNamespace: event.Event.Request.Namespace,
 		WorkspaceID:               event.Event.WorkspaceID,
 		SharedResourceEventLoop:   sharedResourceEventLoop,
+		NotificationSender:        notificationSender,
+		EventRecorder:             eventRecorder,
 		InputChan:                 make(chan application_event_loop.RequestMessage),
 		Client:                    k8sClient,
 	}
@@ -488,124 +501,164 @@ func (d defaultApplicationEventLoopFactory) startApplicationEventQueueLoop(ctx c
 
 var _ applicationEventQueueLoopFactory = defaultApplicationEventLoopFactory{}
 
-// Processes events related to GitOpsDeploymentSyncRuns: determines whether the referenced GitOpsDeployment exists.
+// Processes events related to GitOpsDeploymentSyncRuns and GitOpsDeploymentDiffs (resources that reference a
+// GitOpsDeployment by name): determines whether the referenced GitOpsDeployment exists.
 // - If the GitOpsDepl exists, return the name
-// - Otherwise, add the SyncRun resource to orphaned list (why? because it is a gitopsdeplsyncrun that refers to a gitopsdepl that doesn't exist)
+// - Otherwise, add the resource to orphaned list (why? because it is a resource that refers to a gitopsdepl that doesn't
+// exist), so that it is processed once the GitOpsDeployment is created.
 //
 // If the resource is orphaned, or an error occurred, "" is returned.
 //
 //	See https://docs.google.com/document/d/1e1UwCbwK-Ew5ODWedqp_jZmhiZzYWaxEvIL-tqebMzo/edit#heading=h.8tiycl1h7rns for details.
-func checkIfOrphanedGitOpsDeploymentSyncRun(ctx context.Context, event eventlooptypes.EventLoopMessage,
+func checkIfOrphanedResource(ctx context.Context, event eventlooptypes.EventLoopMessage,
 	orphanedResources map[string]map[string]eventlooptypes.EventLoopEvent, log logr.Logger) string {
 
-	if event.Event.ReqResource != eventlooptypes.GitOpsDeploymentSyncRunTypeName { // sanity test
-		log.Error(nil, "SEVERE: unexpected event resource type in handleOrphaned")
-		return ""
-	}
+	resourceKind := event.Event.ReqResource
 
-	// 1) Retrieve the SyncRun
-	syncRunCR := &v1alpha1.GitOpsDeploymentSyncRun{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      event.Event.Request.Name,
-			Namespace: event.Event.Request.Namespace,
-		},
-	}
-	if err := event.Event.Client.Get(ctx, client.ObjectKeyFromObject(syncRunCR), syncRunCR); err != nil {
-		if apierr.IsNotFound(err) {
-			log.V(logutil.LogLevel_Debug).Info("skipping potentially orphaned resource that could no longer be found:")
+	// 1) Retrieve the resource, and the name of the GitOpsDeployment it references
+	gitopsDeplName, err := getGitOpsDeploymentNameOfResource(ctx, event.Event.Client, resourceKind,
+		client.ObjectKey{Namespace: event.Event.Request.Namespace, Name: event.Event.Request.Name})
+	if err != nil {
+		if !apierr.IsNotFound(err) {
+			log.Error(err, "unexpected client error on retrieving "+string(resourceKind)+" object")
+			return ""
+		}
 
-			// The SyncRun CR doesn't exist, so try fetching the SyncOperation from the database and extract the name of GitOpsDeployment
-			dbQueries, err := db.NewSharedProductionPostgresDBQueries(false)
-			if err != nil {
-				log.Error(err, "failed to get a connection to the database")
-				return ""
-			}
+		log.V(logutil.LogLevel_Debug).Info("skipping potentially orphaned resource that could no longer be found:")
 
-			syncOperation, err := getDBSyncOperationFromAPIMapping(ctx, dbQueries, event.Event.Client, syncRunCR)
-			if err != nil {
-				log.Error(err, "failed to get SyncOperation using APICRToDBMapping")
-				return ""
-			}
+		// The CR doesn't exist, so try fetching the corresponding operation from the database and extract the name of GitOpsDeployment
+		dbQueries, err := db.NewSharedProductionPostgresDBQueries(false)
+		if err != nil {
+			log.Error(err, "failed to get a connection to the database")
+			return ""
+		}
 
-			return syncOperation.DeploymentNameField
-		} else {
-			log.Error(err, "unexpected client error on retrieving syncrun object")
+		gitopsDeplName, err := getGitOpsDeploymentNameFromAPIMapping(ctx, dbQueries, event.Event.Client, resourceKind,
+			event.Event.Request.Name, event.Event.Request.Namespace)
+		if err != nil {
+			log.Error(err, "failed to get the name of the GitOpsDeployment using APICRToDBMapping", "resourceKind", resourceKind)
 			return ""
 		}
+
+		return gitopsDeplName
 	}
 
-	// 2) Retrieve the GitOpsDeployment, referenced by the SyncRun, to see if the SyncRun is orphaned
+	// 2) Retrieve the GitOpsDeployment, referenced by the resource, to see if the resource is orphaned
 	gitopsDeplCR := &v1alpha1.GitOpsDeployment{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      syncRunCR.Spec.GitopsDeploymentName,
+			Name:      gitopsDeplName,
 			Namespace: event.Event.Request.Namespace,
 		},
 	}
-	if err := event.Event.Client.Get(ctx, client.ObjectKeyFromObject(gitopsDeplCR), gitopsDeplCR); err != nil {
-
-		if !apierr.IsNotFound(err) {
-			log.Error(err, "unexpected client error on retrieving GitOpsDeployment references by GitOpsDeploymentSyncRun", "gitopsDeplResource", gitopsDeplCR.ObjectMeta)
-			return ""
-		}
-
-		log.V(logutil.LogLevel_Debug).Info("was unable to locate GitOpsDeployment referenced by GitOpsDeploymentSyncRun, so the SyncRun is still orphaned.")
-
-		// Otherwise, the GitOpsDeployment couldn't be found: therefore the SyncRun is orphaned, so continue.
-
-	} else {
-		// The resource is not orphaned, so our work is done: return the GitOpsDeployment referenced by the GitOpsDeploymentSyncRun
+	if err := event.Event.Client.Get(ctx, client.ObjectKeyFromObject(gitopsDeplCR), gitopsDeplCR); err == nil {
+		// The resource is not orphaned, so our work is done: return the GitOpsDeployment referenced by the resource
 		return gitopsDeplCR.Name
+
+	} else if !apierr.IsNotFound(err) {
+		log.Error(err, "unexpected client error on retrieving GitOpsDeployment references by "+string(resourceKind), "gitopsDeplResource", gitopsDeplCR.ObjectMeta)
+		return ""
 	}
 
-	// At this point, the SyncRun exists, but the GitOpsDeployment referenced by it doesn't
+	log.V(logutil.LogLevel_Debug).Info("was unable to locate GitOpsDeployment referenced by " + string(resourceKind) + ", so the resource is still orphaned.")
 
-	// 3) Retrieve the list of orphaned resources that are depending on the GitOpsDeployment name referenced in the spec
-	// field of the SyncRun.
-	gitopsDeplMap, exists := orphanedResources[syncRunCR.Spec.GitopsDeploymentName]
+	// 3) The resource exists, but the GitOpsDeployment referenced by it doesn't: add this resource event to the list of
+	// orphaned resources that are depending on the GitOpsDeployment name.
+	gitopsDeplMap, exists := orphanedResources[gitopsDeplName]
 	if !exists {
 		gitopsDeplMap = map[string]eventlooptypes.EventLoopEvent{}
-		orphanedResources[syncRunCR.Spec.GitopsDeploymentName] = gitopsDeplMap
+		orphanedResources[gitopsDeplName] = gitopsDeplMap
 	}
 
-	// 4) Add this resource event to that list
-	log.V(logutil.LogLevel_Debug).Info("Adding syncrun CR to orphaned resources list, name: " + syncRunCR.Name + ", missing gitopsdepl name: " + syncRunCR.Spec.GitopsDeploymentName)
-	gitopsDeplMap[syncRunCR.Name] = *event.Event
+	log.V(logutil.LogLevel_Debug).Info("Adding " + string(resourceKind) + " CR to orphaned resources list, name: " + event.Event.Request.Name + ", missing gitopsdepl name: " + gitopsDeplName)
+	gitopsDeplMap[event.Event.Request.Name] = *event.Event
 
 	return ""
+}
+
+// getGitOpsDeploymentNameOfResource retrieves the GitOpsDeploymentSyncRun or GitOpsDeploymentDiff (based on resourceKind)
+// with the given name, and returns the name of the GitOpsDeployment that it references.
+func getGitOpsDeploymentNameOfResource(ctx context.Context, k8sClient client.Client, resourceKind eventlooptypes.GitOpsResourceType,
+	key client.ObjectKey) (string, error) {
+
+	switch resourceKind {
+	case eventlooptypes.GitOpsDeploymentSyncRunTypeName:
+		syncRunCR := &v1alpha1.GitOpsDeploymentSyncRun{}
+		if err := k8sClient.Get(ctx, key, syncRunCR); err != nil {
+			return "", err
+		}
+		return syncRunCR.Spec.GitopsDeploymentName, nil
 
+	case eventlooptypes.GitOpsDeploymentDiffTypeName:
+		diffCR := &v1alpha1.GitOpsDeploymentDiff{}
+		if err := k8sClient.Get(ctx, key, diffCR); err != nil {
+			return "", err
+		}
+		return diffCR.Spec.GitopsDeploymentName, nil
+
+	default:
+		return "", fmt.Errorf("SEVERE: unexpected resource kind '%s' for a resource that references a GitOpsDeployment", resourceKind)
+	}
 }
 
-func getDBSyncOperationFromAPIMapping(ctx context.Context, dbQueries db.DatabaseQueries, k8sclient client.Client, syncRunCR *v1alpha1.GitOpsDeploymentSyncRun) (db.SyncOperation, error) {
+// getGitOpsDeploymentNameFromAPIMapping returns the name of the GitOpsDeployment that is referenced by the database
+// row (SyncOperation or DiffOperation) of a GitOpsDeploymentSyncRun or GitOpsDeploymentDiff (based on resourceKind),
+// using the APICRToDatabaseMapping of the resource. This is used once the resource itself no longer exists.
+func getGitOpsDeploymentNameFromAPIMapping(ctx context.Context, dbQueries db.DatabaseQueries, k8sclient client.Client,
+	resourceKind eventlooptypes.GitOpsResourceType, name string, namespaceName string) (string, error) {
+
+	var apiCRResourceType db.APICRToDatabaseMapping_ResourceType
+	var dbRelationType db.APICRToDatabaseMapping_DBRelationType
+
+	switch resourceKind {
+	case eventlooptypes.GitOpsDeploymentSyncRunTypeName:
+		apiCRResourceType = db.APICRToDatabaseMapping_ResourceType_GitOpsDeploymentSyncRun
+		dbRelationType = db.APICRToDatabaseMapping_DBRelationType_SyncOperation
+	case eventlooptypes.GitOpsDeploymentDiffTypeName:
+		apiCRResourceType = db.APICRToDatabaseMapping_ResourceType_GitOpsDeploymentDiff
+		dbRelationType = db.APICRToDatabaseMapping_DBRelationType_DiffOperation
+	default:
+		return "", fmt.Errorf("SEVERE: unexpected resource kind '%s' for a resource that references a GitOpsDeployment", resourceKind)
+	}
+
 	namespace := &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
-			Name: syncRunCR.Namespace,
+			Name: namespaceName,
 		},
 	}
 	if err := k8sclient.Get(ctx, client.ObjectKeyFromObject(namespace), namespace); err != nil {
-		return db.SyncOperation{}, fmt.Errorf("failed to get namespace %s: %v", namespace.Name, err)
+		return "", fmt.Errorf("failed to get namespace %s: %v", namespace.Name, err)
 	}
 
 	apiCRToDBMappingList := []db.APICRToDatabaseMapping{}
-	if err := dbQueries.ListAPICRToDatabaseMappingByAPINamespaceAndName(ctx, db.APICRToDatabaseMapping_ResourceType_GitOpsDeploymentSyncRun, syncRunCR.Name, syncRunCR.Namespace, string(namespace.UID), db.APICRToDatabaseMapping_DBRelationType_SyncOperation, &apiCRToDBMappingList); err != nil {
-		return db.SyncOperation{}, fmt.Errorf("failed to list APICRToDBMapping by namespace and name: %v", err)
+	if err := dbQueries.ListAPICRToDatabaseMappingByAPINamespaceAndName(ctx, apiCRResourceType, name, namespaceName, string(namespace.UID), dbRelationType, &apiCRToDBMappingList); err != nil {
+		return "", fmt.Errorf("failed to list APICRToDBMapping by namespace and name: %v", err)
 	}
 
 	if len(apiCRToDBMappingList) != 1 {
-		return db.SyncOperation{}, fmt.Errorf("SEVERE: unexpected number of APICRToDBMappings for GitOpsDeploymentSyncRun %s: %d", syncRunCR.Name, len(apiCRToDBMappingList))
+		return "", fmt.Errorf("SEVERE: unexpected number of APICRToDBMappings for %s %s: %d", resourceKind, name, len(apiCRToDBMappingList))
 	}
 
 	apiCRToDBMapping := apiCRToDBMappingList[0]
 
-	if apiCRToDBMapping.DBRelationType != db.APICRToDatabaseMapping_DBRelationType_SyncOperation {
-		return db.SyncOperation{}, fmt.Errorf("expected db relation type to be 'SyncOperation', but found '%s'", apiCRToDBMapping.DBRelationType)
+	if apiCRToDBMapping.DBRelationType != dbRelationType {
+		return "", fmt.Errorf("expected db relation type to be '%s', but found '%s'", dbRelationType, apiCRToDBMapping.DBRelationType)
 	}
 
-	syncOperation := db.SyncOperation{SyncOperation_id: apiCRToDBMapping.DBRelationKey}
-	if err := dbQueries.GetSyncOperationById(ctx, &syncOperation); err != nil {
-		return db.SyncOperation{}, fmt.Errorf("failed to retrieve syncoperation by id: %v", err)
-	}
+	switch dbRelationType {
+	case db.APICRToDatabaseMapping_DBRelationType_SyncOperation:
+		syncOperation := db.SyncOperation{SyncOperation_id: apiCRToDBMapping.DBRelationKey}
+		if err := dbQueries.GetSyncOperationById(ctx, &syncOperation); err != nil {
+			return "", fmt.Errorf("failed to retrieve syncoperation by id: %v", err)
+		}
+		return syncOperation.DeploymentNameField, nil
 
-	return syncOperation, nil
+	default:
+		diffOperation := db.DiffOperation{DiffOperation_id: apiCRToDBMapping.DBRelationKey}
+		if err := dbQueries.GetDiffOperationById(ctx, &diffOperation); err != nil {
+			return "", fmt.Errorf("failed to retrieve diffoperation by id: %v", err)
+		}
+		return diffOperation.DeploymentNameField, nil
+	}
 }
 
 func unorphanResourcesIfPossible(ctx context.Context, event eventlooptypes.EventLoopMessage,
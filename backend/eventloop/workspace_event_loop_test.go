@@ -57,7 +57,7 @@ var _ = Describe("Workspace Event Loop Test", Ordered, func() {
 			tAELF = &testApplicationEventLoopFactory{}
 
 			// Start the workspace event loop with our custom test factory, so that we can capture output
-			workspaceEventLoopRouter = newWorkspaceEventLoopRouterWithFactory(apiNamespace.Name, string(apiNamespace.UID), tAELF)
+			workspaceEventLoopRouter = newWorkspaceEventLoopRouterWithFactory(apiNamespace.Name, string(apiNamespace.UID), nil, tAELF)
 
 			k8sClient = fake.NewClientBuilder().
 				WithScheme(scheme).
@@ -445,7 +445,7 @@ var _ = Describe("Workspace Event Loop Test", Ordered, func() {
 			tAELF := &managedEnvironmentTestApplicationEventLoopFactory{
 				outputChannelMap: map[string]chan application_event_loop.RequestMessage{},
 			}
-			workspaceEventLoopRouter := newWorkspaceEventLoopRouterWithFactory(apiNamespace.Name, string(apiNamespace.UID), tAELF)
+			workspaceEventLoopRouter := newWorkspaceEventLoopRouterWithFactory(apiNamespace.Name, string(apiNamespace.UID), nil, tAELF)
 
 			k8sClient := fake.NewClientBuilder().
 				WithScheme(scheme).
@@ -479,7 +479,7 @@ var _ = Describe("Workspace Event Loop Test", Ordered, func() {
 			tAELF := &managedEnvironmentTestApplicationEventLoopFactory{
 				outputChannelMap: map[string]chan application_event_loop.RequestMessage{},
 			}
-			workspaceEventLoopRouter := newWorkspaceEventLoopRouterWithFactory(apiNamespace.Name, string(apiNamespace.UID), tAELF)
+			workspaceEventLoopRouter := newWorkspaceEventLoopRouterWithFactory(apiNamespace.Name, string(apiNamespace.UID), nil, tAELF)
 
 			k8sClient := fake.NewClientBuilder().
 				WithScheme(scheme).
@@ -935,7 +935,7 @@ var _ = Describe("Workspace Event Loop Test", Ordered, func() {
 
 	})
 
-	Context("Test getDBSyncOperationFromAPIMapping", func() {
+	Context("Test getGitOpsDeploymentNameFromAPIMapping", func() {
 		var (
 			k8sClient client.Client
 			ctx       context.Context
@@ -970,11 +970,12 @@ var _ = Describe("Workspace Event Loop Test", Ordered, func() {
 				},
 			}
 
-			syncOperation, err := getDBSyncOperationFromAPIMapping(ctx, dbQueries, k8sClient, syncRun)
+			gitopsDeplName, err := getGitOpsDeploymentNameFromAPIMapping(ctx, dbQueries, k8sClient,
+				eventlooptypes.GitOpsDeploymentSyncRunTypeName, syncRun.Name, syncRun.Namespace)
 			Expect(err).To(HaveOccurred())
 			expectedMsg := `failed to get namespace unknown: namespaces "unknown" not found`
 			Expect(err.Error()).To(Equal(expectedMsg))
-			Expect(syncOperation).To(Equal(db.SyncOperation{}))
+			Expect(gitopsDeplName).To(BeEmpty())
 		})
 
 		It("should return an error if there are no APICRToDatabaseMapping", func() {
@@ -985,11 +986,21 @@ var _ = Describe("Workspace Event Loop Test", Ordered, func() {
 				},
 			}
 
-			syncOperation, err := getDBSyncOperationFromAPIMapping(ctx, dbQueries, k8sClient, syncRun)
+			gitopsDeplName, err := getGitOpsDeploymentNameFromAPIMapping(ctx, dbQueries, k8sClient,
+				eventlooptypes.GitOpsDeploymentSyncRunTypeName, syncRun.Name, syncRun.Namespace)
 			Expect(err).To(HaveOccurred())
 			expectedMsg := "SEVERE: unexpected number of APICRToDBMappings for GitOpsDeploymentSyncRun test-syncrun: 0"
 			Expect(err.Error()).To(Equal(expectedMsg))
-			Expect(syncOperation).To(Equal(db.SyncOperation{}))
+			Expect(gitopsDeplName).To(BeEmpty())
+		})
+
+		It("should return an error if there are no APICRToDatabaseMapping for a GitOpsDeploymentDiff", func() {
+			gitopsDeplName, err := getGitOpsDeploymentNameFromAPIMapping(ctx, dbQueries, k8sClient,
+				eventlooptypes.GitOpsDeploymentDiffTypeName, "test-diff", ns.Name)
+			Expect(err).To(HaveOccurred())
+			expectedMsg := "SEVERE: unexpected number of APICRToDBMappings for GitOpsDeploymentDiff test-diff: 0"
+			Expect(err.Error()).To(Equal(expectedMsg))
+			Expect(gitopsDeplName).To(BeEmpty())
 		})
 	})
 
@@ -212,7 +212,7 @@ var _ = Describe("Test Workspace Resource Loop", func() {
 				WithObjects(apiNamespace, argocdNamespace, kubesystemNamespace).
 				Build()
 
-			sharedResourceLoop = shared_resource_loop.NewSharedResourceLoop()
+			sharedResourceLoop = shared_resource_loop.NewSharedResourceLoop(nil)
 			workspaceChan = make(chan workspaceEventLoopMessage)
 			mockClientFactory = MockSRLK8sClientFactory{fakeClient: k8sClient}
 		})
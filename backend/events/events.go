@@ -0,0 +1,131 @@
+package events
+
+import (
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// The functions in this file record K8s Events on the GitOps API resources (GitOpsDeployment, GitOpsDeploymentSyncRun,
+// GitOpsDeploymentManagedEnvironment, GitOpsDeploymentRepositoryCredential) when their state changes.
+//
+// Since the state of these resources is (re)computed on every status tick, or every reconcile, identical events for
+// the same resource are deduplicated: an event is only recorded if its message differs from the previous event of the
+// same reason on the same resource, or if 'duplicateEventInterval' has elapsed since that event was recorded.
+
+// Reasons of the events recorded by the backend
+const (
+	ReasonHealthStatusChanged = "HealthStatusChanged"
+	ReasonSyncStarted         = "SyncStarted"
+	ReasonSyncSucceeded       = "SyncSucceeded"
+	ReasonSyncFailed          = "SyncFailed"
+	ReasonSyncTerminated      = "SyncTerminated"
+)
+
+const (
+	// duplicateEventInterval is the interval after which an identical event may be recorded again
+	duplicateEventInterval = 10 * time.Minute
+
+	// maxTrackedEvents ensures that we will keep track of at most 5,000 recently recorded events, for deduplication.
+	maxTrackedEvents = 5000
+)
+
+// Recorder records K8s Events on the GitOps API resources, deduplicating identical events.
+//
+// A nil Recorder does not record events (for example, in unit tests).
+type Recorder struct {
+	// recorder is the EventRecorder used to record events
+	recorder record.EventRecorder
+
+	recentEvents recentEventSet
+}
+
+// NewRecorder returns a Recorder that records events using the given EventRecorder. A single Recorder should be
+// shared by the event loops of the backend, so that events are deduplicated across them.
+func NewRecorder(eventRecorder record.EventRecorder) *Recorder {
+	return &Recorder{
+		recorder: eventRecorder,
+		recentEvents: recentEventSet{
+			events: map[recentEventKey]recentEvent{},
+		},
+	}
+}
+
+type recentEventSet struct {
+	mutex sync.Mutex
+
+	// events contains the most recent event recorded for each resource and reason.
+	// NOTE: Before reading/writing from this map, acquire the mutex.
+	events map[recentEventKey]recentEvent
+}
+
+type recentEventKey struct {
+	uid    string
+	reason string
+}
+
+type recentEvent struct {
+	eventType string
+	message   string
+	recorded  time.Time
+}
+
+// RecordNormal records an event of type Normal on the given resource, unless it is a duplicate.
+func (r *Recorder) RecordNormal(obj client.Object, reason string, message string) {
+	r.recordEvent(obj, corev1.EventTypeNormal, reason, message)
+}
+
+// RecordWarning records an event of type Warning on the given resource, unless it is a duplicate.
+func (r *Recorder) RecordWarning(obj client.Object, reason string, message string) {
+	r.recordEvent(obj, corev1.EventTypeWarning, reason, message)
+}
+
+func (r *Recorder) recordEvent(obj client.Object, eventType string, reason string, message string) {
+
+	// The resource may not exist (yet), for example if it could not be retrieved
+	if r == nil || r.recorder == nil || obj == nil || obj.GetName() == "" || obj.GetUID() == "" {
+		return
+	}
+
+	if r.recentEvents.isDuplicateEvent(obj, eventType, reason, message, time.Now()) {
+		return
+	}
+
+	r.recorder.Event(obj, eventType, reason, message)
+}
+
+// isDuplicateEvent returns true if an identical event was recorded on the resource within the last duplicateEventInterval,
+// otherwise it returns false and tracks the event.
+func (recentEvents *recentEventSet) isDuplicateEvent(obj client.Object, eventType string, reason string, message string, now time.Time) bool {
+
+	recentEvents.mutex.Lock()
+	defer recentEvents.mutex.Unlock()
+
+	key := recentEventKey{uid: string(obj.GetUID()), reason: reason}
+
+	previous, exists := recentEvents.events[key]
+	if exists && previous.eventType == eventType && previous.message == message && now.Sub(previous.recorded) < duplicateEventInterval {
+		return true
+	}
+
+	if !exists && len(recentEvents.events) >= maxTrackedEvents {
+		// Stop tracking the events that can no longer be duplicated
+		for k, v := range recentEvents.events {
+			if now.Sub(v.recorded) >= duplicateEventInterval {
+				delete(recentEvents.events, k)
+			}
+		}
+	}
+
+	// If there are still too many, the event is recorded but not tracked
+	if exists || len(recentEvents.events) < maxTrackedEvents {
+		recentEvents.events[key] = recentEvent{eventType: eventType, message: message, recorded: now}
+	}
+
+	return false
+}
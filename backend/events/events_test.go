@@ -0,0 +1,73 @@
+package events
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/tools/record"
+)
+
+var _ = Describe("Test for recording K8s Events", func() {
+
+	Context("RecordNormal and RecordWarning deduplicate identical events", func() {
+
+		var fakeRecorder *record.FakeRecorder
+		var recorder *Recorder
+
+		gitopsDepl := &managedgitopsv1alpha1.GitOpsDeployment{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "my-gitops-depl",
+				Namespace: "gitops-depl-namespace",
+				UID:       uuid.NewUUID(),
+			},
+		}
+
+		BeforeEach(func() {
+			fakeRecorder = record.NewFakeRecorder(10)
+			recorder = NewRecorder(fakeRecorder)
+		})
+
+		It("should only record an event if it differs from the previous event of the same reason", func() {
+
+			recorder.RecordWarning(gitopsDepl, ReasonHealthStatusChanged, "Health status changed from 'Healthy' to 'Degraded'")
+			Expect(fakeRecorder.Events).To(Receive(Equal("Warning HealthStatusChanged Health status changed from 'Healthy' to 'Degraded'")))
+
+			By("not recording the same event again")
+			recorder.RecordWarning(gitopsDepl, ReasonHealthStatusChanged, "Health status changed from 'Healthy' to 'Degraded'")
+			Expect(fakeRecorder.Events).ToNot(Receive())
+
+			By("recording an event with a different message, or a different reason")
+			recorder.RecordNormal(gitopsDepl, ReasonHealthStatusChanged, "Health status changed from 'Degraded' to 'Healthy'")
+			Expect(fakeRecorder.Events).To(Receive(Equal("Normal HealthStatusChanged Health status changed from 'Degraded' to 'Healthy'")))
+
+			recorder.RecordNormal(gitopsDepl, ReasonSyncStarted, "Sync started")
+			Expect(fakeRecorder.Events).To(Receive(Equal("Normal SyncStarted Sync started")))
+
+			By("not recording events on resources that have not been created")
+			recorder.RecordNormal(&managedgitopsv1alpha1.GitOpsDeployment{}, ReasonSyncStarted, "Sync started")
+			Expect(fakeRecorder.Events).ToNot(Receive())
+		})
+
+		It("should record an identical event again, once the duplicate event interval has elapsed", func() {
+
+			now := time.Now()
+
+			Expect(recorder.recentEvents.isDuplicateEvent(gitopsDepl, "Normal", ReasonSyncStarted, "Sync started", now)).To(BeFalse())
+			Expect(recorder.recentEvents.isDuplicateEvent(gitopsDepl, "Normal", ReasonSyncStarted, "Sync started", now.Add(time.Minute))).To(BeTrue())
+			Expect(recorder.recentEvents.isDuplicateEvent(gitopsDepl, "Normal", ReasonSyncStarted, "Sync started", now.Add(duplicateEventInterval))).To(BeFalse())
+		})
+
+		It("should not record events with a nil Recorder", func() {
+
+			var nilRecorder *Recorder
+
+			nilRecorder.RecordNormal(gitopsDepl, ReasonSyncStarted, "Sync started")
+			nilRecorder.RecordWarning(gitopsDepl, ReasonSyncFailed, "Sync failed")
+			Expect(fakeRecorder.Events).ToNot(Receive())
+		})
+	})
+})
@@ -29,10 +29,13 @@ import (
 	"github.com/redhat-appstudio/managed-gitops/utilities/db-migration/migrate"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
@@ -44,6 +47,7 @@ import (
 	"github.com/redhat-appstudio/managed-gitops/backend/eventloop"
 	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/preprocess_event_loop"
 	"github.com/redhat-appstudio/managed-gitops/backend/eventloop/shared_resource_loop"
+	"github.com/redhat-appstudio/managed-gitops/backend/events"
 	crzap "sigs.k8s.io/controller-runtime/pkg/log/zap"
 	//+kubebuilder:scaffold:imports
 )
@@ -120,13 +124,28 @@ func main() {
 		HealthProbeBindAddress: probeAddr,
 		LeaderElection:         enableLeaderElection,
 		LeaderElectionID:       "5a3f596c.redhat.com",
+		// Only cache (and watch) the ConfigMaps that are labelled as CA bundles of managed environments, rather than
+		// every ConfigMap in the cluster. ConfigMaps are thus read directly from the API server.
+		Cache: cache.Options{
+			ByObject: map[client.Object]cache.ByObject{
+				&corev1.ConfigMap{}: managedgitopscontrollers.CABundleConfigMapCacheOptions(),
+			},
+		},
+		Client: client.Options{
+			Cache: &client.CacheOptions{
+				DisableFor: []client.Object{&corev1.ConfigMap{}},
+			},
+		},
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
-	preprocessEventLoop := preprocess_event_loop.NewPreprocessEventLoop()
+	// Record K8s Events on the GitOps API resources, as their state changes
+	eventRecorder := events.NewRecorder(mgr.GetEventRecorderFor("managed-gitops-backend"))
+
+	preprocessEventLoop := preprocess_event_loop.NewPreprocessEventLoop(eventRecorder)
 
 	if err = (&managedgitopscontrollers.GitOpsDeploymentReconciler{
 		PreprocessEventLoop: preprocessEventLoop,
@@ -144,6 +163,14 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "GitOpsDeploymentSyncRun")
 		os.Exit(1)
 	}
+	if err = (&managedgitopscontrollers.GitOpsDeploymentDiffReconciler{
+		PreprocessEventLoop: preprocessEventLoop,
+		Client:              mgr.GetClient(),
+		Scheme:              mgr.GetScheme(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GitOpsDeploymentDiff")
+		os.Exit(1)
+	}
 	if err = (&managedgitopscontrollers.GitOpsDeploymentRepositoryCredentialReconciler{
 		Client:              mgr.GetClient(),
 		Scheme:              mgr.GetScheme(),
@@ -188,8 +215,9 @@ func main() {
 	//+kubebuilder:scaffold:builder
 
 	startDBReconciler(mgr)
-	startRepoCredReconciler(mgr)
+	startRepoCredReconciler(mgr, eventRecorder)
 	startDBMetricsReconciler(mgr)
+	startManagedEnvironmentProber(mgr, eventRecorder)
 
 	startClusterReconciler(mgr)
 
@@ -233,7 +261,7 @@ func startDBReconciler(mgr ctrl.Manager) {
 	databaseReconciler.StartDatabaseReconciler()
 }
 
-func startRepoCredReconciler(mgr ctrl.Manager) {
+func startRepoCredReconciler(mgr ctrl.Manager, eventRecorder *events.Recorder) {
 
 	dbQueries, err := db.NewSharedProductionPostgresDBQueries(false)
 	if err != nil {
@@ -242,8 +270,9 @@ func startRepoCredReconciler(mgr ctrl.Manager) {
 	}
 
 	repoCredReconciler := eventloop.RepoCredReconciler{
-		DB:     dbQueries,
-		Client: mgr.GetClient(),
+		DB:            dbQueries,
+		Client:        mgr.GetClient(),
+		EventRecorder: eventRecorder,
 	}
 
 	// Start goroutine for Repository Credential reconciler
@@ -267,6 +296,25 @@ func startDBMetricsReconciler(mgr ctrl.Manager) {
 	databaseReconciler.StartDBMetricsReconcilerForMetrics()
 }
 
+func startManagedEnvironmentProber(mgr ctrl.Manager, eventRecorder *events.Recorder) {
+
+	dbQueries, err := db.NewSharedProductionPostgresDBQueries(false)
+	if err != nil {
+		setupLog.Error(err, "never able to connect to database")
+		os.Exit(1)
+	}
+
+	managedEnvProber := eventloop.ManagedEnvironmentProber{
+		DB:               dbQueries,
+		Client:           mgr.GetClient(),
+		K8sClientFactory: shared_resource_loop.DefaultK8sClientFactory{},
+		EventRecorder:    eventRecorder,
+	}
+
+	// Start goroutine for managed environment prober
+	managedEnvProber.StartManagedEnvironmentProber()
+}
+
 func startClusterReconciler(mgr ctrl.Manager) {
 	discoveryClient, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
 	if err != nil {
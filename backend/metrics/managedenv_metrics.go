@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	ManagedEnvironmentReachable = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "managed_environment_reachable",
+			Help: "Whether the cluster of the GitOpsDeploymentManagedEnvironment was reachable (1) or not (0), when it was last probed",
+		},
+		[]string{"name", "namespace"},
+	)
+
+	ManagedEnvironmentLastProbeTimestamp = promauto.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "managed_environment_last_probe_timestamp_seconds",
+			Help: "Unix time at which the cluster of the GitOpsDeploymentManagedEnvironment was last probed",
+		},
+		[]string{"name", "namespace"},
+	)
+)
+
+// SetManagedEnvironmentProbeResult sets the metrics of a managed environment, based on the result of a probe of its cluster.
+func SetManagedEnvironmentProbeResult(name string, namespace string, reachable bool, probeTime time.Time) {
+
+	value := 0.0
+	if reachable {
+		value = 1.0
+	}
+
+	ManagedEnvironmentReachable.WithLabelValues(name, namespace).Set(value)
+	ManagedEnvironmentLastProbeTimestamp.WithLabelValues(name, namespace).Set(float64(probeTime.Unix()))
+}
+
+// RemoveManagedEnvironmentProbeResult removes the metrics of a managed environment, for example once it no longer exists.
+func RemoveManagedEnvironmentProbeResult(name string, namespace string) {
+	ManagedEnvironmentReachable.DeleteLabelValues(name, namespace)
+	ManagedEnvironmentLastProbeTimestamp.DeleteLabelValues(name, namespace)
+}
+
+func ClearManagedEnvironmentMetrics() {
+	ManagedEnvironmentReachable.Reset()
+	ManagedEnvironmentLastProbeTimestamp.Reset()
+}
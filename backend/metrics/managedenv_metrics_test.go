@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+var _ = Describe("Test for ManagedEnvironment probe metrics", func() {
+
+	BeforeEach(func() {
+		ClearManagedEnvironmentMetrics()
+	})
+
+	It("should set the metrics of a managed environment, based on the result of a probe", func() {
+
+		probeTime := time.Unix(1700000000, 0)
+
+		By("setting the metrics of a reachable managed environment")
+		SetManagedEnvironmentProbeResult("my-env", "my-namespace", true, probeTime)
+		Expect(testutil.ToFloat64(ManagedEnvironmentReachable.WithLabelValues("my-env", "my-namespace"))).To(Equal(1.0))
+		Expect(testutil.ToFloat64(ManagedEnvironmentLastProbeTimestamp.WithLabelValues("my-env", "my-namespace"))).To(Equal(1700000000.0))
+
+		By("setting the metrics of the managed environment once it is no longer reachable")
+		SetManagedEnvironmentProbeResult("my-env", "my-namespace", false, probeTime.Add(time.Minute))
+		Expect(testutil.ToFloat64(ManagedEnvironmentReachable.WithLabelValues("my-env", "my-namespace"))).To(Equal(0.0))
+		Expect(testutil.ToFloat64(ManagedEnvironmentLastProbeTimestamp.WithLabelValues("my-env", "my-namespace"))).To(Equal(1700000060.0))
+	})
+
+	It("should remove the metrics of a managed environment", func() {
+
+		SetManagedEnvironmentProbeResult("my-env", "my-namespace", true, time.Now())
+		SetManagedEnvironmentProbeResult("other-env", "my-namespace", true, time.Now())
+		Expect(testutil.CollectAndCount(ManagedEnvironmentReachable)).To(Equal(2))
+
+		RemoveManagedEnvironmentProbeResult("my-env", "my-namespace")
+		Expect(testutil.CollectAndCount(ManagedEnvironmentReachable)).To(Equal(1))
+		Expect(testutil.CollectAndCount(ManagedEnvironmentLastProbeTimestamp)).To(Equal(1))
+	})
+})
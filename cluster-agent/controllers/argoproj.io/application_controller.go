@@ -18,22 +18,28 @@ package argoprojio
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"reflect"
 
 	"strings"
+	"sync"
 	"time"
 
 	apierr "k8s.io/apimachinery/pkg/api/errors"
 
 	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	"github.com/go-logr/logr"
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/db"
 	sharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util"
 	argosharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/argocd"
 	logutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/log"
 	"github.com/redhat-appstudio/managed-gitops/cluster-agent/controllers"
 	"github.com/redhat-appstudio/managed-gitops/cluster-agent/controllers/argoproj.io/application_info_cache"
+	"github.com/redhat-appstudio/managed-gitops/cluster-agent/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -52,8 +58,25 @@ type ApplicationReconciler struct {
 	Cache *application_info_cache.ApplicationInfoCache
 
 	DB db.DatabaseQueries
+
+	// GetResourceTree retrieves the resource tree (and recent warning events) of an Argo CD Application, which is stored
+	// in the ApplicationState. If nil, the resource tree is not collected.
+	GetResourceTree func(ctx context.Context, app appv1.Application) (*managedgitopsv1alpha1.ResourceTree, error)
+
+	// resourceTreeLastCollected is the time at which the resource tree of each Argo CD Application (by namespace/name) was last collected
+	resourceTreeLastCollected map[string]time.Time
+	resourceTreeMutex         sync.Mutex
+
+	// deploymentHistoryLastRecorded is the deployment time of the newest entry in the revision history of each Argo CD
+	// Application (by namespace/name) that has been recorded in the database
+	deploymentHistoryLastRecorded map[string]time.Time
+	deploymentHistoryMutex        sync.Mutex
 }
 
+// resourceTreeCollectionInterval is the minimum amount of time between collections of the resource tree of an Application:
+// as it requires requests to the Argo CD API server, it is not collected on every change to the Application.
+const resourceTreeCollectionInterval = 30 * time.Second
+
 //+kubebuilder:rbac:groups=argoproj.io,resources=applications,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
@@ -77,6 +100,8 @@ func (r *ApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	if err := r.Get(ctx, req.NamespacedName, &app); err != nil {
 		if apierr.IsNotFound(err) {
 			log.Info("Application deleted")
+			r.forgetResourceTreeCollection(req.NamespacedName.String())
+			r.forgetDeploymentHistory(req.NamespacedName.String())
 			return ctrl.Result{}, nil
 		} else {
 			log.Error(err, "Unexpected error on retrieving Application")
@@ -128,15 +153,29 @@ func (r *ApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
-	// 3) Does there exist an ApplicationState for this Application, already?
+	// 3) Record any new entries in the revision history of the Application, so that they are preserved if the
+	// Application is recreated
+	if r.isDeploymentHistoryChanged(req.NamespacedName.String(), app) {
+		if err := recordDeploymentHistory(ctx, r.DB, app, applicationDB.Application_id, log); err != nil {
+			log.Error(err, "Unable to record the deployment history of the Application")
+			return ctrl.Result{}, err
+		}
+		r.setDeploymentHistoryRecorded(req.NamespacedName.String(), app)
+	}
+
+	// 4) Retrieve the resource tree of the Application, if it is due to be collected
+	resourceTreeBytes, resourceTreeCollected, requeueAfter := r.getResourceTreeOfApplication(ctx, app, req.NamespacedName.String(), log)
+
+	// 5) Does there exist an ApplicationState for this Application, already?
 	applicationState := &db.ApplicationState{
 		Applicationstate_application_id: applicationDB.Application_id,
 	}
 
-	if _, _, errGet := r.Cache.GetApplicationStateById(ctx, applicationState.Applicationstate_application_id); errGet != nil {
+	existingApplicationState, _, errGet := r.Cache.GetApplicationStateById(ctx, applicationState.Applicationstate_application_id)
+	if errGet != nil {
 		if db.IsResultNotFoundError(errGet) {
 
-			// 3a) ApplicationState doesn't exist: so create it
+			// 5a) ApplicationState doesn't exist: so create it
 			appStatusBytes, err := sharedutil.CompressObject(app.Status)
 			if err != nil {
 				log.Error(err, "Failed to compress the Argo CD Application status", "name", app.Name, "namespace", app.Namespace)
@@ -144,25 +183,33 @@ func (r *ApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 			}
 
 			applicationState.ArgoCD_Application_Status = appStatusBytes
+			applicationState.Resource_tree = resourceTreeBytes
 			if errCreate := r.Cache.CreateApplicationState(ctx, *applicationState); errCreate != nil {
 				log.Error(errCreate, "unexpected error on writing new application state")
 				return ctrl.Result{}, errCreate
 			}
 			// Successfully created ApplicationState
-			return ctrl.Result{}, nil
+			return ctrl.Result{RequeueAfter: requeueAfter}, nil
 		} else {
 			log.Error(errGet, "Unable to retrieve ApplicationState from database")
 			return ctrl.Result{}, errGet
 		}
 	}
 
-	// 4) ApplicationState already exists, so just update it.
+	// 6) ApplicationState already exists, so just update it.
 	appStatusBytes, err := sharedutil.CompressObject(app.Status)
 	if err != nil {
 		log.Error(err, "Failed to compress the Argo CD Application status", "name", app.Name, "namespace", app.Namespace)
 		return ctrl.Result{}, err
 	}
 	applicationState.ArgoCD_Application_Status = appStatusBytes
+
+	// Keep the previously collected resource tree, if it was not collected this time
+	applicationState.Resource_tree = existingApplicationState.Resource_tree
+	if resourceTreeCollected {
+		applicationState.Resource_tree = resourceTreeBytes
+	}
+
 	if err := r.Cache.UpdateApplicationState(ctx, *applicationState); err != nil {
 
 		if strings.Contains(err.Error(), db.ErrorUnexpectedNumberOfRowsAffected) {
@@ -174,8 +221,270 @@ func (r *ApplicationReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+
+}
+
+// getResourceTreeOfApplication returns the compressed resource tree of the Argo CD Application, if it is due to be collected.
+// If the resource tree was not collected (for example, because it was collected too recently), 'collected' is false, and
+// requeueAfter is the amount of time after which it should be collected.
+//
+// Failures are logged, but are not returned: the resource tree is informational only, and should not prevent the rest of
+// the ApplicationState from being updated.
+func (r *ApplicationReconciler) getResourceTreeOfApplication(ctx context.Context, app appv1.Application, key string, log logr.Logger) (resourceTreeBytes []byte, collected bool, requeueAfter time.Duration) {
+
+	if r.GetResourceTree == nil {
+		return nil, false, 0
+	}
+
+	r.resourceTreeMutex.Lock()
+	if r.resourceTreeLastCollected == nil {
+		r.resourceTreeLastCollected = map[string]time.Time{}
+	}
+	lastCollected, exists := r.resourceTreeLastCollected[key]
+	if exists && time.Since(lastCollected) < resourceTreeCollectionInterval {
+		r.resourceTreeMutex.Unlock()
+		return nil, false, resourceTreeCollectionInterval - time.Since(lastCollected)
+	}
+	r.resourceTreeLastCollected[key] = time.Now()
+	r.resourceTreeMutex.Unlock()
+
+	resourceTree, err := r.GetResourceTree(ctx, app)
+	if err != nil {
+		log.Error(err, "Unable to retrieve the resource tree of the Application")
+		return nil, false, resourceTreeCollectionInterval
+	}
+
+	resourceTreeBytes, err = sharedutil.CompressObject(resourceTree)
+	if err != nil {
+		log.Error(err, "Failed to compress the resource tree of the Application")
+		return nil, false, resourceTreeCollectionInterval
+	}
+
+	return resourceTreeBytes, true, 0
+}
+
+// forgetResourceTreeCollection removes the last collection time of the resource tree of a deleted Argo CD Application.
+func (r *ApplicationReconciler) forgetResourceTreeCollection(key string) {
+	r.resourceTreeMutex.Lock()
+	defer r.resourceTreeMutex.Unlock()
+
+	delete(r.resourceTreeLastCollected, key)
+}
+
+// NewResourceTreeGetter returns a function that retrieves the resource tree of an Argo CD Application, using the
+// Argo CD API server of the namespace that the Application is in.
+func NewResourceTreeGetter(k8sClient client.Client, credentialService *utils.CredentialService) func(context.Context, appv1.Application) (*managedgitopsv1alpha1.ResourceTree, error) {
+
+	return func(ctx context.Context, app appv1.Application) (*managedgitopsv1alpha1.ResourceTree, error) {
+
+		argocdNamespace := corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: app.Namespace,
+			},
+		}
+		if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(&argocdNamespace), &argocdNamespace); err != nil {
+			return nil, fmt.Errorf("unable to retrieve namespace of Application: %v", err)
+		}
+
+		return utils.GetResourceTree(ctx, app.Name, argocdNamespace, credentialService, k8sClient)
+	}
+}
+
+// isDeploymentHistoryChanged returns true if the revision history of the Argo CD Application has changed since it was
+// last recorded: the database is only queried when there may be new entries to record.
+func (r *ApplicationReconciler) isDeploymentHistoryChanged(key string, app appv1.Application) bool {
+
+	if len(app.Status.History) == 0 {
+		return false
+	}
+
+	r.deploymentHistoryMutex.Lock()
+	defer r.deploymentHistoryMutex.Unlock()
+
+	lastRecorded, exists := r.deploymentHistoryLastRecorded[key]
+
+	return !exists || !lastRecorded.Equal(app.Status.History.LastRevisionHistory().DeployedAt.Time)
+}
+
+// setDeploymentHistoryRecorded stores the deployment time of the newest entry in the revision history of the Argo CD
+// Application, once the revision history has been recorded.
+func (r *ApplicationReconciler) setDeploymentHistoryRecorded(key string, app appv1.Application) {
+
+	if len(app.Status.History) == 0 {
+		return
+	}
+
+	r.deploymentHistoryMutex.Lock()
+	defer r.deploymentHistoryMutex.Unlock()
+
+	if r.deploymentHistoryLastRecorded == nil {
+		r.deploymentHistoryLastRecorded = map[string]time.Time{}
+	}
+	r.deploymentHistoryLastRecorded[key] = app.Status.History.LastRevisionHistory().DeployedAt.Time
+}
+
+// forgetDeploymentHistory removes the recorded revision history of a deleted Argo CD Application.
+func (r *ApplicationReconciler) forgetDeploymentHistory(key string) {
+	r.deploymentHistoryMutex.Lock()
+	defer r.deploymentHistoryMutex.Unlock()
+
+	delete(r.deploymentHistoryLastRecorded, key)
+}
+
+// recordDeploymentHistory adds the entries of the revision history of the Argo CD Application, that are newer than
+// those already in the database, to the DeploymentHistory table. At most MaxDeploymentHistory rows are kept for each Application.
+func recordDeploymentHistory(ctx context.Context, dbQueries db.DatabaseQueries, app appv1.Application, applicationID string, log logr.Logger) error {
+
+	if len(app.Status.History) == 0 {
+		return nil
+	}
+
+	var deploymentHistory []db.DeploymentHistory
+	if err := dbQueries.ListDeploymentHistoryByApplicationId(ctx, applicationID, &deploymentHistory); err != nil {
+		return fmt.Errorf("unable to list deployment history: %v", err)
+	}
+
+	var lastDeployedAt time.Time
+	if len(deploymentHistory) > 0 {
+		lastDeployedAt = deploymentHistory[len(deploymentHistory)-1].DeployedAt
+	}
+
+	for _, revisionHistory := range app.Status.History {
+
+		// Skip entries we have already recorded: the revision history of a recreated Application will start over,
+		// but only entries that are newer than the last recorded entry will be added.
+		if !revisionHistory.DeployedAt.Time.After(lastDeployedAt) {
+			continue
+		}
+
+		newDeploymentHistory, err := convertRevisionHistoryToDeploymentHistory(ctx, dbQueries, app, revisionHistory, applicationID)
+		if err != nil {
+			return err
+		}
+
+		if err := dbQueries.CreateDeploymentHistory(ctx, &newDeploymentHistory); err != nil {
+			return fmt.Errorf("unable to create deployment history: %v", err)
+		}
+		log.Info("Recorded deployment history of Application", "revision", newDeploymentHistory.Revision)
+
+		deploymentHistory = append(deploymentHistory, newDeploymentHistory)
+	}
+
+	// Remove the oldest entries, beyond the maximum
+	for len(deploymentHistory) > managedgitopsv1alpha1.MaxDeploymentHistory {
+		if _, err := dbQueries.DeleteDeploymentHistoryById(ctx, deploymentHistory[0].DeploymentHistory_id); err != nil {
+			return fmt.Errorf("unable to delete old deployment history: %v", err)
+		}
+		deploymentHistory = deploymentHistory[1:]
+	}
+
+	return nil
+}
+
+// convertRevisionHistoryToDeploymentHistory converts an entry in the revision history of an Argo CD Application into
+// a DeploymentHistory row.
+func convertRevisionHistoryToDeploymentHistory(ctx context.Context, dbQueries db.DatabaseQueries, app appv1.Application,
+	revisionHistory appv1.RevisionHistory, applicationID string) (db.DeploymentHistory, error) {
+
+	revision := revisionHistory.Revision
+	source := revisionHistory.Source
+
+	// For an Application with multiple sources, every source is recorded, and the first source is also recorded as
+	// the source of the entry
+	var sourcesJSON, revisionsJSON []byte
+	if len(revisionHistory.Sources) > 0 {
+		source = revisionHistory.Sources[0]
+		if len(revisionHistory.Revisions) > 0 {
+			revision = revisionHistory.Revisions[0]
+		}
+
+		var sources []managedgitopsv1alpha1.ApplicationSource
+		for _, multiSource := range revisionHistory.Sources {
+			sources = append(sources, convertSourceOfRevisionHistory(multiSource))
+		}
+
+		var err error
+		if sourcesJSON, err = json.Marshal(sources); err != nil {
+			return db.DeploymentHistory{}, fmt.Errorf("unable to marshal sources of revision history: %v", err)
+		}
+		if revisionsJSON, err = json.Marshal(revisionHistory.Revisions); err != nil {
+			return db.DeploymentHistory{}, fmt.Errorf("unable to marshal revisions of revision history: %v", err)
+		}
+	}
+
+	sourceJSON, err := json.Marshal(convertSourceOfRevisionHistory(source))
+	if err != nil {
+		return db.DeploymentHistory{}, fmt.Errorf("unable to marshal source of revision history: %v", err)
+	}
+
+	initiatedBy := revisionHistory.InitiatedBy.Username
+	if revisionHistory.InitiatedBy.Automated {
+		initiatedBy = db.DeploymentHistory_InitiatedBy_Automated
+	}
+
+	syncRunName, err := getSyncRunNameOfRevisionHistory(ctx, dbQueries, app, revisionHistory)
+	if err != nil {
+		return db.DeploymentHistory{}, err
+	}
+
+	return db.DeploymentHistory{
+		Application_id: applicationID,
+		Revision:       db.TruncateVarchar(revision, db.DeploymentHistoryRevisionLength),
+		Source:         string(sourceJSON),
+		Sources:        string(sourcesJSON),
+		Revisions:      string(revisionsJSON),
+		DeployedAt:     revisionHistory.DeployedAt.UTC(),
+		InitiatedBy:    db.TruncateVarchar(initiatedBy, db.DeploymentHistoryInitiatedByLength),
+		SyncRunName:    syncRunName,
+	}, nil
+}
+
+// convertSourceOfRevisionHistory converts a source of an entry in the revision history of an Argo CD Application.
+// Only the fields that identify the source are recorded, to keep the database row small.
+func convertSourceOfRevisionHistory(source appv1.ApplicationSource) managedgitopsv1alpha1.ApplicationSource {
+	return managedgitopsv1alpha1.ApplicationSource{
+		RepoURL:        source.RepoURL,
+		Path:           source.Path,
+		TargetRevision: source.TargetRevision,
+		Chart:          source.Chart,
+		Ref:            source.Ref,
+	}
+}
+
+// getSyncRunNameOfRevisionHistory returns the name of the GitOpsDeploymentSyncRun that triggered the revision history
+// entry, or "" if it was not triggered by a GitOpsDeploymentSyncRun (or it can no longer be determined).
+//
+// The cluster-agent adds the ID of the SyncOperation to each sync operation that it starts, so the GitOpsDeploymentSyncRun
+// can be determined for the revision history entry that was produced by the current (most recent) operation.
+func getSyncRunNameOfRevisionHistory(ctx context.Context, dbQueries db.DatabaseQueries, app appv1.Application, revisionHistory appv1.RevisionHistory) (string, error) {
+
+	operationState := app.Status.OperationState
+
+	if revisionHistory.InitiatedBy.Automated || operationState == nil || revisionHistory.DeployStartedAt == nil ||
+		!operationState.StartedAt.Equal(revisionHistory.DeployStartedAt) {
+		return "", nil
+	}
+
+	syncOperationID := utils.GetSyncOperationIDOfOperation(operationState.Operation)
+	if syncOperationID == "" {
+		return "", nil
+	}
+
+	apiCRToDBMapping := db.APICRToDatabaseMapping{
+		APIResourceType: db.APICRToDatabaseMapping_ResourceType_GitOpsDeploymentSyncRun,
+		DBRelationType:  db.APICRToDatabaseMapping_DBRelationType_SyncOperation,
+		DBRelationKey:   syncOperationID,
+	}
+	if err := dbQueries.GetAPICRForDatabaseUID(ctx, &apiCRToDBMapping); err != nil {
+		if db.IsResultNotFoundError(err) {
+			// The GitOpsDeploymentSyncRun has since been deleted
+			return "", nil
+		}
+		return "", fmt.Errorf("unable to retrieve GitOpsDeploymentSyncRun of SyncOperation '%s': %v", syncOperationID, err)
+	}
 
+	return apiCRToDBMapping.APIResourceName, nil
 }
 
 type applicationDeleteTask struct {
@@ -2,6 +2,8 @@ package argoprojio
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -14,6 +16,7 @@ import (
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/tests"
 	"github.com/redhat-appstudio/managed-gitops/cluster-agent/controllers"
 	"github.com/redhat-appstudio/managed-gitops/cluster-agent/controllers/argoproj.io/application_info_cache"
+	"github.com/redhat-appstudio/managed-gitops/cluster-agent/utils"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -383,6 +386,127 @@ var _ = Describe("Application Controller", func() {
 			Expect(appStatus.Sync.Status).To(Equal(guestbookApp.Status.Sync.Status))
 		})
 
+		It("Records the revision history of the Application in the DeploymentHistory table, and preserves it when the Application is recreated", func() {
+			By("Close database connection")
+			defer dbQueries.CloseDatabase()
+			defer testTeardown()
+
+			ctx = context.Background()
+
+			applicationDB := &db.Application{
+				Application_id:          guestbookApp.Labels[dbID],
+				Name:                    name,
+				Spec_field:              "{}",
+				Engine_instance_inst_id: gitopsEngineInstance.Gitopsengineinstance_id,
+				Managed_environment_id:  managedEnvironment.Managedenvironment_id,
+			}
+			err = reconciler.DB.CreateApplication(ctx, applicationDB)
+			Expect(err).ToNot(HaveOccurred())
+
+			By("creating a SyncRun mapping for a SyncOperation, which was recorded in the operation of the Application")
+			syncOperation := &db.SyncOperation{
+				SyncOperation_id:    "test-sync-operation",
+				Application_id:      applicationDB.Application_id,
+				DeploymentNameField: "my-gitops-depl",
+				Revision:            "main",
+				DesiredState:        db.SyncOperation_DesiredState_Running,
+			}
+			err = reconciler.DB.CreateSyncOperation(ctx, syncOperation)
+			Expect(err).ToNot(HaveOccurred())
+
+			apiCRToDBMapping := &db.APICRToDatabaseMapping{
+				APIResourceType:      db.APICRToDatabaseMapping_ResourceType_GitOpsDeploymentSyncRun,
+				APIResourceUID:       "test-sync-run-uid",
+				APIResourceName:      "my-sync-run",
+				APIResourceNamespace: "my-namespace",
+				NamespaceUID:         "test-namespace-uid",
+				DBRelationType:       db.APICRToDatabaseMapping_DBRelationType_SyncOperation,
+				DBRelationKey:        syncOperation.SyncOperation_id,
+			}
+			err = reconciler.DB.CreateAPICRToDatabaseMapping(ctx, apiCRToDBMapping)
+			Expect(err).ToNot(HaveOccurred())
+			defer func() {
+				_, err := reconciler.DB.DeleteAPICRToDatabaseMapping(ctx, apiCRToDBMapping)
+				Expect(err).ToNot(HaveOccurred())
+			}()
+
+			firstDeployedAt := metav1.NewTime(time.Now().Add(-time.Hour).Truncate(time.Second))
+			secondStartedAt := metav1.NewTime(time.Now().Add(-time.Minute).Truncate(time.Second))
+			secondDeployedAt := metav1.NewTime(time.Now().Truncate(time.Second))
+
+			guestbookApp.Status.History = appv1.RevisionHistories{
+				{
+					ID:          0,
+					Revision:    "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+					DeployedAt:  firstDeployedAt,
+					Source:      *guestbookApp.Spec.Source,
+					InitiatedBy: appv1.OperationInitiator{Automated: true},
+				},
+				{
+					ID:              1,
+					Revision:        "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb",
+					DeployedAt:      secondDeployedAt,
+					DeployStartedAt: &secondStartedAt,
+					Source:          *guestbookApp.Spec.Source,
+					InitiatedBy:     appv1.OperationInitiator{Username: "admin"},
+				},
+			}
+			guestbookApp.Status.OperationState = &appv1.OperationState{
+				Operation: appv1.Operation{
+					Info: []*appv1.Info{{Name: utils.SyncOperationIDInfoName, Value: syncOperation.SyncOperation_id}},
+				},
+				StartedAt: secondStartedAt,
+			}
+
+			err = reconciler.Create(ctx, guestbookApp)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = reconciler.Reconcile(ctx, newRequest(namespace, name))
+			Expect(err).ToNot(HaveOccurred())
+
+			var deploymentHistory []db.DeploymentHistory
+			err = reconciler.DB.ListDeploymentHistoryByApplicationId(ctx, applicationDB.Application_id, &deploymentHistory)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deploymentHistory).To(HaveLen(2))
+
+			Expect(deploymentHistory[0].Revision).To(Equal("aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"))
+			Expect(deploymentHistory[0].InitiatedBy).To(Equal(db.DeploymentHistory_InitiatedBy_Automated))
+			Expect(deploymentHistory[0].SyncRunName).To(BeEmpty())
+			Expect(deploymentHistory[0].Source).To(ContainSubstring(guestbookApp.Spec.Source.RepoURL))
+
+			Expect(deploymentHistory[1].Revision).To(Equal("bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"))
+			Expect(deploymentHistory[1].InitiatedBy).To(Equal("admin"))
+			Expect(deploymentHistory[1].SyncRunName).To(Equal(apiCRToDBMapping.APIResourceName))
+
+			By("recreating the Application, which resets its revision history")
+			err = reconciler.Delete(ctx, guestbookApp)
+			Expect(err).ToNot(HaveOccurred())
+
+			thirdDeployedAt := metav1.NewTime(time.Now().Add(time.Minute).Truncate(time.Second))
+			guestbookApp.ResourceVersion = ""
+			guestbookApp.Status.OperationState = nil
+			guestbookApp.Status.History = appv1.RevisionHistories{
+				{
+					ID:          0,
+					Revision:    "cccccccccccccccccccccccccccccccccccccccc",
+					DeployedAt:  thirdDeployedAt,
+					Source:      *guestbookApp.Spec.Source,
+					InitiatedBy: appv1.OperationInitiator{Automated: true},
+				},
+			}
+			err = reconciler.Create(ctx, guestbookApp)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = reconciler.Reconcile(ctx, newRequest(namespace, name))
+			Expect(err).ToNot(HaveOccurred())
+
+			deploymentHistory = []db.DeploymentHistory{}
+			err = reconciler.DB.ListDeploymentHistoryByApplicationId(ctx, applicationDB.Application_id, &deploymentHistory)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(deploymentHistory).To(HaveLen(3))
+			Expect(deploymentHistory[2].Revision).To(Equal("cccccccccccccccccccccccccccccccccccccccc"))
+		})
+
 		It("Calls Reconcile on an Argo CD Application resource that doesn't exist", func() {
 			By("Close database connection")
 			defer dbQueries.CloseDatabase()
@@ -831,6 +955,53 @@ var _ = Describe("Namespace Reconciler Tests.", func() {
 			}
 		})
 
+		It("Should not update an OutofSync ArgoCD Application if its GitOpsDeployment is suspended.", func() {
+
+			By("marking the Application as suspended in the DB, with automated sync disabled")
+			applicationFromDB, _, _, err := createDummyApplicationData()
+			Expect(err).ToNot(HaveOccurred())
+
+			applicationFromDB.Annotations = map[string]string{fauxargocd.SuspendedAnnotation: "true"}
+			applicationFromDB.Spec.SyncPolicy = nil
+
+			specBytes, err := yaml.Marshal(applicationFromDB)
+			Expect(err).ToNot(HaveOccurred())
+
+			applicationput.Spec_field = string(specBytes)
+			err = dbQueries.UpdateApplication(ctx, &applicationput)
+			Expect(err).ToNot(HaveOccurred())
+
+			By("updating the ArgoCD Application, so it will be out of Sync with DB entry")
+			argoCdApp.Spec.SyncPolicy = nil
+			argoCdApp.Spec.Source.RepoURL = "https://github.com/test/gitops-repository-template"
+
+			err = reconciler.Update(ctx, &argoCdApp)
+			Expect(err).ToNot(HaveOccurred())
+
+			ctx := context.Background()
+			log := log.FromContext(ctx)
+
+			Expect(syncCRsWithDB_Applications(ctx, reconciler.DB, reconciler.Client, log)).To(Succeed())
+
+			By("Verify that no Operation is created for the Application.")
+
+			listOfK8sOperation := managedgitopsv1alpha1.OperationList{}
+
+			err = reconciler.List(ctx, &listOfK8sOperation)
+			Expect(err).ToNot(HaveOccurred())
+
+			for _, k8sOperation := range listOfK8sOperation.Items {
+				if k8sOperation.Annotations[operations.IdentifierKey] == operations.IdentifierValue {
+					dbOperation := db.Operation{
+						Operation_id: k8sOperation.Spec.OperationID,
+					}
+					err = dbQueries.GetOperationById(ctx, &dbOperation)
+					Expect(err).ToNot(HaveOccurred())
+					Expect(dbOperation.Resource_id).ToNot(Equal(applicationput.Application_id))
+				}
+			}
+		})
+
 		It("Should create new ArgoCD Application if application exists in DB, but it is not available in ArgoCD.", func() {
 
 			// Delete the application from ArgoCD, but keep all DB entries.
@@ -1031,3 +1202,146 @@ func createDummyApplicationData() (fauxargocd.FauxApplication, string, appv1.App
 
 	return dummyApplicationSpec, string(dummyApplicationSpecBytes), dummyArgoCdApplication, nil
 }
+
+var _ = Describe("getResourceTreeOfApplication", func() {
+
+	app := appv1.Application{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "my-application",
+			Namespace: "argocd",
+		},
+	}
+
+	It("should not collect the resource tree if no function to retrieve it was provided", func() {
+		reconciler := ApplicationReconciler{}
+
+		resourceTreeBytes, collected, requeueAfter := reconciler.getResourceTreeOfApplication(context.Background(), app, "argocd/my-application", log.FromContext(context.Background()))
+		Expect(resourceTreeBytes).To(BeNil())
+		Expect(collected).To(BeFalse())
+		Expect(requeueAfter).To(BeZero())
+	})
+
+	It("should collect the resource tree at most once per collection interval", func() {
+		calls := 0
+		reconciler := ApplicationReconciler{
+			GetResourceTree: func(ctx context.Context, app appv1.Application) (*managedgitopsv1alpha1.ResourceTree, error) {
+				calls++
+				return &managedgitopsv1alpha1.ResourceTree{
+					Nodes: []managedgitopsv1alpha1.ResourceNode{{ResourceRef: managedgitopsv1alpha1.ResourceRef{Kind: "ConfigMap", Name: "my-config"}}},
+				}, nil
+			},
+		}
+
+		By("collecting the resource tree on the first reconcile")
+		resourceTreeBytes, collected, requeueAfter := reconciler.getResourceTreeOfApplication(context.Background(), app, "argocd/my-application", log.FromContext(context.Background()))
+		Expect(collected).To(BeTrue())
+		Expect(requeueAfter).To(BeZero())
+
+		decompressed, err := sharedutil.DecompressObject(resourceTreeBytes)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(decompressed)).To(ContainSubstring("my-config"))
+
+		By("not collecting it again within the collection interval, but requeuing")
+		_, collected, requeueAfter = reconciler.getResourceTreeOfApplication(context.Background(), app, "argocd/my-application", log.FromContext(context.Background()))
+		Expect(collected).To(BeFalse())
+		Expect(requeueAfter).To(BeNumerically(">", 0))
+		Expect(requeueAfter).To(BeNumerically("<=", resourceTreeCollectionInterval))
+		Expect(calls).To(Equal(1))
+
+		By("collecting it again once the Application has been deleted and recreated")
+		reconciler.forgetResourceTreeCollection("argocd/my-application")
+		_, collected, _ = reconciler.getResourceTreeOfApplication(context.Background(), app, "argocd/my-application", log.FromContext(context.Background()))
+		Expect(collected).To(BeTrue())
+		Expect(calls).To(Equal(2))
+	})
+
+	It("should not return an error if the resource tree cannot be retrieved", func() {
+		reconciler := ApplicationReconciler{
+			GetResourceTree: func(ctx context.Context, app appv1.Application) (*managedgitopsv1alpha1.ResourceTree, error) {
+				return nil, fmt.Errorf("unable to connect to Argo CD")
+			},
+		}
+
+		resourceTreeBytes, collected, requeueAfter := reconciler.getResourceTreeOfApplication(context.Background(), app, "argocd/my-application", log.FromContext(context.Background()))
+		Expect(resourceTreeBytes).To(BeNil())
+		Expect(collected).To(BeFalse())
+		Expect(requeueAfter).To(Equal(resourceTreeCollectionInterval))
+	})
+})
+
+var _ = Describe("isDeploymentHistoryChanged", func() {
+
+	It("should only report the revision history as changed when it has a new entry, since it was last recorded", func() {
+		reconciler := ApplicationReconciler{}
+
+		app := appv1.Application{}
+		Expect(reconciler.isDeploymentHistoryChanged("argocd/my-application", app)).To(BeFalse())
+
+		app.Status.History = appv1.RevisionHistories{
+			{ID: 0, Revision: "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", DeployedAt: metav1.NewTime(time.Now().Add(-time.Hour))},
+		}
+		Expect(reconciler.isDeploymentHistoryChanged("argocd/my-application", app)).To(BeTrue())
+
+		reconciler.setDeploymentHistoryRecorded("argocd/my-application", app)
+		Expect(reconciler.isDeploymentHistoryChanged("argocd/my-application", app)).To(BeFalse())
+
+		By("adding a new entry to the revision history")
+		app.Status.History = append(app.Status.History,
+			appv1.RevisionHistory{ID: 1, Revision: "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", DeployedAt: metav1.Now()})
+		Expect(reconciler.isDeploymentHistoryChanged("argocd/my-application", app)).To(BeTrue())
+
+		reconciler.setDeploymentHistoryRecorded("argocd/my-application", app)
+		Expect(reconciler.isDeploymentHistoryChanged("argocd/my-application", app)).To(BeFalse())
+
+		By("deleting the Application")
+		reconciler.forgetDeploymentHistory("argocd/my-application")
+		Expect(reconciler.isDeploymentHistoryChanged("argocd/my-application", app)).To(BeTrue())
+	})
+})
+
+var _ = Describe("convertRevisionHistoryToDeploymentHistory", func() {
+
+	It("should record every source of an Application with multiple sources", func() {
+		revisionHistory := appv1.RevisionHistory{
+			Revisions: []string{"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"},
+			Sources: appv1.ApplicationSources{
+				{RepoURL: "https://github.com/redhat-appstudio/managed-gitops", Path: "resources/test-data/sample-gitops-repository/environments/overlays/dev"},
+				{RepoURL: "https://github.com/redhat-appstudio/managed-gitops-values", Ref: "values"},
+			},
+			DeployedAt:  metav1.Now(),
+			InitiatedBy: appv1.OperationInitiator{Automated: true},
+		}
+
+		deploymentHistory, err := convertRevisionHistoryToDeploymentHistory(context.Background(), nil, appv1.Application{}, revisionHistory, "test-application")
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(deploymentHistory.Revision).To(Equal("aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"))
+		Expect(deploymentHistory.Source).To(ContainSubstring("environments/overlays/dev"))
+
+		var sources []managedgitopsv1alpha1.ApplicationSource
+		Expect(json.Unmarshal([]byte(deploymentHistory.Sources), &sources)).To(Succeed())
+		Expect(sources).To(Equal([]managedgitopsv1alpha1.ApplicationSource{
+			{RepoURL: "https://github.com/redhat-appstudio/managed-gitops", Path: "resources/test-data/sample-gitops-repository/environments/overlays/dev"},
+			{RepoURL: "https://github.com/redhat-appstudio/managed-gitops-values", Ref: "values"},
+		}))
+
+		var revisions []string
+		Expect(json.Unmarshal([]byte(deploymentHistory.Revisions), &revisions)).To(Succeed())
+		Expect(revisions).To(Equal(revisionHistory.Revisions))
+	})
+
+	It("should not record the sources of an Application with a single source", func() {
+		revisionHistory := appv1.RevisionHistory{
+			Revision:    "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa",
+			Source:      appv1.ApplicationSource{RepoURL: "https://github.com/redhat-appstudio/managed-gitops"},
+			DeployedAt:  metav1.Now(),
+			InitiatedBy: appv1.OperationInitiator{Automated: true},
+		}
+
+		deploymentHistory, err := convertRevisionHistoryToDeploymentHistory(context.Background(), nil, appv1.Application{}, revisionHistory, "test-application")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(deploymentHistory.Source).To(ContainSubstring("managed-gitops"))
+		Expect(deploymentHistory.Sources).To(BeEmpty())
+		Expect(deploymentHistory.Revisions).To(BeEmpty())
+	})
+})
@@ -249,6 +249,12 @@ func processApplicationRowToSyncWithCluster(ctx context.Context, applicationRowF
 	// If the managed_environment_id row is not empty, then it depends on whether the DB spec field matches the
 	if applicationRowFromDB.Managed_environment_id != "" {
 
+		// While a GitOpsDeployment is suspended, changes to its Argo CD Application are intentionally not reverted
+		if isSuspendedApplication(applicationFromDB, applicationFromArgoCD) {
+			log.V(logutil.LogLevel_Debug).Info("Argo application is suspended, so it is not synced with DB")
+			return nil
+		}
+
 		// At this point we have the applications from ArgoCD and DB, now compare them to check if they are not in Sync.
 
 		if compare, err := controllers.CompareApplication(applicationFromArgoCD, applicationRowFromDB, log); err != nil {
@@ -297,6 +303,20 @@ func processApplicationRowToSyncWithCluster(ctx context.Context, applicationRowF
 	return nil
 }
 
+// isSuspendedApplication returns true if the GitOpsDeployment of the Application is suspended, in which case the Argo CD
+// Application should not be updated to match the database. The exception is an Argo CD Application that still has
+// automated sync enabled: this contradicts the suspension, and so the Argo CD Application is still updated in that case.
+func isSuspendedApplication(applicationFromDB fauxargocd.FauxApplication, applicationFromArgoCD appv1.Application) bool {
+
+	if applicationFromDB.Annotations[fauxargocd.SuspendedAnnotation] != "true" {
+		return false
+	}
+
+	automatedSyncEnabled := applicationFromArgoCD.Spec.SyncPolicy != nil && applicationFromArgoCD.Spec.SyncPolicy.Automated != nil
+
+	return !automatedSyncEnabled
+}
+
 func syncCRsWithDB_Applications_Delete_Operations(ctx context.Context, dbq db.DatabaseQueries, client client.Client, log logr.Logger) error {
 	// Get list of Operations from cluster.
 	listOfK8sOperation := v1alpha1.OperationList{}
@@ -13,6 +13,7 @@ import (
 	dbutil "github.com/redhat-appstudio/managed-gitops/backend-shared/db/util"
 	sharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util"
 	argosharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/argocd"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/fauxargocd"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/operations"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/tests"
 	"github.com/redhat-appstudio/managed-gitops/cluster-agent/controllers/argoproj.io/application_info_cache"
@@ -69,6 +70,35 @@ var _ = Describe("Namespace Reconciler Tests.", func() {
 		})
 	})
 
+	Context("Testing isSuspendedApplication function.", func() {
+
+		var applicationFromDB fauxargocd.FauxApplication
+		var applicationFromArgoCD appv1.Application
+
+		BeforeEach(func() {
+			var err error
+			applicationFromDB, _, applicationFromArgoCD, err = createDummyApplicationData()
+			Expect(err).ToNot(HaveOccurred())
+
+			applicationFromArgoCD.Spec.SyncPolicy = nil
+		})
+
+		It("Should return false if the Application is not suspended.", func() {
+			Expect(isSuspendedApplication(applicationFromDB, applicationFromArgoCD)).To(BeFalse())
+		})
+
+		It("Should return true if the Application is suspended.", func() {
+			applicationFromDB.Annotations = map[string]string{fauxargocd.SuspendedAnnotation: "true"}
+			Expect(isSuspendedApplication(applicationFromDB, applicationFromArgoCD)).To(BeTrue())
+		})
+
+		It("Should return false if the Application is suspended, but the Argo CD Application still has automated sync enabled.", func() {
+			applicationFromDB.Annotations = map[string]string{fauxargocd.SuspendedAnnotation: "true"}
+			applicationFromArgoCD.Spec.SyncPolicy = &appv1.SyncPolicy{Automated: &appv1.SyncPolicyAutomated{}}
+			Expect(isSuspendedApplication(applicationFromDB, applicationFromArgoCD)).To(BeFalse())
+		})
+	})
+
 	Context("Testing syncCRsWithDB_Applications_Delete_Operations function", func() {
 		var err error
 		var dbQueries db.AllDatabaseQueries
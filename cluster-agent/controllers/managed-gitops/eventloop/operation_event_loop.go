@@ -10,6 +10,7 @@ import (
 	"time"
 
 	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	synccommon "github.com/argoproj/gitops-engine/pkg/sync/common"
 	"github.com/go-logr/logr"
 	operation "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/db"
@@ -101,6 +102,13 @@ func operationEventLoopRouter(input chan operationEventLoopEvent) {
 
 	credentialService := utils.NewCredentialService(nil, false)
 
+	// requeueEvent sends the event to the event loop again, once the given delay has elapsed
+	requeueEvent := func(event operationEventLoopEvent, delay time.Duration) {
+		time.AfterFunc(delay, func() {
+			input <- event
+		})
+	}
+
 	for {
 		newEvent := <-input
 
@@ -145,6 +153,7 @@ func operationEventLoopRouter(input chan operationEventLoopEvent) {
 			log:               log,
 			credentialService: credentialService,
 			syncFuncs:         defaultSyncFuncs(),
+			requeueEvent:      requeueEvent,
 		}
 		taskRetryLoop.AddTaskIfNotPresent(mapKey, task, sharedutil.ExponentialBackoff{Factor: 2, Min: time.Millisecond * 200, Max: time.Second * 10, Jitter: true})
 
@@ -226,6 +235,10 @@ type processOperationEventTask struct {
 	log               logr.Logger
 	credentialService *utils.CredentialService
 	syncFuncs         *syncFuncs
+
+	// requeueEvent, if set, is called to process the event again after a delay: for example, once the sync windows of
+	// a SyncOperation allow the sync.
+	requeueEvent func(event operationEventLoopEvent, delay time.Duration)
 }
 
 // PerformTask takes as input an Operation resource event, and processes it based on the contents of that event.
@@ -458,12 +471,32 @@ func (task *processOperationEventTask) internalPerformTask(taskContext context.C
 	} else if dbOperation.Resource_type == db.OperationResourceType_SyncOperation {
 
 		// Process a SyncOperation event
-		shouldRetry, err := processOperation_SyncOperation(taskContext, dbOperation, *operationCR, operationConfigParams)
+		shouldRetry, requeueAt, err := processOperation_SyncOperation(taskContext, dbOperation, *operationCR, operationConfigParams)
 
 		if err != nil {
 			log.Error(err, "error occurred on processing the sync application operation")
 		}
 
+		if !requeueAt.IsZero() {
+			// The sync is not yet allowed, so the Operation is left in progress (rather than being completed), and is
+			// processed again once the sync may occur.
+			if task.requeueEvent != nil {
+				task.requeueEvent(task.event, time.Until(requeueAt))
+			}
+			return nil, shouldRetryFalse, nil
+		}
+
+		return &dbOperation, shouldRetry, err
+
+	} else if dbOperation.Resource_type == db.OperationResourceType_DiffOperation {
+
+		// Process a DiffOperation event
+		shouldRetry, err := processOperation_DiffOperation(taskContext, dbOperation, *operationCR, operationConfigParams)
+
+		if err != nil {
+			log.Error(err, "error occurred on processing the diff application operation")
+		}
+
 		return &dbOperation, shouldRetry, err
 
 	} else if dbOperation.Resource_type == db.OperationResourceType_GitOpsEngineInstance {
@@ -504,21 +537,27 @@ type operationConfig struct {
 	// log is the current logger in used
 	log logr.Logger
 
-	// syncFuncs provide functions to sync/terminate an operation
+	// syncFuncs provide functions to sync/terminate/diff an operation
 	syncFuncs *syncFuncs
 }
 
+// syncWindowRequeueInterval is how long a SyncOperation waits before its sync windows are checked again, when the
+// time at which the next sync window opens could not be determined (for example, because a deny window never closes).
+const syncWindowRequeueInterval = time.Hour
+
 // Process a SyncOperation database entry, that was pointed to by an Operation CR.
-// returns shouldRetry, error
+// returns shouldRetry, requeueAt, error
+// - requeueAt is non-zero if the sync is not yet allowed by the sync windows of the SyncOperation: the Operation
+// should then be processed again at that time.
 func processOperation_SyncOperation(ctx context.Context, dbOperation db.Operation, crOperation operation.Operation,
-	opConfig operationConfig) (bool, error) {
+	opConfig operationConfig) (bool, time.Time, error) {
 
 	log := opConfig.log
 	dbQueries := opConfig.dbQueries
 
 	// Sanity checks
 	if dbOperation.Resource_id == "" {
-		return shouldRetryFalse, errors.New("resource id was nil while processing operation: " + crOperation.Name)
+		return shouldRetryFalse, time.Time{}, errors.New("resource id was nil while processing operation: " + crOperation.Name)
 	}
 
 	// 1) Retrieve the SyncOperation DB entry pointed to by the Operation DB entry
@@ -533,11 +572,11 @@ func processOperation_SyncOperation(ctx context.Context, dbOperation db.Operatio
 		if !db.IsResultNotFoundError(err) {
 			// On generic error, we should retry.
 			log.Error(err, "DB error occurred on retrieving SyncOperation")
-			return shouldRetryTrue, err
+			return shouldRetryTrue, time.Time{}, err
 		} else {
 			// On db row not found, just ignore it and move on.
 			log.V(logutil.LogLevel_Debug).Info("SyncOperation DB entry was no longer available.")
-			return shouldRetryFalse, err
+			return shouldRetryFalse, time.Time{}, err
 		}
 	}
 
@@ -551,33 +590,97 @@ func processOperation_SyncOperation(ctx context.Context, dbOperation db.Operatio
 
 		if db.IsResultNotFoundError(err) {
 			log.Error(err, "Unable to retrieve application ID in SyncOperation table")
-			return shouldRetryFalse, err
+			return shouldRetryFalse, time.Time{}, err
 		} else {
 			// On generic error, return true so the operation is retried.
 			log.Error(err, "Error occurred on retrieving application ID in SyncOperation table")
-			return shouldRetryTrue, err
+			return shouldRetryTrue, time.Time{}, err
 		}
 	}
 
 	// 3) Process the event, based on whether the SyncOperation is requesting an app sync, or a terminate.
 	if dbSyncOperation.DesiredState == db.SyncOperation_DesiredState_Running {
+
+		// Don't sync the Application while the sync windows of the GitOpsDeployment do not allow it: the operation is
+		// instead processed again once the next sync window opens.
+		allowed, nextSyncWindow, err := checkSyncWindowsOfSyncOperation(*dbSyncOperation, time.Now())
+		if err != nil {
+			// The sync windows of the SyncOperation will not change, so there is no point in retrying
+			log.Error(err, "unable to check the sync windows of the SyncOperation")
+
+			syncOperationStatus := db.SyncOperation{
+				SyncOperation_id: dbSyncOperation.SyncOperation_id,
+				Phase:            db.SyncOperation_Phase_Failed,
+				PhaseMessage:     db.TruncateVarchar(err.Error(), db.SyncOperationPhaseMessageLength),
+				FinishedAt:       time.Now(),
+			}
+			updateSyncOperationStatus(ctx, &syncOperationStatus, opConfig)
+
+			return shouldRetryFalse, time.Time{}, err
+		}
+
+		if !allowed {
+			requeueAt := nextSyncWindow
+			phaseMessage := fmt.Sprintf("the sync is waiting for a sync window to open, at %s", nextSyncWindow.UTC().Format(time.RFC3339))
+			if nextSyncWindow.IsZero() {
+				requeueAt = time.Now().Add(syncWindowRequeueInterval)
+				phaseMessage = "the sync is waiting for a sync window to open, but no upcoming sync window was found"
+			}
+
+			log.Info("Sync of Application is blocked by sync windows", "requeueAt", requeueAt)
+
+			syncOperationStatus := db.SyncOperation{
+				SyncOperation_id: dbSyncOperation.SyncOperation_id,
+				Phase:            db.SyncOperation_Phase_Pending,
+				PhaseMessage:     phaseMessage,
+			}
+			updateSyncOperationStatus(ctx, &syncOperationStatus, opConfig)
+
+			return shouldRetryFalse, requeueAt, nil
+		}
+
 		// refresh the Application before syncing to make sure that the latest revision is deployed.
 		if err := opConfig.syncFuncs.refreshApp(ctx, opConfig.eventClient, dbApplication.Name, opConfig.argoCDNamespace.Name); err != nil {
-			return shouldRetryTrue, err
+			return shouldRetryTrue, time.Time{}, err
 		}
 
-		return runAppSync(ctx, dbOperation, *dbSyncOperation, &dbApplication, opConfig)
+		shouldRetry, err := runAppSync(ctx, dbOperation, *dbSyncOperation, &dbApplication, opConfig)
+		return shouldRetry, time.Time{}, err
 
 	} else if dbSyncOperation.DesiredState == db.SyncOperation_DesiredState_Terminated {
 
-		return terminateExistingOperation(ctx, &dbApplication, opConfig)
+		shouldRetry, err := terminateExistingOperation(ctx, &dbApplication, opConfig)
+		return shouldRetry, time.Time{}, err
 
 	} else {
 		log.Error(nil, "SEVERE: unexpected desired state in SyncOperation DB entry")
-		return shouldRetryFalse, nil
+		return shouldRetryFalse, time.Time{}, nil
 	}
 }
 
+// checkSyncWindowsOfSyncOperation returns true if the sync windows that were stored in the SyncOperation allow a sync
+// at the given time. If not, the time at which the next sync window opens is also returned: this is the zero time
+// if it could not be determined.
+// An error is only returned if the sync windows of the SyncOperation are invalid.
+func checkSyncWindowsOfSyncOperation(dbSyncOperation db.SyncOperation, now time.Time) (bool, time.Time, error) {
+
+	if dbSyncOperation.SyncWindows == "" {
+		return true, time.Time{}, nil
+	}
+
+	var syncWindows operation.SyncWindows
+	if err := json.Unmarshal([]byte(dbSyncOperation.SyncWindows), &syncWindows); err != nil {
+		return false, time.Time{}, fmt.Errorf("unable to unmarshal sync windows of SyncOperation '%s': %v", dbSyncOperation.SyncOperation_id, err)
+	}
+
+	allowed, nextSyncWindow, err := syncWindows.SyncAllowed(now)
+	if err != nil {
+		return false, time.Time{}, fmt.Errorf("invalid sync windows in SyncOperation '%s': %v", dbSyncOperation.SyncOperation_id, err)
+	}
+
+	return allowed, nextSyncWindow, nil
+}
+
 func refreshApplication(ctx context.Context, k8sClient client.Client, appName, appNS string) error {
 	appCR := &appv1.Application{
 		ObjectMeta: metav1.ObjectMeta{
@@ -681,12 +784,14 @@ func isOperationRunning(ctx context.Context, k8sClient client.Client, appName, a
 	return true, nil
 }
 
-// syncFuncs is a wrapper over sync and terminate functions and is used in unit testing different sync scenarios
+// syncFuncs is a wrapper over sync, terminate and diff functions and is used in unit testing different sync scenarios
 type syncFuncs struct {
-	appSync            func(context.Context, string, string, string, client.Client, *utils.CredentialService, bool) error
+	appSync            func(context.Context, string, string, string, client.Client, *utils.CredentialService, bool, utils.AppSyncOptions) error
 	terminateOperation func(context.Context, string, corev1.Namespace, *utils.CredentialService, client.Client, time.Duration, logr.Logger) error
 
 	refreshApp func(context.Context, client.Client, string, string) error
+
+	appDiff func(context.Context, string, string, corev1.Namespace, *utils.CredentialService, client.Client) (utils.AppDiffResult, error)
 }
 
 func defaultSyncFuncs() *syncFuncs {
@@ -694,18 +799,36 @@ func defaultSyncFuncs() *syncFuncs {
 		appSync:            utils.AppSync,
 		terminateOperation: utils.TerminateOperation,
 		refreshApp:         refreshApplication,
+		appDiff:            utils.AppDiff,
 	}
 }
 
+// argoCDOperationTerminatedMessagePrefix is the prefix of the message of an Argo CD operation that was terminated:
+// either 'Operation terminated', or 'Operation termination had errors'.
+const argoCDOperationTerminatedMessagePrefix = "Operation terminat"
+
 // returns shouldRetry, error
 func runAppSync(ctx context.Context, dbOperation db.Operation, dbSyncOperation db.SyncOperation,
 	dbApplication *db.Application, opConfig operationConfig) (bool, error) {
 
 	log := opConfig.log
 
-	completeChan := make(chan bool)
+	appSyncOptions, err := getAppSyncOptionsOfSyncOperation(dbSyncOperation)
+	if err != nil {
+		// The SyncOperation row will not change, so there is no point in retrying
+		log.Error(err, "unable to retrieve the sync options of the SyncOperation")
+		return shouldRetryFalse, err
+	}
 
-	var err error
+	// Report that the sync has started
+	syncOperationStatus := db.SyncOperation{
+		SyncOperation_id: dbSyncOperation.SyncOperation_id,
+		Phase:            db.SyncOperation_Phase_Running,
+		StartedAt:        time.Now(),
+	}
+	updateSyncOperationStatus(ctx, &syncOperationStatus, opConfig)
+
+	completeChan := make(chan bool)
 
 	cancellableCtx, cancelFunc := context.WithCancel(ctx)
 
@@ -713,8 +836,12 @@ func runAppSync(ctx context.Context, dbOperation db.Operation, dbSyncOperation d
 
 	// Start the AppSync operation in a separate thread.
 	go func() {
+		// Record the ID of the SyncOperation in the Argo CD operation, so that the resulting deployment history entry
+		// can be traced back to the GitOpsDeploymentSyncRun
+		appSyncOptions.Infos = []*appv1.Info{{Name: utils.SyncOperationIDInfoName, Value: dbSyncOperation.SyncOperation_id}}
+
 		err = opConfig.syncFuncs.appSync(cancellableCtx, dbApplication.Name, dbSyncOperation.Revision, opConfig.argoCDNamespace.Name, opConfig.eventClient,
-			opConfig.credentialService, false)
+			opConfig.credentialService, false, appSyncOptions)
 
 		var failed bool
 		if err != nil {
@@ -757,6 +884,12 @@ outer:
 		// for the appsync to complete, here.
 		if dbSyncOperation.DesiredState != db.SyncOperation_DesiredState_Running {
 			log.V(logutil.LogLevel_Debug).Info("SyncOperation no longer had desired running state.")
+
+			syncOperationStatus.Phase = db.SyncOperation_Phase_Terminated
+			syncOperationStatus.PhaseMessage = "the sync was terminated"
+			syncOperationStatus.FinishedAt = time.Now()
+			updateSyncOperationStatus(ctx, &syncOperationStatus, opConfig)
+
 			shouldRetry = shouldRetryFalse
 			err = nil
 			break outer
@@ -764,7 +897,26 @@ outer:
 
 		// 3) Otherwise, continue waiting the AppSync operation to complete.
 		select {
-		case shouldRetry = <-completeChan:
+		case failed := <-completeChan:
+
+			// Report the result of the sync, including the result of each resource, from the Argo CD Application
+			app := &appv1.Application{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      dbApplication.Name,
+					Namespace: opConfig.argoCDNamespace.Name,
+				},
+			}
+			if innerErr := opConfig.eventClient.Get(ctx, client.ObjectKeyFromObject(app), app); innerErr != nil {
+				log.Error(innerErr, "unable to retrieve Argo CD Application, to report the result of the sync")
+				app = nil
+			}
+
+			syncOperationStatus = getSyncOperationStatusOfApplication(syncOperationStatus, app, err, time.Now(), log)
+			updateSyncOperationStatus(ctx, &syncOperationStatus, opConfig)
+
+			// A failed sync is retried until it succeeds, unless it was terminated
+			shouldRetry = failed && syncOperationStatus.Phase == db.SyncOperation_Phase_Failed
+
 			break outer
 		default:
 			backoff.DelayOnFail(ctx)
@@ -774,15 +926,304 @@ outer:
 	return shouldRetry, err
 }
 
+// updateSyncOperationStatus reports the status of a sync via the status fields of the SyncOperation row, which are
+// published to the GitOpsDeploymentSyncRun by the backend. Failures are logged, but otherwise do not affect the sync.
+func updateSyncOperationStatus(ctx context.Context, syncOperationStatus *db.SyncOperation, opConfig operationConfig) {
+
+	if err := opConfig.dbQueries.UpdateSyncOperationStatus(ctx, syncOperationStatus); err != nil {
+		if db.IsResultNotFoundError(err) {
+			// The SyncOperation was deleted, so there is no longer anywhere to report the status to
+			return
+		}
+		opConfig.log.Error(err, "unable to update the status of the SyncOperation", "syncOperationID", syncOperationStatus.SyncOperation_id)
+	}
+}
+
+// getSyncOperationStatusOfApplication returns the status of a completed sync: if the most recent operation of the Argo CD
+// Application was started by the SyncOperation, the status contains the result of the operation.
+// - syncErr is the error returned by the sync, if any
+//
+// A failed sync is retried, and so has not yet finished: the finish time is only reported once the sync has succeeded,
+// or was terminated.
+func getSyncOperationStatusOfApplication(syncOperationStatus db.SyncOperation, app *appv1.Application, syncErr error, now time.Time, log logr.Logger) db.SyncOperation {
+
+	syncOperationStatus.Phase = db.SyncOperation_Phase_Succeeded
+	syncOperationStatus.PhaseMessage = ""
+	syncOperationStatus.FinishedAt = now
+
+	if syncErr != nil {
+		syncOperationStatus.Phase = db.SyncOperation_Phase_Failed
+		syncOperationStatus.PhaseMessage = syncErr.Error()
+	}
+
+	if app != nil && app.Status.OperationState != nil &&
+		utils.GetSyncOperationIDOfOperation(app.Status.OperationState.Operation) == syncOperationStatus.SyncOperation_id {
+
+		operationState := app.Status.OperationState
+
+		if syncErr == nil {
+			syncOperationStatus.PhaseMessage = operationState.Message
+		}
+		if !operationState.StartedAt.IsZero() {
+			syncOperationStatus.StartedAt = operationState.StartedAt.Time
+		}
+		if operationState.FinishedAt != nil {
+			syncOperationStatus.FinishedAt = operationState.FinishedAt.Time
+		}
+
+		if operationState.SyncResult != nil {
+			syncOperationStatus.SyncedRevision = operationState.SyncResult.Revision
+
+			resourceResults, err := convertResourceResultsToJSON(operationState.SyncResult.Resources)
+			if err != nil {
+				log.Error(err, "unable to convert the results of the resources of the sync")
+			} else {
+				syncOperationStatus.ResourceResults = resourceResults
+			}
+		}
+
+		if isOperationStateTerminated(*operationState) {
+			syncOperationStatus.Phase = db.SyncOperation_Phase_Terminated
+			syncOperationStatus.PhaseMessage = operationState.Message
+		}
+	}
+
+	if syncOperationStatus.Phase == db.SyncOperation_Phase_Failed {
+		syncOperationStatus.FinishedAt = time.Time{}
+		syncOperationStatus.PhaseMessage += " (the sync will be retried)"
+	}
+
+	if len(syncOperationStatus.PhaseMessage) > db.SyncOperationPhaseMessageLength {
+		syncOperationStatus.PhaseMessage = syncOperationStatus.PhaseMessage[:db.SyncOperationPhaseMessageLength]
+	}
+
+	return syncOperationStatus
+}
+
+// isOperationStateTerminated returns true if the operation of an Argo CD Application was terminated before it completed,
+// for example by a user of Argo CD: Argo CD reports an operation that was terminated as having failed.
+func isOperationStateTerminated(operationState appv1.OperationState) bool {
+	return operationState.Phase == synccommon.OperationTerminating ||
+		(operationState.Phase.Completed() && !operationState.Phase.Successful() && strings.HasPrefix(operationState.Message, argoCDOperationTerminatedMessagePrefix))
+}
+
+// convertResourceResultsToJSON converts the results of the resources of an Argo CD sync into the JSON representation that is
+// stored in the SyncOperation row. If there are too many results to fit in the database, the trailing results are omitted.
+func convertResourceResultsToJSON(resourceResults appv1.ResourceResults) (string, error) {
+
+	var results []operation.SyncRunResourceResult
+	for _, resourceResult := range resourceResults {
+		if resourceResult == nil {
+			continue
+		}
+		results = append(results, operation.SyncRunResourceResult{
+			Group:     resourceResult.Group,
+			Version:   resourceResult.Version,
+			Kind:      resourceResult.Kind,
+			Namespace: resourceResult.Namespace,
+			Name:      resourceResult.Name,
+			Status:    string(resourceResult.Status),
+			Message:   resourceResult.Message,
+		})
+	}
+
+	for len(results) > 0 {
+		resultsJSON, err := json.Marshal(results)
+		if err != nil {
+			return "", err
+		}
+
+		if len(resultsJSON) <= db.SyncOperationResourceResultsLength {
+			return string(resultsJSON), nil
+		}
+
+		results = results[:len(results)-1]
+	}
+
+	return "", nil
+}
+
+// getAppSyncOptionsOfSyncOperation returns the options of the Argo CD sync operation, as requested by the GitOpsDeploymentSyncRun
+// that the SyncOperation was created for.
+func getAppSyncOptionsOfSyncOperation(dbSyncOperation db.SyncOperation) (utils.AppSyncOptions, error) {
+
+	appSyncOptions := utils.AppSyncOptions{
+		DryRun:   dbSyncOperation.DryRun,
+		Prune:    dbSyncOperation.Prune,
+		Force:    dbSyncOperation.Force,
+		Strategy: dbSyncOperation.SyncStrategy,
+	}
+
+	if dbSyncOperation.Resources != "" {
+		// The JSON fields of the resources of a GitOpsDeploymentSyncRun match those of the Argo CD SyncOperationResource
+		if err := json.Unmarshal([]byte(dbSyncOperation.Resources), &appSyncOptions.Resources); err != nil {
+			return utils.AppSyncOptions{}, fmt.Errorf("unable to unmarshal resources of SyncOperation '%s': %v", dbSyncOperation.SyncOperation_id, err)
+		}
+	}
+
+	return appSyncOptions, nil
+}
+
+// processOperation_DiffOperation handles an Operation that targets a DiffOperation: the manifests of the revision requested
+// by the DiffOperation are compared with the live resources of the Argo CD Application, and the result is stored in the
+// DiffOperation row. Nothing is modified on the cluster.
+// Returns true if the task should be retried (eg due to failure), false otherwise.
+func processOperation_DiffOperation(ctx context.Context, dbOperation db.Operation, crOperation operation.Operation,
+	opConfig operationConfig) (bool, error) {
+
+	log := opConfig.log
+	dbQueries := opConfig.dbQueries
+
+	// Sanity checks
+	if dbOperation.Resource_id == "" {
+		return shouldRetryFalse, errors.New("resource id was nil while processing operation: " + crOperation.Name)
+	}
+
+	// 1) Retrieve the DiffOperation DB entry pointed to by the Operation DB entry
+	dbDiffOperation := &db.DiffOperation{
+		DiffOperation_id: dbOperation.Resource_id,
+	}
+
+	log = log.WithValues("diffOperationID", dbDiffOperation.DiffOperation_id)
+
+	if err := dbQueries.GetDiffOperationById(ctx, dbDiffOperation); err != nil {
+
+		if !db.IsResultNotFoundError(err) {
+			// On generic error, we should retry.
+			log.Error(err, "DB error occurred on retrieving DiffOperation")
+			return shouldRetryTrue, err
+		} else {
+			// On db row not found, the GitOpsDeploymentDiff was deleted, so there is nothing left to do.
+			log.V(logutil.LogLevel_Debug).Info("DiffOperation DB entry was no longer available.")
+			return shouldRetryFalse, nil
+		}
+	}
+
+	if dbDiffOperation.Phase == db.DiffOperation_Phase_Succeeded || dbDiffOperation.Phase == db.DiffOperation_Phase_Failed {
+		log.V(logutil.LogLevel_Debug).Info("DiffOperation has already completed.")
+		return shouldRetryFalse, nil
+	}
+
+	// 2) Retrieve the Application DB entry pointed to by the DiffOperation DB entry
+	dbApplication := db.Application{
+		Application_id: dbDiffOperation.Application_id,
+	}
+	if err := dbQueries.GetApplicationById(ctx, &dbApplication); err != nil {
+
+		log := log.WithValues(logutil.Log_ApplicationID, dbApplication.Application_id)
+
+		if db.IsResultNotFoundError(err) {
+			log.Error(err, "Unable to retrieve application ID in DiffOperation table")
+			return shouldRetryFalse, err
+		} else {
+			// On generic error, return true so the operation is retried.
+			log.Error(err, "Error occurred on retrieving application ID in DiffOperation table")
+			return shouldRetryTrue, err
+		}
+	}
+
+	// 3) Report that the diff has started, then compare the revision with the live resources
+	diffOperationStatus := db.DiffOperation{
+		DiffOperation_id: dbDiffOperation.DiffOperation_id,
+		Phase:            db.DiffOperation_Phase_Running,
+		StartedAt:        time.Now(),
+	}
+	updateDiffOperationStatus(ctx, &diffOperationStatus, opConfig)
+
+	diffResult, diffErr := opConfig.syncFuncs.appDiff(ctx, dbApplication.Name, dbDiffOperation.Revision, opConfig.argoCDNamespace,
+		opConfig.credentialService, opConfig.eventClient)
+	if diffErr != nil {
+		log.Error(diffErr, "app diff failed on application '"+dbApplication.Name+"'")
+	}
+
+	// 4) Report the result of the diff: an error is reported in the status of the GitOpsDeploymentDiff, rather than retried.
+	diffOperationStatus = getDiffOperationStatusOfDiffResult(diffOperationStatus, diffResult, diffErr, time.Now(), log)
+	updateDiffOperationStatus(ctx, &diffOperationStatus, opConfig)
+
+	return shouldRetryFalse, nil
+}
+
+func updateDiffOperationStatus(ctx context.Context, diffOperationStatus *db.DiffOperation, opConfig operationConfig) {
+
+	if err := opConfig.dbQueries.UpdateDiffOperationStatus(ctx, diffOperationStatus); err != nil {
+		if db.IsResultNotFoundError(err) {
+			// The DiffOperation was deleted, so there is no longer anywhere to report the status to
+			return
+		}
+		opConfig.log.Error(err, "unable to update the status of the DiffOperation", "diffOperationID", diffOperationStatus.DiffOperation_id)
+	}
+}
+
+// getDiffOperationStatusOfDiffResult returns the status of a completed diff.
+// - diffErr is the error returned by the diff, if any
+func getDiffOperationStatusOfDiffResult(diffOperationStatus db.DiffOperation, diffResult utils.AppDiffResult, diffErr error, now time.Time, log logr.Logger) db.DiffOperation {
+
+	diffOperationStatus.FinishedAt = now
+
+	if diffErr != nil {
+		diffOperationStatus.Phase = db.DiffOperation_Phase_Failed
+		diffOperationStatus.PhaseMessage = diffErr.Error()
+		if len(diffOperationStatus.PhaseMessage) > db.DiffOperationPhaseMessageLength {
+			diffOperationStatus.PhaseMessage = diffOperationStatus.PhaseMessage[:db.DiffOperationPhaseMessageLength]
+		}
+		return diffOperationStatus
+	}
+
+	diffOperationStatus.Phase = db.DiffOperation_Phase_Succeeded
+	diffOperationStatus.PhaseMessage = ""
+	diffOperationStatus.ResolvedRevision = diffResult.Revision
+	diffOperationStatus.Truncated = diffResult.Truncated
+
+	if len(diffOperationStatus.ResolvedRevision) > db.DiffOperationResolvedRevisionLength {
+		diffOperationStatus.ResolvedRevision = diffOperationStatus.ResolvedRevision[:db.DiffOperationResolvedRevisionLength]
+	}
+
+	resourceDiffs, omitted, err := convertResourceDiffsToJSON(diffResult.Resources)
+	if err != nil {
+		log.Error(err, "unable to convert the resource diffs of the DiffOperation to JSON")
+	} else {
+		diffOperationStatus.ResourceDiffs = resourceDiffs
+		diffOperationStatus.Truncated = diffOperationStatus.Truncated || omitted
+	}
+
+	return diffOperationStatus
+}
+
+// convertResourceDiffsToJSON converts the resource diffs into the JSON representation that is stored in the DiffOperation row.
+// If there are too many diffs to fit in the database, the trailing diffs are omitted, and 'omitted' is true.
+func convertResourceDiffsToJSON(resourceDiffs []operation.ResourceDiff) (string, bool, error) {
+
+	omitted := false
+
+	for len(resourceDiffs) > 0 {
+		resourceDiffsJSON, err := json.Marshal(resourceDiffs)
+		if err != nil {
+			return "", false, err
+		}
+
+		if len(resourceDiffsJSON) <= db.DiffOperationResourceDiffsLength {
+			return string(resourceDiffsJSON), omitted, nil
+		}
+
+		resourceDiffs = resourceDiffs[:len(resourceDiffs)-1]
+		omitted = true
+	}
+
+	return "", omitted, nil
+}
+
 // processOperation_ManagedEnvironment handles an Operation that targets an Application.
 // Returns true if the task should be retried (eg due to failure), false otherwise.
 func processOperation_ManagedEnvironment(ctx context.Context, dbOperation db.Operation, crOperation operation.Operation,
 	opConfig operationConfig) (bool, error) {
 
-	// The only operation we currently support for managed environment is deletion (creation is handled by Application operations).
-	// Thus, we expect the ManagedEnvironment database entry here to not be found.
+	// An operation on a managed environment either:
+	// - A) refreshes the Argo CD cluster secret of the managed environment, if the ManagedEnvironment database entry exists
+	//   (for example, because the cluster credentials of the managed environment were replaced, after a service account token rotation)
+	// - B) deletes the Argo CD cluster secret of the managed environment, if the ManagedEnvironment database entry doesn't exist.
+	// (creation is otherwise handled by Application operations)
 
-	// 1) Make sure the managed environment db entry DOESN'T exist (see above)
+	// 1) If the managed environment db entry exists, ensure the Argo CD cluster secret is up-to-date with the database entry
 	{
 		managedEnv := &db.ManagedEnvironment{
 			Managedenvironment_id: dbOperation.Resource_id, // managed env id referencing managed env row
@@ -792,8 +1233,11 @@ func processOperation_ManagedEnvironment(ctx context.Context, dbOperation db.Ope
 				return shouldRetryTrue, fmt.Errorf("an unexpected error occcurred on retrieving managed env: %v", err)
 			}
 		} else {
-			// The database entry still exists, so return an error
-			return shouldRetryFalse, fmt.Errorf("managed environment still exists in the database")
+			// The database entry still exists, so refresh the Argo CD cluster secret
+			if err := ensureManagedEnvironmentExists(ctx, managedEnv.Managedenvironment_id, opConfig, opConfig.log); err != nil {
+				return shouldRetryTrue, fmt.Errorf("unable to refresh Argo CD cluster secret of managed environment: %v", err)
+			}
+			return shouldRetryFalse, nil
 		}
 	}
 
@@ -893,9 +1337,14 @@ func processOperation_Application(ctx context.Context, dbOperation db.Operation,
 			// Add databaseID label
 			app.ObjectMeta.Labels = map[string]string{controllers.ArgoCDApplicationDatabaseIDLabel: dbApplication.Application_id}
 
+			if _, err := controllers.UpdateDeletionPolicyAnnotation(app, *dbApplication); err != nil {
+				log.Error(err, "SEVERE: unable to unmarshal application spec field on creating Application CR.")
+				return shouldRetryFalse, nil
+			}
+
 			// Before we create the application, make sure that the managed environment exists that the application points to
 			if app.Spec.Destination.Name != argosharedutil.ArgoCDDefaultDestinationInCluster {
-				if err := ensureManagedEnvironmentExists(ctx, *dbApplication, opConfig, log); err != nil {
+				if err := ensureManagedEnvironmentExists(ctx, dbApplication.Managed_environment_id, opConfig, log); err != nil {
 					log.Error(err, "unable to ensure that managed environment exists")
 					return shouldRetryTrue, err
 				}
@@ -931,7 +1380,14 @@ func processOperation_Application(ctx context.Context, dbOperation db.Operation,
 		return shouldRetryFalse, err
 	}
 
-	if specDiff != "" {
+	// The deletion policy is not part of the spec, so is compared separately
+	deletionPolicyChanged, err := controllers.UpdateDeletionPolicyAnnotation(app, *dbApplication)
+	if err != nil {
+		log.Error(err, "SEVERE: unable to unmarshal DB application spec field, on updating existing Application CR: "+app.Name)
+		return shouldRetryFalse, nil
+	}
+
+	if specDiff != "" || deletionPolicyChanged {
 		specFieldApp := &appv1.Application{}
 
 		if err := yaml.Unmarshal([]byte(dbApplication.Spec_field), specFieldApp); err != nil {
@@ -943,8 +1399,10 @@ func processOperation_Application(ctx context.Context, dbOperation db.Operation,
 
 		app.Spec.Destination = specFieldApp.Spec.Destination
 		app.Spec.Source = specFieldApp.Spec.Source
+		app.Spec.Sources = specFieldApp.Spec.Sources
 		app.Spec.Project = specFieldApp.Spec.Project
 		app.Spec.SyncPolicy = specFieldApp.Spec.SyncPolicy
+		app.Spec.IgnoreDifferences = specFieldApp.Spec.IgnoreDifferences
 
 		if err := opConfig.eventClient.Update(ctx, app); err != nil {
 			log.Error(err, "unable to update application after difference detected.")
@@ -953,7 +1411,7 @@ func processOperation_Application(ctx context.Context, dbOperation db.Operation,
 		}
 		logutil.LogAPIResourceChangeEvent(app.Namespace, app.Name, app, logutil.ResourceModified, log)
 
-		log.Info("Updated Argo CD Application CR", "specDiff", specDiff)
+		log.Info("Updated Argo CD Application CR", "specDiff", specDiff, "deletionPolicyChanged", deletionPolicyChanged)
 
 	} else {
 		log.Info("No changes detected when comparing Application row with Argo CD Application CR via Operation, so no update needed")
@@ -961,7 +1419,7 @@ func processOperation_Application(ctx context.Context, dbOperation db.Operation,
 
 	// Finally, ensure that the managed-environment secret is still up to date
 	if app.Spec.Destination.Name != argosharedutil.ArgoCDDefaultDestinationInCluster {
-		if err := ensureManagedEnvironmentExists(ctx, *dbApplication, opConfig, log); err != nil {
+		if err := ensureManagedEnvironmentExists(ctx, dbApplication.Managed_environment_id, opConfig, log); err != nil {
 			log.Error(err, "unable to ensure that managed environment exists")
 			return shouldRetryTrue, err
 		}
@@ -1160,16 +1618,16 @@ const (
 	ManagedEnvironmentQueryParameter = "?managedEnvironment="
 )
 
-// ensureManagedEnvironmentExists ensures that the managed environment described by 'application' is defined as an Argo CD
+// ensureManagedEnvironmentExists ensures that the managed environment with the given ID is defined as an Argo CD
 // cluster secret, in the Argo CD namespace.
-func ensureManagedEnvironmentExists(ctx context.Context, application db.Application, opConfig operationConfig, log logr.Logger) error {
+func ensureManagedEnvironmentExists(ctx context.Context, managedEnvironmentID string, opConfig operationConfig, log logr.Logger) error {
 
-	if application.Managed_environment_id == "" {
+	if managedEnvironmentID == "" {
 		// No work to do
 		return nil
 	}
 
-	expectedSecret, shouldDeleteSecret, err := generateExpectedClusterSecret(ctx, application, opConfig)
+	expectedSecret, shouldDeleteSecret, err := generateExpectedClusterSecret(ctx, managedEnvironmentID, opConfig)
 	if err != nil {
 		return fmt.Errorf("unable to generate expected cluster secret: %v", err)
 	}
@@ -1178,7 +1636,7 @@ func ensureManagedEnvironmentExists(ctx context.Context, application db.Applicat
 
 	// If we detected that the managed environment row was deleted, ensure the secret is deleted.
 	if shouldDeleteSecret {
-		secretName := argosharedutil.GenerateArgoCDClusterSecretName(db.ManagedEnvironment{Managedenvironment_id: application.Managed_environment_id})
+		secretName := argosharedutil.GenerateArgoCDClusterSecretName(db.ManagedEnvironment{Managedenvironment_id: managedEnvironmentID})
 		secret := &corev1.Secret{
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      secretName,
@@ -1245,12 +1703,12 @@ func ensureManagedEnvironmentExists(ctx context.Context, application db.Applicat
 
 }
 
-// generateExpectedClusterSecret generates (but does apply) an Argo CD cluster secret for the environment of the application.
+// generateExpectedClusterSecret generates (but does apply) an Argo CD cluster secret for the managed environment with the given ID.
 // returns:
 // - argo cd cluster secret based on managed environment
 // - bool: true if secret should be deleted false otherwise
 // - error
-func generateExpectedClusterSecret(ctx context.Context, application db.Application, opConfig operationConfig) (corev1.Secret, bool, error) {
+func generateExpectedClusterSecret(ctx context.Context, managedEnvironmentID string, opConfig operationConfig) (corev1.Secret, bool, error) {
 
 	const (
 		deleteSecret_true  = true
@@ -1258,12 +1716,12 @@ func generateExpectedClusterSecret(ctx context.Context, application db.Applicati
 	)
 
 	managedEnv := &db.ManagedEnvironment{
-		Managedenvironment_id: application.Managed_environment_id,
+		Managedenvironment_id: managedEnvironmentID,
 	}
 
 	if err := opConfig.dbQueries.GetManagedEnvironmentById(ctx, managedEnv); err != nil {
 		if db.IsResultNotFoundError(err) {
-			// Application refers to a managed environment that doesn't exist: no more work to do.
+			// The managed environment doesn't exist: no more work to do.
 			// Return true to indicate that the managed environment cluster secret should be deleted.
 			return corev1.Secret{}, deleteSecret_true, nil
 		} else {
@@ -1304,6 +1762,20 @@ func generateExpectedClusterSecret(ctx context.Context, application db.Applicati
 		},
 	}
 
+	// The CA bundle is only needed if the TLS certificate of the cluster is verified
+	if !insecureVerifyTLS && clusterCredentials.Ca_bundle != "" {
+		clusterSecretConfigJSON.TLSClientConfig.CAData = []byte(clusterCredentials.Ca_bundle)
+	}
+
+	// Client certificate authentication is used if the cluster credentials contain a client certificate/key, rather than a token
+	if clusterCredentials.Client_certificate_data != "" && clusterCredentials.Client_key_data != "" {
+		clusterSecretConfigJSON.TLSClientConfig.CertData = []byte(clusterCredentials.Client_certificate_data)
+		clusterSecretConfigJSON.TLSClientConfig.KeyData = []byte(clusterCredentials.Client_key_data)
+	}
+
+	// Argo CD connects to the cluster via a proxy, if the cluster credentials specify one
+	clusterSecretConfigJSON.ProxyUrl = clusterCredentials.Proxy_url
+
 	jsonString, err := json.Marshal(clusterSecretConfigJSON)
 	if err != nil {
 		return corev1.Secret{}, deleteSecret_false, fmt.Errorf("SEVERE: unable to marshal JSON")
@@ -95,10 +95,12 @@ var _ = Describe("Managed Environment Operation Tests", func() {
 
 		})
 
-		It("reconciles an operation that points to a managed environment that still exists, to ensure no action is taken in this case", func() {
+		It("reconciles an operation that points to a managed environment that still exists, to ensure the Argo CD cluster secret is refreshed", func() {
 
 			clusterCredentials := db.ClusterCredentials{
-				Clustercredentials_cred_id: string(uuid.NewUUID()),
+				Clustercredentials_cred_id:  string(uuid.NewUUID()),
+				Host:                        "https://api.my-cluster.com:6443",
+				Serviceaccount_bearer_token: "rotated-token",
 			}
 
 			err = dbQueries.CreateClusterCredentials(ctx, &clusterCredentials)
@@ -139,7 +141,7 @@ var _ = Describe("Managed Environment Operation Tests", func() {
 			err = task.event.client.Create(ctx, operationCR)
 			Expect(err).ToNot(HaveOccurred())
 
-			By("creating an Argo CD Cluster secret, which we will will test to make sure it has not been deleted.")
+			By("creating an Argo CD Cluster secret, with out of date contents")
 			clusterSecretName := argosharedutil.GenerateArgoCDClusterSecretName(db.ManagedEnvironment{Managedenvironment_id: managedEnvRow.Managedenvironment_id})
 			secret := &corev1.Secret{
 				ObjectMeta: metav1.ObjectMeta{
@@ -150,19 +152,28 @@ var _ = Describe("Managed Environment Operation Tests", func() {
 						controllers.ArgoCDClusterSecretDatabaseIDLabel: managedEnvRow.Managedenvironment_id,
 					},
 				},
-				Data: map[string][]byte{},
+				Data: map[string][]byte{
+					"config": ([]byte)(`{"bearerToken":"old-token"}`),
+				},
 			}
 
 			err = task.event.client.Create(ctx, secret)
 			Expect(err).ToNot(HaveOccurred())
 
 			retry, err := task.PerformTask(ctx)
-			Expect(err).To(HaveOccurred(), "an error is expected here, because an operation on a managedenvironment is only supported if the managed environment doesn't exist")
+			Expect(err).ToNot(HaveOccurred())
 			Expect(retry).To(BeFalse())
 
+			err = expectOperationIsComplete(ctx, operationDB.Operation_id, dbQueries)
+			Expect(err).ToNot(HaveOccurred())
+
 			err = task.event.client.Get(ctx, client.ObjectKeyFromObject(secret), secret)
 			Expect(err).ToNot(HaveOccurred(), "the Argo CD cluster secret should not have been deleted.")
 
+			By("verifying the Argo CD cluster secret contains the token of the current cluster credentials")
+			Expect(string(secret.Data["config"])).To(ContainSubstring(clusterCredentials.Serviceaccount_bearer_token))
+			Expect(string(secret.Data["config"])).ToNot(ContainSubstring("old-token"))
+
 		})
 
 		It("Reconciling a deleted managed environment, to ensure the corresponding Argo CD cluster secret is deleted", func() {
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -134,7 +135,7 @@ var _ = Describe("Operation Controller", func() {
 			err = dbQueries.CreateApplication(ctx, applicationDB)
 			Expect(err).ToNot(HaveOccurred())
 
-			secret, shouldDelete, err := generateExpectedClusterSecret(ctx, *applicationDB, opConfigVal)
+			secret, shouldDelete, err := generateExpectedClusterSecret(ctx, applicationDB.Managed_environment_id, opConfigVal)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(shouldDelete).To(BeFalse())
 
@@ -147,6 +148,126 @@ var _ = Describe("Operation Controller", func() {
 
 		})
 
+		It("generateExpectedClusterSecret should include the CA bundle of the cluster credentials", func() {
+
+			caBundle := "-----BEGIN CERTIFICATE-----\nZmFrZS1jYS1idW5kbGU=\n-----END CERTIFICATE-----\n"
+
+			clusterCredentials := db.ClusterCredentials{
+				Clustercredentials_cred_id:  "test-cluster-creds-test",
+				Host:                        "https://my-cluster-url.com",
+				Kube_config:                 "kube-config",
+				Kube_config_context:         "kube-config-context",
+				Serviceaccount_bearer_token: db.DefaultServiceaccount_bearer_token,
+				Serviceaccount_ns:           "Serviceaccount_ns",
+				Ca_bundle:                   caBundle,
+			}
+			err := dbQueries.CreateClusterCredentials(ctx, &clusterCredentials)
+			Expect(err).ToNot(HaveOccurred())
+
+			managedEnvironment := db.ManagedEnvironment{
+				Managedenvironment_id: "test-managed-env",
+				Clustercredentials_id: clusterCredentials.Clustercredentials_cred_id,
+				Name:                  "my env",
+			}
+			err = dbQueries.CreateManagedEnvironment(ctx, &managedEnvironment)
+			Expect(err).ToNot(HaveOccurred())
+
+			applicationDB := &db.Application{
+				Application_id:          "test-my-application",
+				Name:                    name,
+				Spec_field:              "{}",
+				Engine_instance_inst_id: gitopsEngineInstance.Gitopsengineinstance_id,
+				Managed_environment_id:  managedEnvironment.Managedenvironment_id,
+			}
+			err = dbQueries.CreateApplication(ctx, applicationDB)
+			Expect(err).ToNot(HaveOccurred())
+
+			secret, shouldDelete, err := generateExpectedClusterSecret(ctx, applicationDB.Managed_environment_id, opConfigVal)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(shouldDelete).To(BeFalse())
+
+			secretJSON := argosharedutil.ClusterSecretConfigJSON{}
+			err = json.Unmarshal(secret.Data["config"], &secretJSON)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(secretJSON.TLSClientConfig.CAData)).To(Equal(caBundle))
+		})
+
+		It("generateExpectedClusterSecret should include the client certificate and key of the cluster credentials", func() {
+
+			clusterCredentials := db.ClusterCredentials{
+				Clustercredentials_cred_id: "test-cluster-creds-test",
+				Host:                       "https://my-cluster-url.com",
+				Kube_config:                "kube-config",
+				Kube_config_context:        "kube-config-context",
+				Serviceaccount_ns:          "Serviceaccount_ns",
+				Client_certificate_data:    "client-certificate-data",
+				Client_key_data:            "client-key-data",
+			}
+			err := dbQueries.CreateClusterCredentials(ctx, &clusterCredentials)
+			Expect(err).ToNot(HaveOccurred())
+
+			managedEnvironment := db.ManagedEnvironment{
+				Managedenvironment_id: "test-managed-env",
+				Clustercredentials_id: clusterCredentials.Clustercredentials_cred_id,
+				Name:                  "my env",
+			}
+			err = dbQueries.CreateManagedEnvironment(ctx, &managedEnvironment)
+			Expect(err).ToNot(HaveOccurred())
+
+			applicationDB := &db.Application{
+				Application_id:          "test-my-application",
+				Name:                    name,
+				Spec_field:              "{}",
+				Engine_instance_inst_id: gitopsEngineInstance.Gitopsengineinstance_id,
+				Managed_environment_id:  managedEnvironment.Managedenvironment_id,
+			}
+			err = dbQueries.CreateApplication(ctx, applicationDB)
+			Expect(err).ToNot(HaveOccurred())
+
+			secret, shouldDelete, err := generateExpectedClusterSecret(ctx, applicationDB.Managed_environment_id, opConfigVal)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(shouldDelete).To(BeFalse())
+
+			secretJSON := argosharedutil.ClusterSecretConfigJSON{}
+			err = json.Unmarshal(secret.Data["config"], &secretJSON)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(secretJSON.BearerToken).To(BeEmpty())
+			Expect(string(secretJSON.TLSClientConfig.CertData)).To(Equal(clusterCredentials.Client_certificate_data))
+			Expect(string(secretJSON.TLSClientConfig.KeyData)).To(Equal(clusterCredentials.Client_key_data))
+		})
+
+		It("generateExpectedClusterSecret should include the proxy URL of the cluster credentials", func() {
+
+			clusterCredentials := db.ClusterCredentials{
+				Clustercredentials_cred_id:  "test-cluster-creds-test",
+				Host:                        "https://my-cluster-url.com",
+				Kube_config:                 "kube-config",
+				Kube_config_context:         "kube-config-context",
+				Serviceaccount_bearer_token: db.DefaultServiceaccount_bearer_token,
+				Serviceaccount_ns:           "Serviceaccount_ns",
+				Proxy_url:                   "http://proxy.example.com:3128",
+			}
+			err := dbQueries.CreateClusterCredentials(ctx, &clusterCredentials)
+			Expect(err).ToNot(HaveOccurred())
+
+			managedEnvironment := db.ManagedEnvironment{
+				Managedenvironment_id: "test-managed-env",
+				Clustercredentials_id: clusterCredentials.Clustercredentials_cred_id,
+				Name:                  "my env",
+			}
+			err = dbQueries.CreateManagedEnvironment(ctx, &managedEnvironment)
+			Expect(err).ToNot(HaveOccurred())
+
+			secret, shouldDelete, err := generateExpectedClusterSecret(ctx, managedEnvironment.Managedenvironment_id, opConfigVal)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(shouldDelete).To(BeFalse())
+
+			secretJSON := argosharedutil.ClusterSecretConfigJSON{}
+			err = json.Unmarshal(secret.Data["config"], &secretJSON)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(secretJSON.ProxyUrl).To(Equal(clusterCredentials.Proxy_url))
+		})
+
 		It("generateExpectedClusterSecret should reject an invalid URL containing query parameters", func() {
 
 			clusterCredentials := db.ClusterCredentials{
@@ -181,7 +302,7 @@ var _ = Describe("Operation Controller", func() {
 			Expect(err).ToNot(HaveOccurred())
 
 			By("calling function to test")
-			_, shouldDelete, err := generateExpectedClusterSecret(ctx, *applicationDB, opConfigVal)
+			_, shouldDelete, err := generateExpectedClusterSecret(ctx, applicationDB.Managed_environment_id, opConfigVal)
 			Expect(err).To(HaveOccurred(), "should return an error due to unsupported query parameter characters in the URL")
 			Expect(err.Error()).To(ContainSubstring("the Kubernetes API URL contained unsupported characters"))
 			Expect(shouldDelete).To(BeFalse())
@@ -198,7 +319,7 @@ var _ = Describe("Operation Controller", func() {
 			}
 
 			By("calling function to test")
-			_, shouldDelete, err := generateExpectedClusterSecret(ctx, *applicationDB, opConfigVal)
+			_, shouldDelete, err := generateExpectedClusterSecret(ctx, applicationDB.Managed_environment_id, opConfigVal)
 			Expect(err).ToNot(HaveOccurred())
 			Expect(shouldDelete).To(BeTrue())
 
@@ -236,7 +357,7 @@ var _ = Describe("Operation Controller", func() {
 			Expect(err).ToNot(HaveOccurred())
 
 			By("calling function to test")
-			err = ensureManagedEnvironmentExists(ctx, *applicationDB, opConfigVal, logger)
+			err = ensureManagedEnvironmentExists(ctx, applicationDB.Managed_environment_id, opConfigVal, logger)
 			Expect(err).ToNot(HaveOccurred())
 
 			secretName := argosharedutil.GenerateArgoCDClusterSecretName(db.ManagedEnvironment{Managedenvironment_id: applicationDB.Managed_environment_id})
@@ -307,7 +428,7 @@ var _ = Describe("Operation Controller", func() {
 			Expect(err).ToNot(HaveOccurred())
 
 			By("calling function to test")
-			err = ensureManagedEnvironmentExists(ctx, *applicationDB, opConfigVal, logger)
+			err = ensureManagedEnvironmentExists(ctx, applicationDB.Managed_environment_id, opConfigVal, logger)
 			Expect(err).ToNot(HaveOccurred())
 
 			managedEnvironmentSecret := corev1.Secret{
@@ -354,7 +475,7 @@ var _ = Describe("Operation Controller", func() {
 			err := k8sClient.Create(ctx, &managedEnvironmentSecret)
 			Expect(err).ToNot(HaveOccurred())
 
-			err = ensureManagedEnvironmentExists(ctx, *applicationDB, opConfigVal, logger)
+			err = ensureManagedEnvironmentExists(ctx, applicationDB.Managed_environment_id, opConfigVal, logger)
 			Expect(err).ToNot(HaveOccurred())
 
 			err = k8sClient.Get(ctx, client.ObjectKeyFromObject(&managedEnvironmentSecret), &managedEnvironmentSecret)
@@ -2338,7 +2459,7 @@ var _ = Describe("Operation Controller", func() {
 
 				By("verify there is no retry for a successful sync")
 				task.syncFuncs = &syncFuncs{
-					appSync: func(ctx context.Context, s1, s2, s3 string, c client.Client, cs *utils.CredentialService, b bool) error {
+					appSync: func(ctx context.Context, s1, s2, s3 string, c client.Client, cs *utils.CredentialService, b bool, options utils.AppSyncOptions) error {
 						return nil
 					},
 					refreshApp: refreshApplication,
@@ -2371,7 +2492,7 @@ var _ = Describe("Operation Controller", func() {
 				By("check if the sync failed error is returned with retry")
 				expectedErr := "sync failed due to xyz reason"
 				task.syncFuncs = &syncFuncs{
-					appSync: func(ctx context.Context, s1, s2, s3 string, c client.Client, cs *utils.CredentialService, b bool) error {
+					appSync: func(ctx context.Context, s1, s2, s3 string, c client.Client, cs *utils.CredentialService, b bool, options utils.AppSyncOptions) error {
 						return errors.New(expectedErr)
 					},
 					refreshApp: refreshApplication,
@@ -2385,6 +2506,92 @@ var _ = Describe("Operation Controller", func() {
 				Expect(<-refreshAnnotationFound).To(Equal(struct{}{}))
 			})
 
+			It("should requeue the event without an error, and leave the Operation in progress, while the sync windows don't allow a sync", func() {
+
+				By("create a SyncOperation in the database, with a sync window that only opens on the first minute of the year")
+				syncWindows, err := json.Marshal(managedgitopsv1alpha1.SyncWindows{{
+					Kind:     managedgitopsv1alpha1.SyncWindowKind_Allow,
+					Schedule: "0 0 1 1 *",
+					Duration: "1m",
+				}})
+				Expect(err).ToNot(HaveOccurred())
+
+				syncOperation := db.SyncOperation{
+					SyncOperation_id:    "test-syncoperation",
+					Application_id:      applicationDB.Application_id,
+					DeploymentNameField: "test",
+					Revision:            "main",
+					DesiredState:        db.SyncOperation_DesiredState_Running,
+					SyncWindows:         string(syncWindows),
+				}
+				err = dbQueries.CreateSyncOperation(ctx, &syncOperation)
+				Expect(err).ToNot(HaveOccurred())
+
+				By("create Operation DB row and CR for the SyncOperation")
+				createOperationDBAndCR(syncOperation.SyncOperation_id, gitopsEngineInstanceID)
+
+				task.syncFuncs = &syncFuncs{
+					appSync: func(ctx context.Context, s1, s2, s3 string, c client.Client, cs *utils.CredentialService, b bool, options utils.AppSyncOptions) error {
+						Fail("the Application should not be synced while the sync windows don't allow it")
+						return nil
+					},
+					refreshApp: refreshApplication,
+				}
+
+				var requeueDelay time.Duration
+				task.requeueEvent = func(event operationEventLoopEvent, delay time.Duration) {
+					requeueDelay = delay
+				}
+
+				retry, err := task.PerformTask(ctx)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(retry).To(BeFalse())
+
+				By("verify that the event is requeued for when the sync window next opens")
+				nextYear := time.Date(time.Now().Year()+1, time.January, 1, 0, 0, 0, 0, time.UTC)
+				Expect(requeueDelay).To(BeNumerically("~", time.Until(nextYear), time.Minute))
+
+				By("verify that the Operation is still in progress, and the SyncOperation is pending")
+				operationDB := &db.Operation{Operation_id: "test-operation"}
+				Expect(dbQueries.GetOperationById(ctx, operationDB)).To(Succeed())
+				Expect(operationDB.State).To(Equal(db.OperationState_In_Progress))
+
+				Expect(dbQueries.GetSyncOperationById(ctx, &syncOperation)).To(Succeed())
+				Expect(syncOperation.Phase).To(Equal(db.SyncOperation_Phase_Pending))
+				Expect(syncOperation.PhaseMessage).To(ContainSubstring(nextYear.Format(time.RFC3339)))
+			})
+
+			It("should fail without retrying if the sync windows of the SyncOperation are not valid", func() {
+
+				By("create a SyncOperation in the database, with sync windows that are not valid JSON")
+				syncOperation := db.SyncOperation{
+					SyncOperation_id:    "test-syncoperation",
+					Application_id:      applicationDB.Application_id,
+					DeploymentNameField: "test",
+					Revision:            "main",
+					DesiredState:        db.SyncOperation_DesiredState_Running,
+					SyncWindows:         "{",
+				}
+				err = dbQueries.CreateSyncOperation(ctx, &syncOperation)
+				Expect(err).ToNot(HaveOccurred())
+
+				By("create Operation DB row and CR for the SyncOperation")
+				createOperationDBAndCR(syncOperation.SyncOperation_id, gitopsEngineInstanceID)
+
+				retry, err := task.PerformTask(ctx)
+				Expect(err).To(HaveOccurred())
+				Expect(retry).To(BeFalse())
+
+				By("verify that the Operation and the SyncOperation have failed")
+				operationDB := &db.Operation{Operation_id: "test-operation"}
+				Expect(dbQueries.GetOperationById(ctx, operationDB)).To(Succeed())
+				Expect(operationDB.State).To(Equal(db.OperationState_Failed))
+
+				Expect(dbQueries.GetSyncOperationById(ctx, &syncOperation)).To(Succeed())
+				Expect(syncOperation.Phase).To(Equal(db.SyncOperation_Phase_Failed))
+				Expect(syncOperation.PhaseMessage).To(ContainSubstring("unable to unmarshal sync windows"))
+			})
+
 			It("should return an error and retry if the refresh fails", func() {
 				By("create a SyncOperation in the database")
 				syncOperation := db.SyncOperation{
@@ -2445,7 +2652,7 @@ var _ = Describe("Operation Controller", func() {
 				Expect(apierr.IsConflict(err)).To(BeTrue())
 
 				task.syncFuncs = &syncFuncs{
-					appSync: func(ctx context.Context, s1, s2, s3 string, c client.Client, cs *utils.CredentialService, b bool) error {
+					appSync: func(ctx context.Context, s1, s2, s3 string, c client.Client, cs *utils.CredentialService, b bool, options utils.AppSyncOptions) error {
 						return nil
 					},
 					refreshApp: refreshApplication,
@@ -2467,7 +2674,7 @@ var _ = Describe("Operation Controller", func() {
 
 				By("check if SyncOperation not found error is handled")
 				task.syncFuncs = &syncFuncs{
-					appSync: func(ctx context.Context, s1, s2, s3 string, c client.Client, cs *utils.CredentialService, b bool) error {
+					appSync: func(ctx context.Context, s1, s2, s3 string, c client.Client, cs *utils.CredentialService, b bool, options utils.AppSyncOptions) error {
 						return nil
 					},
 				}
@@ -2495,7 +2702,7 @@ var _ = Describe("Operation Controller", func() {
 				createOperationDBAndCR(syncOperation.SyncOperation_id, gitopsEngineInstanceID)
 
 				task.syncFuncs = &syncFuncs{
-					appSync: func(ctx context.Context, s1, s2, s3 string, c client.Client, cs *utils.CredentialService, b bool) error {
+					appSync: func(ctx context.Context, s1, s2, s3 string, c client.Client, cs *utils.CredentialService, b bool, options utils.AppSyncOptions) error {
 						return nil
 					},
 				}
@@ -3429,3 +3636,246 @@ func createApplicationWithSyncOption(syncOptionParam string) (appv1.Application,
 
 	return dummyApplicationSpec, string(dummyApplicationSpecBytes), nil
 }
+
+var _ = Describe("Sync window tests for SyncOperations", func() {
+
+	// Monday, 9:30 AM UTC
+	monday0930 := time.Date(2024, time.January, 1, 9, 30, 0, 0, time.UTC)
+
+	syncWindowsJSON := func(windows managedgitopsv1alpha1.SyncWindows) string {
+		bytes, err := json.Marshal(windows)
+		Expect(err).ToNot(HaveOccurred())
+		return string(bytes)
+	}
+
+	businessHours := managedgitopsv1alpha1.SyncWindow{
+		Kind:     managedgitopsv1alpha1.SyncWindowKind_Allow,
+		Schedule: "0 9 * * 1-5",
+		Duration: "8h",
+	}
+
+	It("should allow a sync if the SyncOperation has no sync windows", func() {
+		allowed, _, err := checkSyncWindowsOfSyncOperation(db.SyncOperation{}, monday0930)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allowed).To(BeTrue())
+	})
+
+	It("should allow a sync while a sync window is open", func() {
+		syncOperation := db.SyncOperation{SyncWindows: syncWindowsJSON(managedgitopsv1alpha1.SyncWindows{businessHours})}
+
+		allowed, _, err := checkSyncWindowsOfSyncOperation(syncOperation, monday0930)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allowed).To(BeTrue())
+	})
+
+	It("should block a sync without an error while no sync window is open, and report when the next window opens", func() {
+		syncOperation := db.SyncOperation{SyncWindows: syncWindowsJSON(managedgitopsv1alpha1.SyncWindows{businessHours})}
+
+		allowed, nextSyncWindow, err := checkSyncWindowsOfSyncOperation(syncOperation, monday0930.Add(8*time.Hour))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(allowed).To(BeFalse())
+		Expect(nextSyncWindow).To(Equal(time.Date(2024, time.January, 2, 9, 0, 0, 0, time.UTC)))
+	})
+
+	It("should return an error if the sync windows of the SyncOperation are not valid JSON", func() {
+		_, _, err := checkSyncWindowsOfSyncOperation(db.SyncOperation{SyncWindows: "{"}, monday0930)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should return an error if a sync window of the SyncOperation is not valid", func() {
+		syncOperation := db.SyncOperation{SyncWindows: syncWindowsJSON(managedgitopsv1alpha1.SyncWindows{{
+			Kind:     managedgitopsv1alpha1.SyncWindowKind_Allow,
+			Schedule: "not a cron schedule",
+			Duration: "1h",
+		}})}
+
+		_, _, err := checkSyncWindowsOfSyncOperation(syncOperation, monday0930)
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Sync options tests for SyncOperations", func() {
+
+	It("should use the default sync options if the SyncOperation specifies none", func() {
+		appSyncOptions, err := getAppSyncOptionsOfSyncOperation(db.SyncOperation{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(appSyncOptions).To(Equal(utils.AppSyncOptions{}))
+	})
+
+	It("should convert the sync options and resources of the GitOpsDeploymentSyncRun into Argo CD sync options", func() {
+		resources, err := json.Marshal([]managedgitopsv1alpha1.SyncRunResource{
+			{Kind: "ConfigMap", Namespace: "my-namespace", Name: "my-config"},
+			{Group: "apps", Kind: "Deployment", Namespace: "my-namespace", Name: "my-deployment"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		syncOperation := db.SyncOperation{
+			Prune:        true,
+			DryRun:       true,
+			Force:        true,
+			SyncStrategy: db.SyncOperation_SyncStrategy_Apply,
+			Resources:    string(resources),
+		}
+
+		appSyncOptions, err := getAppSyncOptionsOfSyncOperation(syncOperation)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(appSyncOptions).To(Equal(utils.AppSyncOptions{
+			DryRun:   true,
+			Prune:    true,
+			Force:    true,
+			Strategy: "apply",
+			Resources: []appv1.SyncOperationResource{
+				{Kind: "ConfigMap", Namespace: "my-namespace", Name: "my-config"},
+				{Group: "apps", Kind: "Deployment", Namespace: "my-namespace", Name: "my-deployment"},
+			},
+		}))
+	})
+
+	It("should return an error if the resources of the SyncOperation are not valid JSON", func() {
+		_, err := getAppSyncOptionsOfSyncOperation(db.SyncOperation{Resources: "["})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("Sync status tests for SyncOperations", func() {
+
+	now := time.Date(2024, time.January, 1, 9, 30, 0, 0, time.UTC)
+
+	syncOperationStatus := db.SyncOperation{
+		SyncOperation_id: "test-syncoperation",
+		Phase:            db.SyncOperation_Phase_Running,
+		StartedAt:        now.Add(-time.Minute),
+	}
+
+	applicationWithOperationState := func(syncOperationID string) *appv1.Application {
+		return &appv1.Application{
+			Status: appv1.ApplicationStatus{
+				OperationState: &appv1.OperationState{
+					Operation: appv1.Operation{
+						Info: []*appv1.Info{{Name: utils.SyncOperationIDInfoName, Value: syncOperationID}},
+					},
+					Phase:      "Succeeded",
+					Message:    "successfully synced (all tasks run)",
+					StartedAt:  metav1.NewTime(now.Add(-30 * time.Second)),
+					FinishedAt: &metav1.Time{Time: now.Add(-10 * time.Second)},
+					SyncResult: &appv1.SyncOperationResult{
+						Revision: "0a1b2c3d",
+						Resources: appv1.ResourceResults{
+							{Kind: "ConfigMap", Version: "v1", Namespace: "my-namespace", Name: "my-config", Status: "Synced", Message: "configmap/my-config configured"},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	It("should report the result of the sync from the operation state of the Argo CD Application", func() {
+		res := getSyncOperationStatusOfApplication(syncOperationStatus, applicationWithOperationState("test-syncoperation"), nil, now, logr.Discard())
+
+		Expect(res.Phase).To(Equal(db.SyncOperation_Phase_Succeeded))
+		Expect(res.PhaseMessage).To(Equal("successfully synced (all tasks run)"))
+		Expect(res.StartedAt).To(Equal(now.Add(-30 * time.Second)))
+		Expect(res.FinishedAt).To(Equal(now.Add(-10 * time.Second)))
+		Expect(res.SyncedRevision).To(Equal("0a1b2c3d"))
+		Expect(res.ResourceResults).To(Equal(`[{"version":"v1","kind":"ConfigMap","namespace":"my-namespace","name":"my-config","status":"Synced","message":"configmap/my-config configured"}]`))
+	})
+
+	It("should not report the result of an operation that was not started by the SyncOperation", func() {
+		res := getSyncOperationStatusOfApplication(syncOperationStatus, applicationWithOperationState("another-syncoperation"), nil, now, logr.Discard())
+
+		Expect(res.Phase).To(Equal(db.SyncOperation_Phase_Succeeded))
+		Expect(res.StartedAt).To(Equal(syncOperationStatus.StartedAt))
+		Expect(res.FinishedAt).To(Equal(now))
+		Expect(res.SyncedRevision).To(BeEmpty())
+		Expect(res.ResourceResults).To(BeEmpty())
+	})
+
+	It("should report a failed sync, which will be retried, as not yet finished", func() {
+		res := getSyncOperationStatusOfApplication(syncOperationStatus, nil, errors.New("operation has completed with phase: Failed"), now, logr.Discard())
+
+		Expect(res.Phase).To(Equal(db.SyncOperation_Phase_Failed))
+		Expect(res.PhaseMessage).To(Equal("operation has completed with phase: Failed (the sync will be retried)"))
+		Expect(res.FinishedAt).To(BeZero())
+	})
+
+	It("should report a sync that was terminated in Argo CD as Terminated, and as finished", func() {
+		app := applicationWithOperationState("test-syncoperation")
+		app.Status.OperationState.Phase = "Failed"
+		app.Status.OperationState.Message = "Operation terminated"
+
+		res := getSyncOperationStatusOfApplication(syncOperationStatus, app, errors.New("operation has completed with phase: Failed"), now, logr.Discard())
+
+		Expect(res.Phase).To(Equal(db.SyncOperation_Phase_Terminated))
+		Expect(res.PhaseMessage).To(Equal("Operation terminated"))
+		Expect(res.FinishedAt).To(Equal(now.Add(-10 * time.Second)))
+	})
+
+	It("should omit trailing resource results that do not fit in the database", func() {
+		var resourceResults appv1.ResourceResults
+		for i := 0; i < 1000; i++ {
+			resourceResults = append(resourceResults, &appv1.ResourceResult{Kind: "ConfigMap", Namespace: "my-namespace",
+				Name: fmt.Sprintf("my-config-%d", i), Status: "Synced"})
+		}
+
+		resultsJSON, err := convertResourceResultsToJSON(resourceResults)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(len(resultsJSON)).To(BeNumerically("<=", db.SyncOperationResourceResultsLength))
+		Expect(resultsJSON).To(ContainSubstring(`"name":"my-config-0"`))
+		Expect(resultsJSON).ToNot(ContainSubstring(`"name":"my-config-999"`))
+	})
+})
+
+var _ = Describe("Diff status tests for DiffOperations", func() {
+
+	now := time.Date(2024, time.January, 1, 9, 30, 0, 0, time.UTC)
+
+	diffOperationStatus := db.DiffOperation{
+		DiffOperation_id: "test-diffoperation",
+		Phase:            db.DiffOperation_Phase_Running,
+		StartedAt:        now.Add(-time.Minute),
+	}
+
+	It("should report the result of a successful diff", func() {
+		diffResult := utils.AppDiffResult{
+			Revision: "0a1b2c3d",
+			Resources: []managedgitopsv1alpha1.ResourceDiff{
+				{Kind: "ConfigMap", Namespace: "my-namespace", Name: "my-config", Status: managedgitopsv1alpha1.ResourceDiffStatus_Modified, Diff: "-a\n+b\n"},
+			},
+		}
+
+		res := getDiffOperationStatusOfDiffResult(diffOperationStatus, diffResult, nil, now, logr.Discard())
+
+		Expect(res.Phase).To(Equal(db.DiffOperation_Phase_Succeeded))
+		Expect(res.PhaseMessage).To(BeEmpty())
+		Expect(res.StartedAt).To(Equal(diffOperationStatus.StartedAt))
+		Expect(res.FinishedAt).To(Equal(now))
+		Expect(res.ResolvedRevision).To(Equal("0a1b2c3d"))
+		Expect(res.Truncated).To(BeFalse())
+		Expect(res.ResourceDiffs).To(Equal(`[{"kind":"ConfigMap","namespace":"my-namespace","name":"my-config","status":"Modified","diff":"-a\n+b\n"}]`))
+	})
+
+	It("should report a failed diff", func() {
+		res := getDiffOperationStatusOfDiffResult(diffOperationStatus, utils.AppDiffResult{}, errors.New("unable to generate the manifests of revision"), now, logr.Discard())
+
+		Expect(res.Phase).To(Equal(db.DiffOperation_Phase_Failed))
+		Expect(res.PhaseMessage).To(Equal("unable to generate the manifests of revision"))
+		Expect(res.FinishedAt).To(Equal(now))
+		Expect(res.ResourceDiffs).To(BeEmpty())
+	})
+
+	It("should omit trailing resource diffs that do not fit in the database, and report the diff as truncated", func() {
+		var resourceDiffs []managedgitopsv1alpha1.ResourceDiff
+		for i := 0; i < managedgitopsv1alpha1.MaxDiffResources*2; i++ {
+			resourceDiffs = append(resourceDiffs, managedgitopsv1alpha1.ResourceDiff{Kind: "ConfigMap", Namespace: "my-namespace",
+				Name: fmt.Sprintf("my-config-%d", i), Status: managedgitopsv1alpha1.ResourceDiffStatus_Added,
+				Diff: strings.Repeat("+", managedgitopsv1alpha1.MaxResourceDiffLength)})
+		}
+
+		res := getDiffOperationStatusOfDiffResult(diffOperationStatus, utils.AppDiffResult{Resources: resourceDiffs}, nil, now, logr.Discard())
+
+		Expect(res.Truncated).To(BeTrue())
+		Expect(len(res.ResourceDiffs)).To(BeNumerically("<=", db.DiffOperationResourceDiffsLength))
+		Expect(res.ResourceDiffs).To(ContainSubstring(`"name":"my-config-0"`))
+		Expect(res.ResourceDiffs).ToNot(ContainSubstring(fmt.Sprintf(`"name":"my-config-%d"`, managedgitopsv1alpha1.MaxDiffResources*2-1)))
+	})
+})
@@ -7,8 +7,10 @@ import (
 
 	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
 	"github.com/go-logr/logr"
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/db"
 	sharedutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util"
+	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/fauxargocd"
 	logutil "github.com/redhat-appstudio/managed-gitops/backend-shared/util/log"
 	apierr "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -22,7 +24,12 @@ const (
 )
 
 const (
+	// argoCDResourcesFinalizer causes Argo CD to delete the resources of an Application in the background (the default)
 	argoCDResourcesFinalizer = "resources-finalizer.argocd.argoproj.io/background"
+
+	// argoCDForegroundResourcesFinalizer causes Argo CD to delete the resources of an Application in the foreground: the
+	// Application is not deleted until all of its resources have been deleted.
+	argoCDForegroundResourcesFinalizer = "resources-finalizer.argocd.argoproj.io"
 )
 
 const (
@@ -33,6 +40,7 @@ const (
 )
 
 // DeleteArgoCDApplication attempts to gracefully delete an Argo CD application:
+// - Set the Argo CD resources finalizer that matches the deletion policy of the Application (none, for 'Orphan')
 // - Issue a Delete to K8s API
 // - If the Application is not deleted after X minutes, remove the finalizer
 // - If the Application is not deleted after X+2 minutes, return an error
@@ -67,25 +75,14 @@ func DeleteArgoCDApplication(ctx context.Context, appFromList appv1.Application,
 
 	if app.DeletionTimestamp == nil {
 
-		// Ensure finalizer is set
-		{
-			containsFinalizer := false
-			for _, finalizer := range app.Finalizers {
-				if finalizer == argoCDResourcesFinalizer {
-					containsFinalizer = true
-					break
-				}
-			}
-
-			if !containsFinalizer {
-				app.Finalizers = append(app.Finalizers, argoCDResourcesFinalizer)
-				if err := eventClient.Update(ctx, app); err != nil {
-					log.Error(err, "unable to update application with finalizer")
-					return err
-				}
-				logutil.LogAPIResourceChangeEvent(app.Namespace, app.Name, app, logutil.ResourceModified, log)
-
+		// Ensure the finalizer of the deletion policy is set
+		if finalizers := getFinalizersOfDeletionPolicy(*app); !reflect.DeepEqual(finalizers, app.Finalizers) {
+			app.Finalizers = finalizers
+			if err := eventClient.Update(ctx, app); err != nil {
+				log.Error(err, "unable to update application with finalizer")
+				return err
 			}
+			logutil.LogAPIResourceChangeEvent(app.Namespace, app.Name, app, logutil.ResourceModified, log)
 		}
 
 		// Tell K8s to start deleting the Application, which triggers Argo CD to delete children
@@ -196,10 +193,103 @@ func DeleteArgoCDApplication(ctx context.Context, appFromList appv1.Application,
 	return nil
 }
 
+// getFinalizersOfDeletionPolicy returns the finalizers of the Application, with the Argo CD resources finalizer replaced
+// by the one that matches the deletion policy annotation of the Application:
+// - Cascade (or no annotation): the resources are deleted in the background.
+// - Foreground: the resources are deleted in the foreground.
+// - Orphan: no resources finalizer, so the resources are not deleted by Argo CD.
+func getFinalizersOfDeletionPolicy(app appv1.Application) []string {
+
+	var res []string
+	for _, finalizer := range app.Finalizers {
+		if finalizer != argoCDResourcesFinalizer && finalizer != argoCDForegroundResourcesFinalizer {
+			res = append(res, finalizer)
+		}
+	}
+
+	switch managedgitopsv1alpha1.DeletionPolicy(app.Annotations[fauxargocd.DeletionPolicyAnnotation]) {
+	case managedgitopsv1alpha1.DeletionPolicy_Orphan:
+		// No resources finalizer
+	case managedgitopsv1alpha1.DeletionPolicy_Foreground:
+		res = append(res, argoCDForegroundResourcesFinalizer)
+	default:
+		res = append(res, argoCDResourcesFinalizer)
+	}
+
+	return res
+}
+
+// UpdateDeletionPolicyAnnotation copies the deletion policy annotation of the spec field of an Application row to the
+// Argo CD Application, where it is read by DeleteArgoCDApplication. Returns true if the Argo CD Application was modified.
+func UpdateDeletionPolicyAnnotation(app *appv1.Application, dbApplication db.Application) (bool, error) {
+
+	specFieldApp := fauxargocd.FauxApplication{}
+	if err := yaml.Unmarshal([]byte(dbApplication.Spec_field), &specFieldApp); err != nil {
+		return false, err
+	}
+
+	deletionPolicy := specFieldApp.Annotations[fauxargocd.DeletionPolicyAnnotation]
+
+	if app.Annotations[fauxargocd.DeletionPolicyAnnotation] == deletionPolicy {
+		return false, nil
+	}
+
+	if deletionPolicy == "" {
+		delete(app.Annotations, fauxargocd.DeletionPolicyAnnotation)
+	} else {
+		if app.Annotations == nil {
+			app.Annotations = map[string]string{}
+		}
+		app.Annotations[fauxargocd.DeletionPolicyAnnotation] = deletionPolicy
+	}
+
+	return true, nil
+}
+
+// getSyncPolicyAutomated returns the automated sync policy of the Application, or nil if automated sync is not enabled.
+func getSyncPolicyAutomated(app appv1.Application) *appv1.SyncPolicyAutomated {
+	if app.Spec.SyncPolicy == nil {
+		return nil
+	}
+	return app.Spec.SyncPolicy.Automated
+}
+
 // CompareApplication compares an Argo CD Application and the spec field of a DB Application row, returning "" if the same,
 // otherwise returning the specific difference.
 func CompareApplication(argoCDApp appv1.Application, dbApplication db.Application, log logr.Logger) (string, error) {
 
+	// sanitizeSource normalizes the empty slices/maps of a source to nil, for the same reason as sanitizeApp, below.
+	sanitizeSource := func(source *appv1.ApplicationSource) {
+
+		// Empty Helm value file and parameter lists are normalized to nil
+		if source.Helm != nil {
+
+			if len(source.Helm.ValueFiles) == 0 {
+				source.Helm.ValueFiles = nil
+			}
+
+			if len(source.Helm.Parameters) == 0 {
+				source.Helm.Parameters = nil
+			}
+		}
+
+		// ... as are empty Kustomize image lists and label/annotation maps
+		if source.Kustomize != nil {
+
+			if len(source.Kustomize.Images) == 0 {
+				source.Kustomize.Images = nil
+			}
+
+			if len(source.Kustomize.CommonLabels) == 0 {
+				source.Kustomize.CommonLabels = nil
+			}
+
+			if len(source.Kustomize.CommonAnnotations) == 0 {
+				source.Kustomize.CommonAnnotations = nil
+			}
+		}
+	}
+
 	// reflect.DeepEqual will treat empty slices differently depending on how they are defined, so we ensure that
 	// in every case, an empty slice is defined as a appv1.SyncOptions{}
 	sanitizeApp := func(input appv1.Application) appv1.Application {
@@ -209,6 +299,31 @@ func CompareApplication(argoCDApp appv1.Application, dbApplication db.Applicatio
 				input.Spec.SyncPolicy.SyncOptions = appv1.SyncOptions{}
 			}
 		}
+
+		if input.Spec.Source != nil {
+			sanitizeSource(input.Spec.Source)
+		}
+
+		if len(input.Spec.Sources) == 0 {
+			input.Spec.Sources = nil
+		}
+		for idx := range input.Spec.Sources {
+			sanitizeSource(&input.Spec.Sources[idx])
+		}
+
+		if len(input.Spec.IgnoreDifferences) == 0 {
+			input.Spec.IgnoreDifferences = nil
+		}
+		for idx := range input.Spec.IgnoreDifferences {
+			ignoreDifference := &input.Spec.IgnoreDifferences[idx]
+			if len(ignoreDifference.JSONPointers) == 0 {
+				ignoreDifference.JSONPointers = nil
+			}
+			if len(ignoreDifference.JQPathExpressions) == 0 {
+				ignoreDifference.JQPathExpressions = nil
+			}
+		}
+
 		return input
 	}
 	argoCDApp = sanitizeApp(*argoCDApp.DeepCopy())
@@ -227,10 +342,17 @@ func CompareApplication(argoCDApp appv1.Application, dbApplication db.Applicatio
 	var specDiff string
 	if !reflect.DeepEqual(specFieldAppFromDB.Spec.Source, argoCDApp.Spec.Source) {
 		specDiff = "spec.source fields differ"
+	} else if !reflect.DeepEqual(specFieldAppFromDB.Spec.Sources, argoCDApp.Spec.Sources) {
+		specDiff = "spec.sources fields differ"
 	} else if !reflect.DeepEqual(specFieldAppFromDB.Spec.Destination, argoCDApp.Spec.Destination) {
 		specDiff = "spec.destination fields differ"
 	} else if specFieldAppFromDB.Spec.Project != argoCDApp.Spec.Project {
 		specDiff = "spec project fields differ"
+	} else if !reflect.DeepEqual(specFieldAppFromDB.Spec.IgnoreDifferences, argoCDApp.Spec.IgnoreDifferences) {
+		specDiff = "spec.ignoreDifferences fields differ"
+	} else if !reflect.DeepEqual(getSyncPolicyAutomated(specFieldAppFromDB), getSyncPolicyAutomated(argoCDApp)) {
+		// The prune/selfHeal/allowEmpty values are user-configurable, so we report them separately from the rest of the sync policy
+		specDiff = "sync policy automated fields differ"
 	} else if !reflect.DeepEqual(specFieldAppFromDB.Spec.SyncPolicy, argoCDApp.Spec.SyncPolicy) {
 		specDiff = "sync policy fields differ"
 	}
@@ -11,6 +11,7 @@ import (
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/db"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/fauxargocd"
 	"github.com/redhat-appstudio/managed-gitops/backend-shared/util/tests"
+	goyaml "gopkg.in/yaml.v2"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -391,6 +392,232 @@ var _ = Describe("Tests for the small number of utility functions in cluster-age
 			applicationFromArgoCD.Spec.SyncPolicy.Automated.AllowEmpty = applicationFromDB.Spec.SyncPolicy.Automated.AllowEmpty
 		})
 
+		It("Should honor the automated sync policy prune/selfHeal/allowEmpty fields when comparing applications.", func() {
+
+			applicationFromDB, _, applicationFromArgoCD, err := createDummyApplicationData()
+			Expect(err).ToNot(HaveOccurred())
+
+			// All automated fields are disabled in the DB, which Argo CD stores as an empty 'automated' field
+			applicationFromDB.Spec.SyncPolicy.Automated = &fauxargocd.SyncPolicyAutomated{}
+			applicationFromArgoCD.Spec.SyncPolicy.Automated = &appv1.SyncPolicyAutomated{}
+
+			yamlData, err := goyaml.Marshal(applicationFromDB)
+			Expect(err).ToNot(HaveOccurred())
+			dbApp := db.Application{Spec_field: string(yamlData)}
+
+			var ctx context.Context
+			log := log.FromContext(ctx)
+
+			result, err := CompareApplication(applicationFromArgoCD, dbApp, log)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(BeEmpty())
+
+			By("verifying that self-heal being re-enabled on the Argo CD Application is detected")
+			applicationFromArgoCD.Spec.SyncPolicy.Automated.SelfHeal = true
+			result, err = CompareApplication(applicationFromArgoCD, dbApp, log)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal("sync policy automated fields differ"))
+			applicationFromArgoCD.Spec.SyncPolicy.Automated.SelfHeal = false
+
+			By("verifying that automated sync being removed from the Argo CD Application is detected")
+			applicationFromArgoCD.Spec.SyncPolicy.Automated = nil
+			result, err = CompareApplication(applicationFromArgoCD, dbApp, log)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal("sync policy automated fields differ"))
+		})
+
+		It("Should compare applications which are sourced from a Helm chart.", func() {
+
+			applicationFromDB, _, applicationFromArgoCD, err := createDummyApplicationData()
+			Expect(err).ToNot(HaveOccurred())
+
+			applicationFromDB.Spec.Source.Path = ""
+			applicationFromDB.Spec.Source.Chart = "my-chart"
+			applicationFromDB.Spec.Source.Helm = &fauxargocd.ApplicationSourceHelm{
+				ReleaseName: "my-release",
+				Values:      "replicaCount: 2\n",
+				ValueFiles:  []string{"values-dev.yaml"},
+				Parameters: []fauxargocd.HelmParameter{
+					{Name: "image.tag", Value: "v1"},
+				},
+			}
+
+			applicationFromArgoCD.Spec.Source.Path = ""
+			applicationFromArgoCD.Spec.Source.Chart = "my-chart"
+			applicationFromArgoCD.Spec.Source.Helm = &appv1.ApplicationSourceHelm{
+				ReleaseName: "my-release",
+				Values:      "replicaCount: 2\n",
+				ValueFiles:  []string{"values-dev.yaml"},
+				Parameters: []appv1.HelmParameter{
+					{Name: "image.tag", Value: "v1"},
+				},
+			}
+
+			yamlData, err := yaml.Marshal(applicationFromDB)
+			Expect(err).ToNot(HaveOccurred())
+			dbApp := db.Application{Spec_field: string(yamlData)}
+
+			var ctx context.Context
+			log := log.FromContext(ctx)
+
+			result, err := CompareApplication(applicationFromArgoCD, dbApp, log)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(BeEmpty())
+
+			By("verifying that a change to a Helm parameter is detected")
+			applicationFromArgoCD.Spec.Source.Helm.Parameters[0].Value = "v2"
+			result, err = CompareApplication(applicationFromArgoCD, dbApp, log)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).ToNot(BeEmpty())
+			applicationFromArgoCD.Spec.Source.Helm.Parameters[0].Value = "v1"
+
+			By("verifying that a change to the chart is detected")
+			applicationFromArgoCD.Spec.Source.Chart = "other-chart"
+			result, err = CompareApplication(applicationFromArgoCD, dbApp, log)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).ToNot(BeEmpty())
+			applicationFromArgoCD.Spec.Source.Chart = "my-chart"
+
+			By("verifying that empty and nil Helm lists are treated as equal")
+			applicationFromDB.Spec.Source.Helm.ValueFiles = nil
+			applicationFromDB.Spec.Source.Helm.Parameters = nil
+			yamlData, err = yaml.Marshal(applicationFromDB)
+			Expect(err).ToNot(HaveOccurred())
+			dbApp = db.Application{Spec_field: string(yamlData)}
+
+			applicationFromArgoCD.Spec.Source.Helm.ValueFiles = []string{}
+			applicationFromArgoCD.Spec.Source.Helm.Parameters = []appv1.HelmParameter{}
+			result, err = CompareApplication(applicationFromArgoCD, dbApp, log)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(BeEmpty())
+		})
+
+		It("Should compare applications which have Kustomize overrides.", func() {
+
+			applicationFromDB, _, applicationFromArgoCD, err := createDummyApplicationData()
+			Expect(err).ToNot(HaveOccurred())
+
+			applicationFromDB.Spec.Source.Kustomize = &fauxargocd.ApplicationSourceKustomize{
+				NamePrefix:   "prod-",
+				Images:       []string{"quay.io/org/image:v2"},
+				CommonLabels: map[string]string{"env": "prod"},
+			}
+
+			applicationFromArgoCD.Spec.Source.Kustomize = &appv1.ApplicationSourceKustomize{
+				NamePrefix:        "prod-",
+				Images:            appv1.KustomizeImages{"quay.io/org/image:v2"},
+				CommonLabels:      map[string]string{"env": "prod"},
+				CommonAnnotations: map[string]string{},
+			}
+
+			yamlData, err := yaml.Marshal(applicationFromDB)
+			Expect(err).ToNot(HaveOccurred())
+			dbApp := db.Application{Spec_field: string(yamlData)}
+
+			var ctx context.Context
+			log := log.FromContext(ctx)
+
+			result, err := CompareApplication(applicationFromArgoCD, dbApp, log)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(BeEmpty())
+
+			By("verifying that a change to an image override is detected")
+			applicationFromArgoCD.Spec.Source.Kustomize.Images = appv1.KustomizeImages{"quay.io/org/image:v3"}
+			result, err = CompareApplication(applicationFromArgoCD, dbApp, log)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).ToNot(BeEmpty())
+			applicationFromArgoCD.Spec.Source.Kustomize.Images = appv1.KustomizeImages{"quay.io/org/image:v2"}
+
+			By("verifying that a change to a common label is detected")
+			applicationFromArgoCD.Spec.Source.Kustomize.CommonLabels["env"] = "staging"
+			result, err = CompareApplication(applicationFromArgoCD, dbApp, log)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).ToNot(BeEmpty())
+		})
+
+		It("Should compare applications which have multiple sources.", func() {
+
+			applicationFromDB, _, applicationFromArgoCD, err := createDummyApplicationData()
+			Expect(err).ToNot(HaveOccurred())
+
+			applicationFromDB.Spec.Source = fauxargocd.ApplicationSource{}
+			applicationFromDB.Spec.Sources = fauxargocd.ApplicationSources{
+				{RepoURL: "https://charts.example.com", Chart: "my-chart", TargetRevision: "1.2.3",
+					Helm: &fauxargocd.ApplicationSourceHelm{ValueFiles: []string{"$values/values.yaml"}}},
+				{RepoURL: "https://github.com/test/values", Ref: "values"},
+			}
+
+			applicationFromArgoCD.Spec.Source = nil
+			applicationFromArgoCD.Spec.Sources = appv1.ApplicationSources{
+				{RepoURL: "https://charts.example.com", Chart: "my-chart", TargetRevision: "1.2.3",
+					Helm: &appv1.ApplicationSourceHelm{ValueFiles: []string{"$values/values.yaml"}}},
+				{RepoURL: "https://github.com/test/values", Ref: "values"},
+			}
+
+			yamlData, err := goyaml.Marshal(applicationFromDB)
+			Expect(err).ToNot(HaveOccurred())
+			dbApp := db.Application{Spec_field: string(yamlData)}
+
+			var ctx context.Context
+			log := log.FromContext(ctx)
+
+			result, err := CompareApplication(applicationFromArgoCD, dbApp, log)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(BeEmpty())
+
+			By("verifying that a change to one of the sources is detected")
+			applicationFromArgoCD.Spec.Sources[1].TargetRevision = "staging"
+			result, err = CompareApplication(applicationFromArgoCD, dbApp, log)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).ToNot(BeEmpty())
+			applicationFromArgoCD.Spec.Sources[1].TargetRevision = ""
+
+			By("verifying that a removed source is detected")
+			applicationFromArgoCD.Spec.Sources = applicationFromArgoCD.Spec.Sources[:1]
+			result, err = CompareApplication(applicationFromArgoCD, dbApp, log)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).ToNot(BeEmpty())
+		})
+
+		It("Should compare applications which have ignoreDifferences.", func() {
+
+			applicationFromDB, _, applicationFromArgoCD, err := createDummyApplicationData()
+			Expect(err).ToNot(HaveOccurred())
+
+			applicationFromDB.Spec.IgnoreDifferences = []fauxargocd.ResourceIgnoreDifferences{
+				{Group: "apps", Kind: "Deployment", JSONPointers: []string{"/spec/replicas"}},
+				{Kind: "ConfigMap", Name: "injected", JQPathExpressions: []string{`.data["ca.crt"]`}},
+			}
+
+			applicationFromArgoCD.Spec.IgnoreDifferences = appv1.IgnoreDifferences{
+				{Group: "apps", Kind: "Deployment", JSONPointers: []string{"/spec/replicas"}, JQPathExpressions: []string{}},
+				{Kind: "ConfigMap", Name: "injected", JQPathExpressions: []string{`.data["ca.crt"]`}},
+			}
+
+			yamlData, err := goyaml.Marshal(applicationFromDB)
+			Expect(err).ToNot(HaveOccurred())
+			dbApp := db.Application{Spec_field: string(yamlData)}
+
+			var ctx context.Context
+			log := log.FromContext(ctx)
+
+			result, err := CompareApplication(applicationFromArgoCD, dbApp, log)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(BeEmpty())
+
+			By("verifying that a change to a JSON pointer is detected")
+			applicationFromArgoCD.Spec.IgnoreDifferences[0].JSONPointers = []string{"/spec/template"}
+			result, err = CompareApplication(applicationFromArgoCD, dbApp, log)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal("spec.ignoreDifferences fields differ"))
+
+			By("verifying that removing ignoreDifferences is detected")
+			applicationFromArgoCD.Spec.IgnoreDifferences = nil
+			result, err = CompareApplication(applicationFromArgoCD, dbApp, log)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(result).To(Equal("spec.ignoreDifferences fields differ"))
+		})
+
 		It("Should compare applications if fields are nil.", func() {
 
 			// Convert a FauxApplication into a db.Application, by marshalling the FA back into YAML
@@ -496,4 +723,63 @@ var _ = Describe("Tests for the small number of utility functions in cluster-age
 		})
 	})
 
+	Context("Testing the deletion policy of Argo CD Applications", func() {
+
+		It("should return the Argo CD resources finalizer that matches the deletion policy annotation", func() {
+
+			app := appv1.Application{
+				ObjectMeta: metav1.ObjectMeta{
+					Finalizers: []string{"other-finalizer", argoCDResourcesFinalizer},
+				},
+			}
+
+			By("defaulting to background deletion, when there is no annotation")
+			Expect(getFinalizersOfDeletionPolicy(app)).To(Equal([]string{"other-finalizer", argoCDResourcesFinalizer}))
+
+			By("replacing the background finalizer with the foreground finalizer, for the Foreground policy")
+			app.Annotations = map[string]string{fauxargocd.DeletionPolicyAnnotation: "Foreground"}
+			Expect(getFinalizersOfDeletionPolicy(app)).To(Equal([]string{"other-finalizer", argoCDForegroundResourcesFinalizer}))
+
+			By("removing the resources finalizers, for the Orphan policy")
+			app.Annotations = map[string]string{fauxargocd.DeletionPolicyAnnotation: "Orphan"}
+			app.Finalizers = []string{argoCDForegroundResourcesFinalizer, "other-finalizer", argoCDResourcesFinalizer}
+			Expect(getFinalizersOfDeletionPolicy(app)).To(Equal([]string{"other-finalizer"}))
+		})
+
+		It("should copy the deletion policy annotation of the spec field to the Argo CD Application", func() {
+
+			specField := func(annotations map[string]string) string {
+				fauxApp := fauxargocd.FauxApplication{
+					FauxObjectMeta: fauxargocd.FauxObjectMeta{
+						Name:        "test-app",
+						Annotations: annotations,
+					},
+				}
+				specFieldBytes, err := goyaml.Marshal(fauxApp)
+				Expect(err).ToNot(HaveOccurred())
+				return string(specFieldBytes)
+			}
+
+			app := appv1.Application{}
+
+			By("adding the annotation, when it is set in the spec field")
+			changed, err := UpdateDeletionPolicyAnnotation(&app, db.Application{
+				Spec_field: specField(map[string]string{fauxargocd.DeletionPolicyAnnotation: "Orphan"})})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed).To(BeTrue())
+			Expect(app.Annotations).To(HaveKeyWithValue(fauxargocd.DeletionPolicyAnnotation, "Orphan"))
+
+			By("not modifying the Application, when the annotation is unchanged")
+			changed, err = UpdateDeletionPolicyAnnotation(&app, db.Application{
+				Spec_field: specField(map[string]string{fauxargocd.DeletionPolicyAnnotation: "Orphan"})})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed).To(BeFalse())
+
+			By("removing the annotation, when it is no longer set in the spec field")
+			changed, err = UpdateDeletionPolicyAnnotation(&app, db.Application{Spec_field: specField(nil)})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(changed).To(BeTrue())
+			Expect(app.Annotations).ToNot(HaveKey(fauxargocd.DeletionPolicyAnnotation))
+		})
+	})
 })
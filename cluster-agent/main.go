@@ -32,6 +32,7 @@ import (
 	"github.com/redhat-appstudio/managed-gitops/cluster-agent/controllers/managed-gitops/eventloop"
 	"github.com/redhat-appstudio/managed-gitops/cluster-agent/metrics"
 	argocdmetrics "github.com/redhat-appstudio/managed-gitops/cluster-agent/metrics/argocd"
+	"github.com/redhat-appstudio/managed-gitops/cluster-agent/utils"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -128,13 +129,19 @@ func main() {
 	operationsGC := controllers.NewGarbageCollector(dbQueries, mgr.GetClient())
 	operationsGC.StartGarbageCollector()
 
-	if err = (&argoprojiocontrollers.ApplicationReconciler{
+	applicationReconciler := &argoprojiocontrollers.ApplicationReconciler{
 		Client:                mgr.GetClient(),
 		Scheme:                mgr.GetScheme(),
 		DB:                    dbQueries,
 		DeletionTaskRetryLoop: sharedutil.NewTaskRetryLoop("application-reconciler"),
 		Cache:                 application_info_cache.NewApplicationInfoCache(),
-	}).SetupWithManager(mgr); err != nil {
+	}
+	if sharedutil.ResourceTreeCollectionEnabled() {
+		setupLog.Info("Resource tree collection is enabled")
+		applicationReconciler.GetResourceTree = argoprojiocontrollers.NewResourceTreeGetter(mgr.GetClient(), utils.NewCredentialService(nil, false))
+	}
+
+	if err = applicationReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Application")
 		os.Exit(1)
 	}
@@ -0,0 +1,253 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	argocdclient "github.com/argoproj/argo-cd/v2/pkg/apiclient"
+	applicationpkg "github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	argoio "github.com/argoproj/argo-cd/v2/util/io"
+	"github.com/argoproj/gitops-engine/pkg/diff"
+	"github.com/argoproj/gitops-engine/pkg/utils/kube"
+	"github.com/pmezard/go-difflib/difflib"
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// This contents of this file are loosely based on the 'argocd app diff --revision' CLI command:
+// https://github.com/argoproj/argo-cd/blob/0a46d37fc6af9fe0aa963bdd845e3d799aa0320d/cmd/argocd/commands/app.go#L1076
+
+// AppDiffResult is the result of comparing the manifests of a revision with the live resources of an Argo CD Application.
+type AppDiffResult struct {
+	// Revision is the resolved revision (for example, the commit SHA) that the manifests were generated from
+	Revision string
+
+	// Resources contains an entry for each resource that would be added, modified or removed by a sync to the revision.
+	Resources []managedgitopsv1alpha1.ResourceDiff
+
+	// Truncated is true if there were more than MaxDiffResources differences, and the trailing differences were omitted.
+	Truncated bool
+}
+
+// AppDiff calls the Argo CD GRPC API to generate the manifests of the given revision of an Argo CD Application, and compares
+// them with the live resources of the Application, without modifying either.
+func AppDiff(ctx context.Context, appName string, revision string, argocdNamespace corev1.Namespace,
+	credentialService *CredentialService, k8sClient client.Client) (AppDiffResult, error) {
+
+	_, acdClient, err := credentialService.GetArgoCDLoginCredentials(ctx, argocdNamespace.Name,
+		string(argocdNamespace.UID), false, k8sClient)
+	if err != nil {
+		return AppDiffResult{}, err
+	}
+
+	return appDiff(ctx, acdClient, appName, revision)
+}
+
+func appDiff(ctx context.Context, acdClient argocdclient.Client, appName string, revision string) (AppDiffResult, error) {
+
+	conn, appIf, err := acdClient.NewApplicationClient()
+	if err != nil {
+		return AppDiffResult{}, fmt.Errorf("unable to create application client for diff: %v", err)
+	}
+	defer argoio.Close(conn)
+
+	manifests, err := appIf.GetManifests(ctx, &applicationpkg.ApplicationManifestQuery{Name: &appName, Revision: &revision})
+	if err != nil {
+		return AppDiffResult{}, fmt.Errorf("unable to generate the manifests of revision '%s': %v", revision, err)
+	}
+
+	managedResources, err := appIf.ManagedResources(ctx, &applicationpkg.ResourcesQuery{ApplicationName: &appName})
+	if err != nil {
+		return AppDiffResult{}, fmt.Errorf("unable to retrieve the managed resources of application '%s': %v", appName, err)
+	}
+
+	// 1) Key the target resources of the revision, and the live resources of the Application, by group/kind/namespace/name
+	targetObjs := map[kube.ResourceKey]*unstructured.Unstructured{}
+	for _, manifest := range manifests.Manifests {
+		obj, err := unmarshalToUnstructured(manifest)
+		if err != nil {
+			return AppDiffResult{}, fmt.Errorf("unable to unmarshal manifest of revision '%s': %v", revision, err)
+		}
+		if obj == nil {
+			continue
+		}
+		if obj.GetNamespace() == "" {
+			obj.SetNamespace(manifests.Namespace)
+		}
+		targetObjs[kube.GetResourceKey(obj)] = obj
+	}
+
+	liveObjs := map[kube.ResourceKey]*unstructured.Unstructured{}
+	for _, item := range managedResources.Items {
+		if item == nil || item.Hook {
+			continue
+		}
+
+		key := kube.NewResourceKey(item.Group, item.Kind, item.Namespace, item.Name)
+
+		// Manifests of cluster-scoped resources were assigned the destination namespace above
+		if _, exists := targetObjs[key]; !exists && item.Namespace == "" {
+			if obj, exists := targetObjs[kube.NewResourceKey(item.Group, item.Kind, manifests.Namespace, item.Name)]; exists {
+				delete(targetObjs, kube.NewResourceKey(item.Group, item.Kind, manifests.Namespace, item.Name))
+				obj.SetNamespace("")
+				targetObjs[key] = obj
+			}
+		}
+
+		obj, err := unmarshalToUnstructured(item.NormalizedLiveState)
+		if err != nil {
+			return AppDiffResult{}, fmt.Errorf("unable to unmarshal live state of '%s': %v", item.FullName(), err)
+		}
+		liveObjs[key] = obj
+	}
+
+	// 2) Compare the target and live state of each resource, in a stable order
+	keys := map[kube.ResourceKey]bool{}
+	for key := range targetObjs {
+		keys[key] = true
+	}
+	for key := range liveObjs {
+		keys[key] = true
+	}
+
+	sortedKeys := make([]kube.ResourceKey, 0, len(keys))
+	for key := range keys {
+		sortedKeys = append(sortedKeys, key)
+	}
+	sort.Slice(sortedKeys, func(i, j int) bool {
+		return sortedKeys[i].String() < sortedKeys[j].String()
+	})
+
+	res := AppDiffResult{Revision: manifests.Revision}
+
+	for _, key := range sortedKeys {
+
+		resourceDiff, err := diffResource(targetObjs[key], liveObjs[key])
+		if err != nil {
+			return AppDiffResult{}, fmt.Errorf("unable to compare '%s': %v", key.String(), err)
+		}
+		if resourceDiff == nil {
+			// The resource is unchanged
+			continue
+		}
+
+		if len(res.Resources) >= managedgitopsv1alpha1.MaxDiffResources {
+			res.Truncated = true
+			break
+		}
+
+		resourceDiff.Group = key.Group
+		resourceDiff.Kind = key.Kind
+		resourceDiff.Namespace = key.Namespace
+		resourceDiff.Name = key.Name
+
+		res.Resources = append(res.Resources, *resourceDiff)
+	}
+
+	return res, nil
+}
+
+// diffResource returns the difference between the target and the live state of a resource, or nil if the resource is
+// unchanged. Either target or live may be nil, if the resource is only defined in the revision, or only exists on the cluster.
+func diffResource(target *unstructured.Unstructured, live *unstructured.Unstructured) (*managedgitopsv1alpha1.ResourceDiff, error) {
+
+	var status managedgitopsv1alpha1.ResourceDiffStatus
+	var from, to *unstructured.Unstructured
+
+	if target == nil && live == nil {
+		return nil, nil
+
+	} else if target == nil {
+		status = managedgitopsv1alpha1.ResourceDiffStatus_Removed
+		from = live
+
+	} else if live == nil {
+		status = managedgitopsv1alpha1.ResourceDiffStatus_Added
+		to = target
+
+	} else {
+		diffRes, err := diff.Diff(target, live)
+		if err != nil {
+			return nil, err
+		}
+		if !diffRes.Modified {
+			return nil, nil
+		}
+
+		status = managedgitopsv1alpha1.ResourceDiffStatus_Modified
+
+		from, to = &unstructured.Unstructured{}, &unstructured.Unstructured{}
+		if err := json.Unmarshal(diffRes.NormalizedLive, from); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(diffRes.PredictedLive, to); err != nil {
+			return nil, err
+		}
+	}
+
+	unifiedDiff, err := unifiedYAMLDiff(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceDiff := &managedgitopsv1alpha1.ResourceDiff{
+		Status: status,
+		Diff:   unifiedDiff,
+	}
+
+	if len(resourceDiff.Diff) > managedgitopsv1alpha1.MaxResourceDiffLength {
+		resourceDiff.Diff = resourceDiff.Diff[:managedgitopsv1alpha1.MaxResourceDiffLength]
+		resourceDiff.Truncated = true
+	}
+
+	return resourceDiff, nil
+}
+
+// unifiedYAMLDiff returns the YAML representation of 'from' and 'to', as a unified diff. Either may be nil.
+func unifiedYAMLDiff(from *unstructured.Unstructured, to *unstructured.Unstructured) (string, error) {
+
+	toYAML := func(obj *unstructured.Unstructured) (string, error) {
+		if obj == nil {
+			return "", nil
+		}
+		res, err := yaml.Marshal(obj.Object)
+		return string(res), err
+	}
+
+	fromYAML, err := toYAML(from)
+	if err != nil {
+		return "", err
+	}
+
+	toYAMLStr, err := toYAML(to)
+	if err != nil {
+		return "", err
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(fromYAML),
+		B:        difflib.SplitLines(toYAMLStr),
+		FromFile: "live",
+		ToFile:   "target",
+		Context:  3,
+	})
+}
+
+// unmarshalToUnstructured returns the resource of the given JSON, or nil if the JSON is empty or null.
+func unmarshalToUnstructured(resource string) (*unstructured.Unstructured, error) {
+	if resource == "" || resource == "null" {
+		return nil, nil
+	}
+
+	var obj unstructured.Unstructured
+	if err := json.Unmarshal([]byte(resource), &obj); err != nil {
+		return nil, err
+	}
+
+	return &obj, nil
+}
@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	applicationpkg "github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	repoapiclient "github.com/argoproj/argo-cd/v2/reposerver/apiclient"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
+	"github.com/redhat-appstudio/managed-gitops/cluster-agent/utils/mocks"
+	"github.com/stretchr/testify/mock"
+)
+
+var _ = Describe("Diff of an Argo CD Application", func() {
+
+	Context("appDiff Test", func() {
+
+		configMapJSON := func(name string, value string) string {
+			return fmt.Sprintf(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"%s","namespace":"my-namespace"},"data":{"key":"%s"}}`, name, value)
+		}
+
+		var mockAppServiceClient *mocks.ApplicationServiceClient
+		var mockAppClient *mocks.Client
+
+		BeforeEach(func() {
+			mockAppServiceClient = &mocks.ApplicationServiceClient{}
+			mockAppClient = &mocks.Client{}
+			mockAppClient.On("NewApplicationClient").Return(mockCloser{}, mockAppServiceClient, nil)
+		})
+
+		It("should report the resources that would be added, modified and removed by the revision", func() {
+
+			appName := "my-app"
+			revision := "my-branch"
+
+			mockAppServiceClient.On("GetManifests", mock.Anything, &applicationpkg.ApplicationManifestQuery{Name: &appName, Revision: &revision}).
+				Return(&repoapiclient.ManifestResponse{
+					Revision:  "0a1b2c3d",
+					Namespace: "my-namespace",
+					Manifests: []string{
+						configMapJSON("added", "a"),
+						configMapJSON("modified", "new-value"),
+						configMapJSON("unchanged", "a"),
+					},
+				}, nil)
+
+			mockAppServiceClient.On("ManagedResources", mock.Anything, &applicationpkg.ResourcesQuery{ApplicationName: &appName}).
+				Return(&applicationpkg.ManagedResourcesResponse{
+					Items: []*appv1.ResourceDiff{
+						{Kind: "ConfigMap", Namespace: "my-namespace", Name: "modified", NormalizedLiveState: configMapJSON("modified", "old-value")},
+						{Kind: "ConfigMap", Namespace: "my-namespace", Name: "removed", NormalizedLiveState: configMapJSON("removed", "a")},
+						{Kind: "ConfigMap", Namespace: "my-namespace", Name: "unchanged", NormalizedLiveState: configMapJSON("unchanged", "a")},
+					},
+				}, nil)
+
+			res, err := appDiff(context.Background(), mockAppClient, appName, revision)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(res.Revision).To(Equal("0a1b2c3d"))
+			Expect(res.Truncated).To(BeFalse())
+			Expect(res.Resources).To(HaveLen(3))
+
+			Expect(res.Resources[0].Name).To(Equal("added"))
+			Expect(res.Resources[0].Status).To(Equal(managedgitopsv1alpha1.ResourceDiffStatus_Added))
+			Expect(res.Resources[0].Diff).To(ContainSubstring("+  key: a"))
+
+			Expect(res.Resources[1].Name).To(Equal("modified"))
+			Expect(res.Resources[1].Status).To(Equal(managedgitopsv1alpha1.ResourceDiffStatus_Modified))
+			Expect(res.Resources[1].Diff).To(ContainSubstring("-  key: old-value"))
+			Expect(res.Resources[1].Diff).To(ContainSubstring("+  key: new-value"))
+
+			Expect(res.Resources[2].Name).To(Equal("removed"))
+			Expect(res.Resources[2].Status).To(Equal(managedgitopsv1alpha1.ResourceDiffStatus_Removed))
+			Expect(res.Resources[2].Diff).To(ContainSubstring("-  key: a"))
+		})
+
+		It("should truncate the number of resources, and the length of each diff", func() {
+
+			appName := "my-app"
+			revision := "my-branch"
+
+			manifests := []string{}
+			for i := 0; i < managedgitopsv1alpha1.MaxDiffResources+1; i++ {
+				manifests = append(manifests, configMapJSON(fmt.Sprintf("config-%03d", i), strings.Repeat("a", managedgitopsv1alpha1.MaxResourceDiffLength)))
+			}
+
+			mockAppServiceClient.On("GetManifests", mock.Anything, mock.Anything).
+				Return(&repoapiclient.ManifestResponse{Revision: "0a1b2c3d", Namespace: "my-namespace", Manifests: manifests}, nil)
+			mockAppServiceClient.On("ManagedResources", mock.Anything, mock.Anything).
+				Return(&applicationpkg.ManagedResourcesResponse{}, nil)
+
+			res, err := appDiff(context.Background(), mockAppClient, appName, revision)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(res.Truncated).To(BeTrue())
+			Expect(res.Resources).To(HaveLen(managedgitopsv1alpha1.MaxDiffResources))
+			for _, resource := range res.Resources {
+				Expect(resource.Truncated).To(BeTrue())
+				Expect(resource.Diff).To(HaveLen(managedgitopsv1alpha1.MaxResourceDiffLength))
+			}
+		})
+
+		It("should return an error if the manifests of the revision cannot be generated", func() {
+
+			appName := "my-app"
+
+			mockAppServiceClient.On("GetManifests", mock.Anything, mock.Anything).
+				Return(nil, fmt.Errorf("revision not found"))
+
+			_, err := appDiff(context.Background(), mockAppClient, appName, "does-not-exist")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("revision not found"))
+		})
+	})
+})
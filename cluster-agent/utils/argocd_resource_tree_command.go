@@ -0,0 +1,176 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	argocdclient "github.com/argoproj/argo-cd/v2/pkg/apiclient"
+	applicationpkg "github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	argoio "github.com/argoproj/argo-cd/v2/util/io"
+	"github.com/argoproj/gitops-engine/pkg/health"
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// This contents of this file are loosely based on the resource tree and events views of the Argo CD Web UI, which use
+// the ResourceTree and ListResourceEvents GRPC APIs.
+
+// maxUnhealthyResourcesForEvents is the maximum number of unhealthy resources whose events are retrieved, as each
+// requires a separate request to the Argo CD API server.
+const maxUnhealthyResourcesForEvents = 10
+
+// GetResourceTree calls the Argo CD GRPC API to retrieve the resource tree of the given Argo CD Application, along with
+// the most recent warning events of any unhealthy resources. The result is bounded by MaxResourceTreeNodes and MaxResourceTreeEvents.
+func GetResourceTree(ctx context.Context, appName string, argocdNamespace corev1.Namespace,
+	credentialService *CredentialService, k8sClient client.Client) (*managedgitopsv1alpha1.ResourceTree, error) {
+
+	_, acdClient, err := credentialService.GetArgoCDLoginCredentials(ctx, argocdNamespace.Name,
+		string(argocdNamespace.UID), false, k8sClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return getResourceTree(ctx, acdClient, appName)
+}
+
+func getResourceTree(ctx context.Context, acdClient argocdclient.Client, appName string) (*managedgitopsv1alpha1.ResourceTree, error) {
+
+	conn, appIf, err := acdClient.NewApplicationClient()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create application client for resource tree: %v", err)
+	}
+	defer argoio.Close(conn)
+
+	appTree, err := appIf.ResourceTree(ctx, &applicationpkg.ResourcesQuery{ApplicationName: &appName})
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve the resource tree of application '%s': %v", appName, err)
+	}
+
+	res := &managedgitopsv1alpha1.ResourceTree{}
+
+	// 1) Convert the nodes of the tree, and find the unhealthy resources
+	var unhealthyNodes []appv1.ResourceNode
+	for _, node := range appTree.Nodes {
+
+		if len(res.Nodes) >= managedgitopsv1alpha1.MaxResourceTreeNodes {
+			res.Truncated = true
+		} else {
+			res.Nodes = append(res.Nodes, convertResourceNode(node))
+		}
+
+		if node.Health != nil && isUnhealthy(node.Health.Status) {
+			unhealthyNodes = append(unhealthyNodes, node)
+		}
+	}
+
+	// 2) Retrieve the warning events of the unhealthy resources
+	if len(unhealthyNodes) > maxUnhealthyResourcesForEvents {
+		unhealthyNodes = unhealthyNodes[:maxUnhealthyResourcesForEvents]
+		res.Truncated = true
+	}
+
+	for _, node := range unhealthyNodes {
+
+		resourceNamespace, resourceName, resourceUID := node.Namespace, node.Name, node.UID
+
+		eventList, err := appIf.ListResourceEvents(ctx, &applicationpkg.ApplicationResourceEventsQuery{
+			Name:              &appName,
+			ResourceNamespace: &resourceNamespace,
+			ResourceName:      &resourceName,
+			ResourceUID:       &resourceUID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to retrieve the events of '%s' in application '%s': %v", node.FullName(), appName, err)
+		}
+		if eventList == nil {
+			continue
+		}
+
+		for _, event := range eventList.Items {
+			if event.Type != corev1.EventTypeWarning {
+				continue
+			}
+			res.Events = append(res.Events, convertResourceEvent(node, event))
+		}
+	}
+
+	// 3) Keep only the most recent events
+	sort.SliceStable(res.Events, func(i, j int) bool {
+		return res.Events[i].LastTimestamp.After(res.Events[j].LastTimestamp.Time)
+	})
+
+	if len(res.Events) > managedgitopsv1alpha1.MaxResourceTreeEvents {
+		res.Events = res.Events[:managedgitopsv1alpha1.MaxResourceTreeEvents]
+		res.Truncated = true
+	}
+
+	return res, nil
+}
+
+// isUnhealthy returns true if the health status indicates that the resource may need attention.
+func isUnhealthy(status health.HealthStatusCode) bool {
+	return status == health.HealthStatusDegraded || status == health.HealthStatusMissing ||
+		status == health.HealthStatusProgressing || status == health.HealthStatusUnknown
+}
+
+func convertResourceRef(ref appv1.ResourceRef) managedgitopsv1alpha1.ResourceRef {
+	return managedgitopsv1alpha1.ResourceRef{
+		Group:     ref.Group,
+		Version:   ref.Version,
+		Kind:      ref.Kind,
+		Namespace: ref.Namespace,
+		Name:      ref.Name,
+	}
+}
+
+func convertResourceNode(node appv1.ResourceNode) managedgitopsv1alpha1.ResourceNode {
+
+	res := managedgitopsv1alpha1.ResourceNode{
+		ResourceRef: convertResourceRef(node.ResourceRef),
+	}
+
+	for _, parentRef := range node.ParentRefs {
+		res.ParentRefs = append(res.ParentRefs, convertResourceRef(parentRef))
+	}
+
+	if node.Health != nil {
+		res.Health = &managedgitopsv1alpha1.HealthStatus{
+			Status:  managedgitopsv1alpha1.HealthStatusCode(node.Health.Status),
+			Message: truncateResourceTreeMessage(node.Health.Message),
+		}
+	}
+
+	return res
+}
+
+func convertResourceEvent(node appv1.ResourceNode, event corev1.Event) managedgitopsv1alpha1.ResourceEvent {
+
+	// Events created with the events.k8s.io API may only set the EventTime, or the Series
+	lastTimestamp := event.LastTimestamp
+	if lastTimestamp.IsZero() {
+		if event.Series != nil {
+			lastTimestamp = metav1.NewTime(event.Series.LastObservedTime.Time)
+		} else {
+			lastTimestamp = metav1.NewTime(event.EventTime.Time)
+		}
+	}
+
+	return managedgitopsv1alpha1.ResourceEvent{
+		InvolvedObject: convertResourceRef(node.ResourceRef),
+		Reason:         event.Reason,
+		Message:        truncateResourceTreeMessage(event.Message),
+		Count:          event.Count,
+		LastTimestamp:  lastTimestamp,
+	}
+}
+
+func truncateResourceTreeMessage(message string) string {
+	if len(message) > managedgitopsv1alpha1.MaxResourceTreeMessageLength {
+		return message[:managedgitopsv1alpha1.MaxResourceTreeMessageLength]
+	}
+	return message
+}
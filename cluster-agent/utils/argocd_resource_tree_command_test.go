@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	applicationpkg "github.com/argoproj/argo-cd/v2/pkg/apiclient/application"
+	appv1 "github.com/argoproj/argo-cd/v2/pkg/apis/application/v1alpha1"
+	"github.com/argoproj/gitops-engine/pkg/health"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	managedgitopsv1alpha1 "github.com/redhat-appstudio/managed-gitops/backend-shared/apis/managed-gitops/v1alpha1"
+	"github.com/redhat-appstudio/managed-gitops/cluster-agent/utils/mocks"
+	"github.com/stretchr/testify/mock"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var _ = Describe("Resource tree of an Argo CD Application", func() {
+
+	Context("getResourceTree Test", func() {
+
+		appName := "my-app"
+
+		deploymentRef := appv1.ResourceRef{Group: "apps", Version: "v1", Kind: "Deployment", Namespace: "my-namespace", Name: "my-deployment", UID: "deployment-uid"}
+		podRef := appv1.ResourceRef{Version: "v1", Kind: "Pod", Namespace: "my-namespace", Name: "my-deployment-abcde", UID: "pod-uid"}
+
+		var mockAppServiceClient *mocks.ApplicationServiceClient
+		var mockAppClient *mocks.Client
+
+		BeforeEach(func() {
+			mockAppServiceClient = &mocks.ApplicationServiceClient{}
+			mockAppClient = &mocks.Client{}
+			mockAppClient.On("NewApplicationClient").Return(mockCloser{}, mockAppServiceClient, nil)
+		})
+
+		It("should return the nodes of the tree, and the warning events of unhealthy resources", func() {
+
+			now := time.Date(2024, time.January, 1, 9, 30, 0, 0, time.UTC)
+
+			mockAppServiceClient.On("ResourceTree", mock.Anything, &applicationpkg.ResourcesQuery{ApplicationName: &appName}).
+				Return(&appv1.ApplicationTree{
+					Nodes: []appv1.ResourceNode{
+						{ResourceRef: deploymentRef, Health: &appv1.HealthStatus{Status: health.HealthStatusHealthy}},
+						{ResourceRef: podRef, ParentRefs: []appv1.ResourceRef{deploymentRef},
+							Health: &appv1.HealthStatus{Status: health.HealthStatusDegraded, Message: "Back-off pulling image"}},
+					},
+				}, nil)
+
+			podNamespace, podName, podUID := podRef.Namespace, podRef.Name, podRef.UID
+			mockAppServiceClient.On("ListResourceEvents", mock.Anything, &applicationpkg.ApplicationResourceEventsQuery{
+				Name: &appName, ResourceNamespace: &podNamespace, ResourceName: &podName, ResourceUID: &podUID,
+			}).Return(&corev1.EventList{
+				Items: []corev1.Event{
+					{Type: corev1.EventTypeNormal, Reason: "Scheduled", LastTimestamp: metav1.NewTime(now.Add(-time.Minute))},
+					{Type: corev1.EventTypeWarning, Reason: "Failed", Message: "Failed to pull image", Count: 1, LastTimestamp: metav1.NewTime(now.Add(-30 * time.Second))},
+					{Type: corev1.EventTypeWarning, Reason: "BackOff", Message: "Back-off pulling image", Count: 3, LastTimestamp: metav1.NewTime(now)},
+				},
+			}, nil)
+
+			res, err := getResourceTree(context.Background(), mockAppClient, appName)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(res.Truncated).To(BeFalse())
+			Expect(res.Nodes).To(HaveLen(2))
+			Expect(res.Nodes[1].Name).To(Equal("my-deployment-abcde"))
+			Expect(res.Nodes[1].ParentRefs).To(Equal([]managedgitopsv1alpha1.ResourceRef{
+				{Group: "apps", Version: "v1", Kind: "Deployment", Namespace: "my-namespace", Name: "my-deployment"},
+			}))
+			Expect(res.Nodes[1].Health.Status).To(Equal(managedgitopsv1alpha1.HeathStatusCodeDegraded))
+			Expect(res.Nodes[1].Health.Message).To(Equal("Back-off pulling image"))
+
+			By("only including warning events, from newest to oldest")
+			Expect(res.Events).To(HaveLen(2))
+			Expect(res.Events[0].Reason).To(Equal("BackOff"))
+			Expect(res.Events[0].Count).To(Equal(int32(3)))
+			Expect(res.Events[0].InvolvedObject.Name).To(Equal("my-deployment-abcde"))
+			Expect(res.Events[1].Reason).To(Equal("Failed"))
+
+			By("not retrieving the events of healthy resources")
+			mockAppServiceClient.AssertNumberOfCalls(GinkgoT(), "ListResourceEvents", 1)
+		})
+
+		It("should bound the number of nodes and events", func() {
+
+			var nodes []appv1.ResourceNode
+			for i := 0; i < managedgitopsv1alpha1.MaxResourceTreeNodes+1; i++ {
+				nodes = append(nodes, appv1.ResourceNode{
+					ResourceRef: appv1.ResourceRef{Version: "v1", Kind: "Pod", Namespace: "my-namespace", Name: fmt.Sprintf("pod-%d", i)},
+					Health:      &appv1.HealthStatus{Status: health.HealthStatusDegraded},
+				})
+			}
+
+			var events []corev1.Event
+			for i := 0; i < managedgitopsv1alpha1.MaxResourceTreeEvents; i++ {
+				events = append(events, corev1.Event{Type: corev1.EventTypeWarning, Reason: "BackOff"})
+			}
+
+			mockAppServiceClient.On("ResourceTree", mock.Anything, mock.Anything).Return(&appv1.ApplicationTree{Nodes: nodes}, nil)
+			mockAppServiceClient.On("ListResourceEvents", mock.Anything, mock.Anything).Return(&corev1.EventList{Items: events}, nil)
+
+			res, err := getResourceTree(context.Background(), mockAppClient, appName)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(res.Truncated).To(BeTrue())
+			Expect(res.Nodes).To(HaveLen(managedgitopsv1alpha1.MaxResourceTreeNodes))
+			Expect(res.Events).To(HaveLen(managedgitopsv1alpha1.MaxResourceTreeEvents))
+			mockAppServiceClient.AssertNumberOfCalls(GinkgoT(), "ListResourceEvents", maxUnhealthyResourcesForEvents)
+		})
+
+		It("should return an error if the resource tree cannot be retrieved", func() {
+
+			mockAppServiceClient.On("ResourceTree", mock.Anything, mock.Anything).Return(nil, fmt.Errorf("application not found"))
+
+			_, err := getResourceTree(context.Background(), mockAppClient, appName)
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("application not found"))
+		})
+	})
+})
@@ -25,9 +25,50 @@ import (
 // This contents of this file are loosely based on the 'argocd app sync' CLI command:
 // https://github.com/argoproj/argo-cd/blob/0a46d37fc6af9fe0aa963bdd845e3d799aa0320d/cmd/argocd/commands/app.go#L1333
 
+const (
+	// SyncOperationIDInfoName is the name of the informational item, added to the Argo CD sync operation, which
+	// contains the ID of the SyncOperation database row that requested the sync.
+	SyncOperationIDInfoName = "syncOperationID"
+)
+
+// GetSyncOperationIDOfOperation returns the ID of the SyncOperation database row that requested the given Argo CD
+// operation, or an empty string if the operation was not requested by a SyncOperation.
+func GetSyncOperationIDOfOperation(operation argoappv1.Operation) string {
+
+	for _, info := range operation.Info {
+		if info != nil && info.Name == SyncOperationIDInfoName {
+			return info.Value
+		}
+	}
+
+	return ""
+}
+
+// AppSyncOptions are the optional parameters of a synchronize operation: the zero value syncs all the resources of the
+// Application, using the default sync strategy, without pruning.
+type AppSyncOptions struct {
+	// DryRun, if true, only simulates the sync
+	DryRun bool
+
+	// Prune, if true, deletes resources that are no longer defined in the GitOps repository
+	Prune bool
+
+	// Force, if true, deletes and re-creates resources which cannot be updated
+	Force bool
+
+	// Strategy is either 'apply' or 'hook' (the default, if empty)
+	Strategy string
+
+	// Resources, if non-empty, limits the sync to the given resources
+	Resources []argoappv1.SyncOperationResource
+
+	// Infos are informational items that are added to the resulting Argo CD operation (may be nil)
+	Infos []*argoappv1.Info
+}
+
 // AppSync will trigger a synchronize application on the given Argo CD appliatication, in the given namespace.
 func AppSync(ctx context.Context, appName string, revision string, namespaceName string, k8sClient client.Client,
-	credentialsService *CredentialService, skipTLSTest bool) error {
+	credentialsService *CredentialService, skipTLSTest bool, options AppSyncOptions) error {
 
 	namespace := &corev1.Namespace{
 		ObjectMeta: metav1.ObjectMeta{
@@ -45,7 +86,8 @@ func AppSync(ctx context.Context, appName string, revision string, namespaceName
 		return err
 	}
 
-	err = appSync(ctx, acdClient, appName, false, false, revision, false, "", false, false, 0, 0, 0, 0, 0)
+	err = appSync(ctx, acdClient, appName, options.DryRun, false, revision, options.Prune, options.Strategy, options.Force, false, 0, 0, 0, 0, 0,
+		options.Resources, options.Infos)
 	if err != nil {
 		return err
 	}
@@ -56,7 +98,8 @@ func AppSync(ctx context.Context, appName string, revision string, namespaceName
 
 func appSync(ctx context.Context, acdClient argocdclient.Client, appName string, dryRun bool, replace bool, revision string, prune bool,
 	strategy string, force bool, async bool, timeout int, retryLimit int64, retryBackoffDuration time.Duration,
-	retryBackoffMaxDuration time.Duration, retryBackoffFactor int64) error {
+	retryBackoffMaxDuration time.Duration, retryBackoffFactor int64, selectedResources []argoappv1.SyncOperationResource,
+	infos []*argoappv1.Info) error {
 
 	conn, appIf, err := acdClient.NewApplicationClient()
 	if err != nil {
@@ -83,10 +126,10 @@ func appSync(ctx context.Context, acdClient argocdclient.Client, appName string,
 		Name:        &appName,
 		DryRun:      &dryRun,
 		Revision:    &revision,
-		Resources:   nil,
+		Resources:   syncOperationResourcesToPointers(selectedResources),
 		Prune:       &prune,
 		Manifests:   nil,
-		Infos:       []*argoappv1.Info{},
+		Infos:       infos,
 		SyncOptions: syncOptionsFactory(),
 	}
 
@@ -116,7 +159,7 @@ func appSync(ctx context.Context, acdClient argocdclient.Client, appName string,
 	}
 
 	if !async {
-		app, err := waitOnApplicationStatus(ctx, acdClient, appName, timeout, false, false, true, false, []argoappv1.SyncOperationResource{})
+		app, err := waitOnApplicationStatus(ctx, acdClient, appName, timeout, false, false, true, false, selectedResources)
 		if err != nil {
 			return err
 		}
@@ -125,7 +168,7 @@ func appSync(ctx context.Context, acdClient argocdclient.Client, appName string,
 			operationState := app.Status.OperationState
 			if !operationState.Phase.Successful() {
 				return fmt.Errorf("operation has completed with phase: %s and message: %s", operationState.Phase, operationState.Message)
-			} else if /*len(selectedResources) == 0 &&*/ app.Status.Sync.Status != argoappv1.SyncStatusCodeSynced {
+			} else if len(selectedResources) == 0 && app.Status.Sync.Status != argoappv1.SyncStatusCodeSynced {
 				// Only get resources to be pruned if sync was application-wide and final status is not synced
 				pruningRequired := operationState.SyncResult.Resources.PruningRequired()
 				if pruningRequired > 0 {
@@ -138,6 +181,20 @@ func appSync(ctx context.Context, acdClient argocdclient.Client, appName string,
 	return nil
 }
 
+func syncOperationResourcesToPointers(resources []argoappv1.SyncOperationResource) []*argoappv1.SyncOperationResource {
+
+	if len(resources) == 0 {
+		return nil
+	}
+
+	res := make([]*argoappv1.SyncOperationResource, 0, len(resources))
+	for i := range resources {
+		res = append(res, &resources[i])
+	}
+
+	return res
+}
+
 // ResourceDiff tracks the state of a resource when waiting on an application status.
 type resourceState struct {
 	Group     string
@@ -125,7 +125,7 @@ var _ = Describe("ArgoCD AppSync Command", func() {
 			}
 
 			cs := NewCredentialService(&clientGenerator, true)
-			err = AppSync(context.Background(), appName, "master", "openshift-gitops", k8sClient, cs, true)
+			err = AppSync(context.Background(), appName, "master", "openshift-gitops", k8sClient, cs, true, AppSyncOptions{})
 			Expect(err).ToNot(HaveOccurred())
 		})
 	})
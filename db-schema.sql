@@ -49,7 +49,20 @@ CREATE TABLE ClusterCredentials (
 
 	-- Whether or not Argo CD is able to deploy cluster-scoped resources using these cluster credentials
 	-- - This corresponds to the Argo CD cluster secret field of the same name.
-	cluster_resources BOOLEAN DEFAULT FALSE
+	cluster_resources BOOLEAN DEFAULT FALSE,
+
+	-- The PEM-encoded CA bundle that is used to verify the TLS certificate of the cluster (may be empty)
+	-- - This corresponds to the 'tlsClientConfig.caData' field of the Argo CD cluster secret.
+	ca_bundle VARCHAR (65000),
+
+	-- The PEM-encoded client certificate/key that is used to authenticate with the cluster (may be empty, if a bearer token is used instead)
+	-- - These correspond to the 'tlsClientConfig.certData'/'tlsClientConfig.keyData' fields of the Argo CD cluster secret.
+	client_certificate_data VARCHAR (65000),
+	client_key_data VARCHAR (65000),
+
+	-- The URL of the HTTP proxy that is used to connect to the cluster (may be empty)
+	-- - This corresponds to the 'proxyUrl' field of the Argo CD cluster secret.
+	proxy_url VARCHAR (512)
 
 );
 
@@ -218,6 +231,7 @@ CREATE TABLE Operation (
 	-- * GitopsEngineInstance (specified to CRUD an Argo instance, for example to create a new namespace and put Argo CD in it, then signal when it's done)
 	-- * Application (user creates a new Application via service/web UI)
 	-- * SyncOperation (user wants a GitOps engine sync operation performed)
+	-- * DiffOperation (user wants to preview the changes that deploying a revision would make)
 	resource_type VARCHAR(32) NOT NULL,
 
 	-- When the operation was created. Used for garbage collection, as operations should be short lived.
@@ -257,7 +271,7 @@ CREATE TABLE Application (
 	-- '.spec' field of the Application CR
 	-- Note: Rather than converting individual JSON fields into SQL Table fields, we just pull the whole spec field. 
 	-- In the future, it might be beneficial to pull out SOME of the fields, to reduce CPU time spent on json parsing
-	spec_field VARCHAR ( 16384 ) NOT NULL,
+	spec_field VARCHAR ( 65536 ) NOT NULL,
 
 	-- Which Argo CD instance it's hosted on
 	-- Foreign key to: GitopsEngineInstance.gitopsengineinstance_id
@@ -284,9 +298,54 @@ CREATE TABLE ApplicationState (
 	CONSTRAINT fk_app_id FOREIGN KEY (applicationstate_application_id) REFERENCES Application(application_id) ON DELETE NO ACTION ON UPDATE NO ACTION,
 
 	-- argocd_application_status field contains the entire status of the Argo CD Application
-	argocd_application_status bytea 
+	argocd_application_status bytea,
+
+	-- resource_tree field contains the resource tree, and the recent warning events of unhealthy resources, of the Argo CD Application
+	resource_tree bytea
+);
+
+-- DeploymentHistory is a record of a previous deployment (sync) of an Application, based on the revision history
+-- of the Argo CD Application. Unlike the Argo CD Application, this is preserved if the Argo CD Application is recreated.
+CREATE TABLE DeploymentHistory (
+
+	-- Primary key for the DeploymentHistory (UID), is a random UUID
+	deploymenthistory_id VARCHAR(48) NOT NULL PRIMARY KEY,
+
+	-- The Application that was deployed
+	-- Foreign key to: Application.application_id
+	application_id VARCHAR(48) NOT NULL,
+	CONSTRAINT fk_dh_app_id FOREIGN KEY (application_id) REFERENCES Application(application_id) ON DELETE NO ACTION ON UPDATE NO ACTION,
+
+	-- The revision (for example, the Git commit SHA) that was deployed
+	revision VARCHAR(256) NOT NULL,
+
+	-- The source of the Application that was deployed, as JSON
+	source VARCHAR(4096),
+
+	-- The sources of the Application that was deployed, as a JSON list, for an Application with multiple sources
+	sources VARCHAR(16384),
+
+	-- The revisions that were deployed, as a JSON list (one for each of the sources), for an Application with multiple sources
+	revisions VARCHAR(2048),
+
+	-- When the deployment completed
+	deployed_at TIMESTAMP NOT NULL,
+
+	-- Who initiated the deployment: either the name of a user, or 'automated' for an automated sync
+	initiated_by VARCHAR(256),
+
+	-- The name of the GitOpsDeploymentSyncRun that triggered the deployment, if any
+	sync_run_name VARCHAR(256),
+
+	seq_id serial,
+
+	-- When DeploymentHistory was created, which allow us to tell how old the resources are
+	created_on TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+
 );
 
+CREATE INDEX idx_deploymenthistory_1 ON DeploymentHistory(application_id);
+
 -- Represents the relationship from GitOpsDeployment CR in the API namespace, to an Application table row.
 -- This means: if we see a change in a GitOpsDeployment CR, we can easily find the corresponding database entry
 -- by looking for a DeploymentToApplicationMapping that captures the relationship (and vice versa)
@@ -449,6 +508,40 @@ CREATE TABLE SyncOperation (
 	-- values: Running, Terminated
 	desired_state VARCHAR(16) NOT NULL,	
 
+	-- The sync windows of the GitOpsDeployment (as JSON), at the time the SyncOperation was created.
+	-- The sync will only be started while the sync windows allow it. May be empty.
+	sync_windows VARCHAR(4096),
+
+	-- The 'prune', 'dryRun' and 'force' fields of the GitOpsDeploymentSyncRun CR
+	prune BOOLEAN DEFAULT FALSE,
+	dry_run BOOLEAN DEFAULT FALSE,
+	force BOOLEAN DEFAULT FALSE,
+
+	-- The 'syncStrategy' field of the GitOpsDeploymentSyncRun CR
+	-- values: apply, hook, or empty (equivalent to hook)
+	sync_strategy VARCHAR(16),
+
+	-- The 'resources' field of the GitOpsDeploymentSyncRun CR (as JSON): if non-empty, only these resources are synced.
+	resources VARCHAR(4096),
+
+	-- The status of the sync, as reported by the cluster-agent:
+
+	-- values: Pending, Running, Succeeded, Failed, Terminated (empty is equivalent to Pending)
+	phase VARCHAR(16),
+
+	-- Human-readable details about the phase, for example the reason the sync failed
+	phase_message VARCHAR(1024),
+
+	-- When the sync was started/finished
+	started_at TIMESTAMP,
+	finished_at TIMESTAMP,
+
+	-- The revision (for example, the Git commit SHA) that was synced
+	synced_revision VARCHAR(256),
+
+	-- The result of the sync of each resource (as JSON)
+	resource_results VARCHAR(16384),
+
 	seq_id serial,
 
 	-- When SyncOperation was created, which allow us to tell how old the resources are
@@ -456,6 +549,54 @@ CREATE TABLE SyncOperation (
 
 );
 
+-- DiffOperation tracks a diff request from the API (a GitOpsDeploymentDiff CR): the cluster-agent renders the manifests
+-- of the Application at a candidate revision, via the Argo CD repo server, and compares them against the live state.
+CREATE TABLE DiffOperation (
+
+	-- Primary key for the DiffOperation (UID), is a random UUID
+	diffoperation_id VARCHAR(48) NOT NULL PRIMARY KEY,
+
+	-- The target Application whose manifests are compared against the live state
+	-- Foreign key to: Application.application_id
+	application_id VARCHAR(48) NOT NULL,
+	CONSTRAINT fk_dfo_app_id FOREIGN KEY (application_id) REFERENCES Application(application_id) ON DELETE NO ACTION ON UPDATE NO ACTION,
+
+	-- The 'gitopsDeploymentName' field of the GitOpsDeploymentDiff CR
+	deployment_name VARCHAR(256) NOT NULL,
+
+	-- The 'revision' field of the GitOpsDeploymentDiff CR
+	revision VARCHAR(256) NOT NULL,
+
+	-- The status of the diff, as reported by the cluster-agent:
+
+	-- values: Pending, Running, Succeeded, Failed (empty is equivalent to Pending)
+	phase VARCHAR(16),
+
+	-- Human-readable details about the phase, for example the reason the diff failed
+	phase_message VARCHAR(1024),
+
+	-- When the diff was started/finished
+	started_at TIMESTAMP,
+	finished_at TIMESTAMP,
+
+	-- The revision (for example, the Git commit SHA) that the manifests were rendered from
+	resolved_revision VARCHAR(256),
+
+	-- The diff of each changed resource (as JSON)
+	resource_diffs VARCHAR(262144),
+
+	-- Whether some of the changed resources were omitted from resource_diffs
+	truncated BOOLEAN DEFAULT FALSE,
+
+	seq_id serial,
+
+	-- When DiffOperation was created, which allow us to tell how old the resources are
+	created_on TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+
+);
+
+CREATE INDEX idx_diffoperation_1 ON DiffOperation(application_id);
+
 -- RepositoryCredentials represents Git repository credentials (username/password, or an SSH key).
 -- This database table will then correspond to an Argo CD repository secret in the namespace of the target Argo CD instance.
 CREATE TABLE RepositoryCredentials (
@@ -602,6 +743,8 @@ Miro Diagram: https://miro.com/app/board/o9J_lgiqJAs=/?moveToWidget=345876451385
 SyncOperation -> Operation
 SyncOperation -> Application
 
+DiffOperation -> Application
+
 ApplicationState ->  Application
 
 DeploymentToApplicationMapping -> Application
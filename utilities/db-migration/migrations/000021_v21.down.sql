@@ -0,0 +1 @@
+ALTER TABLE Application ALTER COLUMN spec_field type VARCHAR (16384);
@@ -0,0 +1 @@
+ALTER TABLE SyncOperation DROP COLUMN sync_windows;
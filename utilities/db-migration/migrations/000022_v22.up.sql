@@ -0,0 +1 @@
+ALTER TABLE SyncOperation ADD COLUMN sync_windows VARCHAR ( 4096 );
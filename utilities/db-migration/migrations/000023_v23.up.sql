@@ -0,0 +1,34 @@
+-- DeploymentHistory is a record of a previous deployment (sync) of an Application, based on the revision history
+-- of the Argo CD Application. Unlike the Argo CD Application, this is preserved if the Argo CD Application is recreated.
+CREATE TABLE DeploymentHistory (
+
+    -- Primary key for the DeploymentHistory (UID), is a random UUID
+    deploymenthistory_id VARCHAR(48) NOT NULL PRIMARY KEY,
+
+    -- The Application that was deployed
+    -- Foreign key to: Application.application_id
+    application_id VARCHAR(48) NOT NULL,
+    CONSTRAINT fk_dh_app_id FOREIGN KEY (application_id) REFERENCES Application(application_id) ON DELETE NO ACTION ON UPDATE NO ACTION,
+
+    -- The revision (for example, the Git commit SHA) that was deployed
+    revision VARCHAR(256) NOT NULL,
+
+    -- The source of the Application that was deployed, as JSON
+    source VARCHAR(4096),
+
+    -- When the deployment completed
+    deployed_at TIMESTAMP NOT NULL,
+
+    -- Who initiated the deployment: either the name of a user, or 'automated' for an automated sync
+    initiated_by VARCHAR(256),
+
+    -- The name of the GitOpsDeploymentSyncRun that triggered the deployment, if any
+    sync_run_name VARCHAR(256),
+
+    seq_id SERIAL,
+
+    -- When DeploymentHistory was created, which allows us to tell how old the resources are
+    created_on TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_deploymenthistory_1 ON DeploymentHistory(application_id);
@@ -0,0 +1,5 @@
+ALTER TABLE SyncOperation ADD COLUMN prune BOOLEAN DEFAULT FALSE;
+ALTER TABLE SyncOperation ADD COLUMN dry_run BOOLEAN DEFAULT FALSE;
+ALTER TABLE SyncOperation ADD COLUMN force BOOLEAN DEFAULT FALSE;
+ALTER TABLE SyncOperation ADD COLUMN sync_strategy VARCHAR(16);
+ALTER TABLE SyncOperation ADD COLUMN resources VARCHAR(4096);
@@ -0,0 +1,6 @@
+ALTER TABLE SyncOperation ADD COLUMN phase VARCHAR(16);
+ALTER TABLE SyncOperation ADD COLUMN phase_message VARCHAR(1024);
+ALTER TABLE SyncOperation ADD COLUMN started_at TIMESTAMP;
+ALTER TABLE SyncOperation ADD COLUMN finished_at TIMESTAMP;
+ALTER TABLE SyncOperation ADD COLUMN synced_revision VARCHAR(256);
+ALTER TABLE SyncOperation ADD COLUMN resource_results VARCHAR(16384);
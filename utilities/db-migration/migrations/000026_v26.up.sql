@@ -0,0 +1,46 @@
+-- DiffOperation tracks a diff request from the API (a GitOpsDeploymentDiff CR): the cluster-agent renders the manifests
+-- of the Application at a candidate revision, via the Argo CD repo server, and compares them against the live state.
+CREATE TABLE DiffOperation (
+
+    -- Primary key for the DiffOperation (UID), is a random UUID
+    diffoperation_id VARCHAR(48) NOT NULL PRIMARY KEY,
+
+    -- The target Application whose manifests are compared against the live state
+    -- Foreign key to: Application.application_id
+    application_id VARCHAR(48) NOT NULL,
+    CONSTRAINT fk_dfo_app_id FOREIGN KEY (application_id) REFERENCES Application(application_id) ON DELETE NO ACTION ON UPDATE NO ACTION,
+
+    -- The 'gitopsDeploymentName' field of the GitOpsDeploymentDiff CR
+    deployment_name VARCHAR(256) NOT NULL,
+
+    -- The 'revision' field of the GitOpsDeploymentDiff CR
+    revision VARCHAR(256) NOT NULL,
+
+    -- The status of the diff, as reported by the cluster-agent:
+
+    -- values: Pending, Running, Succeeded, Failed (empty is equivalent to Pending)
+    phase VARCHAR(16),
+
+    -- Human-readable details about the phase, for example the reason the diff failed
+    phase_message VARCHAR(1024),
+
+    -- When the diff was started/finished
+    started_at TIMESTAMP,
+    finished_at TIMESTAMP,
+
+    -- The revision (for example, the Git commit SHA) that the manifests were rendered from
+    resolved_revision VARCHAR(256),
+
+    -- The diff of each changed resource (as JSON)
+    resource_diffs VARCHAR(262144),
+
+    -- Whether some of the changed resources were omitted from resource_diffs
+    truncated BOOLEAN DEFAULT FALSE,
+
+    seq_id SERIAL,
+
+    -- When DiffOperation was created, which allows us to tell how old the resources are
+    created_on TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_diffoperation_1 ON DiffOperation(application_id);
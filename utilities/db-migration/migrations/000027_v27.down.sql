@@ -0,0 +1 @@
+ALTER TABLE ApplicationState DROP COLUMN resource_tree;
@@ -0,0 +1 @@
+ALTER TABLE ApplicationState ADD COLUMN resource_tree bytea;
@@ -0,0 +1 @@
+ALTER TABLE ClusterCredentials DROP COLUMN ca_bundle;
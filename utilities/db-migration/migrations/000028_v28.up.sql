@@ -0,0 +1 @@
+ALTER TABLE ClusterCredentials ADD COLUMN ca_bundle VARCHAR(65000);
@@ -0,0 +1 @@
+ALTER TABLE ClusterCredentials DROP COLUMN client_certificate_data, DROP COLUMN client_key_data;
@@ -0,0 +1 @@
+ALTER TABLE ClusterCredentials ADD COLUMN client_certificate_data VARCHAR(65000), ADD COLUMN client_key_data VARCHAR(65000);
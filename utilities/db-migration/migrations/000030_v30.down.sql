@@ -0,0 +1 @@
+ALTER TABLE ClusterCredentials DROP COLUMN proxy_url;
@@ -0,0 +1,2 @@
+ALTER TABLE DeploymentHistory DROP COLUMN revisions;
+ALTER TABLE DeploymentHistory DROP COLUMN sources;
@@ -0,0 +1,2 @@
+ALTER TABLE DeploymentHistory ADD COLUMN sources VARCHAR(16384);
+ALTER TABLE DeploymentHistory ADD COLUMN revisions VARCHAR(2048);
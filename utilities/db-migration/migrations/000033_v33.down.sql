@@ -0,0 +1 @@
+ALTER TABLE SyncOperation ADD COLUMN attempts INT;